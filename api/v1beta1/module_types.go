@@ -19,6 +19,7 @@ package v1beta1
 import (
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // BuildArg represents a build argument used when building a container image.
@@ -92,7 +93,7 @@ type Sign struct {
 }
 
 // KernelMapping pairs kernel versions with a DriverContainer image.
-// Kernel versions can be matched literally or using a regular expression.
+// Kernel versions can be matched literally, using a regular expression or using version constraints.
 type KernelMapping struct {
 
 	// +optional
@@ -118,6 +119,14 @@ type KernelMapping struct {
 	// Regexp is a regular expression to be match against node kernels.
 	Regexp string `json:"regexp"`
 
+	// +optional
+	// VersionRange is a comma-separated list of version constraints that node kernels must all satisfy, such as
+	// ">=5.14.0-284, <5.14.0-400".
+	// Supported operators are =, !=, <, <=, > and >=.
+	// Kernels must have the same flavour (such as +rt or -generic) as the versions in the constraints, and the same
+	// architecture if they specify one.
+	VersionRange string `json:"versionRange,omitempty"`
+
 	// Deprecated: please use InTreeModulesToRemove.
 	// +optional
 	// InTreeModuleToRemove specifies one in-tree kernel module that should be removed (if present)
@@ -128,6 +137,13 @@ type KernelMapping struct {
 	// InTreeModulesToRemove specifies any number of  in-tree kernel modules that should be removed (if present)
 	// before loading the kernel module from the ContainerImage
 	InTreeModulesToRemove []string `json:"inTreeModulesToRemove"`
+
+	// +optional
+	// Modprobe overrides the Container's modprobe settings for the kernels matched by this mapping.
+	// Each field that is set replaces the corresponding field of the Container's modprobe settings.
+	// Setting rawArgs clears moduleName and args, and setting one of modulesLoadingOrder, modulesGraph or
+	// moduleFiles clears the two others.
+	Modprobe *ModprobeOverride `json:"modprobe,omitempty"`
 }
 
 type ModprobeArgs struct {
@@ -184,6 +200,105 @@ type ModprobeSpec struct {
 	// In order to load all 3 modules, moduleA shoud be defined in the ModuleName parameter of this struct
 	// +optional
 	ModulesLoadingOrder []string `json:"modulesLoadingOrder,omitempty"`
+
+	// ModulesGraph describes the kernel modules to load along with ModuleName, in case their dependencies were not
+	// created by depmod and cannot be expressed as a single chain by ModulesLoadingOrder.
+	// The modules are loaded one by one in an order that satisfies all their dependencies, and unloaded in the
+	// reverse order.
+	// ModuleName must be one of the modules; its parameters are set by Parameters.
+	// Cannot be used with ModulesLoadingOrder.
+	// +optional
+	ModulesGraph []ModuleGraphNode `json:"modulesGraph,omitempty"`
+
+	// ModuleFiles lists kernel module files to load one by one without modprobe, like insmod does, for images that
+	// ship modules without depmod metadata.
+	// The paths are relative to the <dirName>/lib/modules/<kernel version> directory of the image.
+	// The files are loaded in the listed order and unloaded in the reverse order; the last file must be the module
+	// set by ModuleName, and Parameters only apply to it.
+	// Cannot be used with ModulesLoadingOrder or ModulesGraph.
+	// +optional
+	ModuleFiles []string `json:"moduleFiles,omitempty"`
+
+	// UnloadWaitTimeout is how long the worker waits for the kernel module to be unused before unloading it.
+	// While the module is used by other modules or by processes, the worker checks it again with an increasing delay.
+	// If not set, the worker does not wait.
+	// +optional
+	UnloadWaitTimeout *metav1.Duration `json:"unloadWaitTimeout,omitempty"`
+
+	// SignatureVerification makes the worker verify the signature of the kernel modules before loading them.
+	// +optional
+	SignatureVerification *SignatureVerificationSpec `json:"signatureVerification,omitempty"`
+}
+
+// ModprobeOverride holds the fields of ModprobeSpec to override in a KernelMapping.
+// Fields that are not set keep the value of the Container's ModprobeSpec; see ModprobeSpec for their meaning.
+type ModprobeOverride struct {
+	// +optional
+	ModuleName string `json:"moduleName,omitempty"`
+
+	// +optional
+	Parameters []string `json:"parameters,omitempty"`
+
+	// +optional
+	DirName string `json:"dirName,omitempty"`
+
+	// +optional
+	Args *ModprobeArgs `json:"args,omitempty"`
+
+	// +optional
+	RawArgs *ModprobeArgs `json:"rawArgs,omitempty"`
+
+	// +optional
+	FirmwarePath string `json:"firmwarePath,omitempty"`
+
+	// +optional
+	ModulesLoadingOrder []string `json:"modulesLoadingOrder,omitempty"`
+
+	// +optional
+	ModulesGraph []ModuleGraphNode `json:"modulesGraph,omitempty"`
+
+	// +optional
+	ModuleFiles []string `json:"moduleFiles,omitempty"`
+
+	// +optional
+	UnloadWaitTimeout *metav1.Duration `json:"unloadWaitTimeout,omitempty"`
+
+	// +optional
+	SignatureVerification *SignatureVerificationSpec `json:"signatureVerification,omitempty"`
+}
+
+// ModuleGraphNode describes a kernel module and its dependencies.
+type ModuleGraphNode struct {
+	// Name is the name of the kernel module.
+	Name string `json:"name"`
+
+	// Parameters is an optional list of kernel module parameters, in the form of key=value, to load the module with.
+	// +optional
+	Parameters []string `json:"parameters,omitempty"`
+
+	// Pre lists the modules that must be loaded before this module, and unloaded after it.
+	// +optional
+	Pre []string `json:"pre,omitempty"`
+
+	// Post lists the modules that must be loaded after this module, and unloaded before it.
+	// +optional
+	Post []string `json:"post,omitempty"`
+}
+
+// SignatureVerificationSpec describes the keys that must have signed the kernel modules.
+// Exactly one of CertSecret and UEFIKeys must be set.
+type SignatureVerificationSpec struct {
+	// CertSecret is a secret containing, in its "cert" key, the PEM-encoded certificate that must have signed the
+	// kernel modules.
+	// +optional
+	CertSecret *v1.LocalObjectReference `json:"certSecret,omitempty"`
+
+	// UEFIKeys makes the worker check that the kernel modules are signed with a key enrolled in the UEFI db or MOK
+	// list of the node; the verification is skipped on nodes where Secure Boot is disabled.
+	// Keys built into the kernel cannot be read from user space, so modules signed with them are rejected; use
+	// CertSecret with the signing certificate of the distribution to verify such modules.
+	// +optional
+	UEFIKeys bool `json:"uefiKeys,omitempty"`
 }
 
 type ModuleLoaderContainerSpec struct {
@@ -213,11 +328,18 @@ type ModuleLoaderContainerSpec struct {
 	ImagePullPolicy v1.PullPolicy `json:"imagePullPolicy,omitempty" protobuf:"bytes,14,opt,name=imagePullPolicy,casttype=PullPolicy"`
 
 	// KernelMappings is a list of kernel mappings.
-	// When a node's labels match Selector, then the KMM Operator will look for the first mapping that matches its
+	// When a node's labels match Selector, then the KMM Operator will look for the mapping that matches its
 	// kernel version, and use the corresponding container image to run the DriverContainer.
+	// If several mappings match, literal mappings take precedence over version ranges, which take precedence over
+	// regular expressions; among mappings of the same kind, the first one is used.
 	// +kubebuilder:validation:MinItems=1
 	KernelMappings []KernelMapping `json:"kernelMappings"`
 
+	// StrictKernelMappings makes the webhook reject the Module if several kernel mappings match the kernel of a node
+	// targeted by the Module, instead of relying on the precedence between mappings.
+	// +optional
+	StrictKernelMappings bool `json:"strictKernelMappings,omitempty"`
+
 	// Modprobe is a set of properties to customize which module modprobe loads and with which properties.
 	Modprobe ModprobeSpec `json:"modprobe"`
 
@@ -235,6 +357,17 @@ type ModuleLoaderContainerSpec struct {
 	// InTreeModulesToRemove specifies any number of  in-tree kernel modules that should be removed (if present)
 	// before loading the kernel module from the ContainerImage
 	InTreeModulesToRemove []string `json:"inTreeModulesToRemove"`
+
+	// +optional
+	// BlacklistInTreeModules makes the worker write a /etc/modprobe.d/kmm-<namespace>-<name>.conf file on the host
+	// that blacklists the in-tree modules to remove, so that they are not loaded again after a reboot.
+	// The file is removed when the module is unloaded.
+	BlacklistInTreeModules bool `json:"blacklistInTreeModules,omitempty"`
+
+	// +optional
+	// RestoreInTreeModulesOnUnload makes the worker load again, from the host's modules tree, the in-tree modules
+	// that it removed before loading the kernel module from the ContainerImage, when that module is unloaded.
+	RestoreInTreeModulesOnUnload bool `json:"restoreInTreeModulesOnUnload,omitempty"`
 }
 
 type ModuleLoaderSpec struct {
@@ -332,8 +465,95 @@ type ModuleSpec struct {
 	// If specified, the pod's tolerations.
 	// +optional
 	Tolerations []v1.Toleration `json:"tolerations,omitempty"`
+
+	// RolloutStrategy moves the targeted nodes to a new module configuration in batches instead of all at once.
+	// +optional
+	RolloutStrategy *RolloutStrategy `json:"rolloutStrategy,omitempty"`
+
+	// Drain makes KMM cordon a node and evict the Pods that use the kernel module before reloading it, and uncordon the
+	// node once the kernel module is loaded again.
+	// Evictions honour PodDisruptionBudgets.
+	// +optional
+	Drain *DrainSpec `json:"drain,omitempty"`
+
+	// MaintenanceWindow restricts the reloads of the kernel module on nodes where it is loaded, caused by changes of the
+	// Module, to the periods during which the window is open.
+	// Nodes on which the kernel module is not loaded yet get it immediately.
+	// +optional
+	MaintenanceWindow *MaintenanceWindow `json:"maintenanceWindow,omitempty"`
+}
+
+// MaintenanceWindow describes recurring periods during which disruptive operations are allowed.
+type MaintenanceWindow struct {
+	// Schedule is a cron expression with five fields (minute, hour, day of month, month and day of week) that
+	// defines when the window opens, for example "0 2 * * 6" for every Saturday at 2am.
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+
+	// Duration is how long the window stays open once it opened, for example "2h".
+	Duration metav1.Duration `json:"duration"`
+
+	// TimeZone is the name of the time zone in which Schedule is interpreted, as found in the IANA Time Zone database,
+	// for example "Europe/Paris".
+	// Defaults to UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// DrainSpec describes the Pods that KMM evicts from a node before reloading the kernel module on it.
+type DrainSpec struct {
+	// ResourceNames lists extended resources, such as those advertised by the device plugin; Pods that request any of
+	// them are evicted.
+	// +optional
+	ResourceNames []v1.ResourceName `json:"resourceNames,omitempty"`
+
+	// PodSelector selects additional Pods to evict.
+	// +optional
+	PodSelector *metav1.LabelSelector `json:"podSelector,omitempty"`
 }
 
+// RolloutStrategy describes how the targeted nodes are moved to a new module configuration, such as a new image or
+// new parameters.
+type RolloutStrategy struct {
+	// MaxUnavailable is the maximum number, or percentage, of the targeted nodes that can be moving to a new
+	// configuration at the same time.
+	// A node stops counting once it has loaded the new configuration.
+	// If the module fails to load on a node, no other node is moved until the failure is resolved or the Module
+	// is updated.
+	// Defaults to 1.
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:XIntOrString
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+const (
+	// ModuleConditionRolloutProgressing is true while some targeted nodes are not running the current configuration
+	// of the Module yet.
+	ModuleConditionRolloutProgressing = "RolloutProgressing"
+	// ModuleConditionRolloutFailed is true when the module failed to load on nodes that were moved to the current
+	// configuration.
+	ModuleConditionRolloutFailed = "RolloutFailed"
+	// ModuleConditionMaintenancePending is true while some nodes wait for the maintenance window to reload the kernel
+	// module.
+	ModuleConditionMaintenancePending = "MaintenancePending"
+
+	// ModuleReasonRolloutInProgress means that some nodes are moving or waiting to move to the current configuration.
+	ModuleReasonRolloutInProgress = "RolloutInProgress"
+	// ModuleReasonRolloutComplete means that all targeted nodes have loaded the current configuration.
+	ModuleReasonRolloutComplete = "RolloutComplete"
+	// ModuleReasonRolloutHalted means that the rollout is stopped because the module failed to load on some nodes.
+	ModuleReasonRolloutHalted = "RolloutHalted"
+	// ModuleReasonNodesFailed means that the module failed to load on some nodes.
+	ModuleReasonNodesFailed = "NodesFailed"
+	// ModuleReasonNoFailedNodes means that the module did not fail to load on any node.
+	ModuleReasonNoFailedNodes = "NoFailedNodes"
+	// ModuleReasonWaitingForMaintenanceWindow means that some nodes wait for the maintenance window to open.
+	ModuleReasonWaitingForMaintenanceWindow = "WaitingForMaintenanceWindow"
+	// ModuleReasonNoPendingOperations means that no node waits for the maintenance window.
+	ModuleReasonNoPendingOperations = "NoPendingOperations"
+)
+
 // DaemonSetStatus contains the status for a daemonset deployed during
 // reconciliation loop
 type DaemonSetStatus struct {
@@ -345,6 +565,29 @@ type DaemonSetStatus struct {
 	AvailableNumber int32 `json:"availableNumber,omitempty"`
 }
 
+// NodeConditionCount is the number of nodes on which a condition of the module is true with the same reason.
+type NodeConditionCount struct {
+	// Type is the type of the condition, such as Loaded or Failed.
+	Type string `json:"type"`
+	// Reason is the reason of the condition, such as ImagePullFailed.
+	Reason string `json:"reason"`
+	// Count is the number of nodes.
+	Count int32 `json:"count"`
+}
+
+// KernelMappingStatus describes the kernel mapping used for a kernel version.
+type KernelMappingStatus struct {
+	// KernelVersion is the kernel version of at least one node targeted by the Module.
+	KernelVersion string `json:"kernelVersion"`
+	// MappingIndex is the index of the kernel mapping used for KernelVersion.
+	// It is not set if no kernel mapping matches KernelVersion.
+	// +optional
+	MappingIndex *int32 `json:"mappingIndex,omitempty"`
+	// ContainerImage is the container image used for KernelVersion.
+	// +optional
+	ContainerImage string `json:"containerImage,omitempty"`
+}
+
 // ModuleStatus defines the observed state of Module.
 type ModuleStatus struct {
 	// DevicePlugin contains the status of the Device Plugin daemonset
@@ -352,6 +595,20 @@ type ModuleStatus struct {
 	DevicePlugin DaemonSetStatus `json:"devicePlugin,omitempty"`
 	// ModuleLoader contains the status of the ModuleLoader daemonset
 	ModuleLoader DaemonSetStatus `json:"moduleLoader"`
+	// NodeConditions aggregates the true conditions reported for the module in the NodeModulesConfigs of the
+	// targeted nodes, per type and reason.
+	// +optional
+	NodeConditions []NodeConditionCount `json:"nodeConditions,omitempty"`
+	// KernelMappings lists the kernel mapping used for each kernel version running on the targeted nodes.
+	// +listType=map
+	// +listMapKey=kernelVersion
+	// +optional
+	KernelMappings []KernelMappingStatus `json:"kernelMappings,omitempty"`
+	// Conditions describe the progress of the rollout of the Module's configuration to the targeted nodes.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 //+kubebuilder:object:root=true
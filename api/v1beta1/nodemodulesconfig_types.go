@@ -31,10 +31,18 @@ type ModuleConfig struct {
 	//+optional
 	InTreeModulesToRemove []string `json:"inTreeModulesToRemove,omitempty"`
 	//+optional
-	InTreeModuleToRemove string       `json:"inTreeModuleToRemove,omitempty"`
-	Modprobe             ModprobeSpec `json:"modprobe"`
+	InTreeModuleToRemove string `json:"inTreeModuleToRemove,omitempty"`
+	// BlacklistInTreeModules makes the worker maintain a modprobe configuration file on the host that blacklists the
+	// in-tree modules to remove.
 	//+optional
-	Tolerations          []v1.Toleration `json:"tolerations,omitempty"`
+	BlacklistInTreeModules bool `json:"blacklistInTreeModules,omitempty"`
+	// RestoreInTreeModulesOnUnload makes the worker load again the in-tree modules that it removed, when the module is
+	// unloaded.
+	//+optional
+	RestoreInTreeModulesOnUnload bool         `json:"restoreInTreeModulesOnUnload,omitempty"`
+	Modprobe                     ModprobeSpec `json:"modprobe"`
+	//+optional
+	Tolerations []v1.Toleration `json:"tolerations,omitempty"`
 }
 
 type ModuleItem struct {
@@ -48,6 +56,14 @@ type NodeModuleSpec struct {
 	ModuleItem `json:",inline"`
 
 	Config ModuleConfig `json:"config"`
+	// Drain describes the Pods to evict from the node before reloading the module.
+	// It is kept out of Config so that changing the drain policy does not reload the module.
+	//+optional
+	Drain *DrainSpec `json:"drain,omitempty"`
+	// MaintenanceWindow restricts the reloads of the module to the periods during which the window is open.
+	// It is kept out of Config so that changing the window does not reload the module.
+	//+optional
+	MaintenanceWindow *MaintenanceWindow `json:"maintenanceWindow,omitempty"`
 }
 
 // NodeModulesConfigSpec describes the desired state of modules on the node
@@ -59,13 +75,160 @@ type NodeModulesConfigSpec struct {
 	Modules []NodeModuleSpec `json:"modules,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
 }
 
+// Types of the conditions of a NodeModuleProgress.
+const (
+	// ModuleConditionLoading is true while a worker Pod loads the module or updates its parameters.
+	ModuleConditionLoading = "Loading"
+	// ModuleConditionLoaded is true once a worker Pod loaded the module.
+	ModuleConditionLoaded = "Loaded"
+	// ModuleConditionUnloading is true while a worker Pod unloads the module.
+	ModuleConditionUnloading = "Unloading"
+	// ModuleConditionFailed is true if the last worker Pod for the module failed.
+	ModuleConditionFailed = "Failed"
+	// ModuleConditionDrifted is true if the last drift check found that the module is not loaded in the kernel
+	// anymore, for example because it was removed with rmmod.
+	ModuleConditionDrifted = "Drifted"
+)
+
+// Reasons of the conditions of a NodeModuleProgress.
+const (
+	ModuleReasonFirmwareCopyFailed     = "FirmwareCopyFailed"
+	ModuleReasonImagePullFailed        = "ImagePullFailed"
+	ModuleReasonModuleLive             = "ModuleLive"
+	ModuleReasonModuleNotLoaded        = "ModuleNotLoaded"
+	ModuleReasonModprobeFailed         = "ModprobeFailed"
+	ModuleReasonParametersUpdateFailed = "ParametersUpdateFailed"
+	ModuleReasonWorkerPodFailed        = "WorkerPodFailed"
+	ModuleReasonWorkerPodPending       = "WorkerPodPending"
+	ModuleReasonWorkerPodRunning       = "WorkerPodRunning"
+	ModuleReasonWorkerPodSucceeded     = "WorkerPodSucceeded"
+)
+
+// NodeModuleProgress describes the progress of the worker Pods for a module on the node.
+type NodeModuleProgress struct {
+	// Conditions describe the progress of the worker Pods for this module.
+	//+listType=map
+	//+listMapKey=type
+	//+optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// WorkerPodName is the name of the last worker Pod that failed for this module.
+	//+optional
+	WorkerPodName string `json:"workerPodName,omitempty"`
+	// WorkerRestartCount is the number of times the containers of the failing worker Pod were restarted.
+	//+optional
+	WorkerRestartCount int32 `json:"workerRestartCount,omitempty"`
+	// LastError is the last error reported by a worker Pod for this module.
+	//+optional
+	LastError string `json:"lastError,omitempty"`
+}
+
 type NodeModuleStatus struct {
-	ModuleItem `json:",inline"`
+	ModuleItem         `json:",inline"`
+	NodeModuleProgress `json:",inline"`
 
 	//+optional
 	Config ModuleConfig `json:"config,omitempty"`
 	//+optional
 	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+	// BootID is the boot ID of the node at the time the module was loaded.
+	// The module is reloaded when the node reports a different boot ID, which means that it was rebooted.
+	//+optional
+	BootID string `json:"bootID,omitempty"`
+	// BlacklistedModules holds the in-tree modules that are blacklisted on the node by the modprobe configuration file
+	// maintained for this module. It is empty if no blacklist is in place.
+	//+optional
+	BlacklistedModules []string `json:"blacklistedModules,omitempty"`
+	// RemovedInTreeModules holds the in-tree modules that were removed from the node before loading this module.
+	//+optional
+	RemovedInTreeModules []string `json:"removedInTreeModules,omitempty"`
+	// LastDriftCheckTime is the last time KMM checked that the module is still loaded in the kernel.
+	//+optional
+	LastDriftCheckTime *metav1.Time `json:"lastDriftCheckTime,omitempty"`
+}
+
+// NodeModulePendingStatus reports the progress of the worker Pods for a module that is not loaded on the node yet.
+type NodeModulePendingStatus struct {
+	Name               string `json:"name"`
+	Namespace          string `json:"namespace"`
+	NodeModuleProgress `json:",inline"`
+}
+
+// ModuleResult is the outcome of a worker operation for one kernel module.
+type ModuleResult struct {
+	// Name is the name of the kernel module.
+	Name string `json:"name"`
+	// State is Loaded or Unloaded if the operation succeeded, Failed if it failed, or Skipped if it was not attempted
+	// because the operation failed for a module this one depends on.
+	State string `json:"state"`
+	// Error is the error returned while loading or unloading the kernel module, if any.
+	//+optional
+	Error string `json:"error,omitempty"`
+}
+
+// WorkerResult is the outcome of a worker Pod, as written by the worker in its termination message.
+type WorkerResult struct {
+	// Action is the operation performed by the worker, either load or unload.
+	Action string `json:"action"`
+	// ModprobeArgs are the arguments of the last modprobe invocation.
+	//+optional
+	ModprobeArgs []string `json:"modprobeArgs,omitempty"`
+	// ExitCode is the exit code of the last modprobe invocation.
+	//+optional
+	ExitCode int32 `json:"exitCode,omitempty"`
+	// Stderr holds the last lines printed by modprobe on its standard error.
+	//+optional
+	Stderr []string `json:"stderr,omitempty"`
+	// Modules holds the names of the kernel modules that were actually inserted or removed.
+	//+optional
+	Modules []string `json:"modules,omitempty"`
+	// Parameters holds the values of the module parameters read back from sysfs after a live update.
+	//+optional
+	Parameters map[string]string `json:"parameters,omitempty"`
+	// BootID is the boot ID of the node, as read by the worker after loading the module.
+	//+optional
+	BootID string `json:"bootID,omitempty"`
+	// BlacklistedModules holds the in-tree modules that were blacklisted on the host after loading the module.
+	//+optional
+	BlacklistedModules []string `json:"blacklistedModules,omitempty"`
+	// RemovedInTreeModules holds the in-tree modules that were loaded and removed before loading the module.
+	//+optional
+	RemovedInTreeModules []string `json:"removedInTreeModules,omitempty"`
+	// RestoredInTreeModules holds the in-tree modules that were loaded again after unloading the module.
+	//+optional
+	RestoredInTreeModules []string `json:"restoredInTreeModules,omitempty"`
+	// InTreeModulesRestoreError is the error that prevented some in-tree modules from being loaded again after
+	// unloading the module.
+	//+optional
+	InTreeModulesRestoreError string `json:"inTreeModulesRestoreError,omitempty"`
+	// ModuleResults holds the outcome of the operation for each kernel module of the modules graph.
+	//+optional
+	ModuleResults []ModuleResult `json:"moduleResults,omitempty"`
+	// BlockedBy holds the modules and processes that prevented the kernel module from being unloaded.
+	//+optional
+	BlockedBy []string `json:"blockedBy,omitempty"`
+	// KernelLog holds the kernel log records related to the module that were written during a failed operation.
+	//+optional
+	KernelLog []string `json:"kernelLog,omitempty"`
+	// ErrorCategory is a short, machine-readable description of the error, if any.
+	//+optional
+	ErrorCategory string `json:"errorCategory,omitempty"`
+	// Error is the error returned by the worker, if any.
+	//+optional
+	Error string `json:"error,omitempty"`
+}
+
+type NodeModuleWorkerResult struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	// PodName is the name of the worker Pod that reported the result.
+	PodName string `json:"podName"`
+	// Time is the time at which the worker container terminated.
+	Time metav1.Time `json:"time"`
+	// RequestedParameters holds the module parameters that a setParameters worker Pod tried to apply.
+	//+optional
+	RequestedParameters []string `json:"requestedParameters,omitempty"`
+
+	WorkerResult `json:",inline"`
 }
 
 // NodeModuleConfigStatus is the most recently observed status of the KMM modules on node.
@@ -77,6 +240,13 @@ type NodeModulesConfigStatus struct {
 	// +patchStrategy=merge
 	// +optional
 	Modules []NodeModuleStatus `json:"modules,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+	// PendingModules reports the progress of the worker Pods for the configured modules that were never loaded on
+	// the node; a module moves to Modules once it is loaded.
+	// +optional
+	PendingModules []NodeModulePendingStatus `json:"pendingModules,omitempty"`
+	// WorkerResults holds the last result reported by a worker Pod for each module.
+	// +optional
+	WorkerResults []NodeModuleWorkerResult `json:"workerResults,omitempty"`
 }
 
 // +kubebuilder:object:root=true
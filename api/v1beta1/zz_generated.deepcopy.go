@@ -23,7 +23,9 @@ package v1beta1
 import (
 	"github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta2"
 	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -162,6 +164,31 @@ func (in *DevicePluginSpec) DeepCopy() *DevicePluginSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DrainSpec) DeepCopyInto(out *DrainSpec) {
+	*out = *in
+	if in.ResourceNames != nil {
+		in, out := &in.ResourceNames, &out.ResourceNames
+		*out = make([]v1.ResourceName, len(*in))
+		copy(*out, *in)
+	}
+	if in.PodSelector != nil {
+		in, out := &in.PodSelector, &out.PodSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrainSpec.
+func (in *DrainSpec) DeepCopy() *DrainSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DrainSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KanikoParams) DeepCopyInto(out *KanikoParams) {
 	*out = *in
@@ -200,6 +227,11 @@ func (in *KernelMapping) DeepCopyInto(out *KernelMapping) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Modprobe != nil {
+		in, out := &in.Modprobe, &out.Modprobe
+		*out = new(ModprobeOverride)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KernelMapping.
@@ -212,6 +244,42 @@ func (in *KernelMapping) DeepCopy() *KernelMapping {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KernelMappingStatus) DeepCopyInto(out *KernelMappingStatus) {
+	*out = *in
+	if in.MappingIndex != nil {
+		in, out := &in.MappingIndex, &out.MappingIndex
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KernelMappingStatus.
+func (in *KernelMappingStatus) DeepCopy() *KernelMappingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KernelMappingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ModprobeArgs) DeepCopyInto(out *ModprobeArgs) {
 	*out = *in
@@ -237,6 +305,63 @@ func (in *ModprobeArgs) DeepCopy() *ModprobeArgs {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModprobeOverride) DeepCopyInto(out *ModprobeOverride) {
+	*out = *in
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = new(ModprobeArgs)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RawArgs != nil {
+		in, out := &in.RawArgs, &out.RawArgs
+		*out = new(ModprobeArgs)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ModulesLoadingOrder != nil {
+		in, out := &in.ModulesLoadingOrder, &out.ModulesLoadingOrder
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ModulesGraph != nil {
+		in, out := &in.ModulesGraph, &out.ModulesGraph
+		*out = make([]ModuleGraphNode, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ModuleFiles != nil {
+		in, out := &in.ModuleFiles, &out.ModuleFiles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.UnloadWaitTimeout != nil {
+		in, out := &in.UnloadWaitTimeout, &out.UnloadWaitTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.SignatureVerification != nil {
+		in, out := &in.SignatureVerification, &out.SignatureVerification
+		*out = new(SignatureVerificationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModprobeOverride.
+func (in *ModprobeOverride) DeepCopy() *ModprobeOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(ModprobeOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ModprobeSpec) DeepCopyInto(out *ModprobeSpec) {
 	*out = *in
@@ -260,6 +385,28 @@ func (in *ModprobeSpec) DeepCopyInto(out *ModprobeSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ModulesGraph != nil {
+		in, out := &in.ModulesGraph, &out.ModulesGraph
+		*out = make([]ModuleGraphNode, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ModuleFiles != nil {
+		in, out := &in.ModuleFiles, &out.ModuleFiles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.UnloadWaitTimeout != nil {
+		in, out := &in.UnloadWaitTimeout, &out.UnloadWaitTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.SignatureVerification != nil {
+		in, out := &in.SignatureVerification, &out.SignatureVerification
+		*out = new(SignatureVerificationSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModprobeSpec.
@@ -278,7 +425,7 @@ func (in *Module) DeepCopyInto(out *Module) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Module.
@@ -327,6 +474,36 @@ func (in *ModuleConfig) DeepCopy() *ModuleConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModuleGraphNode) DeepCopyInto(out *ModuleGraphNode) {
+	*out = *in
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Pre != nil {
+		in, out := &in.Pre, &out.Pre
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Post != nil {
+		in, out := &in.Post, &out.Post
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModuleGraphNode.
+func (in *ModuleGraphNode) DeepCopy() *ModuleGraphNode {
+	if in == nil {
+		return nil
+	}
+	out := new(ModuleGraphNode)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ModuleItem) DeepCopyInto(out *ModuleItem) {
 	*out = *in
@@ -434,6 +611,21 @@ func (in *ModuleLoaderSpec) DeepCopy() *ModuleLoaderSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModuleResult) DeepCopyInto(out *ModuleResult) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModuleResult.
+func (in *ModuleResult) DeepCopy() *ModuleResult {
+	if in == nil {
+		return nil
+	}
+	out := new(ModuleResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ModuleSpec) DeepCopyInto(out *ModuleSpec) {
 	*out = *in
@@ -462,6 +654,21 @@ func (in *ModuleSpec) DeepCopyInto(out *ModuleSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.RolloutStrategy != nil {
+		in, out := &in.RolloutStrategy, &out.RolloutStrategy
+		*out = new(RolloutStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Drain != nil {
+		in, out := &in.Drain, &out.Drain
+		*out = new(DrainSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaintenanceWindow != nil {
+		in, out := &in.MaintenanceWindow, &out.MaintenanceWindow
+		*out = new(MaintenanceWindow)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModuleSpec.
@@ -479,6 +686,25 @@ func (in *ModuleStatus) DeepCopyInto(out *ModuleStatus) {
 	*out = *in
 	out.DevicePlugin = in.DevicePlugin
 	out.ModuleLoader = in.ModuleLoader
+	if in.NodeConditions != nil {
+		in, out := &in.NodeConditions, &out.NodeConditions
+		*out = make([]NodeConditionCount, len(*in))
+		copy(*out, *in)
+	}
+	if in.KernelMappings != nil {
+		in, out := &in.KernelMappings, &out.KernelMappings
+		*out = make([]KernelMappingStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModuleStatus.
@@ -491,11 +717,74 @@ func (in *ModuleStatus) DeepCopy() *ModuleStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeConditionCount) DeepCopyInto(out *NodeConditionCount) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeConditionCount.
+func (in *NodeConditionCount) DeepCopy() *NodeConditionCount {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeConditionCount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeModulePendingStatus) DeepCopyInto(out *NodeModulePendingStatus) {
+	*out = *in
+	in.NodeModuleProgress.DeepCopyInto(&out.NodeModuleProgress)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeModulePendingStatus.
+func (in *NodeModulePendingStatus) DeepCopy() *NodeModulePendingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeModulePendingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeModuleProgress) DeepCopyInto(out *NodeModuleProgress) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeModuleProgress.
+func (in *NodeModuleProgress) DeepCopy() *NodeModuleProgress {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeModuleProgress)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeModuleSpec) DeepCopyInto(out *NodeModuleSpec) {
 	*out = *in
 	in.ModuleItem.DeepCopyInto(&out.ModuleItem)
 	in.Config.DeepCopyInto(&out.Config)
+	if in.Drain != nil {
+		in, out := &in.Drain, &out.Drain
+		*out = new(DrainSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaintenanceWindow != nil {
+		in, out := &in.MaintenanceWindow, &out.MaintenanceWindow
+		*out = new(MaintenanceWindow)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeModuleSpec.
@@ -512,8 +801,23 @@ func (in *NodeModuleSpec) DeepCopy() *NodeModuleSpec {
 func (in *NodeModuleStatus) DeepCopyInto(out *NodeModuleStatus) {
 	*out = *in
 	in.ModuleItem.DeepCopyInto(&out.ModuleItem)
+	in.NodeModuleProgress.DeepCopyInto(&out.NodeModuleProgress)
 	in.Config.DeepCopyInto(&out.Config)
 	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	if in.BlacklistedModules != nil {
+		in, out := &in.BlacklistedModules, &out.BlacklistedModules
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RemovedInTreeModules != nil {
+		in, out := &in.RemovedInTreeModules, &out.RemovedInTreeModules
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastDriftCheckTime != nil {
+		in, out := &in.LastDriftCheckTime, &out.LastDriftCheckTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeModuleStatus.
@@ -526,6 +830,28 @@ func (in *NodeModuleStatus) DeepCopy() *NodeModuleStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeModuleWorkerResult) DeepCopyInto(out *NodeModuleWorkerResult) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	if in.RequestedParameters != nil {
+		in, out := &in.RequestedParameters, &out.RequestedParameters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.WorkerResult.DeepCopyInto(&out.WorkerResult)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeModuleWorkerResult.
+func (in *NodeModuleWorkerResult) DeepCopy() *NodeModuleWorkerResult {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeModuleWorkerResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeModulesConfig) DeepCopyInto(out *NodeModulesConfig) {
 	*out = *in
@@ -617,6 +943,20 @@ func (in *NodeModulesConfigStatus) DeepCopyInto(out *NodeModulesConfigStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.PendingModules != nil {
+		in, out := &in.PendingModules, &out.PendingModules
+		*out = make([]NodeModulePendingStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.WorkerResults != nil {
+		in, out := &in.WorkerResults, &out.WorkerResults
+		*out = make([]NodeModuleWorkerResult, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeModulesConfigStatus.
@@ -778,6 +1118,26 @@ func (in *PreflightValidationStatus) DeepCopy() *PreflightValidationStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutStrategy) DeepCopyInto(out *RolloutStrategy) {
+	*out = *in
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStrategy.
+func (in *RolloutStrategy) DeepCopy() *RolloutStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Sign) DeepCopyInto(out *Sign) {
 	*out = *in
@@ -809,6 +1169,26 @@ func (in *Sign) DeepCopy() *Sign {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SignatureVerificationSpec) DeepCopyInto(out *SignatureVerificationSpec) {
+	*out = *in
+	if in.CertSecret != nil {
+		in, out := &in.CertSecret, &out.CertSecret
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SignatureVerificationSpec.
+func (in *SignatureVerificationSpec) DeepCopy() *SignatureVerificationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SignatureVerificationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TLSOptions) DeepCopyInto(out *TLSOptions) {
 	*out = *in
@@ -823,3 +1203,70 @@ func (in *TLSOptions) DeepCopy() *TLSOptions {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkerResult) DeepCopyInto(out *WorkerResult) {
+	*out = *in
+	if in.ModprobeArgs != nil {
+		in, out := &in.ModprobeArgs, &out.ModprobeArgs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Stderr != nil {
+		in, out := &in.Stderr, &out.Stderr
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Modules != nil {
+		in, out := &in.Modules, &out.Modules
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.BlacklistedModules != nil {
+		in, out := &in.BlacklistedModules, &out.BlacklistedModules
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RemovedInTreeModules != nil {
+		in, out := &in.RemovedInTreeModules, &out.RemovedInTreeModules
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RestoredInTreeModules != nil {
+		in, out := &in.RestoredInTreeModules, &out.RestoredInTreeModules
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ModuleResults != nil {
+		in, out := &in.ModuleResults, &out.ModuleResults
+		*out = make([]ModuleResult, len(*in))
+		copy(*out, *in)
+	}
+	if in.BlockedBy != nil {
+		in, out := &in.BlockedBy, &out.BlockedBy
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.KernelLog != nil {
+		in, out := &in.KernelLog, &out.KernelLog
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkerResult.
+func (in *WorkerResult) DeepCopy() *WorkerResult {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkerResult)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -78,7 +78,7 @@ func main() {
 	if enableModule {
 		logger.Info("Enabling Module webhook")
 
-		if err = webhook.NewModuleValidator(logger).SetupWebhookWithManager(mgr); err != nil {
+		if err = webhook.NewModuleValidator(logger, mgr.GetAPIReader()).SetupWebhookWithManager(mgr); err != nil {
 			cmd.FatalError(setupLogger, err, "unable to create webhook", "webhook", "ModuleValidator")
 		}
 	}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/utils"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/worker"
+	"github.com/spf13/cobra"
+)
+
+func imagePullFunc(cmd *cobra.Command, args []string) error {
+	cfgPath := args[0]
+
+	logger.Info("Reading config", "path", cfgPath)
+
+	cfg, err := configHelper.ReadConfigFile(cfgPath)
+	if err != nil {
+		return fmt.Errorf("could not read config file %s: %v", cfgPath, err)
+	}
+
+	ctx := cmd.Context()
+
+	keychains := make([]authn.Keychain, 0, 2)
+
+	// The pull secrets directory is only mounted if the Module has an imageRepoSecret.
+	if _, err = os.Stat(pullSecretsDir); err == nil {
+		kc, err := worker.ReadKubernetesSecrets(ctx, pullSecretsDir, logger)
+		if err != nil {
+			return fmt.Errorf("could not read pull secrets: %v", err)
+		}
+
+		keychains = append(keychains, kc)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("could not stat %s: %v", pullSecretsDir, err)
+	}
+
+	kc, err := worker.ReadGlobalPullSecret(ctx, globalPullSecretPath, logger)
+	if err != nil {
+		return fmt.Errorf("could not read the global pull secret: %v", err)
+	}
+
+	opts := worker.PullOptions{
+		Insecure: cfg.InsecurePull,
+		Keychain: authn.NewMultiKeychain(append(keychains, kc)...),
+		Paths:    worker.ModuleImagePaths(cfg),
+	}
+
+	res, err := ip.PullAndExtract(ctx, cfg.ContainerImage, worker.SharedFilesDir, opts)
+	if err != nil {
+		return fmt.Errorf("could not pull and extract %s: %v", cfg.ContainerImage, err)
+	}
+
+	logger.Info("Image extracted", "reference", res.Reference)
+
+	return nil
+}
+
+func imageCopyBinaryFunc(_ *cobra.Command, args []string) error {
+	if err := fe.CopyExecutable(args[0]); err != nil {
+		return fmt.Errorf("could not copy the worker binary: %v", err)
+	}
+
+	return nil
+}
+
+// imageExtractFunc runs in the kmod image and copies the module tree and firmware out of it.
+// Its error is written to the termination log, where it is read from the Pod's status.
+func imageExtractFunc(_ *cobra.Command, args []string) (err error) {
+	defer func() {
+		if err == nil {
+			return
+		}
+
+		if werr := os.WriteFile(terminationLogPath, []byte(err.Error()), 0644); werr != nil {
+			logger.Info(utils.WarnString("Could not write the termination message"), "error", werr)
+		}
+	}()
+
+	cfgPath := args[0]
+
+	logger.Info("Reading config", "path", cfgPath)
+
+	cfg, err := configHelper.ReadConfigFile(cfgPath)
+	if err != nil {
+		return fmt.Errorf("could not read config file %s: %v", cfgPath, err)
+	}
+
+	if err = fe.Extract(imageRootDir, worker.SharedFilesDir, worker.ModuleImagePaths(cfg)); err != nil {
+		return fmt.Errorf("could not extract files from %s: %v", cfg.ContainerImage, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/worker"
+	"github.com/spf13/cobra"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("imagePullFunc", func() {
+	const (
+		configPath = "/some/path"
+		imageName  = "registry.example.com/ns/kmod:tag"
+	)
+
+	var (
+		ch  *worker.MockConfigHelper
+		mip *worker.MockImagePuller
+	)
+
+	BeforeEach(func() {
+		ctrl := gomock.NewController(GinkgoT())
+		ch = worker.NewMockConfigHelper(ctrl)
+		configHelper = ch
+		mip = worker.NewMockImagePuller(ctrl)
+		ip = mip
+		globalPullSecretPath = "/non/existent/path"
+		pullSecretsDir = "/non/existent/path"
+	})
+
+	AfterEach(func() {
+		configHelper = worker.NewConfigHelper()
+		globalPullSecretPath = worker.GlobalPullSecretPath
+		ip = nil
+		pullSecretsDir = worker.PullSecretsDir
+	})
+
+	cfg := &kmmv1beta1.ModuleConfig{
+		ContainerImage: imageName,
+		InsecurePull:   true,
+		KernelVersion:  "5.14.0",
+		Modprobe: kmmv1beta1.ModprobeSpec{
+			DirName:      "/opt",
+			FirmwarePath: "/firmware",
+		},
+	}
+
+	It("should return an error if we cannot read the config", func() {
+		ch.EXPECT().ReadConfigFile(configPath).Return(nil, errors.New("some error"))
+
+		Expect(
+			imagePullFunc(&cobra.Command{}, []string{configPath}),
+		).To(
+			HaveOccurred(),
+		)
+	})
+
+	It("should return an error if the pull secrets cannot be read", func() {
+		pullSecretsDir = "/proc/self/fd/0/invalid"
+
+		ch.EXPECT().ReadConfigFile(configPath).Return(cfg, nil)
+
+		Expect(
+			imagePullFunc(&cobra.Command{}, []string{configPath}),
+		).To(
+			HaveOccurred(),
+		)
+	})
+
+	It("should return an error if the image cannot be pulled", func() {
+		cmd := &cobra.Command{}
+		cmd.SetContext(context.TODO())
+
+		gomock.InOrder(
+			ch.EXPECT().ReadConfigFile(configPath).Return(cfg, nil),
+			mip.EXPECT().PullAndExtract(cmd.Context(), imageName, worker.SharedFilesDir, gomock.Any()).Return(nil, errors.New("some error")),
+		)
+
+		Expect(
+			imagePullFunc(cmd, []string{configPath}),
+		).To(
+			HaveOccurred(),
+		)
+	})
+
+	It("should pull the module tree and firmware", func() {
+		pullSecretsDir = "../../internal/worker/testdata/pull-secrets"
+
+		cmd := &cobra.Command{}
+		cmd.SetContext(context.TODO())
+
+		gomock.InOrder(
+			ch.EXPECT().ReadConfigFile(configPath).Return(cfg, nil),
+			mip.EXPECT().
+				PullAndExtract(cmd.Context(), imageName, worker.SharedFilesDir, gomock.Any()).
+				DoAndReturn(func(_ context.Context, _, _ string, opts worker.PullOptions) (*worker.PullResult, error) {
+					Expect(opts.Insecure).To(BeTrue())
+					Expect(opts.Keychain).NotTo(BeNil())
+					Expect(opts.Paths).To(Equal([]string{"/opt/lib/modules/5.14.0", "/firmware"}))
+
+					return &worker.PullResult{Reference: "registry.example.com/ns/kmod@sha256:123"}, nil
+				}),
+		)
+
+		Expect(
+			imagePullFunc(cmd, []string{configPath}),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+})
+
+var _ = Describe("imageCopyBinaryFunc", func() {
+	const dst = "/var/run/kmm/bin/worker"
+
+	var mfe *worker.MockFileExtractor
+
+	BeforeEach(func() {
+		mfe = worker.NewMockFileExtractor(gomock.NewController(GinkgoT()))
+		fe = mfe
+	})
+
+	AfterEach(func() {
+		fe = nil
+	})
+
+	It("should return an error if the binary cannot be copied", func() {
+		mfe.EXPECT().CopyExecutable(dst).Return(errors.New("some error"))
+
+		Expect(
+			imageCopyBinaryFunc(&cobra.Command{}, []string{dst}),
+		).To(
+			HaveOccurred(),
+		)
+	})
+
+	It("should copy the binary", func() {
+		mfe.EXPECT().CopyExecutable(dst)
+
+		Expect(
+			imageCopyBinaryFunc(&cobra.Command{}, []string{dst}),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+})
+
+var _ = Describe("imageExtractFunc", func() {
+	const configPath = "/some/path"
+
+	var (
+		ch  *worker.MockConfigHelper
+		mfe *worker.MockFileExtractor
+	)
+
+	BeforeEach(func() {
+		ctrl := gomock.NewController(GinkgoT())
+		ch = worker.NewMockConfigHelper(ctrl)
+		configHelper = ch
+		mfe = worker.NewMockFileExtractor(ctrl)
+		fe = mfe
+		terminationLogPath = filepath.Join(GinkgoT().TempDir(), "termination-log")
+	})
+
+	AfterEach(func() {
+		configHelper = worker.NewConfigHelper()
+		fe = nil
+		terminationLogPath = worker.TerminationLogPath
+	})
+
+	cfg := &kmmv1beta1.ModuleConfig{
+		ContainerImage: "registry.example.com/ns/kmod:tag",
+		KernelVersion:  "5.14.0",
+		Modprobe: kmmv1beta1.ModprobeSpec{
+			DirName:      "/opt",
+			FirmwarePath: "/firmware",
+		},
+	}
+
+	It("should write the error to the termination log", func() {
+		gomock.InOrder(
+			ch.EXPECT().ReadConfigFile(configPath).Return(cfg, nil),
+			mfe.
+				EXPECT().
+				Extract("/", worker.SharedFilesDir, []string{"/opt/lib/modules/5.14.0", "/firmware"}).
+				Return(errors.New("/firmware does not exist in the kmod image")),
+		)
+
+		Expect(
+			imageExtractFunc(&cobra.Command{}, []string{configPath}),
+		).To(
+			HaveOccurred(),
+		)
+
+		Expect(
+			os.ReadFile(terminationLogPath),
+		).To(
+			BeEquivalentTo("could not extract files from registry.example.com/ns/kmod:tag: /firmware does not exist in the kmod image"),
+		)
+	})
+
+	It("should extract the module tree and firmware", func() {
+		gomock.InOrder(
+			ch.EXPECT().ReadConfigFile(configPath).Return(cfg, nil),
+			mfe.EXPECT().Extract("/", worker.SharedFilesDir, []string{"/opt/lib/modules/5.14.0", "/firmware"}),
+		)
+
+		Expect(
+			imageExtractFunc(&cobra.Command{}, []string{configPath}),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(terminationLogPath).NotTo(BeAnExistingFile())
+	})
+})
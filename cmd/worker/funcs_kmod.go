@@ -1,8 +1,10 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 
+	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/utils"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/worker"
 	"github.com/spf13/cobra"
@@ -11,21 +13,54 @@ import (
 func rootFuncPreRunE(cmd *cobra.Command, args []string) error {
 	logger.Info("Starting worker", "version", Version, "git commit", GitCommit)
 
-	mr := worker.NewModprobeRunner(logger)
+	var mr worker.ModprobeRunner
+
+	if f := cmd.Flags().Lookup(worker.FlagNativeLoader); f != nil && f.Value.String() == "true" {
+		logger.Info("Using the native kernel module loader")
+		mr = worker.NewNativeModprobeRunner(logger)
+	} else {
+		mr = worker.NewModprobeRunner(logger)
+	}
+
+	fe = worker.NewFileExtractor(logger)
+	ip = worker.NewImagePuller(worker.NewMirrorResolver(logger), logger)
+
 	fsh := utils.NewFSHelper(logger)
-	w = worker.NewWorker(mr, fsh, logger)
+	w = worker.NewWorker(
+		mr,
+		fsh,
+		worker.NewModuleStateReader(),
+		worker.NewKmsgWatcher(),
+		worker.NewModuleParametersWriter(),
+		worker.NewFirmwareInstaller(logger),
+		worker.NewSignatureVerifier(logger),
+		logger,
+	)
 
 	return nil
 }
 
-func kmodLoadFunc(cmd *cobra.Command, args []string) error {
+// writeResult writes res and err to the termination log, so that they can be read from the Pod's status.
+func writeResult(res *kmmv1beta1.WorkerResult, err error) {
+	if werr := worker.WriteResult(terminationLogPath, res, err); werr != nil {
+		logger.Info(utils.WarnString("Could not write the termination message"), "error", werr)
+	}
+}
+
+func kmodLoadFunc(cmd *cobra.Command, args []string) (err error) {
+	res := &kmmv1beta1.WorkerResult{Action: worker.ActionLoad}
+
+	defer func() {
+		writeResult(res, err)
+	}()
+
 	cfgPath := args[0]
 
 	logger.Info("Reading config", "path", cfgPath)
 
 	cfg, err := configHelper.ReadConfigFile(cfgPath)
 	if err != nil {
-		return fmt.Errorf("could not read config file %s: %v", cfgPath, err)
+		return fmt.Errorf("%w: could not read config file %s: %v", worker.ErrInvalidConfig, cfgPath, err)
 	}
 
 	mountPathFlag := cmd.Flags().Lookup(worker.FlagFirmwarePath)
@@ -37,10 +72,112 @@ func kmodLoadFunc(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	return w.LoadKmod(cmd.Context(), cfg, mountPathFlag.Value.String())
+	err = w.LoadKmod(cmd.Context(), cfg, mountPathFlag.Value.String())
+	res = w.Result()
+
+	if err != nil {
+		return err
+	}
+
+	if f := cmd.Flags().Lookup(worker.FlagBlacklistPath); f != nil && f.Changed {
+		if err = w.WriteBlacklist(cfg, f.Value.String()); err != nil {
+			return fmt.Errorf("could not write the in-tree modules blacklist: %v", err)
+		}
+
+		res = w.Result()
+	}
+
+	return nil
 }
 
-func kmodUnloadFunc(cmd *cobra.Command, args []string) error {
+func kmodUnloadFunc(cmd *cobra.Command, args []string) (err error) {
+	res := &kmmv1beta1.WorkerResult{Action: worker.ActionUnload}
+
+	defer func() {
+		writeResult(res, err)
+	}()
+
+	cfgPath := args[0]
+
+	logger.Info("Reading config", "path", cfgPath)
+
+	cfg, err := configHelper.ReadConfigFile(cfgPath)
+	if err != nil {
+		return fmt.Errorf("%w: could not read config file %s: %v", worker.ErrInvalidConfig, cfgPath, err)
+	}
+
+	err = w.UnloadKmod(cmd.Context(), cfg, cmd.Flags().Lookup(worker.FlagFirmwarePath).Value.String())
+	res = w.Result()
+
+	if err != nil {
+		return err
+	}
+
+	if f := cmd.Flags().Lookup(worker.FlagBlacklistPath); f != nil && f.Changed {
+		if err = w.RemoveBlacklist(f.Value.String()); err != nil {
+			return fmt.Errorf("could not remove the in-tree modules blacklist: %v", err)
+		}
+	}
+
+	if f := cmd.Flags().Lookup(worker.FlagRestoreInTreeModules); f != nil && f.Changed {
+		modules, err := cmd.Flags().GetStringSlice(worker.FlagRestoreInTreeModules)
+		if err != nil {
+			return fmt.Errorf("could not read the in-tree modules to restore: %v", err)
+		}
+
+		// The out-of-tree module is unloaded already; failing now would not bring the in-tree modules back.
+		if rerr := w.RestoreInTreeModules(cmd.Context(), modules); rerr != nil {
+			logger.Info(utils.WarnString("Could not restore all in-tree modules"), "error", rerr)
+		}
+
+		res = w.Result()
+	}
+
+	return nil
+}
+
+func kmodSetParamsFunc(cmd *cobra.Command, args []string) (err error) {
+	res := &kmmv1beta1.WorkerResult{Action: worker.ActionSetParameters}
+
+	defer func() {
+		writeResult(res, err)
+	}()
+
+	cfgPath := args[0]
+
+	logger.Info("Reading config", "path", cfgPath)
+
+	cfg, err := configHelper.ReadConfigFile(cfgPath)
+	if err != nil {
+		return fmt.Errorf("%w: could not read config file %s: %v", worker.ErrInvalidConfig, cfgPath, err)
+	}
+
+	err = w.SetParameters(cfg)
+	res = w.Result()
+
+	return err
+}
+
+func kmodCheckFunc(cmd *cobra.Command, args []string) (err error) {
+	res := &kmmv1beta1.WorkerResult{Action: worker.ActionCheck}
+
+	defer func() {
+		writeResult(res, err)
+	}()
+
+	cfgPath := args[0]
+
+	logger.Info("Reading config", "path", cfgPath)
+
+	cfg, err := configHelper.ReadConfigFile(cfgPath)
+	if err != nil {
+		return fmt.Errorf("%w: could not read config file %s: %v", worker.ErrInvalidConfig, cfgPath, err)
+	}
+
+	return w.CheckKmod(cfg)
+}
+
+func kmodStatusFunc(cmd *cobra.Command, args []string) error {
 	cfgPath := args[0]
 
 	logger.Info("Reading config", "path", cfgPath)
@@ -50,10 +187,27 @@ func kmodUnloadFunc(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("could not read config file %s: %v", cfgPath, err)
 	}
 
-	return w.UnloadKmod(cmd.Context(), cfg, cmd.Flags().Lookup(worker.FlagFirmwarePath).Value.String())
+	status, err := w.GetKmodStatus(cfg)
+	if err != nil {
+		return fmt.Errorf("could not get the kernel module status: %v", err)
+	}
+
+	b, err := json.MarshalIndent(status, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not marshal the kernel module status: %v", err)
+	}
+
+	_, err = fmt.Fprintln(cmd.OutOrStdout(), string(b))
+
+	return err
 }
 
 func setCommandsFlags() {
+	kmodCmd.PersistentFlags().Bool(
+		worker.FlagNativeLoader,
+		false,
+		"if set, load and unload kernel modules with the finit_module and delete_module syscalls instead of the modprobe binary")
+
 	kmodLoadCmd.Flags().String(
 		worker.FlagFirmwarePath,
 		"",
@@ -63,4 +217,19 @@ func setCommandsFlags() {
 		worker.FlagFirmwarePath,
 		"",
 		"if set, this the value that firmware host path is mounted to")
+
+	kmodLoadCmd.Flags().String(
+		worker.FlagBlacklistPath,
+		"",
+		"if set, the in-tree modules to remove are blacklisted in this modprobe configuration file after loading the module")
+
+	kmodUnloadCmd.Flags().String(
+		worker.FlagBlacklistPath,
+		"",
+		"if set, this modprobe configuration file is removed after unloading the module")
+
+	kmodUnloadCmd.Flags().StringSlice(
+		worker.FlagRestoreInTreeModules,
+		nil,
+		"in-tree modules to load again from the host's modules tree after unloading the module")
 }
@@ -1,8 +1,12 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -27,11 +31,13 @@ var _ = Describe("kmodLoadFunc", func() {
 		configHelper = ch
 		wo = worker.NewMockWorker(ctrl)
 		w = wo
+		terminationLogPath = filepath.Join(GinkgoT().TempDir(), "termination-log")
 	})
 
 	AfterEach(func() {
 		configHelper = worker.NewConfigHelper()
 		w = nil
+		terminationLogPath = worker.TerminationLogPath
 	})
 
 	It("should return an error if we cannot read the config", func() {
@@ -43,6 +49,12 @@ var _ = Describe("kmodLoadFunc", func() {
 		).To(
 			HaveOccurred(),
 		)
+
+		Expect(
+			os.ReadFile(terminationLogPath),
+		).To(
+			MatchJSON(`{"action":"load","exitCode":1,"errorCategory":"InvalidConfig","error":"invalid worker configuration: could not read config file /some/path: some error"}`),
+		)
 	})
 
 	DescribeTable(
@@ -65,11 +77,13 @@ var _ = Describe("kmodLoadFunc", func() {
 					ch.EXPECT().ReadConfigFile(configPath).Return(cfg, nil),
 					wo.EXPECT().SetFirmwareClassPath(*flagFirmwarePath),
 					wo.EXPECT().LoadKmod(ctx, cfg, *flagFirmwarePath),
+					wo.EXPECT().Result().Return(&kmmv1beta1.WorkerResult{Action: worker.ActionLoad}),
 				)
 			} else {
 				gomock.InOrder(
 					ch.EXPECT().ReadConfigFile(configPath).Return(cfg, nil),
 					wo.EXPECT().LoadKmod(ctx, cfg, ""),
+					wo.EXPECT().Result().Return(&kmmv1beta1.WorkerResult{Action: worker.ActionLoad}),
 				)
 			}
 
@@ -78,9 +92,335 @@ var _ = Describe("kmodLoadFunc", func() {
 			).NotTo(
 				HaveOccurred(),
 			)
+
+			Expect(os.ReadFile(terminationLogPath)).To(MatchJSON(`{"action":"load"}`))
 		},
 		Entry("fimrwarePath not defined", nil),
 		Entry("fimrwarePath path defined and empty", ptr.To("")),
 		Entry("firmwarePath defined", ptr.To("/some/path")),
 	)
+
+	It("should write the blacklist after loading the module if its path is defined", func() {
+		const blacklistPath = "/var/run/kmm/modprobe.d/kmm-ns-name.conf"
+
+		cfg := &kmmv1beta1.ModuleConfig{}
+		ctx := context.TODO()
+
+		cmd := &cobra.Command{}
+		cmd.SetContext(ctx)
+		cmd.Flags().String(worker.FlagFirmwarePath, "", "")
+		cmd.Flags().String(worker.FlagBlacklistPath, "", "")
+		Expect(cmd.Flags().Set(worker.FlagBlacklistPath, blacklistPath)).To(Succeed())
+
+		gomock.InOrder(
+			ch.EXPECT().ReadConfigFile(configPath).Return(cfg, nil),
+			wo.EXPECT().LoadKmod(ctx, cfg, ""),
+			wo.EXPECT().Result().Return(&kmmv1beta1.WorkerResult{Action: worker.ActionLoad}),
+			wo.EXPECT().WriteBlacklist(cfg, blacklistPath),
+			wo.EXPECT().Result().Return(&kmmv1beta1.WorkerResult{Action: worker.ActionLoad, BlacklistedModules: []string{"intree"}}),
+		)
+
+		Expect(
+			kmodLoadFunc(cmd, []string{configPath}),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(os.ReadFile(terminationLogPath)).To(MatchJSON(`{"action":"load","blacklistedModules":["intree"]}`))
+	})
+})
+
+var _ = Describe("kmodUnloadFunc", func() {
+	const configPath = "/some/path"
+
+	var (
+		ch *worker.MockConfigHelper
+		wo *worker.MockWorker
+	)
+
+	BeforeEach(func() {
+		ctrl := gomock.NewController(GinkgoT())
+		ch = worker.NewMockConfigHelper(ctrl)
+		configHelper = ch
+		wo = worker.NewMockWorker(ctrl)
+		w = wo
+		terminationLogPath = filepath.Join(GinkgoT().TempDir(), "termination-log")
+	})
+
+	AfterEach(func() {
+		configHelper = worker.NewConfigHelper()
+		w = nil
+		terminationLogPath = worker.TerminationLogPath
+	})
+
+	It("should write the result of the worker to the termination log", func() {
+		cfg := &kmmv1beta1.ModuleConfig{}
+		ctx := context.TODO()
+
+		cmd := &cobra.Command{}
+		cmd.SetContext(ctx)
+		cmd.Flags().String(worker.FlagFirmwarePath, "", "")
+
+		res := &kmmv1beta1.WorkerResult{
+			Action:       worker.ActionUnload,
+			ModprobeArgs: []string{"-rvd", "/tmp/opt", "kmm_test"},
+			ExitCode:     1,
+			Stderr:       []string{"modprobe: FATAL: Module kmm_test is in use."},
+		}
+
+		gomock.InOrder(
+			ch.EXPECT().ReadConfigFile(configPath).Return(cfg, nil),
+			wo.EXPECT().UnloadKmod(ctx, cfg, "").Return(fmt.Errorf("could not unload: %w", worker.ErrModuleInUse)),
+			wo.EXPECT().Result().Return(res),
+		)
+
+		Expect(
+			kmodUnloadFunc(cmd, []string{configPath}),
+		).To(
+			MatchError(worker.ErrModuleInUse),
+		)
+
+		Expect(
+			os.ReadFile(terminationLogPath),
+		).To(
+			MatchJSON(`{
+				"action": "unload",
+				"modprobeArgs": ["-rvd", "/tmp/opt", "kmm_test"],
+				"exitCode": 1,
+				"stderr": ["modprobe: FATAL: Module kmm_test is in use."],
+				"errorCategory": "ModuleInUse",
+				"error": "could not unload: module is in use"
+			}`),
+		)
+	})
+
+	It("should remove the blacklist after unloading the module if its path is defined", func() {
+		const blacklistPath = "/var/run/kmm/modprobe.d/kmm-ns-name.conf"
+
+		cfg := &kmmv1beta1.ModuleConfig{}
+		ctx := context.TODO()
+
+		cmd := &cobra.Command{}
+		cmd.SetContext(ctx)
+		cmd.Flags().String(worker.FlagFirmwarePath, "", "")
+		cmd.Flags().String(worker.FlagBlacklistPath, "", "")
+		Expect(cmd.Flags().Set(worker.FlagBlacklistPath, blacklistPath)).To(Succeed())
+
+		gomock.InOrder(
+			ch.EXPECT().ReadConfigFile(configPath).Return(cfg, nil),
+			wo.EXPECT().UnloadKmod(ctx, cfg, ""),
+			wo.EXPECT().Result().Return(&kmmv1beta1.WorkerResult{Action: worker.ActionUnload}),
+			wo.EXPECT().RemoveBlacklist(blacklistPath),
+		)
+
+		Expect(
+			kmodUnloadFunc(cmd, []string{configPath}),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+	It("should restore the in-tree modules after unloading the module, even if that fails", func() {
+		cfg := &kmmv1beta1.ModuleConfig{}
+		ctx := context.TODO()
+
+		cmd := &cobra.Command{}
+		cmd.SetContext(ctx)
+		cmd.Flags().String(worker.FlagFirmwarePath, "", "")
+		cmd.Flags().StringSlice(worker.FlagRestoreInTreeModules, nil, "")
+		Expect(cmd.Flags().Set(worker.FlagRestoreInTreeModules, "intree1,intree2")).To(Succeed())
+
+		gomock.InOrder(
+			ch.EXPECT().ReadConfigFile(configPath).Return(cfg, nil),
+			wo.EXPECT().UnloadKmod(ctx, cfg, ""),
+			wo.EXPECT().Result().Return(&kmmv1beta1.WorkerResult{Action: worker.ActionUnload}),
+			wo.EXPECT().RestoreInTreeModules(ctx, []string{"intree1", "intree2"}).Return(errors.New("random error")),
+			wo.EXPECT().Result().Return(&kmmv1beta1.WorkerResult{
+				Action:                    worker.ActionUnload,
+				RestoredInTreeModules:     []string{"intree2"},
+				InTreeModulesRestoreError: "random error",
+			}),
+		)
+
+		Expect(
+			kmodUnloadFunc(cmd, []string{configPath}),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(os.ReadFile(terminationLogPath)).To(
+			MatchJSON(`{"action":"unload","restoredInTreeModules":["intree2"],"inTreeModulesRestoreError":"random error"}`),
+		)
+	})
+})
+
+var _ = Describe("kmodSetParamsFunc", func() {
+	const configPath = "/some/path"
+
+	var (
+		ch *worker.MockConfigHelper
+		wo *worker.MockWorker
+	)
+
+	BeforeEach(func() {
+		ctrl := gomock.NewController(GinkgoT())
+		ch = worker.NewMockConfigHelper(ctrl)
+		configHelper = ch
+		wo = worker.NewMockWorker(ctrl)
+		w = wo
+		terminationLogPath = filepath.Join(GinkgoT().TempDir(), "termination-log")
+	})
+
+	AfterEach(func() {
+		configHelper = worker.NewConfigHelper()
+		w = nil
+		terminationLogPath = worker.TerminationLogPath
+	})
+
+	It("should update the parameters and write the result", func() {
+		cfg := &kmmv1beta1.ModuleConfig{}
+
+		gomock.InOrder(
+			ch.EXPECT().ReadConfigFile(configPath).Return(cfg, nil),
+			wo.EXPECT().SetParameters(cfg).Return(fmt.Errorf("some error: %w", worker.ErrParameterReadOnly)),
+			wo.EXPECT().Result().Return(&kmmv1beta1.WorkerResult{Action: worker.ActionSetParameters}),
+		)
+
+		Expect(
+			kmodSetParamsFunc(&cobra.Command{}, []string{configPath}),
+		).To(
+			MatchError(worker.ErrParameterReadOnly),
+		)
+
+		Expect(
+			os.ReadFile(terminationLogPath),
+		).To(
+			MatchJSON(`{
+				"action": "setParameters",
+				"exitCode": 1,
+				"errorCategory": "ParameterReadOnly",
+				"error": "some error: module parameter is not writable at runtime"
+			}`),
+		)
+	})
+})
+
+var _ = Describe("kmodCheckFunc", func() {
+	const configPath = "/some/path"
+
+	var (
+		ch *worker.MockConfigHelper
+		wo *worker.MockWorker
+	)
+
+	BeforeEach(func() {
+		ctrl := gomock.NewController(GinkgoT())
+		ch = worker.NewMockConfigHelper(ctrl)
+		configHelper = ch
+		wo = worker.NewMockWorker(ctrl)
+		w = wo
+		terminationLogPath = filepath.Join(GinkgoT().TempDir(), "termination-log")
+	})
+
+	AfterEach(func() {
+		configHelper = worker.NewConfigHelper()
+		w = nil
+		terminationLogPath = worker.TerminationLogPath
+	})
+
+	It("should write the result of the check to the termination log", func() {
+		cfg := &kmmv1beta1.ModuleConfig{}
+
+		gomock.InOrder(
+			ch.EXPECT().ReadConfigFile(configPath).Return(cfg, nil),
+			wo.EXPECT().CheckKmod(cfg).Return(fmt.Errorf("%w: module test is not present in /proc/modules", worker.ErrModuleNotLoaded)),
+		)
+
+		Expect(
+			kmodCheckFunc(&cobra.Command{}, []string{configPath}),
+		).To(
+			MatchError(worker.ErrModuleNotLoaded),
+		)
+
+		Expect(
+			os.ReadFile(terminationLogPath),
+		).To(
+			MatchJSON(`{
+				"action": "check",
+				"exitCode": 1,
+				"errorCategory": "ModuleNotLoaded",
+				"error": "module is not loaded: module test is not present in /proc/modules"
+			}`),
+		)
+	})
+})
+
+var _ = Describe("kmodStatusFunc", func() {
+	const configPath = "/some/path"
+
+	var (
+		ch *worker.MockConfigHelper
+		wo *worker.MockWorker
+	)
+
+	BeforeEach(func() {
+		ctrl := gomock.NewController(GinkgoT())
+		ch = worker.NewMockConfigHelper(ctrl)
+		configHelper = ch
+		wo = worker.NewMockWorker(ctrl)
+		w = wo
+	})
+
+	AfterEach(func() {
+		configHelper = worker.NewConfigHelper()
+		w = nil
+	})
+
+	It("should return an error if we cannot get the status", func() {
+		cfg := &kmmv1beta1.ModuleConfig{}
+
+		gomock.InOrder(
+			ch.EXPECT().ReadConfigFile(configPath).Return(cfg, nil),
+			wo.EXPECT().GetKmodStatus(cfg).Return(nil, errors.New("some error")),
+		)
+
+		Expect(
+			kmodStatusFunc(&cobra.Command{}, []string{configPath}),
+		).To(
+			HaveOccurred(),
+		)
+	})
+
+	It("should print the status as JSON", func() {
+		cfg := &kmmv1beta1.ModuleConfig{}
+
+		status := &worker.KmodStatus{
+			ModuleState: worker.ModuleState{
+				Name:       "test",
+				Loaded:     true,
+				InitState:  "live",
+				RefCount:   1,
+				SrcVersion: "abc",
+			},
+			ExpectedSrcVersion: "abc",
+		}
+
+		gomock.InOrder(
+			ch.EXPECT().ReadConfigFile(configPath).Return(cfg, nil),
+			wo.EXPECT().GetKmodStatus(cfg).Return(status, nil),
+		)
+
+		buf := bytes.Buffer{}
+
+		cmd := &cobra.Command{}
+		cmd.SetOut(&buf)
+
+		Expect(
+			kmodStatusFunc(cmd, []string{configPath}),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(buf.String()).To(MatchJSON(`{"name":"test","loaded":true,"initState":"live","refCount":1,"srcVersion":"abc","expectedSrcVersion":"abc"}`))
+	})
 })
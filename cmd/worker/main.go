@@ -18,9 +18,15 @@ var (
 	GitCommit = "undefined"
 	Version   = "undefined"
 
-	configHelper = worker.NewConfigHelper()
-	logger       logr.Logger
-	w            worker.Worker
+	configHelper         = worker.NewConfigHelper()
+	fe                   worker.FileExtractor
+	globalPullSecretPath = worker.GlobalPullSecretPath
+	imageRootDir         = "/"
+	ip                   worker.ImagePuller
+	logger               logr.Logger
+	pullSecretsDir       = worker.PullSecretsDir
+	terminationLogPath   = worker.TerminationLogPath
+	w                    worker.Worker
 )
 
 var rootCmd = &cobra.Command{
@@ -32,11 +38,44 @@ var rootCmd = &cobra.Command{
 	PersistentPreRunE: rootFuncPreRunE,
 }
 
+var imageCmd = &cobra.Command{
+	Use:   "image",
+	Short: "Manage kmod images",
+}
+
+var imagePullCmd = &cobra.Command{
+	Use:   "pull",
+	Short: "Pull a kmod image and extract the files needed by the worker",
+	Args:  cobra.ExactArgs(1),
+	RunE:  imagePullFunc,
+}
+
+var imageCopyBinaryCmd = &cobra.Command{
+	Use:   "copy-binary",
+	Short: "Copy the worker binary to a path, so that it can run image extract from the kmod image",
+	Args:  cobra.ExactArgs(1),
+	RunE:  imageCopyBinaryFunc,
+}
+
+var imageExtractCmd = &cobra.Command{
+	Use:   "extract",
+	Short: "Copy the files needed by the worker out of the kmod image the command runs in",
+	Args:  cobra.ExactArgs(1),
+	RunE:  imageExtractFunc,
+}
+
 var kmodCmd = &cobra.Command{
 	Use:   "kmod",
 	Short: "Manage kernel modules",
 }
 
+var kmodCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check that a kernel module is still loaded",
+	Args:  cobra.ExactArgs(1),
+	RunE:  kmodCheckFunc,
+}
+
 var kmodLoadCmd = &cobra.Command{
 	Use:   "load",
 	Short: "Load a kernel module",
@@ -51,13 +90,28 @@ var kmodUnloadCmd = &cobra.Command{
 	RunE:  kmodUnloadFunc,
 }
 
+var kmodSetParamsCmd = &cobra.Command{
+	Use:   "set-params",
+	Short: "Update the parameters of a loaded kernel module through sysfs",
+	Args:  cobra.ExactArgs(1),
+	RunE:  kmodSetParamsFunc,
+}
+
+var kmodStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Print the state of a kernel module as JSON",
+	Args:  cobra.ExactArgs(1),
+	RunE:  kmodStatusFunc,
+}
+
 func main() {
 	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGTERM)
 	defer cancel()
 
-	rootCmd.AddCommand(kmodCmd)
+	rootCmd.AddCommand(imageCmd, kmodCmd)
 
-	kmodCmd.AddCommand(kmodLoadCmd, kmodUnloadCmd)
+	imageCmd.AddCommand(imageCopyBinaryCmd, imageExtractCmd, imagePullCmd)
+	kmodCmd.AddCommand(kmodCheckCmd, kmodLoadCmd, kmodSetParamsCmd, kmodStatusCmd, kmodUnloadCmd)
 
 	setCommandsFlags()
 
@@ -17,6 +17,9 @@ type ModuleLoaderData struct {
 	// kernel version
 	KernelVersion string
 
+	// KernelMappingIndex is the index of the kernel mapping used for KernelVersion.
+	KernelMappingIndex int
+
 	// KernelNormalizedVersion is the kernel version with some characters replaced with '_' so that it can be used in
 	// a Kubernetes label or a container image tag.
 	KernelNormalizedVersion string
@@ -60,12 +63,24 @@ type ModuleLoaderData struct {
 	// InTreeModulesToRemove - in case array not empty, remove the modules prior to loading the module specified in moduleName
 	InTreeModulesToRemove []string
 
+	// BlacklistInTreeModules - if true, the worker blacklists InTreeModulesToRemove on the host
+	BlacklistInTreeModules bool
+
+	// RestoreInTreeModulesOnUnload - if true, the worker loads the removed InTreeModulesToRemove again when unloading the module
+	RestoreInTreeModulesOnUnload bool
+
 	// used for setting the owner field of jobs/buildconfigs
 	Owner metav1.Object
 
 	// If specified, the pod's tolerations.
 	// +optional
 	Tolerations []v1.Toleration `json:"tolerations,omitempty"`
+
+	// Drain describes the Pods to evict from a node before reloading the module on it.
+	Drain *kmmv1beta1.DrainSpec
+
+	// MaintenanceWindow restricts the reloads of the module on a node to the periods during which the window is open.
+	MaintenanceWindow *kmmv1beta1.MaintenanceWindow
 }
 
 func (mld *ModuleLoaderData) NamespacedName() types.NamespacedName {
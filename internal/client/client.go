@@ -2,4 +2,4 @@ package client
 
 import _ "go.uber.org/mock/mockgen/model"
 
-//go:generate mockgen -package=client -destination mock_client.go sigs.k8s.io/controller-runtime/pkg/client Client,StatusWriter
+//go:generate mockgen -package=client -destination mock_client.go sigs.k8s.io/controller-runtime/pkg/client Client,StatusWriter,SubResourceClient
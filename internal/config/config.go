@@ -2,6 +2,7 @@ package config
 
 import (
 	"crypto/tls"
+	"encoding/json"
 	"fmt"
 	"os"
 	"time"
@@ -9,6 +10,7 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/http"
 	"gopkg.in/yaml.v3"
+	v1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/server"
@@ -24,10 +26,77 @@ type Webhook struct {
 	Port         int  `yaml:"port"`
 }
 
+// DriftDetection configures the periodic checks of the modules that KMM loaded on the nodes.
+type DriftDetection struct {
+	// Interval is the time between two checks of a module on a node; checks are disabled if it is zero.
+	Interval time.Duration `yaml:"interval,omitempty"`
+	// Reload makes KMM load again the modules that are found unloaded.
+	Reload bool `yaml:"reload,omitempty"`
+}
+
 type Worker struct {
-	RunAsUser        *int64  `yaml:"runAsUser"`
-	SELinuxType      string  `yaml:"seLinuxType"`
-	FirmwareHostPath *string `yaml:"firmwareHostPath,omitempty"`
+	RunAsUser                *int64            `yaml:"runAsUser"`
+	SELinuxType              string            `yaml:"seLinuxType"`
+	FirmwareHostPath         *string           `yaml:"firmwareHostPath,omitempty"`
+	PullImages               bool              `yaml:"pullImages,omitempty"`
+	GlobalPullSecretHostPath *string           `yaml:"globalPullSecretHostPath,omitempty"`
+	PriorityClassName        string            `yaml:"priorityClassName,omitempty"`
+	Annotations              map[string]string `yaml:"annotations,omitempty"`
+	Labels                   map[string]string `yaml:"labels,omitempty"`
+	DriftDetection           DriftDetection    `yaml:"driftDetection,omitempty"`
+
+	// The fields below hold Kubernetes API types, that are decoded from their JSON representation by UnmarshalYAML.
+
+	Resources      *v1.ResourceRequirements `yaml:"-"`
+	Tolerations    []v1.Toleration          `yaml:"-"`
+	SeccompProfile *v1.SeccompProfile       `yaml:"-"`
+}
+
+// UnmarshalYAML decodes the Worker fields that hold Kubernetes API types through their JSON representation, so that
+// their usual field names and resource quantities can be used in the configuration file.
+func (w *Worker) UnmarshalYAML(node *yaml.Node) error {
+	type plainWorker Worker
+
+	if err := node.Decode((*plainWorker)(w)); err != nil {
+		return err
+	}
+
+	raw := struct {
+		Resources      any `yaml:"resources"`
+		Tolerations    any `yaml:"tolerations"`
+		SeccompProfile any `yaml:"seccompProfile"`
+	}{}
+
+	if err := node.Decode(&raw); err != nil {
+		return err
+	}
+
+	if err := decodeAPIValue(raw.Resources, &w.Resources); err != nil {
+		return fmt.Errorf("invalid worker resources: %v", err)
+	}
+
+	if err := decodeAPIValue(raw.Tolerations, &w.Tolerations); err != nil {
+		return fmt.Errorf("invalid worker tolerations: %v", err)
+	}
+
+	if err := decodeAPIValue(raw.SeccompProfile, &w.SeccompProfile); err != nil {
+		return fmt.Errorf("invalid worker seccomp profile: %v", err)
+	}
+
+	return nil
+}
+
+func decodeAPIValue(in any, out any) error {
+	if in == nil {
+		return nil
+	}
+
+	b, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, out)
 }
 
 type LeaderElection struct {
@@ -1,10 +1,14 @@
 package config
 
 import (
+	"os"
+	"path/filepath"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 )
@@ -15,6 +19,19 @@ var _ = Describe("ParseFile", func() {
 		Expect(err).To(HaveOccurred())
 	})
 
+	It("should return an error if the worker resources are invalid", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "config.yaml")
+
+		Expect(
+			os.WriteFile(path, []byte("worker:\n  resources:\n    limits:\n      cpu: not-a-quantity\n"), 0600),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		_, err := ParseFile(path)
+		Expect(err).To(HaveOccurred())
+	})
+
 	It("should parse the file correctly", func() {
 		expected := &Config{
 			HealthProbeBindAddress: ":8081",
@@ -36,9 +53,31 @@ var _ = Describe("ParseFile", func() {
 				SecureServing:    true,
 			},
 			Worker: Worker{
-				RunAsUser:        ptr.To[int64](1234),
-				SELinuxType:      "mySELinuxType",
-				FirmwareHostPath: ptr.To("/some/path"),
+				RunAsUser:                ptr.To[int64](1234),
+				SELinuxType:              "mySELinuxType",
+				FirmwareHostPath:         ptr.To("/some/path"),
+				PullImages:               true,
+				GlobalPullSecretHostPath: ptr.To("/var/lib/kubelet/config.json"),
+				PriorityClassName:        "system-node-critical",
+				Annotations:              map[string]string{"some-annotation": "some-value"},
+				Labels:                   map[string]string{"some-label": "some-value"},
+				DriftDetection: DriftDetection{
+					Interval: 10 * time.Minute,
+					Reload:   true,
+				},
+				Resources: &v1.ResourceRequirements{
+					Requests: v1.ResourceList{
+						v1.ResourceCPU:    resource.MustParse("250m"),
+						v1.ResourceMemory: resource.MustParse("128Mi"),
+					},
+					Limits: v1.ResourceList{
+						v1.ResourceMemory: resource.MustParse("512Mi"),
+					},
+				},
+				Tolerations: []v1.Toleration{
+					{Key: "some-key", Operator: v1.TolerationOpExists, Effect: v1.TaintEffectNoSchedule},
+				},
+				SeccompProfile: &v1.SeccompProfile{Type: v1.SeccompProfileTypeRuntimeDefault},
 			},
 		}
 
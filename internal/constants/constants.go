@@ -11,6 +11,10 @@ const (
 	BuildTypeLabel       = "kmm.openshift.io/build.type"
 	NamespaceLabelKey    = "kmm.node.k8s.io/contains-modules"
 
+	// NodeCordonedForModulesAnnotation lists the modules for which KMM cordoned a node, as comma-separated
+	// namespace/name pairs.
+	NodeCordonedForModulesAnnotation = "kmm.node.kubernetes.io/cordoned-for-modules"
+
 	WorkerPodVersionLabelPrefix    = "beta.kmm.node.kubernetes.io/version-worker-pod"
 	DevicePluginVersionLabelPrefix = "beta.kmm.node.kubernetes.io/version-device-plugin"
 	ModuleVersionLabelPrefix       = "kmm.node.kubernetes.io/version-module"
@@ -3,7 +3,7 @@
 //
 // Generated by this command:
 //
-//	mockgen -source=module_nmc_reconciler.go -package=controllers -destination=mock_module_nmc_reconciler.go moduleNMCReconcilerHelperAPI,namespaceLabeler
+//	mockgen -source=module_nmc_reconciler.go -package=controllers -destination=mock_module_nmc_reconciler.go
 //
 // Package controllers is a generated GoMock package.
 package controllers
@@ -11,6 +11,7 @@ package controllers
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	v1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
 	api "github.com/rh-ecosystem-edge/kernel-module-management/internal/api"
@@ -42,6 +43,21 @@ func (m *MockmoduleNMCReconcilerHelperAPI) EXPECT() *MockmoduleNMCReconcilerHelp
 	return m.recorder
 }
 
+// applyRolloutStrategy mocks base method.
+func (m *MockmoduleNMCReconcilerHelperAPI) applyRolloutStrategy(ctx context.Context, mod *v1beta1.Module, sdMap map[string]schedulingData) (time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "applyRolloutStrategy", ctx, mod, sdMap)
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// applyRolloutStrategy indicates an expected call of applyRolloutStrategy.
+func (mr *MockmoduleNMCReconcilerHelperAPIMockRecorder) applyRolloutStrategy(ctx, mod, sdMap any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "applyRolloutStrategy", reflect.TypeOf((*MockmoduleNMCReconcilerHelperAPI)(nil).applyRolloutStrategy), ctx, mod, sdMap)
+}
+
 // disableModuleOnNode mocks base method.
 func (m *MockmoduleNMCReconcilerHelperAPI) disableModuleOnNode(ctx context.Context, modNamespace, modName, nodeName string) error {
 	m.ctrl.T.Helper()
@@ -3,7 +3,7 @@
 //
 // Generated by this command:
 //
-//	mockgen -source=nmc_reconciler.go -package=controllers -destination=mock_nmc_reconciler.go podManager
+//	mockgen -source=nmc_reconciler.go -package=controllers -destination=mock_nmc_reconciler.go
 //
 // Package controllers is a generated GoMock package.
 package controllers
@@ -112,17 +112,17 @@ func (mr *MocknmcReconcilerHelperMockRecorder) RemovePodFinalizers(ctx, nodeName
 }
 
 // SyncStatus mocks base method.
-func (m *MocknmcReconcilerHelper) SyncStatus(ctx context.Context, nmc *v1beta1.NodeModulesConfig) error {
+func (m *MocknmcReconcilerHelper) SyncStatus(ctx context.Context, nmc *v1beta1.NodeModulesConfig, node *v1.Node) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SyncStatus", ctx, nmc)
+	ret := m.ctrl.Call(m, "SyncStatus", ctx, nmc, node)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SyncStatus indicates an expected call of SyncStatus.
-func (mr *MocknmcReconcilerHelperMockRecorder) SyncStatus(ctx, nmc any) *gomock.Call {
+func (mr *MocknmcReconcilerHelperMockRecorder) SyncStatus(ctx, nmc, node any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncStatus", reflect.TypeOf((*MocknmcReconcilerHelper)(nil).SyncStatus), ctx, nmc)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncStatus", reflect.TypeOf((*MocknmcReconcilerHelper)(nil).SyncStatus), ctx, nmc, node)
 }
 
 // UpdateNodeLabels mocks base method.
@@ -271,6 +271,20 @@ func (m *MockpodManager) EXPECT() *MockpodManagerMockRecorder {
 	return m.recorder
 }
 
+// CreateDriftCheckPod mocks base method.
+func (m *MockpodManager) CreateDriftCheckPod(ctx context.Context, nmc client.Object, nms *v1beta1.NodeModuleSpec) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDriftCheckPod", ctx, nmc, nms)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateDriftCheckPod indicates an expected call of CreateDriftCheckPod.
+func (mr *MockpodManagerMockRecorder) CreateDriftCheckPod(ctx, nmc, nms any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDriftCheckPod", reflect.TypeOf((*MockpodManager)(nil).CreateDriftCheckPod), ctx, nmc, nms)
+}
+
 // CreateLoaderPod mocks base method.
 func (m *MockpodManager) CreateLoaderPod(ctx context.Context, nmc client.Object, nms *v1beta1.NodeModuleSpec) error {
 	m.ctrl.T.Helper()
@@ -285,6 +299,20 @@ func (mr *MockpodManagerMockRecorder) CreateLoaderPod(ctx, nmc, nms any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLoaderPod", reflect.TypeOf((*MockpodManager)(nil).CreateLoaderPod), ctx, nmc, nms)
 }
 
+// CreateParametersUpdaterPod mocks base method.
+func (m *MockpodManager) CreateParametersUpdaterPod(ctx context.Context, nmc client.Object, nms *v1beta1.NodeModuleSpec) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateParametersUpdaterPod", ctx, nmc, nms)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateParametersUpdaterPod indicates an expected call of CreateParametersUpdaterPod.
+func (mr *MockpodManagerMockRecorder) CreateParametersUpdaterPod(ctx, nmc, nms any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateParametersUpdaterPod", reflect.TypeOf((*MockpodManager)(nil).CreateParametersUpdaterPod), ctx, nmc, nms)
+}
+
 // CreateUnloaderPod mocks base method.
 func (m *MockpodManager) CreateUnloaderPod(ctx context.Context, nmc client.Object, nms *v1beta1.NodeModuleStatus) error {
 	m.ctrl.T.Helper()
@@ -313,6 +341,21 @@ func (mr *MockpodManagerMockRecorder) DeletePod(ctx, pod any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePod", reflect.TypeOf((*MockpodManager)(nil).DeletePod), ctx, pod)
 }
 
+// DriftCheckPodTemplate mocks base method.
+func (m *MockpodManager) DriftCheckPodTemplate(ctx context.Context, nmc client.Object, nms *v1beta1.NodeModuleSpec) (*v1.Pod, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DriftCheckPodTemplate", ctx, nmc, nms)
+	ret0, _ := ret[0].(*v1.Pod)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DriftCheckPodTemplate indicates an expected call of DriftCheckPodTemplate.
+func (mr *MockpodManagerMockRecorder) DriftCheckPodTemplate(ctx, nmc, nms any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DriftCheckPodTemplate", reflect.TypeOf((*MockpodManager)(nil).DriftCheckPodTemplate), ctx, nmc, nms)
+}
+
 // GetWorkerPod mocks base method.
 func (m *MockpodManager) GetWorkerPod(ctx context.Context, podName, namespace string) (*v1.Pod, error) {
 	m.ctrl.T.Helper()
@@ -358,6 +401,21 @@ func (mr *MockpodManagerMockRecorder) LoaderPodTemplate(ctx, nmc, nms any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoaderPodTemplate", reflect.TypeOf((*MockpodManager)(nil).LoaderPodTemplate), ctx, nmc, nms)
 }
 
+// ParametersUpdaterPodTemplate mocks base method.
+func (m *MockpodManager) ParametersUpdaterPodTemplate(ctx context.Context, nmc client.Object, nms *v1beta1.NodeModuleSpec) (*v1.Pod, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ParametersUpdaterPodTemplate", ctx, nmc, nms)
+	ret0, _ := ret[0].(*v1.Pod)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ParametersUpdaterPodTemplate indicates an expected call of ParametersUpdaterPodTemplate.
+func (mr *MockpodManagerMockRecorder) ParametersUpdaterPodTemplate(ctx, nmc, nms any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParametersUpdaterPodTemplate", reflect.TypeOf((*MockpodManager)(nil).ParametersUpdaterPodTemplate), ctx, nmc, nms)
+}
+
 // UnloaderPodTemplate mocks base method.
 func (m *MockpodManager) UnloaderPodTemplate(ctx context.Context, nmc client.Object, nms *v1beta1.NodeModuleStatus) (*v1.Pod, error) {
 	m.ctrl.T.Helper()
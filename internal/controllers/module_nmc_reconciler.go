@@ -1,11 +1,14 @@
 package controllers
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
 	"reflect"
+	"slices"
 	"strings"
+	"time"
 
 	buildv1 "github.com/openshift/api/build/v1"
 	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
@@ -13,6 +16,7 @@ import (
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/auth"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/constants"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/filter"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/maintenance"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/meta"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/module"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/nmc"
@@ -20,10 +24,13 @@ import (
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/registry"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/utils"
 	v1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -126,6 +133,11 @@ func (mnr *ModuleNMCReconciler) Reconcile(ctx context.Context, mod *kmmv1beta1.M
 	errs := make([]error, 0, len(sdMap)+1)
 	errs = append(errs, prepareErrs...)
 
+	requeueAfter, err := mnr.reconHelper.applyRolloutStrategy(ctx, mod, sdMap)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to apply the rollout strategy of Module %s/%s: %v", mod.Namespace, mod.Name, err)
+	}
+
 	for nodeName, sd := range sdMap {
 		if sd.action == actionAdd {
 			err = mnr.reconHelper.enableModuleOnNode(ctx, sd.mld, sd.node)
@@ -144,7 +156,7 @@ func (mnr *ModuleNMCReconciler) Reconcile(ctx context.Context, mod *kmmv1beta1.M
 	if err != nil {
 		return ctrl.Result{}, fmt.Errorf("failed to reconcile module %s/%s config: %v", mod.Namespace, mod.Name, err)
 	}
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
 
 func (mnr *ModuleNMCReconciler) SetupWithManager(mgr ctrl.Manager, watchBuilds bool) error {
@@ -180,6 +192,7 @@ type moduleNMCReconcilerHelperAPI interface {
 	finalizeModule(ctx context.Context, mod *kmmv1beta1.Module) error
 	getNMCsByModuleSet(ctx context.Context, mod *kmmv1beta1.Module) (sets.Set[string], error)
 	prepareSchedulingData(ctx context.Context, mod *kmmv1beta1.Module, targetedNodes []v1.Node, currentNMCs sets.Set[string]) (map[string]schedulingData, []error)
+	applyRolloutStrategy(ctx context.Context, mod *kmmv1beta1.Module, sdMap map[string]schedulingData) (time.Duration, error)
 	enableModuleOnNode(ctx context.Context, mld *api.ModuleLoaderData, node *v1.Node) error
 	disableModuleOnNode(ctx context.Context, modNamespace, modName, nodeName string) error
 	moduleUpdateWorkerPodsStatus(ctx context.Context, mod *kmmv1beta1.Module, targetedNodes []v1.Node) error
@@ -345,6 +358,247 @@ func (mnrh *moduleNMCReconcilerHelper) prepareSchedulingData(ctx context.Context
 	return result, errs
 }
 
+// applyRolloutStrategy removes from sdMap the nodes that have to wait before being moved to the Module's current
+// configuration, according to its rollout strategy and maintenance window, and reports the progress of the rollout in
+// the Module's conditions.
+// A node is moving to the configuration until it is loaded; if it fails to load, no other node is moved.
+// Only the nodes on which the module is loaded with the same kernel are gated; new nodes and nodes that booted another
+// kernel do not run the module, so they are moved immediately.
+// Without rollout strategy, all nodes are moved at once.
+// While the maintenance window is closed, the nodes on which moving would reload the module wait; applyRolloutStrategy
+// then returns the delay after which the window opens.
+func (mnrh *moduleNMCReconcilerHelper) applyRolloutStrategy(ctx context.Context, mod *kmmv1beta1.Module, sdMap map[string]schedulingData) (time.Duration, error) {
+	logger := log.FromContext(ctx)
+
+	nmcList, err := mnrh.getNMCsForModule(ctx, mod)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get configured NMCs for module %s/%s: %v", mod.Namespace, mod.Name, err)
+	}
+
+	now := time.Now()
+
+	windowOpen, windowOpensAt, err := maintenance.IsOpen(mod.Spec.MaintenanceWindow, now)
+	if err != nil {
+		return 0, fmt.Errorf("invalid maintenance window: %v", err)
+	}
+
+	nmcByName := make(map[string]*kmmv1beta1.NodeModulesConfig, len(nmcList))
+
+	for i := range nmcList {
+		nmcByName[nmcList[i].Name] = &nmcList[i]
+	}
+
+	var (
+		total                                     int
+		moving, pending, inProgress, failed, held []string
+	)
+
+	for nodeName, sd := range sdMap {
+		if sd.action != actionAdd {
+			continue
+		}
+
+		total++
+
+		var (
+			spec     *kmmv1beta1.NodeModuleSpec
+			status   *kmmv1beta1.NodeModuleStatus
+			progress *kmmv1beta1.NodeModuleProgress
+		)
+
+		nmcObj := nmcByName[nodeName]
+		if nmcObj != nil {
+			spec, _ = mnrh.nmcHelper.GetModuleSpecEntry(nmcObj, mod.Namespace, mod.Name)
+			status = mnrh.nmcHelper.GetModuleStatusEntry(nmcObj, mod.Namespace, mod.Name)
+			progress = moduleProgress(nmcObj, mod, status)
+		}
+
+		if config := moduleConfigFromMLD(sd.mld); spec == nil || !reflect.DeepEqual(spec.Config, *config) {
+			switch {
+			case status == nil || status.Config.KernelVersion != config.KernelVersion:
+				// nothing to disrupt
+				moving = append(moving, nodeName)
+			case !windowOpen && requiresReload(config, status):
+				held = append(held, nodeName)
+				delete(sdMap, nodeName)
+			default:
+				pending = append(pending, nodeName)
+			}
+
+			continue
+		}
+
+		switch {
+		case status != nil && reflect.DeepEqual(status.Config, spec.Config):
+			// loaded
+		case progress != nil && apimeta.IsStatusConditionTrue(progress.Conditions, kmmv1beta1.ModuleConditionFailed):
+			failed = append(failed, nodeName)
+		default:
+			inProgress = append(inProgress, nodeName)
+		}
+	}
+
+	if rs := mod.Spec.RolloutStrategy; rs != nil && len(pending) > 0 {
+		maxUnavailable := 1
+
+		if rs.MaxUnavailable != nil {
+			maxUnavailable, err = intstr.GetScaledValueFromIntOrPercent(rs.MaxUnavailable, total, true)
+			if err != nil {
+				return 0, fmt.Errorf("invalid maxUnavailable: %v", err)
+			}
+		}
+
+		available := max(maxUnavailable, 1) - len(inProgress) - len(failed)
+
+		if len(failed) > 0 {
+			available = 0
+		}
+
+		// move the nodes in a stable order
+		slices.Sort(pending)
+
+		for i, nodeName := range pending {
+			if i >= available {
+				delete(sdMap, nodeName)
+			}
+		}
+
+		logger.Info(
+			"Applied the rollout strategy",
+			"maxUnavailable", maxUnavailable,
+			"moving", max(available, 0),
+			"waiting", max(len(pending)-available, 0),
+			"inProgress", len(inProgress),
+			"failed", len(failed),
+		)
+	}
+
+	var requeueAfter time.Duration
+
+	if len(held) > 0 {
+		requeueAfter = windowOpensAt.Sub(now)
+		logger.Info("Waiting for the maintenance window", "nodes", len(held), "opensAt", windowOpensAt)
+	}
+
+	unmodifiedMod := mod.DeepCopy()
+
+	changed := setRolloutConditions(mod, total, len(moving)+len(pending)+len(inProgress)+len(held), failed)
+
+	if setMaintenanceCondition(mod, held, windowOpensAt) {
+		changed = true
+	}
+
+	if !changed {
+		return requeueAfter, nil
+	}
+
+	return requeueAfter, mnrh.client.Status().Patch(ctx, mod, client.MergeFrom(unmodifiedMod))
+}
+
+// requiresReload returns true if moving a node with status to config requires reloading the module, that is if the
+// module is loaded with the same kernel and more than its parameters change.
+func requiresReload(config *kmmv1beta1.ModuleConfig, status *kmmv1beta1.NodeModuleStatus) bool {
+	if status == nil || status.Config.KernelVersion != config.KernelVersion {
+		return false
+	}
+
+	return !onlyParametersChanged(config, &status.Config)
+}
+
+// setMaintenanceCondition sets the MaintenancePending condition of mod from the nodes that wait for the maintenance
+// window, that opens at opensAt.
+// The condition is removed if mod has no maintenance window.
+// It returns true if the conditions changed.
+func setMaintenanceCondition(mod *kmmv1beta1.Module, held []string, opensAt time.Time) bool {
+	if mod.Spec.MaintenanceWindow == nil {
+		return apimeta.RemoveStatusCondition(&mod.Status.Conditions, kmmv1beta1.ModuleConditionMaintenancePending)
+	}
+
+	cond := metav1.Condition{
+		Type:   kmmv1beta1.ModuleConditionMaintenancePending,
+		Status: metav1.ConditionFalse,
+		Reason: kmmv1beta1.ModuleReasonNoPendingOperations,
+	}
+
+	if len(held) > 0 {
+		slices.Sort(held)
+
+		cond.Status = metav1.ConditionTrue
+		cond.Reason = kmmv1beta1.ModuleReasonWaitingForMaintenanceWindow
+		cond.Message = fmt.Sprintf(
+			"nodes %s wait for the maintenance window opening at %s to reload the module",
+			strings.Join(held, ", "),
+			opensAt.UTC().Format(time.RFC3339),
+		)
+	}
+
+	return apimeta.SetStatusCondition(&mod.Status.Conditions, cond)
+}
+
+// setRolloutConditions sets the rollout conditions of mod from the number of targeted nodes, the number of nodes that
+// did not load the current configuration yet and the nodes on which it failed to load.
+// The conditions are removed if mod has no rollout strategy.
+// It returns true if the conditions changed.
+func setRolloutConditions(mod *kmmv1beta1.Module, total, notUpdated int, failed []string) bool {
+	if mod.Spec.RolloutStrategy == nil {
+		removed := apimeta.RemoveStatusCondition(&mod.Status.Conditions, kmmv1beta1.ModuleConditionRolloutProgressing)
+		return apimeta.RemoveStatusCondition(&mod.Status.Conditions, kmmv1beta1.ModuleConditionRolloutFailed) || removed
+	}
+
+	progressing := metav1.Condition{
+		Type:    kmmv1beta1.ModuleConditionRolloutProgressing,
+		Status:  metav1.ConditionFalse,
+		Reason:  kmmv1beta1.ModuleReasonRolloutComplete,
+		Message: fmt.Sprintf("%d of %d nodes run the current configuration", total-notUpdated-len(failed), total),
+	}
+
+	rolloutFailed := metav1.Condition{
+		Type:   kmmv1beta1.ModuleConditionRolloutFailed,
+		Status: metav1.ConditionFalse,
+		Reason: kmmv1beta1.ModuleReasonNoFailedNodes,
+	}
+
+	switch {
+	case len(failed) > 0:
+		progressing.Reason = kmmv1beta1.ModuleReasonRolloutHalted
+	case notUpdated+len(failed) > 0:
+		progressing.Status = metav1.ConditionTrue
+		progressing.Reason = kmmv1beta1.ModuleReasonRolloutInProgress
+	}
+
+	if len(failed) > 0 {
+		slices.Sort(failed)
+
+		rolloutFailed.Status = metav1.ConditionTrue
+		rolloutFailed.Reason = kmmv1beta1.ModuleReasonNodesFailed
+		rolloutFailed.Message = "the module failed to load on nodes " + strings.Join(failed, ", ")
+	}
+
+	changed := apimeta.SetStatusCondition(&mod.Status.Conditions, progressing)
+
+	return apimeta.SetStatusCondition(&mod.Status.Conditions, rolloutFailed) || changed
+}
+
+// moduleConfigFromMLD returns the configuration of the module in the NMC of a node that mld applies to.
+func moduleConfigFromMLD(mld *api.ModuleLoaderData) *kmmv1beta1.ModuleConfig {
+	moduleConfig := kmmv1beta1.ModuleConfig{
+		KernelVersion:                mld.KernelVersion,
+		ContainerImage:               mld.ContainerImage,
+		ImagePullPolicy:              mld.ImagePullPolicy,
+		InTreeModulesToRemove:        mld.InTreeModulesToRemove,
+		BlacklistInTreeModules:       mld.BlacklistInTreeModules,
+		RestoreInTreeModulesOnUnload: mld.RestoreInTreeModulesOnUnload,
+		Modprobe:                     mld.Modprobe,
+		Tolerations:                  mld.Tolerations,
+	}
+
+	if tls := mld.RegistryTLS; tls != nil {
+		moduleConfig.InsecurePull = tls.Insecure || tls.InsecureSkipTLSVerify
+	}
+
+	return &moduleConfig
+}
+
 func (mnrh *moduleNMCReconcilerHelper) enableModuleOnNode(ctx context.Context, mld *api.ModuleLoaderData, node *v1.Node) error {
 	logger := log.FromContext(ctx)
 	if module.ShouldBeBuilt(mld) || module.ShouldBeSigned(mld) {
@@ -359,25 +613,14 @@ func (mnrh *moduleNMCReconcilerHelper) enableModuleOnNode(ctx context.Context, m
 		}
 	}
 
-	moduleConfig := kmmv1beta1.ModuleConfig{
-		KernelVersion:         mld.KernelVersion,
-		ContainerImage:        mld.ContainerImage,
-		ImagePullPolicy:       mld.ImagePullPolicy,
-		InTreeModulesToRemove: mld.InTreeModulesToRemove,
-		Modprobe:              mld.Modprobe,
-		Tolerations:           mld.Tolerations,
-	}
-
-	if tls := mld.RegistryTLS; tls != nil {
-		moduleConfig.InsecurePull = tls.Insecure || tls.InsecureSkipTLSVerify
-	}
+	moduleConfig := moduleConfigFromMLD(mld)
 
 	nmcObj := &kmmv1beta1.NodeModulesConfig{
 		ObjectMeta: metav1.ObjectMeta{Name: node.Name},
 	}
 
 	opRes, err := controllerutil.CreateOrPatch(ctx, mnrh.client, nmcObj, func() error {
-		if err := mnrh.nmcHelper.SetModuleConfig(nmcObj, mld, &moduleConfig); err != nil {
+		if err := mnrh.nmcHelper.SetModuleConfig(nmcObj, mld, moduleConfig); err != nil {
 			return err
 		}
 
@@ -431,17 +674,27 @@ func (mnrh *moduleNMCReconcilerHelper) moduleUpdateWorkerPodsStatus(ctx context.
 	}
 
 	numAvailable := 0
-	for _, nmc := range nmcs {
-		modSpec, _ := mnrh.nmcHelper.GetModuleSpecEntry(&nmc, mod.Namespace, mod.Name)
+	conditionCounts := make(map[conditionKey]int32)
+	for _, nmcObj := range nmcs {
+		modSpec, _ := mnrh.nmcHelper.GetModuleSpecEntry(&nmcObj, mod.Namespace, mod.Name)
 		if modSpec == nil {
 			logger.Info(utils.WarnString(
-				fmt.Sprintf("module %s/%s spec is missing in NMC %s although config label is present", mod.Namespace, mod.Name, nmc.Name)))
+				fmt.Sprintf("module %s/%s spec is missing in NMC %s although config label is present", mod.Namespace, mod.Name, nmcObj.Name)))
 			continue
 		}
-		modStatus := mnrh.nmcHelper.GetModuleStatusEntry(&nmc, mod.Namespace, mod.Name)
+		modStatus := mnrh.nmcHelper.GetModuleStatusEntry(&nmcObj, mod.Namespace, mod.Name)
 		if modStatus != nil && reflect.DeepEqual(modSpec.Config, modStatus.Config) {
 			numAvailable += 1
 		}
+		progress := moduleProgress(&nmcObj, mod, modStatus)
+		if progress == nil {
+			continue
+		}
+		for _, c := range progress.Conditions {
+			if c.Status == metav1.ConditionTrue {
+				conditionCounts[conditionKey{condType: c.Type, reason: c.Reason}]++
+			}
+		}
 	}
 
 	unmodifiedMod := mod.DeepCopy()
@@ -449,10 +702,99 @@ func (mnrh *moduleNMCReconcilerHelper) moduleUpdateWorkerPodsStatus(ctx context.
 	mod.Status.ModuleLoader.NodesMatchingSelectorNumber = int32(len(targetedNodes))
 	mod.Status.ModuleLoader.DesiredNumber = int32(len(nmcs))
 	mod.Status.ModuleLoader.AvailableNumber = int32(numAvailable)
+	mod.Status.NodeConditions = nodeConditionCounts(conditionCounts)
+	mod.Status.KernelMappings = mnrh.kernelMappingStatuses(ctx, mod, targetedNodes)
 
 	return mnrh.client.Status().Patch(ctx, mod, client.MergeFrom(unmodifiedMod))
 }
 
+// kernelMappingStatuses returns the kernel mapping used for each kernel version running on targetedNodes, sorted by
+// kernel version.
+// Kernel versions for which the mapping could not be determined are left out.
+func (mnrh *moduleNMCReconcilerHelper) kernelMappingStatuses(ctx context.Context, mod *kmmv1beta1.Module, targetedNodes []v1.Node) []kmmv1beta1.KernelMappingStatus {
+	logger := log.FromContext(ctx)
+
+	kernelVersions := sets.New[string]()
+
+	for _, n := range targetedNodes {
+		if kernelVersion := strings.TrimSuffix(n.Status.NodeInfo.KernelVersion, "+"); kernelVersion != "" {
+			kernelVersions.Insert(kernelVersion)
+		}
+	}
+
+	if kernelVersions.Len() == 0 {
+		return nil
+	}
+
+	statuses := make([]kmmv1beta1.KernelMappingStatus, 0, kernelVersions.Len())
+
+	for _, kernelVersion := range sets.List(kernelVersions) {
+		s := kmmv1beta1.KernelMappingStatus{KernelVersion: kernelVersion}
+
+		mld, err := mnrh.kernelAPI.GetModuleLoaderDataForKernel(mod, kernelVersion)
+		if err != nil && !errors.Is(err, module.ErrNoMatchingKernelMapping) {
+			logger.Info(utils.WarnString("Could not determine the kernel mapping"), "kernel version", kernelVersion, "error", err)
+			continue
+		}
+
+		if mld != nil {
+			s.MappingIndex = ptr.To(int32(mld.KernelMappingIndex))
+			s.ContainerImage = mld.ContainerImage
+		}
+
+		statuses = append(statuses, s)
+	}
+
+	return statuses
+}
+
+// moduleProgress returns the progress of the worker Pods for mod on the node of nmcObj.
+// The progress of a loaded module is held by its status; that of a module that was never loaded on the node is held by
+// its pending entry, if any.
+func moduleProgress(
+	nmcObj *kmmv1beta1.NodeModulesConfig,
+	mod *kmmv1beta1.Module,
+	status *kmmv1beta1.NodeModuleStatus,
+) *kmmv1beta1.NodeModuleProgress {
+	if status != nil {
+		return &status.NodeModuleProgress
+	}
+
+	if p := nmc.FindPendingModule(nmcObj.Status.PendingModules, mod.Namespace, mod.Name); p != nil {
+		return &p.NodeModuleProgress
+	}
+
+	return nil
+}
+
+type conditionKey struct {
+	condType string
+	reason   string
+}
+
+// nodeConditionCounts returns the counts indexed by condition type and reason as a list sorted by type and reason.
+func nodeConditionCounts(counts map[conditionKey]int32) []kmmv1beta1.NodeConditionCount {
+	if len(counts) == 0 {
+		return nil
+	}
+
+	list := make([]kmmv1beta1.NodeConditionCount, 0, len(counts))
+
+	for k, count := range counts {
+		list = append(list, kmmv1beta1.NodeConditionCount{Type: k.condType, Reason: k.reason, Count: count})
+	}
+
+	slices.SortFunc(list, func(a, b kmmv1beta1.NodeConditionCount) int {
+		if c := cmp.Compare(a.Type, b.Type); c != 0 {
+			return c
+		}
+
+		return cmp.Compare(a.Reason, b.Reason)
+	})
+
+	return list
+}
+
 type namespaceLabeler interface {
 	setLabel(ctx context.Context, name string) error
 	tryRemovingLabel(ctx context.Context, name, moduleName string) error
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -21,10 +22,13 @@ import (
 	"go.uber.org/mock/gomock"
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/ptr"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -90,6 +94,7 @@ var _ = Describe("Reconcile", func() {
 		getNodesError              bool
 		getNMCsMapError            bool
 		prepareSchedulingError     bool
+		applyRolloutError          bool
 		shouldBeOnNode             bool
 		disableEnableError         bool
 		moduleUpdateStatusErr      bool
@@ -125,9 +130,15 @@ var _ = Describe("Reconcile", func() {
 		mockReconHelper.EXPECT().getNMCsByModuleSet(ctx, mod).Return(currentNMCs, nil)
 		if c.prepareSchedulingError {
 			mockReconHelper.EXPECT().prepareSchedulingData(ctx, mod, targetedNodes, currentNMCs).Return(nil, []error{returnedError})
+			mockReconHelper.EXPECT().applyRolloutStrategy(ctx, mod, nil).Return(time.Duration(0), nil)
 			goto moduleStatusUpdateFunction
 		}
 		mockReconHelper.EXPECT().prepareSchedulingData(ctx, mod, targetedNodes, currentNMCs).Return(nmcMLDConfigs, []error{})
+		if c.applyRolloutError {
+			mockReconHelper.EXPECT().applyRolloutStrategy(ctx, mod, nmcMLDConfigs).Return(time.Duration(0), returnedError)
+			goto executeTestFunction
+		}
+		mockReconHelper.EXPECT().applyRolloutStrategy(ctx, mod, nmcMLDConfigs).Return(time.Duration(0), nil)
 		if c.disableEnableError {
 			if c.shouldBeOnNode {
 				mockReconHelper.EXPECT().enableModuleOnNode(ctx, &mld, &node).Return(returnedError)
@@ -159,6 +170,7 @@ var _ = Describe("Reconcile", func() {
 		Entry("getNodesListBySelector failed", errorFlowTestCase{getNodesError: true}),
 		Entry("getNMCsByModuleMap failed", errorFlowTestCase{getNMCsMapError: true}),
 		Entry("prepareSchedulingData failed", errorFlowTestCase{prepareSchedulingError: true}),
+		Entry("applyRolloutStrategy failed", errorFlowTestCase{shouldBeOnNode: true, applyRolloutError: true}),
 		Entry("enableModuleOnNode failed", errorFlowTestCase{shouldBeOnNode: true, disableEnableError: true}),
 		Entry("disableModuleOnNode failed", errorFlowTestCase{disableEnableError: true}),
 		Entry(".moduleUpdateWorkerPodsStatus failed", errorFlowTestCase{moduleUpdateStatusErr: true}),
@@ -173,6 +185,7 @@ var _ = Describe("Reconcile", func() {
 			mn.EXPECT().GetNodesListBySelector(ctx, mod.Spec.Selector, nil).Return(targetedNodes, nil),
 			mockReconHelper.EXPECT().getNMCsByModuleSet(ctx, mod).Return(currentNMCs, nil),
 			mockReconHelper.EXPECT().prepareSchedulingData(ctx, mod, targetedNodes, currentNMCs).Return(nmcMLDConfigs, nil),
+			mockReconHelper.EXPECT().applyRolloutStrategy(ctx, mod, nmcMLDConfigs).Return(time.Duration(0), nil),
 			mockReconHelper.EXPECT().enableModuleOnNode(ctx, &mld, &node).Return(nil),
 			mockReconHelper.EXPECT().moduleUpdateWorkerPodsStatus(ctx, mod, targetedNodes).Return(nil),
 		)
@@ -191,6 +204,7 @@ var _ = Describe("Reconcile", func() {
 			mn.EXPECT().GetNodesListBySelector(ctx, mod.Spec.Selector, nil).Return(targetedNodes, nil),
 			mockReconHelper.EXPECT().getNMCsByModuleSet(ctx, mod).Return(currentNMCs, nil),
 			mockReconHelper.EXPECT().prepareSchedulingData(ctx, mod, targetedNodes, currentNMCs).Return(nmcMLDConfigs, nil),
+			mockReconHelper.EXPECT().applyRolloutStrategy(ctx, mod, nmcMLDConfigs).Return(time.Duration(0), nil),
 			mockReconHelper.EXPECT().disableModuleOnNode(ctx, mod.Namespace, mod.Name, node.Name).Return(nil),
 			mockReconHelper.EXPECT().moduleUpdateWorkerPodsStatus(ctx, mod, targetedNodes).Return(nil),
 		)
@@ -200,6 +214,53 @@ var _ = Describe("Reconcile", func() {
 		Expect(res).To(Equal(reconcile.Result{}))
 		Expect(err).NotTo(HaveOccurred())
 	})
+
+	It("Good flow, should not move the node if the rollout strategy defers it", func() {
+		nmcMLDConfigs := map[string]schedulingData{nodeName: enableSchedulingData}
+		gomock.InOrder(
+			mockNamespaceHelper.EXPECT().setLabel(ctx, mod.Namespace),
+			mockReconHelper.EXPECT().setFinalizerAndStatus(ctx, mod).Return(nil),
+			mn.EXPECT().GetNodesListBySelector(ctx, mod.Spec.Selector, nil).Return(targetedNodes, nil),
+			mockReconHelper.EXPECT().getNMCsByModuleSet(ctx, mod).Return(currentNMCs, nil),
+			mockReconHelper.EXPECT().prepareSchedulingData(ctx, mod, targetedNodes, currentNMCs).Return(nmcMLDConfigs, nil),
+			mockReconHelper.
+				EXPECT().
+				applyRolloutStrategy(ctx, mod, nmcMLDConfigs).
+				Do(func(_ context.Context, _ *kmmv1beta1.Module, sdMap map[string]schedulingData) {
+					delete(sdMap, nodeName)
+				}),
+			mockReconHelper.EXPECT().moduleUpdateWorkerPodsStatus(ctx, mod, targetedNodes).Return(nil),
+		)
+
+		res, err := mnr.Reconcile(ctx, mod)
+
+		Expect(res).To(Equal(reconcile.Result{}))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("Good flow, should requeue when the maintenance window opens", func() {
+		nmcMLDConfigs := map[string]schedulingData{nodeName: enableSchedulingData}
+		gomock.InOrder(
+			mockNamespaceHelper.EXPECT().setLabel(ctx, mod.Namespace),
+			mockReconHelper.EXPECT().setFinalizerAndStatus(ctx, mod).Return(nil),
+			mn.EXPECT().GetNodesListBySelector(ctx, mod.Spec.Selector, nil).Return(targetedNodes, nil),
+			mockReconHelper.EXPECT().getNMCsByModuleSet(ctx, mod).Return(currentNMCs, nil),
+			mockReconHelper.EXPECT().prepareSchedulingData(ctx, mod, targetedNodes, currentNMCs).Return(nmcMLDConfigs, nil),
+			mockReconHelper.
+				EXPECT().
+				applyRolloutStrategy(ctx, mod, nmcMLDConfigs).
+				DoAndReturn(func(_ context.Context, _ *kmmv1beta1.Module, sdMap map[string]schedulingData) (time.Duration, error) {
+					delete(sdMap, nodeName)
+					return time.Hour, nil
+				}),
+			mockReconHelper.EXPECT().moduleUpdateWorkerPodsStatus(ctx, mod, targetedNodes).Return(nil),
+		)
+
+		res, err := mnr.Reconcile(ctx, mod)
+
+		Expect(res).To(Equal(reconcile.Result{RequeueAfter: time.Hour}))
+		Expect(err).NotTo(HaveOccurred())
+	})
 })
 
 var _ = Describe("setFinalizerAndStatus", func() {
@@ -625,6 +686,369 @@ var _ = Describe("prepareSchedulingData", func() {
 	)
 })
 
+var _ = Describe("applyRolloutStrategy", func() {
+	const (
+		modName      = "modName"
+		modNamespace = "modNamespace"
+	)
+
+	var (
+		ctx          context.Context
+		clnt         *client.MockClient
+		statusWriter *client.MockStatusWriter
+		mnrh         *moduleNMCReconcilerHelper
+		mod          *kmmv1beta1.Module
+		mld          *api.ModuleLoaderData
+		oldConfig    kmmv1beta1.ModuleConfig
+		newConfig    kmmv1beta1.ModuleConfig
+		nmcs         []kmmv1beta1.NodeModulesConfig
+		sdMap        map[string]schedulingData
+	)
+
+	makeNMC := func(name string, specConfig, statusConfig kmmv1beta1.ModuleConfig, conditions ...metav1.Condition) kmmv1beta1.NodeModulesConfig {
+		mi := kmmv1beta1.ModuleItem{Name: modName, Namespace: modNamespace}
+
+		return kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: kmmv1beta1.NodeModulesConfigSpec{
+				Modules: []kmmv1beta1.NodeModuleSpec{
+					{ModuleItem: mi, Config: specConfig},
+				},
+			},
+			Status: kmmv1beta1.NodeModulesConfigStatus{
+				Modules: []kmmv1beta1.NodeModuleStatus{
+					{
+						ModuleItem:         mi,
+						Config:             statusConfig,
+						NodeModuleProgress: kmmv1beta1.NodeModuleProgress{Conditions: conditions},
+					},
+				},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		ctrl := gomock.NewController(GinkgoT())
+		clnt = client.NewMockClient(ctrl)
+		statusWriter = client.NewMockStatusWriter(ctrl)
+		mnrh = &moduleNMCReconcilerHelper{client: clnt, nmcHelper: nmc.NewHelper(clnt)}
+		mod = &kmmv1beta1.Module{
+			ObjectMeta: metav1.ObjectMeta{Name: modName, Namespace: modNamespace},
+		}
+		mld = &api.ModuleLoaderData{Name: modName, Namespace: modNamespace, ContainerImage: "new-image"}
+		oldConfig = kmmv1beta1.ModuleConfig{ContainerImage: "old-image"}
+		newConfig = *moduleConfigFromMLD(mld)
+
+		// node1 has loaded the new configuration, node2 is loading it, node3 has not been moved yet and node4 does not
+		// run the module yet.
+		nmcs = []kmmv1beta1.NodeModulesConfig{
+			makeNMC("node1", newConfig, newConfig),
+			makeNMC("node2", newConfig, oldConfig),
+			makeNMC("node3", oldConfig, oldConfig),
+		}
+
+		sdMap = make(map[string]schedulingData)
+
+		for _, name := range []string{"node1", "node2", "node3", "node4"} {
+			sdMap[name] = schedulingData{action: actionAdd, mld: mld, node: &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}}}
+		}
+
+		sdMap["node5"] = schedulingData{action: actionDelete}
+	})
+
+	expectList := func() *gomock.Call {
+		return clnt.
+			EXPECT().
+			List(ctx, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, list *kmmv1beta1.NodeModulesConfigList, _ ...ctrlclient.ListOption) error {
+				list.Items = nmcs
+				return nil
+			})
+	}
+
+	expectStatusPatch := func() {
+		clnt.EXPECT().Status().Return(statusWriter)
+		statusWriter.EXPECT().Patch(ctx, mod, gomock.Any())
+	}
+
+	It("should return an error if the NMCs cannot be listed", func() {
+		clnt.EXPECT().List(ctx, gomock.Any(), gomock.Any()).Return(errors.New("some error"))
+
+		_, err := mnrh.applyRolloutStrategy(ctx, mod, sdMap)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should move all nodes at once without rollout strategy", func() {
+		expectList()
+
+		_, err := mnrh.applyRolloutStrategy(ctx, mod, sdMap)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(sdMap).To(HaveLen(5))
+		Expect(mod.Status.Conditions).To(BeEmpty())
+	})
+
+	It("should remove the rollout conditions if the rollout strategy was unset", func() {
+		mod.Spec.RolloutStrategy = &kmmv1beta1.RolloutStrategy{}
+		setRolloutConditions(mod, 4, 3, []string{"node2"})
+		mod.Spec.RolloutStrategy = nil
+
+		expectList()
+		expectStatusPatch()
+
+		_, err := mnrh.applyRolloutStrategy(ctx, mod, sdMap)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(sdMap).To(HaveLen(5))
+		Expect(mod.Status.Conditions).To(BeEmpty())
+	})
+
+	DescribeTable(
+		"should only move as many nodes as allowed by maxUnavailable",
+		func(maxUnavailable *intstr.IntOrString, expectedNodes ...string) {
+			mod.Spec.RolloutStrategy = &kmmv1beta1.RolloutStrategy{MaxUnavailable: maxUnavailable}
+
+			expectList()
+			expectStatusPatch()
+
+			_, err := mnrh.applyRolloutStrategy(ctx, mod, sdMap)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(sdMap).To(HaveLen(len(expectedNodes)))
+
+			for _, n := range expectedNodes {
+				Expect(sdMap).To(HaveKey(n))
+			}
+		},
+		Entry("default", nil, "node1", "node2", "node4", "node5"),
+		Entry("one node", ptr.To(intstr.FromInt32(1)), "node1", "node2", "node4", "node5"),
+		Entry("two nodes", ptr.To(intstr.FromInt32(2)), "node1", "node2", "node3", "node4", "node5"),
+		Entry("percentage", ptr.To(intstr.FromString("75%")), "node1", "node2", "node3", "node4", "node5"),
+	)
+
+	It("should stop the rollout if a node failed to load the new configuration", func() {
+		mod.Spec.RolloutStrategy = &kmmv1beta1.RolloutStrategy{MaxUnavailable: ptr.To(intstr.FromString("100%"))}
+
+		nmcs[1] = makeNMC(
+			"node2",
+			newConfig,
+			oldConfig,
+			metav1.Condition{Type: kmmv1beta1.ModuleConditionFailed, Status: metav1.ConditionTrue},
+		)
+
+		expectList()
+		expectStatusPatch()
+
+		_, err := mnrh.applyRolloutStrategy(ctx, mod, sdMap)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(sdMap).To(HaveLen(4))
+		Expect(sdMap).NotTo(HaveKey("node3"))
+		Expect(sdMap).To(HaveKey("node4"))
+
+		cond := apimeta.FindStatusCondition(mod.Status.Conditions, kmmv1beta1.ModuleConditionRolloutProgressing)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+		Expect(cond.Reason).To(Equal(kmmv1beta1.ModuleReasonRolloutHalted))
+
+		cond = apimeta.FindStatusCondition(mod.Status.Conditions, kmmv1beta1.ModuleConditionRolloutFailed)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+		Expect(cond.Reason).To(Equal(kmmv1beta1.ModuleReasonNodesFailed))
+		Expect(cond.Message).To(Equal("the module failed to load on nodes node2"))
+	})
+
+	It("should stop the rollout if a node failed to load the module for the first time", func() {
+		mod.Spec.RolloutStrategy = &kmmv1beta1.RolloutStrategy{MaxUnavailable: ptr.To(intstr.FromString("100%"))}
+
+		nmcs[1].Status.Modules = nil
+		nmcs[1].Status.PendingModules = []kmmv1beta1.NodeModulePendingStatus{
+			{
+				Name:      modName,
+				Namespace: modNamespace,
+				NodeModuleProgress: kmmv1beta1.NodeModuleProgress{
+					Conditions: []metav1.Condition{
+						{Type: kmmv1beta1.ModuleConditionFailed, Status: metav1.ConditionTrue},
+					},
+				},
+			},
+		}
+
+		expectList()
+		expectStatusPatch()
+
+		_, err := mnrh.applyRolloutStrategy(ctx, mod, sdMap)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(sdMap).To(HaveLen(4))
+		Expect(sdMap).NotTo(HaveKey("node3"))
+		Expect(sdMap).To(HaveKey("node4"))
+
+		cond := apimeta.FindStatusCondition(mod.Status.Conditions, kmmv1beta1.ModuleConditionRolloutFailed)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+		Expect(cond.Message).To(Equal("the module failed to load on nodes node2"))
+	})
+
+	It("should move the nodes that do not run the module with the same kernel even if the rollout is halted", func() {
+		mod.Spec.RolloutStrategy = &kmmv1beta1.RolloutStrategy{}
+
+		nmcs[1] = makeNMC(
+			"node2",
+			newConfig,
+			oldConfig,
+			metav1.Condition{Type: kmmv1beta1.ModuleConditionFailed, Status: metav1.ConditionTrue},
+		)
+
+		// node3 booted another kernel
+		oldKernelConfig := oldConfig
+		oldKernelConfig.KernelVersion = "old-kernel"
+		nmcs[2] = makeNMC("node3", oldKernelConfig, oldKernelConfig)
+
+		expectList()
+		expectStatusPatch()
+
+		_, err := mnrh.applyRolloutStrategy(ctx, mod, sdMap)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(sdMap).To(HaveLen(5))
+		Expect(sdMap).To(HaveKey("node3"))
+		Expect(sdMap).To(HaveKey("node4"))
+	})
+
+	Context("with a maintenance window", func() {
+		// closedWindow opens every day in two hours, for one hour.
+		closedWindow := func() *kmmv1beta1.MaintenanceWindow {
+			opensAt := time.Now().UTC().Add(2 * time.Hour)
+
+			return &kmmv1beta1.MaintenanceWindow{
+				Schedule: fmt.Sprintf("%d %d * * *", opensAt.Minute(), opensAt.Hour()),
+				Duration: metav1.Duration{Duration: time.Hour},
+			}
+		}
+
+		It("should return an error if the window is invalid", func() {
+			mod.Spec.MaintenanceWindow = &kmmv1beta1.MaintenanceWindow{Schedule: "invalid"}
+
+			expectList()
+
+			_, err := mnrh.applyRolloutStrategy(ctx, mod, sdMap)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should hold the nodes that require a reload while the window is closed", func() {
+			mod.Spec.MaintenanceWindow = closedWindow()
+			mod.Spec.RolloutStrategy = &kmmv1beta1.RolloutStrategy{MaxUnavailable: ptr.To(intstr.FromString("100%"))}
+
+			expectList()
+			expectStatusPatch()
+
+			requeueAfter, err := mnrh.applyRolloutStrategy(ctx, mod, sdMap)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(requeueAfter).To(BeNumerically("~", 2*time.Hour, time.Minute))
+
+			// node4 does not run the module yet, so it can be moved
+			Expect(sdMap).To(HaveLen(4))
+			Expect(sdMap).NotTo(HaveKey("node3"))
+
+			cond := apimeta.FindStatusCondition(mod.Status.Conditions, kmmv1beta1.ModuleConditionMaintenancePending)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+			Expect(cond.Reason).To(Equal(kmmv1beta1.ModuleReasonWaitingForMaintenanceWindow))
+			Expect(cond.Message).To(HavePrefix("nodes node3 wait for the maintenance window opening at "))
+
+			Expect(
+				apimeta.FindStatusCondition(mod.Status.Conditions, kmmv1beta1.ModuleConditionRolloutProgressing).Message,
+			).To(
+				Equal("1 of 4 nodes run the current configuration"),
+			)
+		})
+
+		It("should not hold the nodes on which only the parameters change", func() {
+			mod.Spec.MaintenanceWindow = closedWindow()
+			mld.ContainerImage = oldConfig.ContainerImage
+			mld.Modprobe.Parameters = []string{"a=b"}
+			nmcs = []kmmv1beta1.NodeModulesConfig{makeNMC("node3", oldConfig, oldConfig)}
+			sdMap = map[string]schedulingData{
+				"node3": {action: actionAdd, mld: mld},
+			}
+
+			expectList()
+			expectStatusPatch()
+
+			requeueAfter, err := mnrh.applyRolloutStrategy(ctx, mod, sdMap)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(requeueAfter).To(BeZero())
+			Expect(sdMap).To(HaveKey("node3"))
+			Expect(
+				apimeta.IsStatusConditionFalse(mod.Status.Conditions, kmmv1beta1.ModuleConditionMaintenancePending),
+			).To(
+				BeTrue(),
+			)
+		})
+
+		It("should move all nodes while the window is open", func() {
+			mod.Spec.MaintenanceWindow = &kmmv1beta1.MaintenanceWindow{
+				Schedule: "* * * * *",
+				Duration: metav1.Duration{Duration: time.Hour},
+			}
+
+			expectList()
+			expectStatusPatch()
+
+			requeueAfter, err := mnrh.applyRolloutStrategy(ctx, mod, sdMap)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(requeueAfter).To(BeZero())
+			Expect(sdMap).To(HaveLen(5))
+
+			cond := apimeta.FindStatusCondition(mod.Status.Conditions, kmmv1beta1.ModuleConditionMaintenancePending)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+			Expect(cond.Reason).To(Equal(kmmv1beta1.ModuleReasonNoPendingOperations))
+		})
+
+		It("should remove the condition when the window is removed", func() {
+			mod.Spec.MaintenanceWindow = closedWindow()
+			setMaintenanceCondition(mod, []string{"node3"}, time.Now())
+			mod.Spec.MaintenanceWindow = nil
+
+			expectList()
+			expectStatusPatch()
+
+			_, err := mnrh.applyRolloutStrategy(ctx, mod, sdMap)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(
+				apimeta.FindStatusCondition(mod.Status.Conditions, kmmv1beta1.ModuleConditionMaintenancePending),
+			).To(
+				BeNil(),
+			)
+		})
+	})
+
+	It("should not patch the Module if the conditions did not change", func() {
+		mod.Spec.RolloutStrategy = &kmmv1beta1.RolloutStrategy{}
+
+		nmcs = []kmmv1beta1.NodeModulesConfig{makeNMC("node1", newConfig, newConfig)}
+		sdMap = map[string]schedulingData{
+			"node1": {action: actionAdd, mld: mld},
+		}
+
+		setRolloutConditions(mod, 1, 0, nil)
+
+		expectList()
+
+		_, err := mnrh.applyRolloutStrategy(ctx, mod, sdMap)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(
+			apimeta.FindStatusCondition(mod.Status.Conditions, kmmv1beta1.ModuleConditionRolloutProgressing).Reason,
+		).To(
+			Equal(kmmv1beta1.ModuleReasonRolloutComplete),
+		)
+	})
+})
+
 var _ = Describe("enableModuleOnNode", func() {
 	const (
 		moduleNamespace = "moduleNamespace"
@@ -660,18 +1084,22 @@ var _ = Describe("enableModuleOnNode", func() {
 		kernelVersion = "some version"
 		ctx = context.Background()
 		mld = &api.ModuleLoaderData{
-			KernelVersion:         kernelVersion,
-			Name:                  moduleName,
-			Namespace:             moduleNamespace,
-			InTreeModulesToRemove: []string{"InTreeModuleToRemove"},
-			ContainerImage:        "containerImage",
+			KernelVersion:                kernelVersion,
+			Name:                         moduleName,
+			Namespace:                    moduleNamespace,
+			InTreeModulesToRemove:        []string{"InTreeModuleToRemove"},
+			BlacklistInTreeModules:       true,
+			RestoreInTreeModulesOnUnload: true,
+			ContainerImage:               "containerImage",
 		}
 
 		expectedModuleConfig = &kmmv1beta1.ModuleConfig{
-			KernelVersion:         mld.KernelVersion,
-			ContainerImage:        mld.ContainerImage,
-			InTreeModulesToRemove: mld.InTreeModulesToRemove,
-			Modprobe:              mld.Modprobe,
+			KernelVersion:                mld.KernelVersion,
+			ContainerImage:               mld.ContainerImage,
+			InTreeModulesToRemove:        mld.InTreeModulesToRemove,
+			BlacklistInTreeModules:       true,
+			RestoreInTreeModulesOnUnload: true,
+			Modprobe:                     mld.Modprobe,
 		}
 	})
 
@@ -1024,6 +1452,117 @@ var _ = Describe("moduleUpdateWorkerPodsStatus", func() {
 		Expect(err).NotTo(HaveOccurred())
 	})
 
+	It("should count the true conditions of the nodes per type and reason, including never loaded modules", func() {
+		nmcModuleSpec := kmmv1beta1.NodeModuleSpec{
+			Config: kmmv1beta1.ModuleConfig{ContainerImage: "some image1"},
+		}
+		failedStatus := func(reason string) *kmmv1beta1.NodeModuleStatus {
+			return &kmmv1beta1.NodeModuleStatus{
+				NodeModuleProgress: kmmv1beta1.NodeModuleProgress{
+					Conditions: []metav1.Condition{
+						{Type: kmmv1beta1.ModuleConditionLoading, Status: metav1.ConditionTrue, Reason: kmmv1beta1.ModuleReasonWorkerPodRunning},
+						{Type: kmmv1beta1.ModuleConditionLoaded, Status: metav1.ConditionFalse, Reason: kmmv1beta1.ModuleReasonWorkerPodRunning},
+						{Type: kmmv1beta1.ModuleConditionFailed, Status: metav1.ConditionTrue, Reason: reason},
+					},
+				},
+			}
+		}
+		nmcs := []kmmv1beta1.NodeModulesConfig{
+			{ObjectMeta: metav1.ObjectMeta{Name: "nmc1"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "nmc2"}},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "nmc3"},
+				Status: kmmv1beta1.NodeModulesConfigStatus{
+					PendingModules: []kmmv1beta1.NodeModulePendingStatus{
+						{
+							Name:               mod.Name,
+							Namespace:          mod.Namespace,
+							NodeModuleProgress: failedStatus(kmmv1beta1.ModuleReasonImagePullFailed).NodeModuleProgress,
+						},
+					},
+				},
+			},
+		}
+		expectedMod := mod.DeepCopy()
+		expectedMod.Status.ModuleLoader.NodesMatchingSelectorNumber = int32(3)
+		expectedMod.Status.ModuleLoader.DesiredNumber = int32(3)
+		expectedMod.Status.NodeConditions = []kmmv1beta1.NodeConditionCount{
+			{Type: kmmv1beta1.ModuleConditionFailed, Reason: kmmv1beta1.ModuleReasonImagePullFailed, Count: 2},
+			{Type: kmmv1beta1.ModuleConditionFailed, Reason: kmmv1beta1.ModuleReasonModprobeFailed, Count: 1},
+			{Type: kmmv1beta1.ModuleConditionLoading, Reason: kmmv1beta1.ModuleReasonWorkerPodRunning, Count: 3},
+		}
+		gomock.InOrder(
+			clnt.EXPECT().List(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ interface{}, list *kmmv1beta1.NodeModulesConfigList, _ ...interface{}) error {
+					list.Items = nmcs
+					return nil
+				},
+			),
+			helper.EXPECT().GetModuleSpecEntry(&nmcs[0], mod.Namespace, mod.Name).Return(&nmcModuleSpec, 0),
+			helper.EXPECT().
+				GetModuleStatusEntry(&nmcs[0], mod.Namespace, mod.Name).
+				Return(failedStatus(kmmv1beta1.ModuleReasonImagePullFailed)),
+			helper.EXPECT().GetModuleSpecEntry(&nmcs[1], mod.Namespace, mod.Name).Return(&nmcModuleSpec, 0),
+			helper.EXPECT().
+				GetModuleStatusEntry(&nmcs[1], mod.Namespace, mod.Name).
+				Return(failedStatus(kmmv1beta1.ModuleReasonModprobeFailed)),
+			helper.EXPECT().GetModuleSpecEntry(&nmcs[2], mod.Namespace, mod.Name).Return(&nmcModuleSpec, 0),
+			helper.EXPECT().GetModuleStatusEntry(&nmcs[2], mod.Namespace, mod.Name).Return(nil),
+			clnt.EXPECT().Status().Return(statusWriter),
+			statusWriter.EXPECT().Patch(ctx, expectedMod, gomock.Any()),
+		)
+
+		err := mnrh.moduleUpdateWorkerPodsStatus(ctx, &mod, []v1.Node{{}, {}, {}})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should report the kernel mapping used for each kernel version", func() {
+		kernelAPI := module.NewMockKernelMapper(ctrl)
+		mnrh.kernelAPI = kernelAPI
+
+		nodeWithKernel := func(kernelVersion string) v1.Node {
+			return v1.Node{
+				Status: v1.NodeStatus{
+					NodeInfo: v1.NodeSystemInfo{KernelVersion: kernelVersion},
+				},
+			}
+		}
+
+		targetedNodes := []v1.Node{
+			nodeWithKernel("kernel-b"),
+			nodeWithKernel("kernel-a+"),
+			nodeWithKernel("kernel-b"),
+			nodeWithKernel("kernel-c"),
+			nodeWithKernel("kernel-d"),
+		}
+
+		expectedMod := mod.DeepCopy()
+		expectedMod.Status.ModuleLoader.NodesMatchingSelectorNumber = int32(5)
+		expectedMod.Status.KernelMappings = []kmmv1beta1.KernelMappingStatus{
+			{KernelVersion: "kernel-a", MappingIndex: ptr.To[int32](1), ContainerImage: "image-a"},
+			{KernelVersion: "kernel-b", MappingIndex: ptr.To[int32](0), ContainerImage: "image-b"},
+			{KernelVersion: "kernel-c"},
+		}
+
+		gomock.InOrder(
+			clnt.EXPECT().List(ctx, gomock.Any(), gomock.Any()),
+			kernelAPI.
+				EXPECT().
+				GetModuleLoaderDataForKernel(&mod, "kernel-a").
+				Return(&api.ModuleLoaderData{KernelMappingIndex: 1, ContainerImage: "image-a"}, nil),
+			kernelAPI.
+				EXPECT().
+				GetModuleLoaderDataForKernel(&mod, "kernel-b").
+				Return(&api.ModuleLoaderData{KernelMappingIndex: 0, ContainerImage: "image-b"}, nil),
+			kernelAPI.EXPECT().GetModuleLoaderDataForKernel(&mod, "kernel-c").Return(nil, module.ErrNoMatchingKernelMapping),
+			kernelAPI.EXPECT().GetModuleLoaderDataForKernel(&mod, "kernel-d").Return(nil, errors.New("some error")),
+			clnt.EXPECT().Status().Return(statusWriter),
+			statusWriter.EXPECT().Patch(ctx, expectedMod, gomock.Any()),
+		)
+
+		err := mnrh.moduleUpdateWorkerPodsStatus(ctx, &mod, targetedNodes)
+		Expect(err).NotTo(HaveOccurred())
+	})
 })
 
 var _ = Describe("namespaceHelper_setLabel", func() {
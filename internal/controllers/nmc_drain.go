@@ -0,0 +1,197 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
+	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+//+kubebuilder:rbac:groups="core",resources=pods/eviction,verbs=create
+
+// drainRequeueDelay is the delay after which a NodeModulesConfig is reconciled again while its node is drained.
+const drainRequeueDelay = 10 * time.Second
+
+// errDrainInProgress is returned while Pods are being evicted from a node before reloading a module.
+var errDrainInProgress = errors.New("waiting for Pods to be evicted from the node")
+
+// drainNode cordons node and evicts the Pods selected by the drain policy of spec through the eviction API, so that
+// PodDisruptionBudgets are honoured.
+// It returns errDrainInProgress until all those Pods are gone.
+func (h *nmcReconcilerHelperImpl) drainNode(ctx context.Context, node *v1.Node, spec *kmmv1beta1.NodeModuleSpec) error {
+	logger := ctrl.LoggerFrom(ctx)
+
+	nsn := types.NamespacedName{Namespace: spec.Namespace, Name: spec.Name}.String()
+	annotations := map[string]string{"module": nsn}
+
+	cordoned, err := h.nodeAPI.Cordon(ctx, node, nsn)
+	if err != nil {
+		return fmt.Errorf("could not cordon the node: %v", err)
+	}
+
+	if cordoned {
+		logger.Info("Cordoned the node to reload the module")
+		h.recorder.AnnotatedEventf(node, annotations, v1.EventTypeNormal, "NodeCordoned", "Node cordoned to reload module %s", nsn)
+	}
+
+	pods, err := h.podsToEvict(ctx, node.Name, spec.Drain)
+	if err != nil {
+		return fmt.Errorf("could not list the Pods to evict: %v", err)
+	}
+
+	if len(pods) == 0 {
+		logger.Info("Node drained")
+		h.recorder.AnnotatedEventf(node, annotations, v1.EventTypeNormal, "NodeDrained", "Node drained to reload module %s", nsn)
+		return nil
+	}
+
+	for i := range pods {
+		pod := &pods[i]
+
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+
+		podNSN := client.ObjectKeyFromObject(pod)
+
+		eviction := &policyv1.Eviction{
+			ObjectMeta: metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace},
+		}
+
+		err = h.client.SubResource("eviction").Create(ctx, pod, eviction)
+
+		switch {
+		case err == nil:
+			logger.Info("Evicted Pod", "pod", podNSN)
+			h.recorder.AnnotatedEventf(
+				node,
+				annotations,
+				v1.EventTypeNormal,
+				"PodEvicted",
+				"Evicted Pod %s to reload module %s",
+				podNSN,
+				nsn,
+			)
+		case k8serrors.IsTooManyRequests(err):
+			// A PodDisruptionBudget does not allow the eviction yet.
+			logger.Info("Cannot evict Pod yet", "pod", podNSN, "error", err)
+			h.recorder.AnnotatedEventf(
+				node,
+				annotations,
+				v1.EventTypeWarning,
+				"EvictionBlocked",
+				"Cannot evict Pod %s to reload module %s yet: %v",
+				podNSN,
+				nsn,
+				err,
+			)
+		case k8serrors.IsNotFound(err):
+			// The Pod is already gone.
+		default:
+			return fmt.Errorf("could not evict Pod %s: %v", podNSN, err)
+		}
+	}
+
+	return errDrainInProgress
+}
+
+// uncordonNode marks node as schedulable again if KMM cordoned it to reload the module described by item.
+func (h *nmcReconcilerHelperImpl) uncordonNode(ctx context.Context, node *v1.Node, item *kmmv1beta1.ModuleItem) error {
+	nsn := types.NamespacedName{Namespace: item.Namespace, Name: item.Name}.String()
+
+	uncordoned, err := h.nodeAPI.Uncordon(ctx, node, nsn)
+	if err != nil {
+		return fmt.Errorf("could not uncordon the node: %v", err)
+	}
+
+	if uncordoned {
+		ctrl.LoggerFrom(ctx).Info("Uncordoned the node")
+		h.recorder.AnnotatedEventf(
+			node,
+			map[string]string{"module": nsn},
+			v1.EventTypeNormal,
+			"NodeUncordoned",
+			"Node uncordoned after reloading module %s",
+			nsn,
+		)
+	}
+
+	return nil
+}
+
+// podsToEvict returns the Pods running on nodeName that request one of the resources listed in drain or that match
+// its selector.
+// Like kubectl drain, it ignores DaemonSet and static Pods, which cannot be evicted; it also ignores KMM worker Pods.
+func (h *nmcReconcilerHelperImpl) podsToEvict(ctx context.Context, nodeName string, drain *kmmv1beta1.DrainSpec) ([]v1.Pod, error) {
+	selector := labels.Nothing()
+
+	if drain.PodSelector != nil {
+		var err error
+
+		if selector, err = metav1.LabelSelectorAsSelector(drain.PodSelector); err != nil {
+			return nil, fmt.Errorf("invalid Pod selector: %v", err)
+		}
+	}
+
+	pl := v1.PodList{}
+
+	if err := h.client.List(ctx, &pl, client.MatchingFields{".spec.nodeName": nodeName}); err != nil {
+		return nil, fmt.Errorf("could not list Pods on node %s: %v", nodeName, err)
+	}
+
+	pods := make([]v1.Pod, 0, len(pl.Items))
+
+	for _, p := range pl.Items {
+		if p.Status.Phase == v1.PodSucceeded || p.Status.Phase == v1.PodFailed {
+			continue
+		}
+
+		if _, ok := p.Labels[actionLabelKey]; ok {
+			continue
+		}
+
+		if _, ok := p.Annotations[v1.MirrorPodAnnotationKey]; ok {
+			continue
+		}
+
+		if owner := metav1.GetControllerOf(&p); owner != nil && owner.Kind == "DaemonSet" {
+			continue
+		}
+
+		if selector.Matches(labels.Set(p.Labels)) || requestsAnyResource(&p, drain.ResourceNames) {
+			pods = append(pods, p)
+		}
+	}
+
+	return pods, nil
+}
+
+// requestsAnyResource returns true if a container of pod requests or limits one of names.
+func requestsAnyResource(pod *v1.Pod, names []v1.ResourceName) bool {
+	containers := append(slices.Clone(pod.Spec.InitContainers), pod.Spec.Containers...)
+
+	for _, c := range containers {
+		for _, name := range names {
+			if _, ok := c.Resources.Requests[name]; ok {
+				return true
+			}
+
+			if _, ok := c.Resources.Limits[name]; ok {
+				return true
+			}
+		}
+	}
+
+	return false
+}
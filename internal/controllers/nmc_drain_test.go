@@ -0,0 +1,299 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
+	testclient "github.com/rh-ecosystem-edge/kernel-module-management/internal/client"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/config"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/node"
+	"go.uber.org/mock/gomock"
+	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("nmcReconcilerHelperImpl_drain", func() {
+	const (
+		modName      = "mod"
+		resourceName = "example.com/gpu"
+	)
+
+	var (
+		ctx = context.TODO()
+
+		client       *testclient.MockClient
+		evictions    *testclient.MockSubResourceClient
+		fakeRecorder *record.FakeRecorder
+		nm           *node.MockNode
+		pm           *MockpodManager
+		h            *nmcReconcilerHelperImpl
+
+		nodeObj *v1.Node
+		spec    *kmmv1beta1.NodeModuleSpec
+		status  *kmmv1beta1.NodeModuleStatus
+		nmcObj  *kmmv1beta1.NodeModulesConfig
+		podName string
+	)
+
+	BeforeEach(func() {
+		ctrl := gomock.NewController(GinkgoT())
+		client = testclient.NewMockClient(ctrl)
+		evictions = testclient.NewMockSubResourceClient(ctrl)
+		fakeRecorder = record.NewFakeRecorder(10)
+		nm = node.NewMockNode(ctrl)
+		pm = NewMockpodManager(ctrl)
+		h = newNMCReconcilerHelper(client, pm, fakeRecorder, nm, config.DriftDetection{}).(*nmcReconcilerHelperImpl)
+
+		nodeObj = &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+		}
+
+		spec = &kmmv1beta1.NodeModuleSpec{
+			ModuleItem: kmmv1beta1.ModuleItem{Name: modName, Namespace: namespace},
+			Config: kmmv1beta1.ModuleConfig{
+				ContainerImage: "new-image",
+				KernelVersion:  "same kernel",
+			},
+			Drain: &kmmv1beta1.DrainSpec{
+				ResourceNames: []v1.ResourceName{resourceName},
+				PodSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"app": "gpu-app"},
+				},
+			},
+		}
+
+		status = &kmmv1beta1.NodeModuleStatus{
+			ModuleItem: spec.ModuleItem,
+			Config:     kmmv1beta1.ModuleConfig{ContainerImage: "old-image", KernelVersion: "same kernel"},
+		}
+
+		nmcObj = &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+		}
+
+		podName = workerPodName(nmcName, modName)
+	})
+
+	podUsingResource := v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "resource-user", Namespace: "apps"},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{
+					Resources: v1.ResourceRequirements{
+						Limits: v1.ResourceList{resourceName: resource.MustParse("1")},
+					},
+				},
+			},
+		},
+	}
+
+	expectPodList := func(pods ...v1.Pod) *gomock.Call {
+		return client.
+			EXPECT().
+			List(ctx, &v1.PodList{}, ctrlclient.MatchingFields{".spec.nodeName": nmcName}).
+			Do(func(_ context.Context, pl *v1.PodList, _ ...ctrlclient.ListOption) {
+				pl.Items = pods
+			})
+	}
+
+	It("should cordon the node and evict the Pods before creating the unloader Pod", func() {
+		gomock.InOrder(
+			pm.EXPECT().GetWorkerPod(ctx, podName, namespace),
+			nm.EXPECT().Cordon(ctx, nodeObj, namespace+"/"+modName).Return(true, nil),
+			expectPodList(podUsingResource),
+			client.EXPECT().SubResource("eviction").Return(evictions),
+			evictions.
+				EXPECT().
+				Create(ctx, &podUsingResource, &policyv1.Eviction{
+					ObjectMeta: metav1.ObjectMeta{Name: podUsingResource.Name, Namespace: podUsingResource.Namespace},
+				}),
+		)
+
+		err := h.ProcessModuleSpec(ctx, nmcObj, spec, status, nodeObj)
+		Expect(errors.Is(err, errDrainInProgress)).To(BeTrue())
+
+		Expect(fakeRecorder.Events).To(HaveLen(2))
+		Expect(<-fakeRecorder.Events).To(ContainSubstring("Normal NodeCordoned Node cordoned to reload module namespace/mod"))
+		Expect(<-fakeRecorder.Events).To(ContainSubstring("Normal PodEvicted Evicted Pod apps/resource-user to reload module namespace/mod"))
+	})
+
+	It("should create the unloader Pod once the node is drained", func() {
+		gomock.InOrder(
+			pm.EXPECT().GetWorkerPod(ctx, podName, namespace),
+			nm.EXPECT().Cordon(ctx, nodeObj, namespace+"/"+modName),
+			expectPodList(),
+			pm.EXPECT().CreateUnloaderPod(ctx, nmcObj, status),
+		)
+
+		Expect(
+			h.ProcessModuleSpec(ctx, nmcObj, spec, status, nodeObj),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(fakeRecorder.Events).To(HaveLen(1))
+		Expect(<-fakeRecorder.Events).To(ContainSubstring("Normal NodeDrained Node drained to reload module namespace/mod"))
+	})
+
+	It("should keep waiting if a PodDisruptionBudget blocks the eviction", func() {
+		terminating := v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "terminating",
+				Namespace:         "apps",
+				Labels:            map[string]string{"app": "gpu-app"},
+				DeletionTimestamp: ptr.To(metav1.Now()),
+			},
+		}
+
+		gomock.InOrder(
+			nm.EXPECT().Cordon(ctx, nodeObj, namespace+"/"+modName),
+			expectPodList(terminating, podUsingResource),
+			client.EXPECT().SubResource("eviction").Return(evictions),
+			evictions.
+				EXPECT().
+				Create(ctx, &podUsingResource, gomock.Any()).
+				Return(k8serrors.NewTooManyRequests("Cannot evict pod as it would violate the pod's disruption budget.", 10)),
+		)
+
+		Expect(
+			h.drainNode(ctx, nodeObj, spec),
+		).To(
+			MatchError(errDrainInProgress),
+		)
+
+		Expect(fakeRecorder.Events).To(HaveLen(1))
+		Expect(<-fakeRecorder.Events).To(ContainSubstring("Warning EvictionBlocked Cannot evict Pod apps/resource-user"))
+	})
+
+	It("should return an error if a Pod cannot be evicted", func() {
+		gomock.InOrder(
+			nm.EXPECT().Cordon(ctx, nodeObj, namespace+"/"+modName),
+			expectPodList(podUsingResource),
+			client.EXPECT().SubResource("eviction").Return(evictions),
+			evictions.
+				EXPECT().
+				Create(ctx, &podUsingResource, gomock.Any()).
+				Return(k8serrors.NewForbidden(schema.GroupResource{}, podUsingResource.Name, errors.New("forbidden"))),
+		)
+
+		err := h.drainNode(ctx, nodeObj, spec)
+		Expect(err).To(HaveOccurred())
+		Expect(errors.Is(err, errDrainInProgress)).To(BeFalse())
+	})
+
+	It("should return an error if the node cannot be cordoned", func() {
+		nm.EXPECT().Cordon(ctx, nodeObj, namespace+"/"+modName).Return(false, errors.New("some error"))
+
+		Expect(
+			h.drainNode(ctx, nodeObj, spec),
+		).To(
+			HaveOccurred(),
+		)
+	})
+
+	It("should uncordon the node once the module is loaded", func() {
+		status.Config = spec.Config
+
+		gomock.InOrder(
+			pm.EXPECT().GetWorkerPod(ctx, podName, namespace),
+			nm.EXPECT().Uncordon(ctx, nodeObj, namespace+"/"+modName).Return(true, nil),
+			nm.EXPECT().NodeBecomeReadyAfter(nodeObj, status.LastTransitionTime),
+		)
+
+		Expect(
+			h.ProcessModuleSpec(ctx, nmcObj, spec, status, nodeObj),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(fakeRecorder.Events).To(HaveLen(1))
+		Expect(<-fakeRecorder.Events).To(ContainSubstring("Normal NodeUncordoned Node uncordoned after reloading module namespace/mod"))
+	})
+
+	DescribeTable(
+		"podsToEvict",
+		func(pod v1.Pod, expected bool) {
+			expectPodList(pod)
+
+			pods, err := h.podsToEvict(ctx, nmcName, spec.Drain)
+			Expect(err).NotTo(HaveOccurred())
+
+			if expected {
+				Expect(pods).To(ConsistOf(pod))
+			} else {
+				Expect(pods).To(BeEmpty())
+			}
+		},
+		Entry("Pod requesting the resource", podUsingResource, true),
+		Entry(
+			"Pod matching the selector",
+			v1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "gpu-app"}}},
+			true,
+		),
+		Entry(
+			"init container requesting the resource",
+			v1.Pod{
+				Spec: v1.PodSpec{
+					InitContainers: []v1.Container{
+						{
+							Resources: v1.ResourceRequirements{
+								Requests: v1.ResourceList{resourceName: resource.MustParse("1")},
+							},
+						},
+					},
+				},
+			},
+			true,
+		),
+		Entry("unrelated Pod", v1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "other"}}}, false),
+		Entry(
+			"completed Pod",
+			v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "gpu-app"}},
+				Status:     v1.PodStatus{Phase: v1.PodSucceeded},
+			},
+			false,
+		),
+		Entry(
+			"worker Pod",
+			v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"app": "gpu-app", actionLabelKey: WorkerActionLoad},
+				},
+			},
+			false,
+		),
+		Entry(
+			"static Pod",
+			v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      map[string]string{"app": "gpu-app"},
+					Annotations: map[string]string{v1.MirrorPodAnnotationKey: "some-hash"},
+				},
+			},
+			false,
+		),
+		Entry(
+			"DaemonSet Pod",
+			v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"app": "gpu-app"},
+					OwnerReferences: []metav1.OwnerReference{
+						{Kind: "DaemonSet", Name: "ds", Controller: ptr.To(true)},
+					},
+				},
+			},
+			false,
+		),
+	)
+})
@@ -2,17 +2,22 @@ package controllers
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"path/filepath"
 	"reflect"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/mitchellh/hashstructure/v2"
 	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/config"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/constants"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/filter"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/maintenance"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/meta"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/nmc"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/node"
@@ -21,6 +26,7 @@ import (
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/worker"
 	v1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -41,8 +47,10 @@ import (
 type WorkerAction string
 
 const (
-	WorkerActionLoad   = "Load"
-	WorkerActionUnload = "Unload"
+	WorkerActionCheck         = "Check"
+	WorkerActionLoad          = "Load"
+	WorkerActionSetParameters = "SetParameters"
+	WorkerActionUnload        = "Unload"
 
 	NodeModulesConfigReconcilerName = "NodeModulesConfig"
 
@@ -63,7 +71,7 @@ const (
 //+kubebuilder:rbac:groups=kmm.sigs.x-k8s.io,resources=nodemodulesconfigs/finalizers,verbs=patch;update
 //+kubebuilder:rbac:groups="core",resources=configmaps,verbs=get;list;watch
 //+kubebuilder:rbac:groups="core",resources=pods,verbs=create;delete;get;list;watch
-//+kubebuilder:rbac:groups="core",resources=nodes,verbs=get;list;watch
+//+kubebuilder:rbac:groups="core",resources=nodes,verbs=get;list;patch;watch
 //+kubebuilder:rbac:groups="core",resources=secrets,verbs=get;list;watch
 //+kubebuilder:rbac:groups="core",resources=serviceaccounts,verbs=get;list;watch
 
@@ -71,6 +79,8 @@ type NMCReconciler struct {
 	client  client.Client
 	helper  nmcReconcilerHelper
 	nodeAPI node.Node
+
+	driftCheckInterval time.Duration
 }
 
 func NewNMCReconciler(
@@ -83,11 +93,12 @@ func NewNMCReconciler(
 	nodeAPI node.Node,
 ) *NMCReconciler {
 	pm := newPodManager(client, workerImage, scheme, caHelper, workerCfg)
-	helper := newNMCReconcilerHelper(client, pm, recorder, nodeAPI)
+	helper := newNMCReconcilerHelper(client, pm, recorder, nodeAPI, workerCfg.DriftDetection)
 	return &NMCReconciler{
-		client:  client,
-		helper:  helper,
-		nodeAPI: nodeAPI,
+		client:             client,
+		helper:             helper,
+		nodeAPI:            nodeAPI,
+		driftCheckInterval: workerCfg.DriftDetection.Interval,
 	}
 }
 
@@ -112,7 +123,12 @@ func (r *NMCReconciler) Reconcile(ctx context.Context, req reconcile.Request) (r
 		return reconcile.Result{}, fmt.Errorf("could not get NodeModuleState %s: %v", req.NamespacedName, err)
 	}
 
-	if err := r.helper.SyncStatus(ctx, &nmcObj); err != nil {
+	node := v1.Node{}
+	if err := r.client.Get(ctx, types.NamespacedName{Name: nmcObj.Name}, &node); err != nil {
+		return ctrl.Result{}, fmt.Errorf("could not get node %s: %v", nmcObj.Name, err)
+	}
+
+	if err := r.helper.SyncStatus(ctx, &nmcObj, &node); err != nil {
 		return reconcile.Result{}, fmt.Errorf("could not reconcile status for NodeModulesConfig %s: %v", nmcObj.Name, err)
 	}
 
@@ -125,12 +141,14 @@ func (r *NMCReconciler) Reconcile(ctx context.Context, req reconcile.Request) (r
 		statusMap[status.Namespace+"/"+status.Name] = &nmcObj.Status.Modules[i]
 	}
 
-	node := v1.Node{}
-	if err := r.client.Get(ctx, types.NamespacedName{Name: nmcObj.Name}, &node); err != nil {
-		return ctrl.Result{}, fmt.Errorf("could not get node %s: %v", nmcObj.Name, err)
-	}
-
 	errs := make([]error, 0, len(nmcObj.Spec.Modules)+len(nmcObj.Status.Modules))
+	var requeueAfter time.Duration
+
+	requeueAt := func(d time.Duration) {
+		if requeueAfter == 0 || d < requeueAfter {
+			requeueAfter = d
+		}
+	}
 
 	for _, mod := range nmcObj.Spec.Modules {
 		moduleNameKey := mod.Namespace + "/" + mod.Name
@@ -143,7 +161,15 @@ func (r *NMCReconciler) Reconcile(ctx context.Context, req reconcile.Request) (r
 			continue
 		}
 
-		if err := r.helper.ProcessModuleSpec(ctrl.LoggerInto(ctx, logger), &nmcObj, &mod, statusMap[moduleNameKey], &node); err != nil {
+		var windowErr *maintenanceWindowClosedError
+
+		if err := r.helper.ProcessModuleSpec(ctrl.LoggerInto(ctx, logger), &nmcObj, &mod, statusMap[moduleNameKey], &node); errors.Is(err, errDrainInProgress) {
+			logger.Info("Waiting for the node to be drained")
+			requeueAt(drainRequeueDelay)
+		} else if errors.As(err, &windowErr) {
+			logger.Info("Waiting for the maintenance window to reload the module", "opensAt", windowErr.opensAt)
+			requeueAt(max(time.Until(windowErr.opensAt), time.Second))
+		} else if err != nil {
 			errs = append(
 				errs,
 				fmt.Errorf("error processing Module %s: %v", moduleNameKey, err),
@@ -179,7 +205,17 @@ func (r *NMCReconciler) Reconcile(ctx context.Context, req reconcile.Request) (r
 		r.helper.RecordEvents(&node, loaded, unloaded)
 	}
 
-	return ctrl.Result{}, errors.Join(errs...)
+	if err := errors.Join(errs...); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if r.driftCheckInterval > 0 {
+		if next := nextDriftCheck(&nmcObj, r.driftCheckInterval); !next.IsZero() {
+			requeueAt(max(time.Until(next), time.Second))
+		}
+	}
+
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
 
 func (r *NMCReconciler) SetupWithManager(ctx context.Context, mgr manager.Manager) error {
@@ -233,7 +269,7 @@ type nmcReconcilerHelper interface {
 	ProcessModuleSpec(ctx context.Context, nmc *kmmv1beta1.NodeModulesConfig, spec *kmmv1beta1.NodeModuleSpec, status *kmmv1beta1.NodeModuleStatus, node *v1.Node) error
 	ProcessUnconfiguredModuleStatus(ctx context.Context, nmc *kmmv1beta1.NodeModulesConfig, status *kmmv1beta1.NodeModuleStatus, node *v1.Node) error
 	RemovePodFinalizers(ctx context.Context, nodeName string) error
-	SyncStatus(ctx context.Context, nmc *kmmv1beta1.NodeModulesConfig) error
+	SyncStatus(ctx context.Context, nmc *kmmv1beta1.NodeModulesConfig, node *v1.Node) error
 	UpdateNodeLabels(ctx context.Context, nmc *kmmv1beta1.NodeModulesConfig, node *v1.Node) ([]types.NamespacedName, []types.NamespacedName, error)
 	RecordEvents(node *v1.Node, loadedModules, unloadedModules []types.NamespacedName)
 }
@@ -244,15 +280,23 @@ type nmcReconcilerHelperImpl struct {
 	recorder record.EventRecorder
 	nodeAPI  node.Node
 	lph      labelPreparationHelper
+	driftCfg config.DriftDetection
 }
 
-func newNMCReconcilerHelper(client client.Client, pm podManager, recorder record.EventRecorder, nodeAPI node.Node) nmcReconcilerHelper {
+func newNMCReconcilerHelper(
+	client client.Client,
+	pm podManager,
+	recorder record.EventRecorder,
+	nodeAPI node.Node,
+	driftCfg config.DriftDetection,
+) nmcReconcilerHelper {
 	return &nmcReconcilerHelperImpl{
 		client:   client,
 		pm:       pm,
 		recorder: recorder,
 		nodeAPI:  nodeAPI,
 		lph:      newLabelPreparationHelper(),
+		driftCfg: driftCfg,
 	}
 }
 
@@ -312,7 +356,11 @@ func (h *nmcReconcilerHelperImpl) GarbageCollectInUseLabels(ctx context.Context,
 //   - there is no corresponding entry in the NodeModulesConfig's .status.modules list;
 //   - the lastTransitionTime property in the .status.modules entry is older that the last transition time
 //     of the Ready condition on the node. This makes sure that we always load modules after maintenance operations
-//     that would make a node not Ready, such as a reboot.
+//     that would make a node not Ready, such as a reboot;
+//   - the last drift check found that the module is not loaded anymore, if drifted modules are reloaded.
+//
+// If drift detection is enabled, a drift check Pod is created once the last check of a loaded module is older than
+// the check interval.
 //
 // An unloading worker Pod is created when the entry in .spec.modules has a different config compared to the entry in
 // .status.modules.
@@ -345,6 +393,42 @@ func (h *nmcReconcilerHelperImpl) ProcessModuleSpec(
 		*/
 		if !reflect.DeepEqual(spec.Config, status.Config) {
 			if spec.Config.KernelVersion == status.Config.KernelVersion {
+				if onlyParametersChanged(&spec.Config, &status.Config) {
+					r := nmc.FindWorkerResult(nmcObj.Status.WorkerResults, spec.Namespace, spec.Name)
+
+					// Only a failure to apply the same parameters means that they cannot be updated through sysfs.
+					if r == nil ||
+						r.Action != worker.ActionSetParameters ||
+						r.Error == "" ||
+						!slices.Equal(r.RequestedParameters, spec.Config.Modprobe.Parameters) {
+						logger.Info("Only the module parameters changed; creating parameters updater Pod")
+						return h.pm.CreateParametersUpdaterPod(ctx, nmcObj, spec)
+					}
+
+					logger.Info(
+						"Could not update the parameters without reloading the module",
+						"category",
+						r.ErrorCategory,
+						"error",
+						r.Error,
+					)
+				}
+
+				open, opensAt, err := maintenance.IsOpen(spec.MaintenanceWindow, time.Now())
+				if err != nil {
+					return fmt.Errorf("invalid maintenance window: %v", err)
+				}
+
+				if !open {
+					return &maintenanceWindowClosedError{opensAt: opensAt}
+				}
+
+				if spec.Drain != nil {
+					if err = h.drainNode(ctx, node, spec); err != nil {
+						return fmt.Errorf("could not drain the node: %w", err)
+					}
+				}
+
 				logger.Info("Outdated config in status; creating unloader Pod")
 				return h.pm.CreateUnloaderPod(ctx, nmcObj, status)
 			}
@@ -352,11 +436,25 @@ func (h *nmcReconcilerHelperImpl) ProcessModuleSpec(
 			return h.pm.CreateLoaderPod(ctx, nmcObj, spec)
 		}
 
-		if h.nodeAPI.NodeBecomeReadyAfter(node, status.LastTransitionTime) {
-			logger.Info("node has been rebooted and become ready after kernel module was loaded; creating loader Pod")
+		if err = h.uncordonNode(ctx, node, &spec.ModuleItem); err != nil {
+			return err
+		}
+
+		if h.nodeRebooted(node, status) {
+			logger.Info("node has been rebooted after kernel module was loaded; creating loader Pod")
+			return h.pm.CreateLoaderPod(ctx, nmcObj, spec)
+		}
+
+		if h.driftCfg.Reload && apimeta.IsStatusConditionTrue(status.Conditions, kmmv1beta1.ModuleConditionDrifted) {
+			logger.Info("The module is not loaded anymore; creating loader Pod")
 			return h.pm.CreateLoaderPod(ctx, nmcObj, spec)
 		}
 
+		if h.driftCfg.Interval > 0 && !time.Now().Before(lastDriftCheck(status).Add(h.driftCfg.Interval)) {
+			logger.Info("Creating drift check Pod")
+			return h.pm.CreateDriftCheckPod(ctx, nmcObj, spec)
+		}
+
 		return nil
 	}
 
@@ -386,8 +484,8 @@ func (h *nmcReconcilerHelperImpl) ProcessModuleSpec(
 // ProcessUnconfiguredModuleStatus cleans up a NodeModuleStatus.
 // It should be called for each status entry for which the NodeModulesConfigs does not have a spec entry; this means
 // that KMM wants the module unloaded from the node.
-// If status.Config field is nil, then it represents a module that could not be loaded by a worker Pod.
-// ProcessUnconfiguredModuleStatus will then remove status from nmcObj's Status.Modules.
+// If status.Config field is empty, then it represents a module that could not be loaded by a worker Pod.
+// SyncStatus will then have removed status from nmcObj's Status.Modules.
 // If status.Config is not nil, it means that the module was successfully loaded.
 // ProcessUnconfiguredModuleStatus will then create a worker pod to unload the module.
 func (h *nmcReconcilerHelperImpl) ProcessUnconfiguredModuleStatus(
@@ -400,10 +498,15 @@ func (h *nmcReconcilerHelperImpl) ProcessUnconfiguredModuleStatus(
 
 	logger := ctrl.LoggerFrom(ctx).WithValues("pod name", podName)
 
+	// The module is not reloaded anymore.
+	if err := h.uncordonNode(ctx, node, &status.ModuleItem); err != nil {
+		return err
+	}
+
 	/* node was rebooted, spec not set so no kernel module is loaded, no need to unload.
 	   it also fixes the scenario when node's kernel was upgraded, so unload pod will fail anyway
 	*/
-	if h.nodeAPI.NodeBecomeReadyAfter(node, status.LastTransitionTime) {
+	if h.nodeRebooted(node, status) {
 		logger.Info("node was rebooted, no need to unload kernel module that is not present in kernel, will wait until NMC spec is updated")
 		return nil
 	}
@@ -469,7 +572,7 @@ func (h *nmcReconcilerHelperImpl) RemovePodFinalizers(ctx context.Context, nodeN
 	return errors.Join(errs...)
 }
 
-func (h *nmcReconcilerHelperImpl) SyncStatus(ctx context.Context, nmcObj *kmmv1beta1.NodeModulesConfig) error {
+func (h *nmcReconcilerHelperImpl) SyncStatus(ctx context.Context, nmcObj *kmmv1beta1.NodeModulesConfig, node *v1.Node) error {
 	logger := ctrl.LoggerFrom(ctx)
 
 	logger.Info("Syncing status")
@@ -481,10 +584,6 @@ func (h *nmcReconcilerHelperImpl) SyncStatus(ctx context.Context, nmcObj *kmmv1b
 
 	logger.V(1).Info("List worker Pods", "count", len(pods))
 
-	if len(pods) == 0 {
-		return nil
-	}
-
 	specEntries := sets.New[types.NamespacedName]()
 
 	for _, e := range nmcObj.Spec.Modules {
@@ -492,6 +591,20 @@ func (h *nmcReconcilerHelperImpl) SyncStatus(ctx context.Context, nmcObj *kmmv1b
 	}
 
 	patchFrom := client.MergeFrom(nmcObj.DeepCopy())
+
+	// Pending entries are not needed anymore once the module is not configured on the node.
+	pendingCount := len(nmcObj.Status.PendingModules)
+
+	nmcObj.Status.PendingModules = slices.DeleteFunc(nmcObj.Status.PendingModules, func(p kmmv1beta1.NodeModulePendingStatus) bool {
+		return !specEntries.Has(types.NamespacedName{Namespace: p.Namespace, Name: p.Name})
+	})
+
+	bootIDsRecorded := h.recordMissingBootIDs(nmcObj, node)
+
+	if len(pods) == 0 && len(nmcObj.Status.PendingModules) == pendingCount && !bootIDsRecorded {
+		return nil
+	}
+
 	errs := make([]error, 0, len(pods))
 	podsToDelete := make([]v1.Pod, 0, len(pods))
 
@@ -506,8 +619,43 @@ func (h *nmcReconcilerHelperImpl) SyncStatus(ctx context.Context, nmcObj *kmmv1b
 
 		logger.Info("Processing worker Pod")
 
+		if p.Labels[actionLabelKey] == WorkerActionCheck {
+			if h.syncDriftCheck(ctrl.LoggerInto(ctx, logger), nmcObj, &p) {
+				podsToDelete = append(podsToDelete, p)
+			}
+
+			continue
+		}
+
+		res := h.syncWorkerResult(ctrl.LoggerInto(ctx, logger), nmcObj, &p)
+
 		status := nmc.FindModuleStatus(nmcObj.Status.Modules, modNamespace, modName)
 
+		var progress *kmmv1beta1.NodeModuleProgress
+
+		if status != nil {
+			progress = &status.NodeModuleProgress
+		} else if p.Labels[actionLabelKey] != WorkerActionUnload &&
+			(phase == v1.PodSucceeded || specEntries.Has(types.NamespacedName{Namespace: modNamespace, Name: modName})) {
+			// The module was not loaded yet; report the progress of the worker Pod in a pending entry.
+			pending := nmc.FindPendingModule(nmcObj.Status.PendingModules, modNamespace, modName)
+
+			if pending == nil {
+				nmcObj.Status.PendingModules = append(
+					nmcObj.Status.PendingModules,
+					kmmv1beta1.NodeModulePendingStatus{Name: modName, Namespace: modNamespace},
+				)
+
+				pending = &nmcObj.Status.PendingModules[len(nmcObj.Status.PendingModules)-1]
+			}
+
+			progress = &pending.NodeModuleProgress
+		}
+
+		if progress != nil && !(phase == v1.PodSucceeded && p.Labels[actionLabelKey] == WorkerActionUnload) {
+			syncModuleConditions(progress, &p, res)
+		}
+
 		switch phase {
 		case v1.PodRunning:
 			// Delete Pod if orphan
@@ -522,6 +670,7 @@ func (h *nmcReconcilerHelperImpl) SyncStatus(ctx context.Context, nmcObj *kmmv1b
 			if p.Labels[actionLabelKey] == WorkerActionUnload {
 				podsToDelete = append(podsToDelete, p)
 				nmc.RemoveModuleStatus(&nmcObj.Status.Modules, modNamespace, modName)
+				nmc.RemoveWorkerResult(&nmcObj.Status.WorkerResults, modNamespace, modName)
 				break
 			}
 
@@ -531,6 +680,7 @@ func (h *nmcReconcilerHelperImpl) SyncStatus(ctx context.Context, nmcObj *kmmv1b
 						Name:      modName,
 						Namespace: modNamespace,
 					},
+					NodeModuleProgress: *progress,
 				}
 			}
 
@@ -555,12 +705,42 @@ func (h *nmcReconcilerHelperImpl) SyncStatus(ctx context.Context, nmcObj *kmmv1b
 
 			status.ServiceAccountName = p.Spec.ServiceAccountName
 
-			status.LastTransitionTime = GetContainerStatus(p.Status.ContainerStatuses, workerContainerName).
-				State.
-				Terminated.
-				FinishedAt
+			// The module was not reloaded; keep the time at which it was loaded.
+			if p.Labels[actionLabelKey] != WorkerActionSetParameters || status.LastTransitionTime.IsZero() {
+				status.LastTransitionTime = GetContainerStatus(p.Status.ContainerStatuses, workerContainerName).
+					State.
+					Terminated.
+					FinishedAt
+			}
+
+			// Updating the parameters does not touch the blacklist.
+			if p.Labels[actionLabelKey] == WorkerActionLoad {
+				// The node may have rebooted since the worker loaded the module, so only trust the boot ID it read.
+				// Without one, the boot ID is recorded later if the node did not become Ready after the module was
+				// loaded.
+				status.BootID = ""
+				status.BlacklistedModules = nil
+
+				// A module that was removed by a previous loader Pod is not loaded anymore, for example because it was
+				// blacklisted; keep it so that it can still be restored.
+				removed := sets.New(status.RemovedInTreeModules...).
+					Intersection(sets.New(nmc.InTreeModulesToRemove(&status.Config)...))
+
+				if res != nil {
+					status.BootID = res.BootID
+					status.BlacklistedModules = res.BlacklistedModules
+					removed.Insert(res.RemovedInTreeModules...)
+				}
+
+				status.RemovedInTreeModules = nil
+
+				if removed.Len() > 0 {
+					status.RemovedInTreeModules = sets.List(removed)
+				}
+			}
 
 			nmc.SetModuleStatus(&nmcObj.Status.Modules, *status)
+			nmc.RemovePendingModule(&nmcObj.Status.PendingModules, modNamespace, modName)
 
 			podsToDelete = append(podsToDelete, p)
 		}
@@ -584,6 +764,439 @@ func (h *nmcReconcilerHelperImpl) SyncStatus(ctx context.Context, nmcObj *kmmv1b
 	return errors.Join(errs...)
 }
 
+// workerTermination returns the last termination state of the worker container, if any.
+// For Pods that are still running, this is the state of the previous attempt.
+func workerTermination(pod *v1.Pod) *v1.ContainerStateTerminated {
+	cs := GetContainerStatus(pod.Status.ContainerStatuses, workerContainerName)
+
+	if cs.State.Terminated != nil {
+		return cs.State.Terminated
+	}
+
+	return cs.LastTerminationState.Terminated
+}
+
+// syncWorkerResult copies the result written by the worker in its termination message into the NMC status, and
+// returns it; it returns nil if the Pod has no result.
+// A Warning event is recorded on the node the first time a failed result is seen.
+func (h *nmcReconcilerHelperImpl) syncWorkerResult(ctx context.Context, nmcObj *kmmv1beta1.NodeModulesConfig, pod *v1.Pod) *kmmv1beta1.WorkerResult {
+	logger := ctrl.LoggerFrom(ctx)
+
+	term := workerTermination(pod)
+	if term == nil || term.Message == "" {
+		return nil
+	}
+
+	res := kmmv1beta1.WorkerResult{}
+
+	if err := json.Unmarshal([]byte(term.Message), &res); err != nil {
+		logger.Info(utils.WarnString("Could not decode the worker termination message"), "error", err)
+		return nil
+	}
+
+	modNamespace := pod.Namespace
+	modName := pod.Labels[constants.ModuleNameLabel]
+
+	if r := nmc.FindWorkerResult(nmcObj.Status.WorkerResults, modNamespace, modName); r != nil && r.PodName == pod.Name && r.Time.Equal(&term.FinishedAt) {
+		// already recorded
+		return &res
+	}
+
+	nmr := kmmv1beta1.NodeModuleWorkerResult{
+		Name:         modName,
+		Namespace:    modNamespace,
+		PodName:      pod.Name,
+		Time:         term.FinishedAt,
+		WorkerResult: res,
+	}
+
+	if pod.Labels[actionLabelKey] == WorkerActionSetParameters {
+		cfg := kmmv1beta1.ModuleConfig{}
+
+		if err := yaml.UnmarshalStrict([]byte(pod.Annotations[configAnnotationKey]), &cfg); err != nil {
+			logger.Info(utils.WarnString("Could not unmarshal the ModuleConfig of the parameters updater Pod"), "error", err)
+		} else {
+			nmr.RequestedParameters = cfg.Modprobe.Parameters
+		}
+	}
+
+	nmc.SetWorkerResult(&nmcObj.Status.WorkerResults, nmr)
+
+	// NMC name == node name
+	node := v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: nmcObj.Name},
+	}
+
+	nsn := types.NamespacedName{Namespace: modNamespace, Name: modName}
+
+	if res.InTreeModulesRestoreError != "" {
+		h.recorder.AnnotatedEventf(
+			&node,
+			map[string]string{"module": nsn.String()},
+			v1.EventTypeWarning,
+			"InTreeModulesRestoreFailed",
+			"Could not restore the in-tree modules replaced by module %s: %s",
+			nsn.String(),
+			res.InTreeModulesRestoreError,
+		)
+	}
+
+	if res.Error == "" {
+		return &res
+	}
+
+	reason := "ModuleLoadFailed"
+	verb := res.Action
+
+	switch res.Action {
+	case worker.ActionSetParameters:
+		reason = "ModuleParametersUpdateFailed"
+		verb = "update the parameters of"
+	case worker.ActionUnload:
+		reason = "ModuleUnloadFailed"
+	}
+
+	h.recorder.AnnotatedEventf(
+		&node,
+		map[string]string{"module": nsn.String(), "errorCategory": res.ErrorCategory},
+		v1.EventTypeWarning,
+		reason,
+		"Could not %s module %s (%s): %s",
+		verb,
+		nsn.String(),
+		res.ErrorCategory,
+		res.Error,
+	)
+
+	return &res
+}
+
+// imagePullFailure returns the error of the init container that pulls the module image, if it failed.
+// It also returns the number of times that container was restarted.
+func imagePullFailure(pod *v1.Pod) (string, int32) {
+	cs := GetContainerStatus(pod.Status.InitContainerStatuses, initContainerName)
+
+	if w := cs.State.Waiting; w != nil {
+		switch w.Reason {
+		case "ErrImagePull", "ImagePullBackOff", "InvalidImageName":
+			return fmt.Sprintf("%s: %s", w.Reason, w.Message), cs.RestartCount
+		}
+	}
+
+	term := cs.State.Terminated
+	if term == nil {
+		term = cs.LastTerminationState.Terminated
+	}
+
+	if term == nil || term.ExitCode == 0 {
+		return "", 0
+	}
+
+	if term.Message != "" {
+		return term.Message, cs.RestartCount
+	}
+
+	return fmt.Sprintf("%s container exited with code %d", initContainerName, term.ExitCode), cs.RestartCount
+}
+
+// workerFailureReason returns the reason of the Failed condition for a worker result that holds an error.
+func workerFailureReason(res *kmmv1beta1.WorkerResult) string {
+	switch {
+	case res.ErrorCategory == worker.CategoryFirmwareCopyFailed:
+		return kmmv1beta1.ModuleReasonFirmwareCopyFailed
+	case res.Action == worker.ActionSetParameters:
+		return kmmv1beta1.ModuleReasonParametersUpdateFailed
+	default:
+		return kmmv1beta1.ModuleReasonModprobeFailed
+	}
+}
+
+// syncModuleConditions updates the conditions of progress from the state of its worker Pod and from the result the
+// worker reported, if any.
+// Unloader Pods that succeeded are not handled, as the status entry is removed.
+func syncModuleConditions(progress *kmmv1beta1.NodeModuleProgress, pod *v1.Pod, res *kmmv1beta1.WorkerResult) {
+	progressType := kmmv1beta1.ModuleConditionLoading
+	if pod.Labels[actionLabelKey] == WorkerActionUnload {
+		progressType = kmmv1beta1.ModuleConditionUnloading
+	}
+
+	var (
+		failureReason  string
+		failureMessage string
+		restartCount   = GetContainerStatus(pod.Status.ContainerStatuses, workerContainerName).RestartCount
+	)
+
+	if msg, count := imagePullFailure(pod); msg != "" {
+		failureReason = kmmv1beta1.ModuleReasonImagePullFailed
+		failureMessage = msg
+		restartCount = count
+	} else if res != nil && res.Error != "" {
+		failureReason = workerFailureReason(res)
+		failureMessage = res.Error
+	} else if pod.Status.Phase == v1.PodFailed {
+		failureReason = kmmv1beta1.ModuleReasonWorkerPodFailed
+		failureMessage = pod.Status.Message
+
+		if term := workerTermination(pod); term != nil && term.Message != "" {
+			failureMessage = term.Message
+		}
+	}
+
+	// SetStatusCondition uses the current time if the transition time is not set.
+	var transitionTime metav1.Time
+
+	if term := workerTermination(pod); term != nil && pod.Status.Phase == v1.PodSucceeded {
+		transitionTime = term.FinishedAt
+	}
+
+	setCondition := func(condType string, condStatus metav1.ConditionStatus, reason, message string) {
+		apimeta.SetStatusCondition(&progress.Conditions, metav1.Condition{
+			Type:               condType,
+			Status:             condStatus,
+			LastTransitionTime: transitionTime,
+			Reason:             reason,
+			Message:            message,
+		})
+	}
+
+	switch {
+	case pod.Status.Phase == v1.PodSucceeded:
+		setCondition(kmmv1beta1.ModuleConditionLoaded, metav1.ConditionTrue, kmmv1beta1.ModuleReasonWorkerPodSucceeded, "")
+		setCondition(progressType, metav1.ConditionFalse, kmmv1beta1.ModuleReasonWorkerPodSucceeded, "")
+		setCondition(kmmv1beta1.ModuleConditionFailed, metav1.ConditionFalse, kmmv1beta1.ModuleReasonWorkerPodSucceeded, "")
+
+		// The module was loaded again.
+		if pod.Labels[actionLabelKey] == WorkerActionLoad && apimeta.FindStatusCondition(progress.Conditions, kmmv1beta1.ModuleConditionDrifted) != nil {
+			setCondition(kmmv1beta1.ModuleConditionDrifted, metav1.ConditionFalse, kmmv1beta1.ModuleReasonWorkerPodSucceeded, "")
+		}
+
+		progress.WorkerPodName = ""
+		progress.WorkerRestartCount = 0
+		progress.LastError = ""
+
+		return
+	case pod.Status.Phase == v1.PodFailed:
+		setCondition(progressType, metav1.ConditionFalse, kmmv1beta1.ModuleReasonWorkerPodFailed, "")
+	case pod.Status.Phase == v1.PodPending:
+		setCondition(progressType, metav1.ConditionTrue, kmmv1beta1.ModuleReasonWorkerPodPending, "")
+	default:
+		setCondition(progressType, metav1.ConditionTrue, kmmv1beta1.ModuleReasonWorkerPodRunning, "")
+	}
+
+	// Updating the parameters does not unload the module.
+	if pod.Labels[actionLabelKey] == WorkerActionLoad {
+		setCondition(kmmv1beta1.ModuleConditionLoaded, metav1.ConditionFalse, apimeta.FindStatusCondition(progress.Conditions, progressType).Reason, "")
+	}
+
+	if failureReason == "" {
+		return
+	}
+
+	setCondition(kmmv1beta1.ModuleConditionFailed, metav1.ConditionTrue, failureReason, failureMessage)
+
+	progress.WorkerPodName = pod.Name
+	progress.WorkerRestartCount = restartCount
+	progress.LastError = failureMessage
+}
+
+// nodeRebooted returns true if node was rebooted since the module of status was loaded.
+// Statuses that do not hold a boot ID fall back to the time at which the node last became Ready.
+func (h *nmcReconcilerHelperImpl) nodeRebooted(node *v1.Node, status *kmmv1beta1.NodeModuleStatus) bool {
+	if status.BootID == "" || node.Status.NodeInfo.BootID == "" {
+		return h.nodeAPI.NodeBecomeReadyAfter(node, status.LastTransitionTime)
+	}
+
+	return status.BootID != node.Status.NodeInfo.BootID
+}
+
+// recordMissingBootIDs sets the current boot ID of node in the statuses of loaded modules that were written before
+// boot IDs were recorded.
+// Statuses of modules loaded before the node last became Ready are left untouched; those modules are reloaded, which
+// records the boot ID.
+// It returns true if any status was changed.
+func (h *nmcReconcilerHelperImpl) recordMissingBootIDs(nmcObj *kmmv1beta1.NodeModulesConfig, node *v1.Node) bool {
+	bootID := node.Status.NodeInfo.BootID
+	if bootID == "" {
+		return false
+	}
+
+	changed := false
+
+	for i := range nmcObj.Status.Modules {
+		status := &nmcObj.Status.Modules[i]
+
+		if status.BootID != "" || h.nodeAPI.NodeBecomeReadyAfter(node, status.LastTransitionTime) {
+			continue
+		}
+
+		status.BootID = bootID
+		changed = true
+	}
+
+	return changed
+}
+
+// syncDriftCheck updates the status of the module checked by pod once it has completed.
+// If the check found that the module is not loaded anymore, the Drifted condition is set and a Warning event is
+// recorded on the node.
+// It returns true if pod should be deleted.
+func (h *nmcReconcilerHelperImpl) syncDriftCheck(ctx context.Context, nmcObj *kmmv1beta1.NodeModulesConfig, pod *v1.Pod) bool {
+	logger := ctrl.LoggerFrom(ctx)
+
+	phase := pod.Status.Phase
+
+	if phase != v1.PodSucceeded && phase != v1.PodFailed {
+		return false
+	}
+
+	modNamespace := pod.Namespace
+	modName := pod.Labels[constants.ModuleNameLabel]
+
+	status := nmc.FindModuleStatus(nmcObj.Status.Modules, modNamespace, modName)
+	if status == nil {
+		logger.Info("The module is not loaded anymore; ignoring the drift check")
+		return true
+	}
+
+	checkTime := metav1.Now()
+
+	term := workerTermination(pod)
+	if term != nil {
+		checkTime = term.FinishedAt
+	}
+
+	status.LastDriftCheckTime = &checkTime
+
+	if phase == v1.PodSucceeded {
+		apimeta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:   kmmv1beta1.ModuleConditionDrifted,
+			Status: metav1.ConditionFalse,
+			Reason: kmmv1beta1.ModuleReasonModuleLive,
+		})
+
+		return true
+	}
+
+	res := kmmv1beta1.WorkerResult{}
+
+	if term == nil || json.Unmarshal([]byte(term.Message), &res) != nil || res.ErrorCategory != worker.CategoryModuleNotLoaded {
+		// Could not check the module; try again at the next interval.
+		logger.Info(utils.WarnString("The drift check failed"), "message", pod.Status.Message)
+		return true
+	}
+
+	logger.Info("The module is not loaded anymore", "error", res.Error)
+
+	if apimeta.IsStatusConditionTrue(status.Conditions, kmmv1beta1.ModuleConditionDrifted) {
+		return true
+	}
+
+	apimeta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               kmmv1beta1.ModuleConditionDrifted,
+		Status:             metav1.ConditionTrue,
+		LastTransitionTime: checkTime,
+		Reason:             kmmv1beta1.ModuleReasonModuleNotLoaded,
+		Message:            res.Error,
+	})
+
+	apimeta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               kmmv1beta1.ModuleConditionLoaded,
+		Status:             metav1.ConditionFalse,
+		LastTransitionTime: checkTime,
+		Reason:             kmmv1beta1.ModuleReasonModuleNotLoaded,
+		Message:            res.Error,
+	})
+
+	nsn := types.NamespacedName{Namespace: modNamespace, Name: modName}
+
+	// NMC name == node name
+	node := v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: nmcObj.Name},
+	}
+
+	h.recorder.AnnotatedEventf(
+		&node,
+		map[string]string{"module": nsn.String()},
+		v1.EventTypeWarning,
+		"ModuleDrifted",
+		"Module %s is not loaded in the kernel anymore: %s",
+		nsn.String(),
+		res.Error,
+	)
+
+	return true
+}
+
+// lastDriftCheck returns the last time the module of status was checked, or loaded if it was not checked since.
+func lastDriftCheck(status *kmmv1beta1.NodeModuleStatus) time.Time {
+	if t := status.LastDriftCheckTime; t != nil && t.After(status.LastTransitionTime.Time) {
+		return t.Time
+	}
+
+	return status.LastTransitionTime.Time
+}
+
+// nextDriftCheck returns the time at which the next drift check is due for the modules loaded on nmcObj's node, or the
+// zero time if no module is loaded.
+func nextDriftCheck(nmcObj *kmmv1beta1.NodeModulesConfig, interval time.Duration) time.Time {
+	var next time.Time
+
+	for i := range nmcObj.Status.Modules {
+		status := &nmcObj.Status.Modules[i]
+
+		if t := lastDriftCheck(status).Add(interval); next.IsZero() || t.Before(next) {
+			next = t
+		}
+	}
+
+	return next
+}
+
+// maintenanceWindowClosedError is returned by ProcessModuleSpec when reloading a module must wait for its maintenance
+// window to open.
+type maintenanceWindowClosedError struct {
+	opensAt time.Time
+}
+
+func (e *maintenanceWindowClosedError) Error() string {
+	return fmt.Sprintf("the maintenance window is closed until %s", e.opensAt.UTC().Format(time.RFC3339))
+}
+
+// onlyParametersChanged returns true if the two configurations differ only by their module parameters, and if none
+// of the current parameters was removed; a removed parameter cannot be reset to its default value without reloading
+// the module.
+func onlyParametersChanged(spec, status *kmmv1beta1.ModuleConfig) bool {
+	if spec.Modprobe.RawArgs != nil || status.Modprobe.RawArgs != nil {
+		return false
+	}
+
+	specCopy := spec.DeepCopy()
+	specCopy.Modprobe.Parameters = status.Modprobe.Parameters
+
+	if !reflect.DeepEqual(*specCopy, *status) {
+		return false
+	}
+
+	names := sets.New[string]()
+
+	for _, p := range spec.Modprobe.Parameters {
+		names.Insert(moduleParameterName(p))
+	}
+
+	for _, p := range status.Modprobe.Parameters {
+		if !names.Has(moduleParameterName(p)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func moduleParameterName(p string) string {
+	name, _, _ := strings.Cut(p, "=")
+	return strings.ReplaceAll(name, "-", "_")
+}
+
 type labelPreparationHelper interface {
 	getDeprecatedKernelModuleReadyLabels(node v1.Node) sets.Set[string]
 	getNodeKernelModuleReadyLabels(node v1.Node) sets.Set[types.NamespacedName]
@@ -653,12 +1266,31 @@ func (h *nmcReconcilerHelperImpl) UpdateNodeLabels(ctx context.Context, nmc *kmm
 	// get status labels and their config
 	statusLabels := h.lph.getStatusLabelsAndTheirConfigs(nmc)
 
+	// drifted modules are not loaded anymore, even though their status was not updated
+	var drifted []types.NamespacedName
+
+	for _, s := range nmc.Status.Modules {
+		if !apimeta.IsStatusConditionTrue(s.Conditions, kmmv1beta1.ModuleConditionDrifted) {
+			continue
+		}
+
+		nsn := types.NamespacedName{Namespace: s.Namespace, Name: s.Name}
+
+		delete(statusLabels, nsn)
+
+		if _, ok := specLabels[nsn]; ok && nodeModuleReadyLabels.Has(nsn) {
+			drifted = append(drifted, nsn)
+		}
+	}
+
 	// label in node but not in spec or status - should be removed
 	nsnLabelsToBeRemoved := h.lph.removeOrphanedLabels(nodeModuleReadyLabels, specLabels, statusLabels)
 
 	// label in spec and status and config equal - should be added
 	nsnLabelsToBeLoaded := h.lph.addEqualLabels(nodeModuleReadyLabels, specLabels, statusLabels)
 
+	nsnLabelsToBeRemoved = append(nsnLabelsToBeRemoved, drifted...)
+
 	var loadedLabels []string
 	unloadedLabels := deprecatedNodeModuleReadyLabels.UnsortedList()
 
@@ -731,19 +1363,28 @@ const (
 	configFileName = "config.yaml"
 	configFullPath = volMountPointConfig + "/" + configFileName
 
+	trustedCAVolumeName    = "trusted-ca"
 	volNameConfig          = "config"
+	volNameEtcContainers   = "etc-containers"
 	volNameImageRepoSecret = "image-repo-secret"
+	volNameWorkerBin       = "worker-bin"
 	volMountPointConfig    = "/etc/kmm-worker"
+
+	workerBinaryContainerName = "worker-binary"
 )
 
 //go:generate mockgen -source=nmc_reconciler.go -package=controllers -destination=mock_nmc_reconciler.go podManager
 
 type podManager interface {
+	CreateDriftCheckPod(ctx context.Context, nmc client.Object, nms *kmmv1beta1.NodeModuleSpec) error
 	CreateLoaderPod(ctx context.Context, nmc client.Object, nms *kmmv1beta1.NodeModuleSpec) error
+	CreateParametersUpdaterPod(ctx context.Context, nmc client.Object, nms *kmmv1beta1.NodeModuleSpec) error
 	CreateUnloaderPod(ctx context.Context, nmc client.Object, nms *kmmv1beta1.NodeModuleStatus) error
 	DeletePod(ctx context.Context, pod *v1.Pod) error
+	DriftCheckPodTemplate(ctx context.Context, nmc client.Object, nms *kmmv1beta1.NodeModuleSpec) (*v1.Pod, error)
 	ListWorkerPodsOnNode(ctx context.Context, nodeName string) ([]v1.Pod, error)
 	LoaderPodTemplate(ctx context.Context, nmc client.Object, nms *kmmv1beta1.NodeModuleSpec) (*v1.Pod, error)
+	ParametersUpdaterPodTemplate(ctx context.Context, nmc client.Object, nms *kmmv1beta1.NodeModuleSpec) (*v1.Pod, error)
 	GetWorkerPod(ctx context.Context, podName, namespace string) (*v1.Pod, error)
 	UnloaderPodTemplate(ctx context.Context, nmc client.Object, nms *kmmv1beta1.NodeModuleStatus) (*v1.Pod, error)
 }
@@ -772,6 +1413,15 @@ func newPodManager(
 	}
 }
 
+func (p *podManagerImpl) CreateDriftCheckPod(ctx context.Context, nmc client.Object, nms *kmmv1beta1.NodeModuleSpec) error {
+	pod, err := p.DriftCheckPodTemplate(ctx, nmc, nms)
+	if err != nil {
+		return fmt.Errorf("could not create the Pod template: %v", err)
+	}
+
+	return p.client.Create(ctx, pod)
+}
+
 func (p *podManagerImpl) CreateLoaderPod(ctx context.Context, nmcObj client.Object, nms *kmmv1beta1.NodeModuleSpec) error {
 	pod, err := p.LoaderPodTemplate(ctx, nmcObj, nms)
 	if err != nil {
@@ -781,6 +1431,15 @@ func (p *podManagerImpl) CreateLoaderPod(ctx context.Context, nmcObj client.Obje
 	return p.client.Create(ctx, pod)
 }
 
+func (p *podManagerImpl) CreateParametersUpdaterPod(ctx context.Context, nmc client.Object, nms *kmmv1beta1.NodeModuleSpec) error {
+	pod, err := p.ParametersUpdaterPodTemplate(ctx, nmc, nms)
+	if err != nil {
+		return fmt.Errorf("could not create the Pod template: %v", err)
+	}
+
+	return p.client.Create(ctx, pod)
+}
+
 func (p *podManagerImpl) CreateUnloaderPod(ctx context.Context, nmc client.Object, nms *kmmv1beta1.NodeModuleStatus) error {
 	pod, err := p.UnloaderPodTemplate(ctx, nmc, nms)
 	if err != nil {
@@ -872,12 +1531,6 @@ func (p *podManagerImpl) LoaderPodTemplate(ctx context.Context, nmc client.Objec
 
 		args = append(args, "--"+worker.FlagFirmwarePath, *firmwareHostPath)
 
-		firmwarePathContainerImg := filepath.Join(nms.Config.Modprobe.FirmwarePath, "*")
-		firmwarePathWorkerImg := filepath.Join(sharedFilesDir, nms.Config.Modprobe.FirmwarePath)
-		if err = addCopyCommand(pod, firmwarePathContainerImg, firmwarePathWorkerImg); err != nil {
-			return nil, fmt.Errorf("could not add the copy command to the init container: %v", err)
-		}
-
 		if err = setFirmwareVolume(pod, firmwareHostPath); err != nil {
 			return nil, fmt.Errorf("could not map host volume needed for firmware loading: %v", err)
 		}
@@ -885,6 +1538,27 @@ func (p *podManagerImpl) LoaderPodTemplate(ctx context.Context, nmc client.Objec
 		privileged = true
 	}
 
+	if nms.Config.BlacklistInTreeModules && hasInTreeModulesToRemove(&nms.Config) {
+		args = append(args, "--"+worker.FlagBlacklistPath, blacklistPath(&nms.ModuleItem))
+
+		if err = setModprobeConfVolume(pod); err != nil {
+			return nil, fmt.Errorf("could not map host volume needed for in-tree modules blacklisting: %v", err)
+		}
+
+		privileged = true
+	}
+
+	if sv := nms.Config.Modprobe.SignatureVerification; sv != nil {
+		if err = setSignatureVerificationVolume(pod, sv); err != nil {
+			return nil, fmt.Errorf("could not set the volume needed for signature verification: %v", err)
+		}
+
+		// /sys/firmware is masked in unprivileged containers
+		if sv.UEFIKeys {
+			privileged = true
+		}
+	}
+
 	if err = setWorkerConfigAnnotation(pod, nms.Config); err != nil {
 		return nil, fmt.Errorf("could not set worker config: %v", err)
 	}
@@ -902,6 +1576,63 @@ func (p *podManagerImpl) LoaderPodTemplate(ctx context.Context, nmc client.Objec
 	return pod, setHashAnnotation(pod)
 }
 
+// ParametersUpdaterPodTemplate returns a Pod that writes the module parameters of nms into sysfs.
+// It does not need the kmod image, and it is not restarted on failure, so that the module can be reloaded instead.
+func (p *podManagerImpl) ParametersUpdaterPodTemplate(ctx context.Context, nmc client.Object, nms *kmmv1beta1.NodeModuleSpec) (*v1.Pod, error) {
+	pod, err := p.baseWorkerPod(ctx, nmc, &nms.ModuleItem, &nms.Config)
+	if err != nil {
+		return nil, fmt.Errorf("could not create the base Pod: %v", err)
+	}
+
+	pod.Spec.InitContainers = nil
+	pod.Spec.RestartPolicy = v1.RestartPolicyNever
+
+	if err = setWorkerConfigAnnotation(pod, nms.Config); err != nil {
+		return nil, fmt.Errorf("could not set worker config: %v", err)
+	}
+
+	// /sys is mounted read-only in unprivileged containers
+	if err = setWorkerSecurityContext(pod, p.workerCfg, true); err != nil {
+		return nil, fmt.Errorf("could not set the worker Pod as privileged: %v", err)
+	}
+
+	if err = setWorkerContainerArgs(pod, []string{"kmod", "set-params", configFullPath}); err != nil {
+		return nil, fmt.Errorf("could not set worker container args: %v", err)
+	}
+
+	meta.SetLabel(pod, actionLabelKey, WorkerActionSetParameters)
+
+	return pod, setHashAnnotation(pod)
+}
+
+// DriftCheckPodTemplate returns a Pod that checks that the module described by nms is still loaded.
+// /proc/modules is not namespaced, so the Pod does not need the kmod image nor to be privileged.
+func (p *podManagerImpl) DriftCheckPodTemplate(ctx context.Context, nmc client.Object, nms *kmmv1beta1.NodeModuleSpec) (*v1.Pod, error) {
+	pod, err := p.baseWorkerPod(ctx, nmc, &nms.ModuleItem, &nms.Config)
+	if err != nil {
+		return nil, fmt.Errorf("could not create the base Pod: %v", err)
+	}
+
+	pod.Spec.InitContainers = nil
+	pod.Spec.RestartPolicy = v1.RestartPolicyNever
+
+	if err = setWorkerConfigAnnotation(pod, nms.Config); err != nil {
+		return nil, fmt.Errorf("could not set worker config: %v", err)
+	}
+
+	if err = setWorkerSecurityContext(pod, p.workerCfg, false); err != nil {
+		return nil, fmt.Errorf("could not set the worker security context: %v", err)
+	}
+
+	if err = setWorkerContainerArgs(pod, []string{"kmod", "check", configFullPath}); err != nil {
+		return nil, fmt.Errorf("could not set worker container args: %v", err)
+	}
+
+	meta.SetLabel(pod, actionLabelKey, WorkerActionCheck)
+
+	return pod, setHashAnnotation(pod)
+}
+
 func (p *podManagerImpl) UnloaderPodTemplate(ctx context.Context, nmc client.Object, nms *kmmv1beta1.NodeModuleStatus) (*v1.Pod, error) {
 	pod, err := p.baseWorkerPod(ctx, nmc, &nms.ModuleItem, &nms.Config)
 	if err != nil {
@@ -914,7 +1645,29 @@ func (p *podManagerImpl) UnloaderPodTemplate(ctx context.Context, nmc client.Obj
 		return nil, fmt.Errorf("could not set worker config: %v", err)
 	}
 
-	if err = setWorkerSecurityContext(pod, p.workerCfg, false); err != nil {
+	// When waiting for the module to be unused, the worker looks for the host processes that use it.
+	waitUnused := nms.Config.Modprobe.UnloadWaitTimeout != nil
+
+	if waitUnused {
+		pod.Spec.HostPID = true
+	}
+
+	// Remove the blacklist even if the in-tree modules list was emptied since, so that no stale file is left behind.
+	removeBlacklist := nms.Config.BlacklistInTreeModules
+
+	if removeBlacklist {
+		args = append(args, "--"+worker.FlagBlacklistPath, blacklistPath(&nms.ModuleItem))
+
+		if err = setModprobeConfVolume(pod); err != nil {
+			return nil, fmt.Errorf("could not map host volume needed for in-tree modules blacklisting: %v", err)
+		}
+	}
+
+	if nms.Config.RestoreInTreeModulesOnUnload && len(nms.RemovedInTreeModules) > 0 {
+		args = append(args, "--"+worker.FlagRestoreInTreeModules, strings.Join(nms.RemovedInTreeModules, ","))
+	}
+
+	if err = setWorkerSecurityContext(pod, p.workerCfg, waitUnused || removeBlacklist); err != nil {
 		return nil, fmt.Errorf("could not set the worker Pod's security context: %v", err)
 	}
 
@@ -931,12 +1684,6 @@ func (p *podManagerImpl) UnloaderPodTemplate(ctx context.Context, nmc client.Obj
 		}
 		args = append(args, "--"+worker.FlagFirmwarePath, *firmwareHostPath)
 
-		firmwarePathContainerImg := filepath.Join(nms.Config.Modprobe.FirmwarePath, "*")
-		firmwarePathWorkerImg := filepath.Join(sharedFilesDir, nms.Config.Modprobe.FirmwarePath)
-		if err = addCopyCommand(pod, firmwarePathContainerImg, firmwarePathWorkerImg); err != nil {
-			return nil, fmt.Errorf("could not add the copy command to the init container: %v", err)
-		}
-
 		if err = setFirmwareVolume(pod, firmwareHostPath); err != nil {
 			return nil, fmt.Errorf("could not map host volume needed for firmware unloading: %v", err)
 		}
@@ -951,6 +1698,7 @@ func (p *podManagerImpl) UnloaderPodTemplate(ctx context.Context, nmc client.Obj
 	return pod, setHashAnnotation(pod)
 }
 
+// Default compute resources of the worker containers, used unless the operator configuration sets worker.resources.
 var (
 	requests = v1.ResourceList{
 		v1.ResourceCPU:    resource.MustParse("0.5"),
@@ -962,29 +1710,22 @@ var (
 	}
 )
 
-func addCopyCommand(pod *v1.Pod, src, dst string) error {
-
-	container, _ := podcmd.FindContainerByName(pod, initContainerName)
-	if container == nil {
-		return errors.New("could not find the init container")
+// workerResources returns the compute resources of the worker Pod containers.
+func (p *podManagerImpl) workerResources() v1.ResourceRequirements {
+	if p.workerCfg.Resources != nil {
+		return *p.workerCfg.Resources.DeepCopy()
 	}
 
-	const template = `
-mkdir -p %s;
-cp -R %s %s;
-`
-	copyCommand := fmt.Sprintf(template, dst, src, dst)
-	container.Args[0] = strings.Join([]string{container.Args[0], copyCommand}, "")
-
-	return nil
+	return v1.ResourceRequirements{
+		Requests: requests,
+		Limits:   limits,
+	}
 }
 
 func (p *podManagerImpl) baseWorkerPod(ctx context.Context, nmc client.Object, item *kmmv1beta1.ModuleItem,
 	moduleConfig *kmmv1beta1.ModuleConfig) (*v1.Pod, error) {
 
 	const (
-		trustedCAVolumeName   = "trusted-ca"
-		volNameEtcContainers  = "etc-containers"
 		volNameLibModules     = "lib-modules"
 		volNameUsrLibModules  = "usr-lib-modules"
 		volNameVarLibFirmware = "var-lib-firmware"
@@ -1123,17 +1864,29 @@ func (p *podManagerImpl) baseWorkerPod(ctx context.Context, nmc client.Object, i
 		imagePullSecrets = append(imagePullSecrets, *item.ImageRepoSecret)
 	}
 
+	// The labels set by KMM take precedence over the ones from the operator configuration.
+	podLabels := maps.Clone(p.workerCfg.Labels)
+	if podLabels == nil {
+		podLabels = make(map[string]string)
+	}
+
+	podLabels["app.kubernetes.io/name"] = "kmm"
+	podLabels["app.kubernetes.io/component"] = "worker"
+	podLabels["app.kubernetes.io/part-of"] = "kmm"
+	podLabels[constants.ModuleNameLabel] = item.Name
+
+	var securityContext *v1.PodSecurityContext
+	if p.workerCfg.SeccompProfile != nil {
+		securityContext = &v1.PodSecurityContext{SeccompProfile: p.workerCfg.SeccompProfile.DeepCopy()}
+	}
+
 	nodeName := nmc.GetName()
 	pod := v1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
-			Namespace: item.Namespace,
-			Name:      workerPodName(nodeName, item.Name),
-			Labels: map[string]string{
-				"app.kubernetes.io/name":      "kmm",
-				"app.kubernetes.io/component": "worker",
-				"app.kubernetes.io/part-of":   "kmm",
-				constants.ModuleNameLabel:     item.Name,
-			},
+			Namespace:   item.Namespace,
+			Name:        workerPodName(nodeName, item.Name),
+			Labels:      podLabels,
+			Annotations: maps.Clone(p.workerCfg.Annotations),
 		},
 		Spec: v1.PodSpec{
 			InitContainers: []v1.Container{
@@ -1141,18 +1894,20 @@ func (p *podManagerImpl) baseWorkerPod(ctx context.Context, nmc client.Object, i
 					Name:            initContainerName,
 					Image:           moduleConfig.ContainerImage,
 					ImagePullPolicy: moduleConfig.ImagePullPolicy,
-					Command:         []string{"/bin/sh", "-c"},
-					Args:            []string{""},
+					Command:         []string{worker.WorkerBinaryPath},
+					Args:            []string{"image", "extract", configFullPath},
 					VolumeMounts: []v1.VolumeMount{
+						{
+							Name:      volNameConfig,
+							MountPath: volMountPointConfig,
+							ReadOnly:  true,
+						},
 						{
 							Name:      volNameTmp,
 							MountPath: sharedFilesDir,
 						},
 					},
-					Resources: v1.ResourceRequirements{
-						Requests: requests,
-						Limits:   limits,
-					},
+					Resources: p.workerResources(),
 				},
 			},
 			Containers: []v1.Container{
@@ -1160,10 +1915,7 @@ func (p *podManagerImpl) baseWorkerPod(ctx context.Context, nmc client.Object, i
 					Name:         workerContainerName,
 					Image:        p.workerImage,
 					VolumeMounts: volumeMounts,
-					Resources: v1.ResourceRequirements{
-						Requests: requests,
-						Limits:   limits,
-					},
+					Resources:    p.workerResources(),
 				},
 			},
 			NodeName:           nodeName,
@@ -1171,7 +1923,9 @@ func (p *podManagerImpl) baseWorkerPod(ctx context.Context, nmc client.Object, i
 			ServiceAccountName: item.ServiceAccountName,
 			ImagePullSecrets:   imagePullSecrets,
 			Volumes:            volumes,
-			Tolerations:        moduleConfig.Tolerations,
+			Tolerations:        append(slices.Clone(moduleConfig.Tolerations), p.workerCfg.Tolerations...),
+			PriorityClassName:  p.workerCfg.PriorityClassName,
+			SecurityContext:    securityContext,
 		},
 	}
 
@@ -1179,10 +1933,10 @@ func (p *podManagerImpl) baseWorkerPod(ctx context.Context, nmc client.Object, i
 		return nil, fmt.Errorf("could not set the owner as controller: %v", err)
 	}
 
-	kmodsPathContainerImg := filepath.Join(moduleConfig.Modprobe.DirName, "lib", "modules", moduleConfig.KernelVersion)
-	kmodsPathWorkerImg := filepath.Join(sharedFilesDir, moduleConfig.Modprobe.DirName, "lib", "modules")
-	if err := addCopyCommand(&pod, kmodsPathContainerImg, kmodsPathWorkerImg); err != nil {
-		return nil, fmt.Errorf("could not add the copy command to the init container: %v", err)
+	if p.workerCfg.PullImages {
+		setImagePullerInitContainer(&pod, p.workerImage, item.ImageRepoSecret, p.workerCfg.GlobalPullSecretHostPath)
+	} else {
+		addWorkerBinaryInitContainer(&pod, p.workerImage)
 	}
 
 	controllerutil.AddFinalizer(&pod, nodeModulesConfigFinalizer)
@@ -1190,6 +1944,112 @@ func (p *podManagerImpl) baseWorkerPod(ctx context.Context, nmc client.Object, i
 	return &pod, nil
 }
 
+// addWorkerBinaryInitContainer adds an init container that copies the worker binary into an emptyDir volume, from
+// which the image-extractor container runs `worker image extract` in the kmod image.
+// The worker binary is static, so that the kmod image does not need a shell or any library.
+func addWorkerBinaryInitContainer(pod *v1.Pod, workerImage string) {
+	pod.Spec.Volumes = append(pod.Spec.Volumes, v1.Volume{
+		Name: volNameWorkerBin,
+		VolumeSource: v1.VolumeSource{
+			EmptyDir: &v1.EmptyDirVolumeSource{},
+		},
+	})
+
+	extractor := &pod.Spec.InitContainers[0]
+
+	extractor.VolumeMounts = append(extractor.VolumeMounts, v1.VolumeMount{
+		Name:      volNameWorkerBin,
+		MountPath: worker.WorkerBinDir,
+		ReadOnly:  true,
+	})
+
+	copier := v1.Container{
+		Name:  workerBinaryContainerName,
+		Image: workerImage,
+		Args:  []string{"image", "copy-binary", worker.WorkerBinaryPath},
+		VolumeMounts: []v1.VolumeMount{
+			{
+				Name:      volNameWorkerBin,
+				MountPath: worker.WorkerBinDir,
+			},
+		},
+		Resources: extractor.Resources,
+	}
+
+	pod.Spec.InitContainers = append([]v1.Container{copier}, pod.Spec.InitContainers...)
+}
+
+// setImagePullerInitContainer replaces the init container running the kmod image with one running
+// `worker image pull`, which fetches the image from the registry and only extracts the files needed by the worker.
+func setImagePullerInitContainer(pod *v1.Pod, workerImage string, imageRepoSecret *v1.LocalObjectReference, globalPullSecretHostPath *string) {
+	const volNameGlobalPullSecret = "global-pull-secret"
+
+	volumeMounts := []v1.VolumeMount{
+		{
+			Name:      volNameConfig,
+			MountPath: volMountPointConfig,
+			ReadOnly:  true,
+		},
+		{
+			Name:      volNameEtcContainers,
+			MountPath: "/etc/containers",
+			ReadOnly:  true,
+		},
+		{
+			Name:      trustedCAVolumeName,
+			MountPath: "/etc/pki/tls/certs",
+			ReadOnly:  true,
+		},
+		{
+			Name:      volNameTmp,
+			MountPath: sharedFilesDir,
+		},
+	}
+
+	if imageRepoSecret != nil {
+		pod.Spec.Volumes = append(pod.Spec.Volumes, v1.Volume{
+			Name: volNameImageRepoSecret,
+			VolumeSource: v1.VolumeSource{
+				Secret: &v1.SecretVolumeSource{SecretName: imageRepoSecret.Name},
+			},
+		})
+
+		volumeMounts = append(volumeMounts, v1.VolumeMount{
+			Name:      volNameImageRepoSecret,
+			MountPath: worker.PullSecretsDir,
+			ReadOnly:  true,
+		})
+	}
+
+	if globalPullSecretHostPath != nil && *globalPullSecretHostPath != "" {
+		hostPathFile := v1.HostPathFile
+
+		pod.Spec.Volumes = append(pod.Spec.Volumes, v1.Volume{
+			Name: volNameGlobalPullSecret,
+			VolumeSource: v1.VolumeSource{
+				HostPath: &v1.HostPathVolumeSource{
+					Path: *globalPullSecretHostPath,
+					Type: &hostPathFile,
+				},
+			},
+		})
+
+		volumeMounts = append(volumeMounts, v1.VolumeMount{
+			Name:      volNameGlobalPullSecret,
+			MountPath: worker.GlobalPullSecretPath,
+			ReadOnly:  true,
+		})
+	}
+
+	pod.Spec.InitContainers[0] = v1.Container{
+		Name:         initContainerName,
+		Image:        workerImage,
+		Args:         []string{"image", "pull", configFullPath},
+		VolumeMounts: volumeMounts,
+		Resources:    pod.Spec.InitContainers[0].Resources,
+	}
+}
+
 func setWorkerConfigAnnotation(pod *v1.Pod, cfg kmmv1beta1.ModuleConfig) error {
 	b, err := yaml.Marshal(cfg)
 	if err != nil {
@@ -1298,8 +2158,140 @@ func setFirmwareVolume(pod *v1.Pod, firmwareHostPath *string) error {
 	return nil
 }
 
+// hasInTreeModulesToRemove returns true if cfg replaces at least one in-tree module.
+func hasInTreeModulesToRemove(cfg *kmmv1beta1.ModuleConfig) bool {
+	return len(nmc.InTreeModulesToRemove(cfg)) > 0
+}
+
+// blacklistPath returns the path, in the worker container, of the modprobe configuration file that blacklists the
+// in-tree modules replaced by item.
+func blacklistPath(item *kmmv1beta1.ModuleItem) string {
+	return filepath.Join(worker.HostModprobeConfDir, fmt.Sprintf("kmm-%s-%s.conf", item.Namespace, item.Name))
+}
+
+// setModprobeConfVolume mounts the host's /etc/modprobe.d directory in the worker container.
+// It is not mounted at /etc/modprobe.d, where the worker may already find the software dependencies configuration.
+func setModprobeConfVolume(pod *v1.Pod) error {
+	const (
+		volNameModprobeConf = "host-modprobe-d"
+		hostModprobeConfDir = "/etc/modprobe.d"
+	)
+
+	container, _ := podcmd.FindContainerByName(pod, workerContainerName)
+	if container == nil {
+		return errors.New("could not find the worker container")
+	}
+
+	hostPathDirectoryOrCreate := v1.HostPathDirectoryOrCreate
+
+	pod.Spec.Volumes = append(pod.Spec.Volumes, v1.Volume{
+		Name: volNameModprobeConf,
+		VolumeSource: v1.VolumeSource{
+			HostPath: &v1.HostPathVolumeSource{
+				Path: hostModprobeConfDir,
+				Type: &hostPathDirectoryOrCreate,
+			},
+		},
+	})
+
+	container.VolumeMounts = append(container.VolumeMounts, v1.VolumeMount{
+		Name:      volNameModprobeConf,
+		MountPath: worker.HostModprobeConfDir,
+	})
+
+	return nil
+}
+
+// setSignatureVerificationVolume mounts the certificate secret of sv in the worker container, or the host's
+// /sys/firmware directory to read the EFI variables if UEFI keys are used.
+func setSignatureVerificationVolume(pod *v1.Pod, sv *kmmv1beta1.SignatureVerificationSpec) error {
+	const (
+		volNameFirmware      = "sys-firmware"
+		volNameSignatureCert = "signature-cert"
+		// /sys/firmware exists on all nodes, and the EFI variables filesystem is mounted below it on EFI ones.
+		sysFirmwarePath = "/sys/firmware"
+	)
+
+	container, _ := podcmd.FindContainerByName(pod, workerContainerName)
+	if container == nil {
+		return errors.New("could not find the worker container")
+	}
+
+	if sv.CertSecret != nil {
+		pod.Spec.Volumes = append(pod.Spec.Volumes, v1.Volume{
+			Name: volNameSignatureCert,
+			VolumeSource: v1.VolumeSource{
+				Secret: &v1.SecretVolumeSource{
+					SecretName: sv.CertSecret.Name,
+					Items: []v1.KeyToPath{
+						{Key: constants.PublicSignDataKey, Path: filepath.Base(worker.SignatureCertPath)},
+					},
+				},
+			},
+		})
+
+		container.VolumeMounts = append(container.VolumeMounts, v1.VolumeMount{
+			Name:      volNameSignatureCert,
+			MountPath: worker.SignatureCertDir,
+			ReadOnly:  true,
+		})
+
+		return nil
+	}
+
+	if !sv.UEFIKeys {
+		return nil
+	}
+
+	hostPathDirectory := v1.HostPathDirectory
+
+	pod.Spec.Volumes = append(pod.Spec.Volumes, v1.Volume{
+		Name: volNameFirmware,
+		VolumeSource: v1.VolumeSource{
+			HostPath: &v1.HostPathVolumeSource{
+				Path: sysFirmwarePath,
+				Type: &hostPathDirectory,
+			},
+		},
+	})
+
+	container.VolumeMounts = append(container.VolumeMounts, v1.VolumeMount{
+		Name:      volNameFirmware,
+		MountPath: sysFirmwarePath,
+		ReadOnly:  true,
+	})
+
+	return nil
+}
+
+// podHash returns a hash of pod.
+// hashstructure ignores unexported fields, so the resource quantities of the containers are hashed as strings.
+func podHash(pod *v1.Pod) (uint64, error) {
+	quantities := make(map[string]string)
+
+	for _, c := range append(slices.Clone(pod.Spec.InitContainers), pod.Spec.Containers...) {
+		for name, q := range c.Resources.Requests {
+			quantities[c.Name+"/requests/"+string(name)] = q.String()
+		}
+
+		for name, q := range c.Resources.Limits {
+			quantities[c.Name+"/limits/"+string(name)] = q.String()
+		}
+	}
+
+	data := struct {
+		Pod        *v1.Pod
+		Quantities map[string]string
+	}{
+		Pod:        pod,
+		Quantities: quantities,
+	}
+
+	return hashstructure.Hash(data, hashstructure.FormatV2, nil)
+}
+
 func setHashAnnotation(pod *v1.Pod) error {
-	hash, err := hashstructure.Hash(pod, hashstructure.FormatV2, nil)
+	hash, err := podHash(pod)
 	if err != nil {
 		return fmt.Errorf("could not hash the pod template: %v", err)
 	}
@@ -11,7 +11,6 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 
 	"github.com/budougumi0617/cmpmock"
-	"github.com/mitchellh/hashstructure/v2"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
@@ -22,9 +21,12 @@ import (
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/node"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/ocp/ca"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/utils"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/worker"
 	"go.uber.org/mock/gomock"
 	v1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
@@ -93,6 +95,7 @@ var _ = Describe("NodeModulesConfigReconciler_Reconcile", func() {
 			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
 		}
 
+		node := v1.Node{}
 		gomock.InOrder(
 			kubeClient.
 				EXPECT().
@@ -100,7 +103,8 @@ var _ = Describe("NodeModulesConfigReconciler_Reconcile", func() {
 				Do(func(_ context.Context, _ types.NamespacedName, kubeNmc ctrlclient.Object, _ ...ctrlclient.Options) {
 					*kubeNmc.(*kmmv1beta1.NodeModulesConfig) = *nmc
 				}),
-			wh.EXPECT().SyncStatus(ctx, nmc).Return(errors.New("random error")),
+			kubeClient.EXPECT().Get(ctx, types.NamespacedName{Name: nmc.Name}, &node),
+			wh.EXPECT().SyncStatus(ctx, nmc, &node).Return(errors.New("random error")),
 		)
 
 		_, err := r.Reconcile(ctx, req)
@@ -119,7 +123,6 @@ var _ = Describe("NodeModulesConfigReconciler_Reconcile", func() {
 				Do(func(_ context.Context, _ types.NamespacedName, kubeNmc ctrlclient.Object, _ ...ctrlclient.Options) {
 					*kubeNmc.(*kmmv1beta1.NodeModulesConfig) = *nmc
 				}),
-			wh.EXPECT().SyncStatus(ctx, nmc),
 			kubeClient.EXPECT().Get(ctx, types.NamespacedName{Name: nmc.Name}, &node).Return(fmt.Errorf("some error")),
 		)
 
@@ -153,8 +156,8 @@ var _ = Describe("NodeModulesConfigReconciler_Reconcile", func() {
 				Do(func(_ context.Context, _ types.NamespacedName, kubeNmc ctrlclient.Object, _ ...ctrlclient.Options) {
 					*kubeNmc.(*kmmv1beta1.NodeModulesConfig) = *nmc
 				}),
-			wh.EXPECT().SyncStatus(ctx, nmc),
 			kubeClient.EXPECT().Get(ctx, types.NamespacedName{Name: nmc.Name}, &node).Return(nil),
+			wh.EXPECT().SyncStatus(ctx, nmc, &node),
 			nm.EXPECT().IsNodeSchedulable(&node, nil).Return(false),
 			wh.EXPECT().GarbageCollectInUseLabels(ctx, nmc),
 			wh.EXPECT().UpdateNodeLabels(ctx, nmc, &node).Return(loaded, unloaded, err),
@@ -227,8 +230,8 @@ var _ = Describe("NodeModulesConfigReconciler_Reconcile", func() {
 				Do(func(_ context.Context, _ types.NamespacedName, kubeNmc ctrlclient.Object, _ ...ctrlclient.Options) {
 					*kubeNmc.(*kmmv1beta1.NodeModulesConfig) = *nmc
 				}),
-			wh.EXPECT().SyncStatus(ctx, nmc),
 			kubeClient.EXPECT().Get(ctx, types.NamespacedName{Name: nmc.Name}, &node).Return(nil),
+			wh.EXPECT().SyncStatus(ctx, nmc, &node),
 			nm.EXPECT().IsNodeSchedulable(&node, nil).Return(true),
 			wh.EXPECT().ProcessModuleSpec(contextWithValueMatch, nmc, &spec0, &status0, &node),
 			nm.EXPECT().IsNodeSchedulable(&node, nil).Return(true),
@@ -246,6 +249,158 @@ var _ = Describe("NodeModulesConfigReconciler_Reconcile", func() {
 		)
 	})
 
+	It("should requeue the NMC while the node is drained", func() {
+		var (
+			loaded   []types.NamespacedName
+			unloaded []types.NamespacedName
+			node     v1.Node
+		)
+
+		spec := kmmv1beta1.NodeModuleSpec{
+			ModuleItem: kmmv1beta1.ModuleItem{
+				Namespace: namespace,
+				Name:      "mod",
+			},
+		}
+
+		nmc := &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+			Spec: kmmv1beta1.NodeModulesConfigSpec{
+				Modules: []kmmv1beta1.NodeModuleSpec{spec},
+			},
+		}
+
+		contextWithValueMatch := gomock.AssignableToTypeOf(
+			reflect.TypeOf((*context.Context)(nil)).Elem(),
+		)
+
+		gomock.InOrder(
+			kubeClient.
+				EXPECT().
+				Get(ctx, nmcNsn, &kmmv1beta1.NodeModulesConfig{}).
+				Do(func(_ context.Context, _ types.NamespacedName, kubeNmc ctrlclient.Object, _ ...ctrlclient.Options) {
+					*kubeNmc.(*kmmv1beta1.NodeModulesConfig) = *nmc
+				}),
+			kubeClient.EXPECT().Get(ctx, types.NamespacedName{Name: nmc.Name}, &node),
+			wh.EXPECT().SyncStatus(ctx, nmc, &node),
+			nm.EXPECT().IsNodeSchedulable(&node, nil).Return(true),
+			wh.
+				EXPECT().
+				ProcessModuleSpec(contextWithValueMatch, nmc, &spec, nil, &node).
+				Return(fmt.Errorf("could not drain the node: %w", errDrainInProgress)),
+			wh.EXPECT().GarbageCollectInUseLabels(ctx, nmc),
+			wh.EXPECT().UpdateNodeLabels(ctx, nmc, &node).Return(loaded, unloaded, nil),
+			wh.EXPECT().RecordEvents(&node, loaded, unloaded),
+		)
+
+		Expect(
+			r.Reconcile(ctx, req),
+		).To(
+			Equal(ctrl.Result{RequeueAfter: drainRequeueDelay}),
+		)
+	})
+
+	It("should requeue the NMC when the maintenance window opens", func() {
+		var (
+			loaded   []types.NamespacedName
+			unloaded []types.NamespacedName
+			node     v1.Node
+		)
+
+		spec := kmmv1beta1.NodeModuleSpec{
+			ModuleItem: kmmv1beta1.ModuleItem{
+				Namespace: namespace,
+				Name:      "mod",
+			},
+		}
+
+		nmc := &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+			Spec: kmmv1beta1.NodeModulesConfigSpec{
+				Modules: []kmmv1beta1.NodeModuleSpec{spec},
+			},
+		}
+
+		contextWithValueMatch := gomock.AssignableToTypeOf(
+			reflect.TypeOf((*context.Context)(nil)).Elem(),
+		)
+
+		gomock.InOrder(
+			kubeClient.
+				EXPECT().
+				Get(ctx, nmcNsn, &kmmv1beta1.NodeModulesConfig{}).
+				Do(func(_ context.Context, _ types.NamespacedName, kubeNmc ctrlclient.Object, _ ...ctrlclient.Options) {
+					*kubeNmc.(*kmmv1beta1.NodeModulesConfig) = *nmc
+				}),
+			kubeClient.EXPECT().Get(ctx, types.NamespacedName{Name: nmc.Name}, &node),
+			wh.EXPECT().SyncStatus(ctx, nmc, &node),
+			nm.EXPECT().IsNodeSchedulable(&node, nil).Return(true),
+			wh.
+				EXPECT().
+				ProcessModuleSpec(contextWithValueMatch, nmc, &spec, nil, &node).
+				Return(&maintenanceWindowClosedError{opensAt: time.Now().Add(time.Hour)}),
+			wh.EXPECT().GarbageCollectInUseLabels(ctx, nmc),
+			wh.EXPECT().UpdateNodeLabels(ctx, nmc, &node).Return(loaded, unloaded, nil),
+			wh.EXPECT().RecordEvents(&node, loaded, unloaded),
+		)
+
+		res, err := r.Reconcile(ctx, req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.RequeueAfter).To(BeNumerically("~", time.Hour, time.Minute))
+	})
+
+	It("should requeue the NMC when the next drift check is due", func() {
+		var (
+			loaded   []types.NamespacedName
+			unloaded []types.NamespacedName
+			node     v1.Node
+		)
+
+		r.driftCheckInterval = 10 * time.Minute
+
+		spec := kmmv1beta1.NodeModuleSpec{
+			ModuleItem: kmmv1beta1.ModuleItem{
+				Namespace: namespace,
+				Name:      "mod",
+			},
+		}
+
+		nmc := &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+			Spec: kmmv1beta1.NodeModulesConfigSpec{
+				Modules: []kmmv1beta1.NodeModuleSpec{spec},
+			},
+			Status: kmmv1beta1.NodeModulesConfigStatus{
+				Modules: []kmmv1beta1.NodeModuleStatus{
+					{
+						ModuleItem:         spec.ModuleItem,
+						Config:             kmmv1beta1.ModuleConfig{ContainerImage: "some-image"},
+						LastTransitionTime: metav1.NewTime(time.Now().Add(-5 * time.Minute)),
+					},
+				},
+			},
+		}
+
+		gomock.InOrder(
+			kubeClient.
+				EXPECT().
+				Get(ctx, nmcNsn, &kmmv1beta1.NodeModulesConfig{}).
+				Do(func(_ context.Context, _ types.NamespacedName, kubeNmc ctrlclient.Object, _ ...ctrlclient.Options) {
+					*kubeNmc.(*kmmv1beta1.NodeModulesConfig) = *nmc
+				}),
+			kubeClient.EXPECT().Get(ctx, types.NamespacedName{Name: nmc.Name}, &node),
+			wh.EXPECT().SyncStatus(ctx, nmc, &node),
+			nm.EXPECT().IsNodeSchedulable(&node, nil).Return(false),
+			wh.EXPECT().GarbageCollectInUseLabels(ctx, nmc),
+			wh.EXPECT().UpdateNodeLabels(ctx, nmc, &node).Return(loaded, unloaded, nil),
+			wh.EXPECT().RecordEvents(&node, loaded, unloaded),
+		)
+
+		res, err := r.Reconcile(ctx, req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.RequeueAfter).To(BeNumerically("~", 5*time.Minute, time.Minute))
+	})
+
 	It("should complete all the reconcile functions and return combined error", func() {
 		const (
 			errorMeassge = "some error"
@@ -307,8 +462,8 @@ var _ = Describe("NodeModulesConfigReconciler_Reconcile", func() {
 				Do(func(_ context.Context, _ types.NamespacedName, kubeNmc ctrlclient.Object, _ ...ctrlclient.Options) {
 					*kubeNmc.(*kmmv1beta1.NodeModulesConfig) = *nmc
 				}),
-			wh.EXPECT().SyncStatus(ctx, nmc).Return(nil),
 			kubeClient.EXPECT().Get(ctx, types.NamespacedName{Name: nmc.Name}, &node).Return(nil),
+			wh.EXPECT().SyncStatus(ctx, nmc, &node).Return(nil),
 			nm.EXPECT().IsNodeSchedulable(&node, nil).Return(true),
 			wh.EXPECT().ProcessModuleSpec(contextWithValueMatch, nmc, &spec0, &status0, &node).Return(fmt.Errorf(errorMeassge)),
 			wh.EXPECT().ProcessUnconfiguredModuleStatus(contextWithValueMatch, nmc, &status2, &node).Return(fmt.Errorf(errorMeassge)),
@@ -350,7 +505,7 @@ var _ = Describe("nmcReconcilerHelperImpl_GarbageCollectInUseLabels", func() {
 		ctrl := gomock.NewController(GinkgoT())
 		client = testclient.NewMockClient(ctrl)
 		pm = NewMockpodManager(ctrl)
-		wh = newNMCReconcilerHelper(client, pm, nil, nil)
+		wh = newNMCReconcilerHelper(client, pm, nil, nil, config.DriftDetection{})
 	})
 
 	It("should do nothing if no labels should be collected", func() {
@@ -468,7 +623,7 @@ var _ = Describe("nmcReconcilerHelperImpl_ProcessModuleSpec", func() {
 		client = testclient.NewMockClient(ctrl)
 		pm = NewMockpodManager(ctrl)
 		nm = node.NewMockNode(ctrl)
-		wh = newNMCReconcilerHelper(client, pm, nil, nm)
+		wh = newNMCReconcilerHelper(client, pm, nil, nm, config.DriftDetection{})
 	})
 
 	It("should create a loader Pod if there is no existing Pod and the status is missing", func() {
@@ -527,6 +682,210 @@ var _ = Describe("nmcReconcilerHelperImpl_ProcessModuleSpec", func() {
 		)
 	})
 
+	It("should not create an unloader Pod while the maintenance window is closed", func() {
+		nmc := &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+		}
+
+		opensAt := time.Now().UTC().Add(2 * time.Hour)
+
+		spec := &kmmv1beta1.NodeModuleSpec{
+			ModuleItem: kmmv1beta1.ModuleItem{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Config: kmmv1beta1.ModuleConfig{
+				ContainerImage: "old-container-image",
+				KernelVersion:  "same kernel",
+			},
+			MaintenanceWindow: &kmmv1beta1.MaintenanceWindow{
+				Schedule: fmt.Sprintf("%d %d * * *", opensAt.Minute(), opensAt.Hour()),
+				Duration: metav1.Duration{Duration: time.Hour},
+			},
+		}
+
+		status := &kmmv1beta1.NodeModuleStatus{
+			ModuleItem: kmmv1beta1.ModuleItem{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Config: kmmv1beta1.ModuleConfig{ContainerImage: "new-container-image", KernelVersion: "same kernel"},
+		}
+
+		pm.EXPECT().GetWorkerPod(ctx, podName, namespace)
+
+		err := wh.ProcessModuleSpec(ctx, nmc, spec, status, nil)
+
+		var windowErr *maintenanceWindowClosedError
+		Expect(errors.As(err, &windowErr)).To(BeTrue())
+		Expect(windowErr.opensAt).To(Equal(opensAt.Truncate(time.Minute)))
+	})
+
+	It("should not unload the module if only the maintenance window changed", func() {
+		nmc := &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+		}
+
+		mi := kmmv1beta1.ModuleItem{Name: name, Namespace: namespace}
+		cfg := kmmv1beta1.ModuleConfig{ContainerImage: "container-image", KernelVersion: "some kernel"}
+		opensAt := time.Now().UTC().Add(2 * time.Hour)
+
+		spec := &kmmv1beta1.NodeModuleSpec{
+			ModuleItem: mi,
+			Config:     cfg,
+			MaintenanceWindow: &kmmv1beta1.MaintenanceWindow{
+				Schedule: fmt.Sprintf("%d %d * * *", opensAt.Minute(), opensAt.Hour()),
+				Duration: metav1.Duration{Duration: time.Hour},
+			},
+		}
+
+		status := &kmmv1beta1.NodeModuleStatus{ModuleItem: mi, Config: cfg}
+		node := &v1.Node{}
+
+		gomock.InOrder(
+			pm.EXPECT().GetWorkerPod(ctx, podName, namespace),
+			nm.EXPECT().Uncordon(ctx, node, namespace+"/"+name),
+			nm.EXPECT().NodeBecomeReadyAfter(node, status.LastTransitionTime).Return(false),
+		)
+
+		Expect(
+			wh.ProcessModuleSpec(ctx, nmc, spec, status, node),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+	It("should not unload the module if only the drain policy changed", func() {
+		nmc := &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+		}
+
+		mi := kmmv1beta1.ModuleItem{Name: name, Namespace: namespace}
+		cfg := kmmv1beta1.ModuleConfig{ContainerImage: "container-image", KernelVersion: "some kernel"}
+
+		spec := &kmmv1beta1.NodeModuleSpec{
+			ModuleItem: mi,
+			Config:     cfg,
+			Drain: &kmmv1beta1.DrainSpec{
+				PodSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"app": "gpu-app"},
+				},
+			},
+		}
+
+		status := &kmmv1beta1.NodeModuleStatus{ModuleItem: mi, Config: cfg}
+		node := &v1.Node{}
+
+		gomock.InOrder(
+			pm.EXPECT().GetWorkerPod(ctx, podName, namespace),
+			nm.EXPECT().Uncordon(ctx, node, namespace+"/"+name),
+			nm.EXPECT().NodeBecomeReadyAfter(node, status.LastTransitionTime).Return(false),
+		)
+
+		Expect(
+			wh.ProcessModuleSpec(ctx, nmc, spec, status, node),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+	Context("only the parameters changed", func() {
+		var (
+			nmcObj *kmmv1beta1.NodeModulesConfig
+			spec   *kmmv1beta1.NodeModuleSpec
+			status *kmmv1beta1.NodeModuleStatus
+		)
+
+		BeforeEach(func() {
+			nmcObj = &kmmv1beta1.NodeModulesConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+			}
+
+			mi := kmmv1beta1.ModuleItem{Name: name, Namespace: namespace}
+
+			spec = &kmmv1beta1.NodeModuleSpec{
+				ModuleItem: mi,
+				Config: kmmv1beta1.ModuleConfig{
+					KernelVersion: "same kernel",
+					Modprobe:      kmmv1beta1.ModprobeSpec{ModuleName: "test", Parameters: []string{"a=2", "b=1"}},
+				},
+			}
+
+			status = &kmmv1beta1.NodeModuleStatus{
+				ModuleItem: mi,
+				Config: kmmv1beta1.ModuleConfig{
+					KernelVersion: "same kernel",
+					Modprobe:      kmmv1beta1.ModprobeSpec{ModuleName: "test", Parameters: []string{"a=1"}},
+				},
+			}
+		})
+
+		It("should create a parameters updater Pod", func() {
+			gomock.InOrder(
+				pm.EXPECT().GetWorkerPod(ctx, podName, namespace),
+				pm.EXPECT().CreateParametersUpdaterPod(ctx, nmcObj, spec),
+			)
+
+			Expect(
+				wh.ProcessModuleSpec(ctx, nmcObj, spec, status, nil),
+			).NotTo(
+				HaveOccurred(),
+			)
+		})
+
+		It("should create an unloader Pod if the last update of the same parameters failed", func() {
+			nmcObj.Status.WorkerResults = []kmmv1beta1.NodeModuleWorkerResult{
+				{
+					Name:                name,
+					Namespace:           namespace,
+					RequestedParameters: []string{"a=2", "b=1"},
+					WorkerResult: kmmv1beta1.WorkerResult{
+						Action:        worker.ActionSetParameters,
+						ErrorCategory: worker.CategoryParameterReadOnly,
+						Error:         "some error",
+					},
+				},
+			}
+
+			gomock.InOrder(
+				pm.EXPECT().GetWorkerPod(ctx, podName, namespace),
+				pm.EXPECT().CreateUnloaderPod(ctx, nmcObj, status),
+			)
+
+			Expect(
+				wh.ProcessModuleSpec(ctx, nmcObj, spec, status, nil),
+			).NotTo(
+				HaveOccurred(),
+			)
+		})
+
+		It("should create a parameters updater Pod if the failed update was for other parameters", func() {
+			nmcObj.Status.WorkerResults = []kmmv1beta1.NodeModuleWorkerResult{
+				{
+					Name:                name,
+					Namespace:           namespace,
+					RequestedParameters: []string{"b=2"},
+					WorkerResult: kmmv1beta1.WorkerResult{
+						Action:        worker.ActionSetParameters,
+						ErrorCategory: worker.CategoryParameterReadOnly,
+						Error:         "some error",
+					},
+				},
+			}
+
+			gomock.InOrder(
+				pm.EXPECT().GetWorkerPod(ctx, podName, namespace),
+				pm.EXPECT().CreateParametersUpdaterPod(ctx, nmcObj, spec),
+			)
+
+			Expect(
+				wh.ProcessModuleSpec(ctx, nmcObj, spec, status, nil),
+			).NotTo(
+				HaveOccurred(),
+			)
+		})
+	})
+
 	It("should create an loader Pod if the spec is different from the status and kernels different equal", func() {
 		nmc := &kmmv1beta1.NodeModulesConfig{
 			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
@@ -595,6 +954,7 @@ var _ = Describe("nmcReconcilerHelperImpl_ProcessModuleSpec", func() {
 
 			gomock.InOrder(
 				pm.EXPECT().GetWorkerPod(ctx, podName, namespace),
+				nm.EXPECT().Uncordon(ctx, node, namespace+"/"+name),
 				nm.EXPECT().NodeBecomeReadyAfter(node, status.LastTransitionTime).Return(returnValue),
 			)
 
@@ -612,20 +972,105 @@ var _ = Describe("nmcReconcilerHelperImpl_ProcessModuleSpec", func() {
 		Entry("pod status is older then node's Ready condition, worker pod should be created", true),
 	)
 
-	It("should do nothing if the pod is not loading a kmod", func() {
-		pm.
-			EXPECT().
-			GetWorkerPod(ctx, podName, namespace).
-			Return(&v1.Pod{}, nil)
+	DescribeTable(
+		"should create a loader Pod if the boot ID of the node changed",
+		func(bootID string, shouldCreate bool) {
+			s := status.DeepCopy()
+			s.BootID = "boot-id"
+
+			n := &v1.Node{
+				Status: v1.NodeStatus{
+					NodeInfo: v1.NodeSystemInfo{BootID: bootID},
+				},
+			}
 
-		Expect(
-			wh.ProcessModuleSpec(ctx, nmc, spec, status, nil),
-		).NotTo(
-			HaveOccurred(),
-		)
-	})
+			gomock.InOrder(
+				pm.EXPECT().GetWorkerPod(ctx, podName, namespace),
+				nm.EXPECT().Uncordon(ctx, n, namespace+"/"+name),
+			)
 
-	It("should do nothing if the worker container has not restarted", func() {
+			if shouldCreate {
+				pm.EXPECT().CreateLoaderPod(ctx, nmc, spec)
+			}
+
+			Expect(
+				wh.ProcessModuleSpec(ctx, nmc, spec, s, n),
+			).NotTo(
+				HaveOccurred(),
+			)
+		},
+		Entry("same boot ID", "boot-id", false),
+		Entry("different boot ID", "other-boot-id", true),
+	)
+
+	Context("with drift detection", func() {
+		BeforeEach(func() {
+			wh = newNMCReconcilerHelper(client, pm, nil, nm, config.DriftDetection{Interval: 10 * time.Minute, Reload: true})
+		})
+
+		DescribeTable(
+			"should create a drift check Pod once the interval elapsed",
+			func(lastCheck *metav1.Time, shouldCreate bool) {
+				s := status.DeepCopy()
+				s.LastTransitionTime = metav1.NewTime(now.Add(-time.Hour))
+				s.LastDriftCheckTime = lastCheck
+
+				gomock.InOrder(
+					pm.EXPECT().GetWorkerPod(ctx, podName, namespace),
+					nm.EXPECT().Uncordon(ctx, node, namespace+"/"+name),
+					nm.EXPECT().NodeBecomeReadyAfter(node, s.LastTransitionTime).Return(false),
+				)
+
+				if shouldCreate {
+					pm.EXPECT().CreateDriftCheckPod(ctx, nmc, spec)
+				}
+
+				Expect(
+					wh.ProcessModuleSpec(ctx, nmc, spec, s, node),
+				).NotTo(
+					HaveOccurred(),
+				)
+			},
+			Entry("never checked", nil, true),
+			Entry("checked recently", &metav1.Time{Time: now.Add(-time.Minute)}, false),
+			Entry("checked long ago", &metav1.Time{Time: now.Add(-11 * time.Minute)}, true),
+		)
+
+		It("should create a loader Pod if the module drifted", func() {
+			s := status.DeepCopy()
+			s.Conditions = []metav1.Condition{
+				{Type: kmmv1beta1.ModuleConditionDrifted, Status: metav1.ConditionTrue, Reason: kmmv1beta1.ModuleReasonModuleNotLoaded},
+			}
+
+			gomock.InOrder(
+				pm.EXPECT().GetWorkerPod(ctx, podName, namespace),
+				nm.EXPECT().Uncordon(ctx, node, namespace+"/"+name),
+				nm.EXPECT().NodeBecomeReadyAfter(node, s.LastTransitionTime).Return(false),
+				pm.EXPECT().CreateLoaderPod(ctx, nmc, spec),
+			)
+
+			Expect(
+				wh.ProcessModuleSpec(ctx, nmc, spec, s, node),
+			).NotTo(
+				HaveOccurred(),
+			)
+		})
+	})
+
+	It("should do nothing if the pod is not loading a kmod", func() {
+		pm.
+			EXPECT().
+			GetWorkerPod(ctx, podName, namespace).
+			Return(&v1.Pod{}, nil)
+
+		Expect(
+			wh.ProcessModuleSpec(ctx, nmc, spec, status, nil),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+	It("should do nothing if the worker container has not restarted", func() {
 		pod := v1.Pod{
 			ObjectMeta: metav1.ObjectMeta{
 				Labels: map[string]string{actionLabelKey: WorkerActionLoad},
@@ -736,7 +1181,7 @@ var _ = Describe("nmcReconcilerHelperImpl_ProcessUnconfiguredModuleStatus", func
 		client = testclient.NewMockClient(ctrl)
 		pm = NewMockpodManager(ctrl)
 		nm = node.NewMockNode(ctrl)
-		helper = newNMCReconcilerHelper(client, pm, nil, nm)
+		helper = newNMCReconcilerHelper(client, pm, nil, nm, config.DriftDetection{})
 	})
 
 	nmc := &kmmv1beta1.NodeModulesConfig{
@@ -753,7 +1198,10 @@ var _ = Describe("nmcReconcilerHelperImpl_ProcessUnconfiguredModuleStatus", func
 	node := v1.Node{}
 
 	It("should do nothing , if the node has been rebooted/ready lately", func() {
-		nm.EXPECT().NodeBecomeReadyAfter(&node, status.LastTransitionTime).Return(true)
+		gomock.InOrder(
+			nm.EXPECT().Uncordon(ctx, &node, namespace+"/"+name),
+			nm.EXPECT().NodeBecomeReadyAfter(&node, status.LastTransitionTime).Return(true),
+		)
 
 		Expect(
 			helper.ProcessUnconfiguredModuleStatus(ctx, nmc, status, &node),
@@ -762,8 +1210,28 @@ var _ = Describe("nmcReconcilerHelperImpl_ProcessUnconfiguredModuleStatus", func
 		)
 	})
 
+	It("should do nothing if the boot ID of the node changed", func() {
+		s := status.DeepCopy()
+		s.BootID = "boot-id"
+
+		n := v1.Node{
+			Status: v1.NodeStatus{
+				NodeInfo: v1.NodeSystemInfo{BootID: "other-boot-id"},
+			},
+		}
+
+		nm.EXPECT().Uncordon(ctx, &n, namespace+"/"+name)
+
+		Expect(
+			helper.ProcessUnconfiguredModuleStatus(ctx, nmc, s, &n),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
 	It("should create an unloader Pod if no worker Pod exists", func() {
 		gomock.InOrder(
+			nm.EXPECT().Uncordon(ctx, &node, namespace+"/"+name),
 			nm.EXPECT().NodeBecomeReadyAfter(&node, status.LastTransitionTime).Return(false),
 			pm.EXPECT().GetWorkerPod(ctx, podName, namespace),
 			pm.EXPECT().CreateUnloaderPod(ctx, nmc, status),
@@ -786,6 +1254,7 @@ var _ = Describe("nmcReconcilerHelperImpl_ProcessUnconfiguredModuleStatus", func
 		}
 
 		gomock.InOrder(
+			nm.EXPECT().Uncordon(ctx, &node, namespace+"/"+name),
 			nm.EXPECT().NodeBecomeReadyAfter(&node, status.LastTransitionTime).Return(false),
 			pm.EXPECT().GetWorkerPod(ctx, podName, namespace).Return(&pod, nil),
 			pm.EXPECT().DeletePod(ctx, &pod),
@@ -807,6 +1276,7 @@ var _ = Describe("nmcReconcilerHelperImpl_ProcessUnconfiguredModuleStatus", func
 		}
 
 		gomock.InOrder(
+			nm.EXPECT().Uncordon(ctx, &node, namespace+"/"+name),
 			nm.EXPECT().NodeBecomeReadyAfter(&node, status.LastTransitionTime).Return(false),
 			pm.EXPECT().GetWorkerPod(ctx, podName, namespace).Return(&pod, nil),
 		)
@@ -835,6 +1305,7 @@ var _ = Describe("nmcReconcilerHelperImpl_ProcessUnconfiguredModuleStatus", func
 		}
 
 		gomock.InOrder(
+			nm.EXPECT().Uncordon(ctx, &node, namespace+"/"+name),
 			nm.EXPECT().NodeBecomeReadyAfter(&node, status.LastTransitionTime).Return(false),
 			pm.EXPECT().GetWorkerPod(ctx, podName, namespace).Return(&pod, nil),
 			pm.EXPECT().UnloaderPodTemplate(ctx, nmc, status).Return(nil, errors.New("random error")),
@@ -868,6 +1339,7 @@ var _ = Describe("nmcReconcilerHelperImpl_ProcessUnconfiguredModuleStatus", func
 		podTemplate.Annotations[hashAnnotationKey] = "456"
 
 		gomock.InOrder(
+			nm.EXPECT().Uncordon(ctx, &node, namespace+"/"+name),
 			nm.EXPECT().NodeBecomeReadyAfter(&node, status.LastTransitionTime).Return(false),
 			pm.EXPECT().GetWorkerPod(ctx, podName, namespace).Return(&pod, nil),
 			pm.EXPECT().UnloaderPodTemplate(ctx, nmc, status).Return(podTemplate, nil),
@@ -898,7 +1370,7 @@ var _ = Describe("nmcReconcilerHelperImpl_SyncStatus", func() {
 		ctrl = gomock.NewController(GinkgoT())
 		kubeClient = testclient.NewMockClient(ctrl)
 		pm = NewMockpodManager(ctrl)
-		wh = newNMCReconcilerHelper(kubeClient, pm, nil, nil)
+		wh = newNMCReconcilerHelper(kubeClient, pm, nil, nil, config.DriftDetection{})
 		sw = testclient.NewMockStatusWriter(ctrl)
 	})
 
@@ -915,12 +1387,68 @@ var _ = Describe("nmcReconcilerHelperImpl_SyncStatus", func() {
 		}
 
 		Expect(
-			wh.SyncStatus(ctx, nmc),
+			wh.SyncStatus(ctx, nmc, &v1.Node{}),
 		).NotTo(
 			HaveOccurred(),
 		)
 	})
 
+	DescribeTable(
+		"should record the boot ID in statuses that do not have one",
+		func(rebooted bool, expectedBootID string) {
+			nm := node.NewMockNode(ctrl)
+			wh = newNMCReconcilerHelper(kubeClient, pm, nil, nm, config.DriftDetection{})
+
+			loadTime := metav1.NewTime(time.Now().Add(-time.Hour))
+
+			nmc := &kmmv1beta1.NodeModulesConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+				Status: kmmv1beta1.NodeModulesConfigStatus{
+					Modules: []kmmv1beta1.NodeModuleStatus{
+						{
+							ModuleItem:         kmmv1beta1.ModuleItem{Name: "loaded", Namespace: namespace},
+							Config:             kmmv1beta1.ModuleConfig{KernelVersion: "some-kernel-version"},
+							LastTransitionTime: loadTime,
+						},
+						{
+							ModuleItem:         kmmv1beta1.ModuleItem{Name: "recorded", Namespace: namespace},
+							Config:             kmmv1beta1.ModuleConfig{KernelVersion: "some-kernel-version"},
+							LastTransitionTime: loadTime,
+							BootID:             "old-boot-id",
+						},
+					},
+				},
+			}
+
+			n := v1.Node{
+				Status: v1.NodeStatus{
+					NodeInfo: v1.NodeSystemInfo{BootID: "boot-id"},
+				},
+			}
+
+			pm.EXPECT().ListWorkerPodsOnNode(ctx, nmcName)
+			nm.EXPECT().NodeBecomeReadyAfter(&n, loadTime).Return(rebooted)
+
+			if !rebooted {
+				gomock.InOrder(
+					kubeClient.EXPECT().Status().Return(sw),
+					sw.EXPECT().Patch(ctx, nmc, gomock.Any()),
+				)
+			}
+
+			Expect(
+				wh.SyncStatus(ctx, nmc, &n),
+			).NotTo(
+				HaveOccurred(),
+			)
+
+			Expect(nmc.Status.Modules[0].BootID).To(Equal(expectedBootID))
+			Expect(nmc.Status.Modules[1].BootID).To(Equal("old-boot-id"))
+		},
+		Entry("node not rebooted since the module was loaded", false, "boot-id"),
+		Entry("node rebooted since the module was loaded", true, ""),
+	)
+
 	It("failed pods", func() {
 		podWithStatus := v1.Pod{
 			ObjectMeta: metav1.ObjectMeta{
@@ -970,7 +1498,7 @@ var _ = Describe("nmcReconcilerHelperImpl_SyncStatus", func() {
 		)
 
 		Expect(
-			wh.SyncStatus(ctx, nmc),
+			wh.SyncStatus(ctx, nmc, &v1.Node{}),
 		).NotTo(
 			HaveOccurred(),
 		)
@@ -1017,7 +1545,7 @@ var _ = Describe("nmcReconcilerHelperImpl_SyncStatus", func() {
 		)
 
 		Expect(
-			wh.SyncStatus(ctx, nmc),
+			wh.SyncStatus(ctx, nmc, &v1.Node{}),
 		).NotTo(
 			HaveOccurred(),
 		)
@@ -1036,12 +1564,10 @@ var _ = Describe("nmcReconcilerHelperImpl_SyncStatus", func() {
 		nmc := &kmmv1beta1.NodeModulesConfig{
 			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
 			Status: kmmv1beta1.NodeModulesConfigStatus{
-				Modules: []kmmv1beta1.NodeModuleStatus{
+				PendingModules: []kmmv1beta1.NodeModulePendingStatus{
 					{
-						ModuleItem: kmmv1beta1.ModuleItem{
-							Name:      modName,
-							Namespace: modNamespace,
-						},
+						Name:      modName,
+						Namespace: modNamespace,
 					},
 				},
 			},
@@ -1082,7 +1608,11 @@ var _ = Describe("nmcReconcilerHelperImpl_SyncStatus", func() {
 					{
 						Name: "worker",
 						State: v1.ContainerState{
-							Terminated: &v1.ContainerStateTerminated{FinishedAt: now},
+							Terminated: &v1.ContainerStateTerminated{
+								FinishedAt: now,
+								// The node rebooted after the worker loaded the module.
+								Message: `{"action":"load","bootID":"worker-boot-id"}`,
+							},
 						},
 					},
 				},
@@ -1102,8 +1632,14 @@ var _ = Describe("nmcReconcilerHelperImpl_SyncStatus", func() {
 			pm.EXPECT().DeletePod(ctx, &pod),
 		)
 
+		node := v1.Node{
+			Status: v1.NodeStatus{
+				NodeInfo: v1.NodeSystemInfo{BootID: "boot-id"},
+			},
+		}
+
 		Expect(
-			wh.SyncStatus(ctx, nmc),
+			wh.SyncStatus(ctx, nmc, &node),
 		).NotTo(
 			HaveOccurred(),
 		)
@@ -1119,9 +1655,33 @@ var _ = Describe("nmcReconcilerHelperImpl_SyncStatus", func() {
 			},
 			Config:             cfg,
 			LastTransitionTime: now,
+			BootID:             "worker-boot-id",
+			NodeModuleProgress: kmmv1beta1.NodeModuleProgress{
+				Conditions: []metav1.Condition{
+					{
+						Type:               kmmv1beta1.ModuleConditionLoaded,
+						Status:             metav1.ConditionTrue,
+						LastTransitionTime: now,
+						Reason:             kmmv1beta1.ModuleReasonWorkerPodSucceeded,
+					},
+					{
+						Type:               kmmv1beta1.ModuleConditionLoading,
+						Status:             metav1.ConditionFalse,
+						LastTransitionTime: now,
+						Reason:             kmmv1beta1.ModuleReasonWorkerPodSucceeded,
+					},
+					{
+						Type:               kmmv1beta1.ModuleConditionFailed,
+						Status:             metav1.ConditionFalse,
+						LastTransitionTime: now,
+						Reason:             kmmv1beta1.ModuleReasonWorkerPodSucceeded,
+					},
+				},
+			},
 		}
 
 		Expect(nmc.Status.Modules[0]).To(BeComparableTo(expectedStatus))
+		Expect(nmc.Status.PendingModules).To(BeEmpty())
 	})
 
 	It("pod should not be deleted if NMC patch failed", func() {
@@ -1162,124 +1722,890 @@ var _ = Describe("nmcReconcilerHelperImpl_SyncStatus", func() {
 		)
 
 		Expect(
-			wh.SyncStatus(ctx, nmc),
+			wh.SyncStatus(ctx, nmc, &v1.Node{}),
 		).To(
 			HaveOccurred(),
 		)
 	})
-})
 
-var _ = Describe("nmcReconcilerHelperImpl_RemovePodFinalizers", func() {
-	const nodeName = "node-name"
+	Context("worker results", func() {
+		const (
+			modName      = "module"
+			modNamespace = "namespace"
+			message      = `{"action":"load","modprobeArgs":["-vd","/tmp/opt","kmm_test"],"exitCode":1,` +
+				`"errorCategory":"SignatureRejected","error":"module signature rejected by the kernel"}`
+		)
 
-	var (
-		ctx = context.TODO()
+		var (
+			fakeRecorder *record.FakeRecorder
+			finishedAt   = metav1.NewTime(time.Now().Truncate(time.Second))
+			nmcObj       *kmmv1beta1.NodeModulesConfig
+			pod          v1.Pod
+		)
 
-		kubeClient *testclient.MockClient
-		pm         *MockpodManager
-		wh         nmcReconcilerHelper
-	)
+		BeforeEach(func() {
+			fakeRecorder = record.NewFakeRecorder(10)
+			wh = newNMCReconcilerHelper(kubeClient, pm, fakeRecorder, nil, config.DriftDetection{})
 
-	BeforeEach(func() {
-		ctrl := gomock.NewController(GinkgoT())
-		kubeClient = testclient.NewMockClient(ctrl)
-		pm = NewMockpodManager(ctrl)
-		wh = newNMCReconcilerHelper(kubeClient, pm, nil, nil)
-	})
+			nmcObj = &kmmv1beta1.NodeModulesConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+				Spec: kmmv1beta1.NodeModulesConfigSpec{
+					Modules: []kmmv1beta1.NodeModuleSpec{
+						{
+							ModuleItem: kmmv1beta1.ModuleItem{Name: modName, Namespace: modNamespace},
+						},
+					},
+				},
+			}
 
-	It("should do nothing if no pods are present", func() {
-		pm.EXPECT().ListWorkerPodsOnNode(ctx, nodeName)
+			pod = v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: modNamespace,
+					Name:      podName,
+					Labels: map[string]string{
+						actionLabelKey:            WorkerActionLoad,
+						constants.ModuleNameLabel: modName,
+					},
+				},
+				Status: v1.PodStatus{
+					Phase: v1.PodRunning,
+					ContainerStatuses: []v1.ContainerStatus{
+						{
+							Name:         workerContainerName,
+							RestartCount: 1,
+							LastTerminationState: v1.ContainerState{
+								Terminated: &v1.ContainerStateTerminated{
+									ExitCode:   1,
+									FinishedAt: finishedAt,
+									Message:    message,
+								},
+							},
+						},
+					},
+				},
+			}
+		})
 
-		Expect(
-			wh.RemovePodFinalizers(ctx, nodeName),
-		).NotTo(
-			HaveOccurred(),
-		)
-	})
+		It("should store the result of a failed attempt and record an event", func() {
+			gomock.InOrder(
+				pm.EXPECT().ListWorkerPodsOnNode(ctx, nmcName).Return([]v1.Pod{pod}, nil),
+				kubeClient.EXPECT().Status().Return(sw),
+				sw.EXPECT().Patch(ctx, nmcObj, gomock.Any()),
+			)
 
-	It("should patch to remove the finalizer if it is set", func() {
-		const (
-			name      = "my-pod"
-			namespace = "my-namespace"
-		)
+			Expect(
+				wh.SyncStatus(ctx, nmcObj, &v1.Node{}),
+			).NotTo(
+				HaveOccurred(),
+			)
 
-		podWithFinalizer := v1.Pod{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:       name,
-				Namespace:  namespace,
-				Finalizers: []string{nodeModulesConfigFinalizer},
-			},
-		}
+			Expect(nmcObj.Status.WorkerResults).To(Equal([]kmmv1beta1.NodeModuleWorkerResult{
+				{
+					Name:      modName,
+					Namespace: modNamespace,
+					PodName:   podName,
+					Time:      finishedAt,
+					WorkerResult: kmmv1beta1.WorkerResult{
+						Action:        "load",
+						ModprobeArgs:  []string{"-vd", "/tmp/opt", "kmm_test"},
+						ExitCode:      1,
+						ErrorCategory: "SignatureRejected",
+						Error:         "module signature rejected by the kernel",
+					},
+				},
+			}))
 
-		podWithoutFinalizer := podWithFinalizer
-		podWithoutFinalizer.Finalizers = []string{}
+			Expect(fakeRecorder.Events).To(HaveLen(1))
+			Expect(<-fakeRecorder.Events).To(
+				ContainSubstring("Warning ModuleLoadFailed Could not load module namespace/module (SignatureRejected): module signature rejected by the kernel"),
+			)
+		})
 
-		gomock.InOrder(
-			pm.EXPECT().ListWorkerPodsOnNode(ctx, nodeName).Return([]v1.Pod{podWithFinalizer, {}}, nil),
-			kubeClient.EXPECT().Patch(ctx, &podWithoutFinalizer, gomock.Any()),
-		)
+		It("should not record an event for a result that was already stored", func() {
+			nmcObj.Status.WorkerResults = []kmmv1beta1.NodeModuleWorkerResult{
+				{Name: modName, Namespace: modNamespace, PodName: podName, Time: finishedAt},
+			}
 
-		Expect(
-			wh.RemovePodFinalizers(ctx, nodeName),
-		).NotTo(
-			HaveOccurred(),
-		)
-	})
-})
+			gomock.InOrder(
+				pm.EXPECT().ListWorkerPodsOnNode(ctx, nmcName).Return([]v1.Pod{pod}, nil),
+				kubeClient.EXPECT().Status().Return(sw),
+				sw.EXPECT().Patch(ctx, nmcObj, gomock.Any()),
+			)
 
-const (
-	moduleName      = "my-module"
-	moduleNamespace = "my-module-namespace"
-	nodeName        = "node-name"
-)
+			Expect(
+				wh.SyncStatus(ctx, nmcObj, &v1.Node{}),
+			).NotTo(
+				HaveOccurred(),
+			)
 
-var kernelModuleLabelName = utils.GetKernelModuleReadyNodeLabel(moduleNamespace, moduleName)
+			Expect(fakeRecorder.Events).To(BeEmpty())
+		})
 
-var _ = Describe("nmcReconcilerHelperImpl_UpdateNodeLabels", func() {
-	var (
-		ctx             context.Context
-		client          *testclient.MockClient
-		fakeRecorder    *record.FakeRecorder
-		n               *node.MockNode
-		nmc             kmmv1beta1.NodeModulesConfig
-		wh              nmcReconcilerHelper
-		mlph            *MocklabelPreparationHelper
-		firstLabelName  string
-		secondLabelName string
-	)
+		It("should update the config but keep the load time after a parameters update", func() {
+			loadTime := metav1.NewTime(finishedAt.Add(-time.Hour))
 
-	BeforeEach(func() {
-		ctx = context.TODO()
-		ctrl := gomock.NewController(GinkgoT())
-		client = testclient.NewMockClient(ctrl)
-		nmc = kmmv1beta1.NodeModulesConfig{
-			ObjectMeta: metav1.ObjectMeta{Name: "nmcName"},
-			Spec: kmmv1beta1.NodeModulesConfigSpec{
-				Modules: []kmmv1beta1.NodeModuleSpec{
+			nmcObj.Status.Modules = []kmmv1beta1.NodeModuleStatus{
+				{
+					ModuleItem:         kmmv1beta1.ModuleItem{Name: modName, Namespace: modNamespace},
+					Config:             kmmv1beta1.ModuleConfig{KernelVersion: "some-kernel"},
+					LastTransitionTime: loadTime,
+				},
+			}
+
+			pod.Labels[actionLabelKey] = WorkerActionSetParameters
+			pod.Annotations = map[string]string{
+				configAnnotationKey: "kernelVersion: some-kernel\nmodprobe:\n  moduleName: test\n  parameters:\n  - a=2\n",
+			}
+			pod.Status = v1.PodStatus{
+				Phase: v1.PodSucceeded,
+				ContainerStatuses: []v1.ContainerStatus{
 					{
-						ModuleItem: kmmv1beta1.ModuleItem{
-							Namespace: nsFirst,
-							Name:      nameFirst,
+						Name: workerContainerName,
+						State: v1.ContainerState{
+							Terminated: &v1.ContainerStateTerminated{
+								FinishedAt: finishedAt,
+								Message:    `{"action":"setParameters","parameters":{"a":"2"}}`,
+							},
 						},
-						Config: kmmv1beta1.ModuleConfig{},
 					},
+				},
+			}
+
+			gomock.InOrder(
+				pm.EXPECT().ListWorkerPodsOnNode(ctx, nmcName).Return([]v1.Pod{pod}, nil),
+				kubeClient.EXPECT().Status().Return(sw),
+				sw.EXPECT().Patch(ctx, nmcObj, gomock.Any()),
+				pm.EXPECT().DeletePod(ctx, &pod),
+			)
+
+			Expect(
+				wh.SyncStatus(ctx, nmcObj, &v1.Node{}),
+			).NotTo(
+				HaveOccurred(),
+			)
+
+			Expect(nmcObj.Status.Modules).To(HaveLen(1))
+			Expect(nmcObj.Status.Modules[0].LastTransitionTime).To(Equal(loadTime))
+			Expect(nmcObj.Status.Modules[0].Config.Modprobe.Parameters).To(Equal([]string{"a=2"}))
+			Expect(nmcObj.Status.WorkerResults[0].Parameters).To(Equal(map[string]string{"a": "2"}))
+			Expect(nmcObj.Status.WorkerResults[0].RequestedParameters).To(Equal([]string{"a=2"}))
+		})
+
+		It("should report the blacklisted modules after a successful load", func() {
+			nmcObj.Status.Modules = []kmmv1beta1.NodeModuleStatus{
+				{
+					ModuleItem:         kmmv1beta1.ModuleItem{Name: modName, Namespace: modNamespace},
+					BlacklistedModules: []string{"previous"},
+				},
+			}
+
+			pod.Annotations = map[string]string{
+				configAnnotationKey: "kernelVersion: some-kernel\ninTreeModulesToRemove:\n- intree\nblacklistInTreeModules: true\n",
+			}
+			pod.Status = v1.PodStatus{
+				Phase: v1.PodSucceeded,
+				ContainerStatuses: []v1.ContainerStatus{
 					{
-						ModuleItem: kmmv1beta1.ModuleItem{
-							Namespace: nsSecond,
-							Name:      nameSecond,
+						Name: workerContainerName,
+						State: v1.ContainerState{
+							Terminated: &v1.ContainerStateTerminated{
+								FinishedAt: finishedAt,
+								Message:    `{"action":"load","blacklistedModules":["intree"]}`,
+							},
 						},
-						Config: kmmv1beta1.ModuleConfig{},
 					},
 				},
-			},
-		}
-		fakeRecorder = record.NewFakeRecorder(10)
-		n = node.NewMockNode(ctrl)
-		wh = newNMCReconcilerHelper(client, nil, fakeRecorder, n)
-		mlph = NewMocklabelPreparationHelper(ctrl)
-		wh = &nmcReconcilerHelperImpl{
-			client:   client,
-			pm:       nil,
+			}
+
+			gomock.InOrder(
+				pm.EXPECT().ListWorkerPodsOnNode(ctx, nmcName).Return([]v1.Pod{pod}, nil),
+				kubeClient.EXPECT().Status().Return(sw),
+				sw.EXPECT().Patch(ctx, nmcObj, gomock.Any()),
+				pm.EXPECT().DeletePod(ctx, &pod),
+			)
+
+			Expect(
+				wh.SyncStatus(ctx, nmcObj, &v1.Node{}),
+			).NotTo(
+				HaveOccurred(),
+			)
+
+			Expect(nmcObj.Status.Modules).To(HaveLen(1))
+			Expect(nmcObj.Status.Modules[0].BlacklistedModules).To(Equal([]string{"intree"}))
+		})
+
+		It("should keep the in-tree modules removed by a previous loader Pod that are still replaced", func() {
+			nmcObj.Status.Modules = []kmmv1beta1.NodeModuleStatus{
+				{
+					ModuleItem:           kmmv1beta1.ModuleItem{Name: modName, Namespace: modNamespace},
+					RemovedInTreeModules: []string{"intree1", "not-replaced-anymore"},
+				},
+			}
+
+			pod.Annotations = map[string]string{
+				configAnnotationKey: "kernelVersion: some-kernel\ninTreeModulesToRemove:\n- intree1\n- intree2\n",
+			}
+			pod.Status = v1.PodStatus{
+				Phase: v1.PodSucceeded,
+				ContainerStatuses: []v1.ContainerStatus{
+					{
+						Name: workerContainerName,
+						State: v1.ContainerState{
+							Terminated: &v1.ContainerStateTerminated{
+								FinishedAt: finishedAt,
+								Message:    `{"action":"load","removedInTreeModules":["intree2"]}`,
+							},
+						},
+					},
+				},
+			}
+
+			gomock.InOrder(
+				pm.EXPECT().ListWorkerPodsOnNode(ctx, nmcName).Return([]v1.Pod{pod}, nil),
+				kubeClient.EXPECT().Status().Return(sw),
+				sw.EXPECT().Patch(ctx, nmcObj, gomock.Any()),
+				pm.EXPECT().DeletePod(ctx, &pod),
+			)
+
+			Expect(
+				wh.SyncStatus(ctx, nmcObj, &v1.Node{}),
+			).NotTo(
+				HaveOccurred(),
+			)
+
+			Expect(nmcObj.Status.Modules).To(HaveLen(1))
+			Expect(nmcObj.Status.Modules[0].RemovedInTreeModules).To(Equal([]string{"intree1", "intree2"}))
+		})
+
+		It("should remove the result when an unloader pod was successful", func() {
+			nmcObj.Spec.Modules = nil
+			nmcObj.Status.WorkerResults = []kmmv1beta1.NodeModuleWorkerResult{
+				{Name: modName, Namespace: modNamespace, PodName: "previous-pod"},
+			}
+
+			pod.Labels[actionLabelKey] = WorkerActionUnload
+			pod.Status = v1.PodStatus{
+				Phase: v1.PodSucceeded,
+				ContainerStatuses: []v1.ContainerStatus{
+					{
+						Name: workerContainerName,
+						State: v1.ContainerState{
+							Terminated: &v1.ContainerStateTerminated{
+								FinishedAt: finishedAt,
+								Message:    `{"action":"unload","modules":["kmm_test"]}`,
+							},
+						},
+					},
+				},
+			}
+
+			gomock.InOrder(
+				pm.EXPECT().ListWorkerPodsOnNode(ctx, nmcName).Return([]v1.Pod{pod}, nil),
+				kubeClient.EXPECT().Status().Return(sw),
+				sw.EXPECT().Patch(ctx, nmcObj, gomock.Any()),
+				pm.EXPECT().DeletePod(ctx, &pod),
+			)
+
+			Expect(
+				wh.SyncStatus(ctx, nmcObj, &v1.Node{}),
+			).NotTo(
+				HaveOccurred(),
+			)
+
+			Expect(nmcObj.Status.WorkerResults).To(BeEmpty())
+			Expect(fakeRecorder.Events).To(BeEmpty())
+		})
+
+		It("should record an event if the in-tree modules could not be restored after unloading", func() {
+			nmcObj.Spec.Modules = nil
+
+			pod.Labels[actionLabelKey] = WorkerActionUnload
+			pod.Status = v1.PodStatus{
+				Phase: v1.PodSucceeded,
+				ContainerStatuses: []v1.ContainerStatus{
+					{
+						Name: workerContainerName,
+						State: v1.ContainerState{
+							Terminated: &v1.ContainerStateTerminated{
+								FinishedAt: finishedAt,
+								Message:    `{"action":"unload","inTreeModulesRestoreError":"could not load in-tree module intree: some error"}`,
+							},
+						},
+					},
+				},
+			}
+
+			gomock.InOrder(
+				pm.EXPECT().ListWorkerPodsOnNode(ctx, nmcName).Return([]v1.Pod{pod}, nil),
+				kubeClient.EXPECT().Status().Return(sw),
+				sw.EXPECT().Patch(ctx, nmcObj, gomock.Any()),
+				pm.EXPECT().DeletePod(ctx, &pod),
+			)
+
+			Expect(
+				wh.SyncStatus(ctx, nmcObj, &v1.Node{}),
+			).NotTo(
+				HaveOccurred(),
+			)
+
+			Expect(fakeRecorder.Events).To(HaveLen(1))
+			Expect(<-fakeRecorder.Events).To(
+				ContainSubstring("Warning InTreeModulesRestoreFailed Could not restore the in-tree modules replaced by module namespace/module: could not load in-tree module intree: some error"),
+			)
+		})
+	})
+
+	Context("drift checks", func() {
+		const (
+			modName      = "module"
+			modNamespace = "namespace"
+		)
+
+		var (
+			fakeRecorder *record.FakeRecorder
+			finishedAt   = metav1.NewTime(time.Now().Truncate(time.Second))
+			nmcObj       *kmmv1beta1.NodeModulesConfig
+			pod          v1.Pod
+		)
+
+		checkPod := func(phase v1.PodPhase, message string) v1.Pod {
+			return v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: modNamespace,
+					Name:      podName,
+					Labels: map[string]string{
+						actionLabelKey:            WorkerActionCheck,
+						constants.ModuleNameLabel: modName,
+					},
+				},
+				Status: v1.PodStatus{
+					Phase: phase,
+					ContainerStatuses: []v1.ContainerStatus{
+						{
+							Name: workerContainerName,
+							State: v1.ContainerState{
+								Terminated: &v1.ContainerStateTerminated{FinishedAt: finishedAt, Message: message},
+							},
+						},
+					},
+				},
+			}
+		}
+
+		BeforeEach(func() {
+			fakeRecorder = record.NewFakeRecorder(10)
+			wh = newNMCReconcilerHelper(kubeClient, pm, fakeRecorder, nil, config.DriftDetection{Interval: time.Minute})
+
+			mi := kmmv1beta1.ModuleItem{Name: modName, Namespace: modNamespace}
+
+			nmcObj = &kmmv1beta1.NodeModulesConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+				Spec: kmmv1beta1.NodeModulesConfigSpec{
+					Modules: []kmmv1beta1.NodeModuleSpec{{ModuleItem: mi}},
+				},
+				Status: kmmv1beta1.NodeModulesConfigStatus{
+					Modules: []kmmv1beta1.NodeModuleStatus{
+						{
+							ModuleItem: mi,
+							Config:     kmmv1beta1.ModuleConfig{ContainerImage: "some-image"},
+							NodeModuleProgress: kmmv1beta1.NodeModuleProgress{
+								Conditions: []metav1.Condition{
+									{Type: kmmv1beta1.ModuleConditionLoaded, Status: metav1.ConditionTrue, Reason: kmmv1beta1.ModuleReasonWorkerPodSucceeded},
+								},
+							},
+						},
+					},
+				},
+			}
+		})
+
+		It("should not do anything while the check is running", func() {
+			pod = checkPod(v1.PodRunning, "")
+
+			gomock.InOrder(
+				pm.EXPECT().ListWorkerPodsOnNode(ctx, nmcName).Return([]v1.Pod{pod}, nil),
+				kubeClient.EXPECT().Status().Return(sw),
+				sw.EXPECT().Patch(ctx, nmcObj, gomock.Any()),
+			)
+
+			Expect(
+				wh.SyncStatus(ctx, nmcObj, &v1.Node{}),
+			).NotTo(
+				HaveOccurred(),
+			)
+
+			Expect(nmcObj.Status.Modules[0].LastDriftCheckTime).To(BeNil())
+		})
+
+		It("should record the time of a successful check", func() {
+			pod = checkPod(v1.PodSucceeded, `{"action":"check"}`)
+
+			gomock.InOrder(
+				pm.EXPECT().ListWorkerPodsOnNode(ctx, nmcName).Return([]v1.Pod{pod}, nil),
+				kubeClient.EXPECT().Status().Return(sw),
+				sw.EXPECT().Patch(ctx, nmcObj, gomock.Any()),
+				pm.EXPECT().DeletePod(ctx, &pod),
+			)
+
+			Expect(
+				wh.SyncStatus(ctx, nmcObj, &v1.Node{}),
+			).NotTo(
+				HaveOccurred(),
+			)
+
+			status := nmcObj.Status.Modules[0]
+			Expect(status.LastDriftCheckTime).To(Equal(&finishedAt))
+			Expect(
+				apimeta.IsStatusConditionFalse(status.Conditions, kmmv1beta1.ModuleConditionDrifted),
+			).To(
+				BeTrue(),
+			)
+			Expect(nmcObj.Status.WorkerResults).To(BeEmpty())
+			Expect(fakeRecorder.Events).To(BeEmpty())
+		})
+
+		It("should mark the module as drifted and record an event if it is not loaded anymore", func() {
+			pod = checkPod(
+				v1.PodFailed,
+				`{"action":"check","exitCode":1,"errorCategory":"ModuleNotLoaded","error":"module is not loaded: module kmm_test is not present in /proc/modules"}`,
+			)
+
+			gomock.InOrder(
+				pm.EXPECT().ListWorkerPodsOnNode(ctx, nmcName).Return([]v1.Pod{pod}, nil),
+				kubeClient.EXPECT().Status().Return(sw),
+				sw.EXPECT().Patch(ctx, nmcObj, gomock.Any()),
+				pm.EXPECT().DeletePod(ctx, &pod),
+			)
+
+			Expect(
+				wh.SyncStatus(ctx, nmcObj, &v1.Node{}),
+			).NotTo(
+				HaveOccurred(),
+			)
+
+			status := nmcObj.Status.Modules[0]
+			Expect(status.LastDriftCheckTime).To(Equal(&finishedAt))
+
+			cond := apimeta.FindStatusCondition(status.Conditions, kmmv1beta1.ModuleConditionDrifted)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+			Expect(cond.Reason).To(Equal(kmmv1beta1.ModuleReasonModuleNotLoaded))
+			Expect(cond.Message).To(Equal("module is not loaded: module kmm_test is not present in /proc/modules"))
+			Expect(
+				apimeta.IsStatusConditionFalse(status.Conditions, kmmv1beta1.ModuleConditionLoaded),
+			).To(
+				BeTrue(),
+			)
+
+			Expect(fakeRecorder.Events).To(HaveLen(1))
+			Expect(<-fakeRecorder.Events).To(
+				ContainSubstring("Warning ModuleDrifted Module namespace/module is not loaded in the kernel anymore: module is not loaded"),
+			)
+		})
+
+		It("should not mark the module as drifted if the check could not run", func() {
+			pod = checkPod(v1.PodFailed, `{"action":"check","exitCode":1,"error":"could not open /proc/modules"}`)
+
+			gomock.InOrder(
+				pm.EXPECT().ListWorkerPodsOnNode(ctx, nmcName).Return([]v1.Pod{pod}, nil),
+				kubeClient.EXPECT().Status().Return(sw),
+				sw.EXPECT().Patch(ctx, nmcObj, gomock.Any()),
+				pm.EXPECT().DeletePod(ctx, &pod),
+			)
+
+			Expect(
+				wh.SyncStatus(ctx, nmcObj, &v1.Node{}),
+			).NotTo(
+				HaveOccurred(),
+			)
+
+			status := nmcObj.Status.Modules[0]
+			Expect(status.LastDriftCheckTime).To(Equal(&finishedAt))
+			Expect(apimeta.FindStatusCondition(status.Conditions, kmmv1beta1.ModuleConditionDrifted)).To(BeNil())
+			Expect(fakeRecorder.Events).To(BeEmpty())
+		})
+
+		It("should clear the drift once the module is loaded again", func() {
+			apimeta.SetStatusCondition(&nmcObj.Status.Modules[0].Conditions, metav1.Condition{
+				Type:   kmmv1beta1.ModuleConditionDrifted,
+				Status: metav1.ConditionTrue,
+				Reason: kmmv1beta1.ModuleReasonModuleNotLoaded,
+			})
+
+			pod = checkPod(v1.PodSucceeded, `{"action":"load"}`)
+			pod.Labels[actionLabelKey] = WorkerActionLoad
+
+			gomock.InOrder(
+				pm.EXPECT().ListWorkerPodsOnNode(ctx, nmcName).Return([]v1.Pod{pod}, nil),
+				kubeClient.EXPECT().Status().Return(sw),
+				sw.EXPECT().Patch(ctx, nmcObj, gomock.Any()),
+				pm.EXPECT().DeletePod(ctx, &pod),
+			)
+
+			Expect(
+				wh.SyncStatus(ctx, nmcObj, &v1.Node{}),
+			).NotTo(
+				HaveOccurred(),
+			)
+
+			Expect(
+				apimeta.IsStatusConditionFalse(nmcObj.Status.Modules[0].Conditions, kmmv1beta1.ModuleConditionDrifted),
+			).To(
+				BeTrue(),
+			)
+		})
+	})
+
+	Context("conditions", func() {
+		const (
+			modName      = "module"
+			modNamespace = "namespace"
+		)
+
+		var nmcObj *kmmv1beta1.NodeModulesConfig
+
+		BeforeEach(func() {
+			wh = newNMCReconcilerHelper(kubeClient, pm, record.NewFakeRecorder(10), nil, config.DriftDetection{})
+
+			nmcObj = &kmmv1beta1.NodeModulesConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+				Spec: kmmv1beta1.NodeModulesConfigSpec{
+					Modules: []kmmv1beta1.NodeModuleSpec{
+						{
+							ModuleItem: kmmv1beta1.ModuleItem{Name: modName, Namespace: modNamespace},
+						},
+					},
+				},
+			}
+		})
+
+		syncPods := func(pods ...v1.Pod) {
+			GinkgoHelper()
+
+			gomock.InOrder(
+				pm.EXPECT().ListWorkerPodsOnNode(ctx, nmcName).Return(pods, nil),
+				kubeClient.EXPECT().Status().Return(sw),
+				sw.EXPECT().Patch(ctx, nmcObj, gomock.Any()),
+			)
+
+			Expect(
+				wh.SyncStatus(ctx, nmcObj, &v1.Node{}),
+			).NotTo(
+				HaveOccurred(),
+			)
+		}
+
+		expectCondition := func(progress *kmmv1beta1.NodeModuleProgress, condType string, condStatus metav1.ConditionStatus, reason string) {
+			GinkgoHelper()
+
+			c := apimeta.FindStatusCondition(progress.Conditions, condType)
+			Expect(c).NotTo(BeNil())
+			Expect(c.Status).To(Equal(condStatus))
+			Expect(c.Reason).To(Equal(reason))
+		}
+
+		loaderPod := func(status v1.PodStatus) v1.Pod {
+			return v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: modNamespace,
+					Name:      podName,
+					Labels: map[string]string{
+						actionLabelKey:            WorkerActionLoad,
+						constants.ModuleNameLabel: modName,
+					},
+				},
+				Status: status,
+			}
+		}
+
+		It("should report a running loader Pod for a module that was never loaded", func() {
+			syncPods(loaderPod(v1.PodStatus{Phase: v1.PodRunning}))
+
+			Expect(nmcObj.Status.Modules).To(BeEmpty())
+			Expect(nmcObj.Status.PendingModules).To(HaveLen(1))
+
+			pending := &nmcObj.Status.PendingModules[0]
+			Expect(pending.Name).To(Equal(modName))
+			Expect(pending.Namespace).To(Equal(modNamespace))
+			expectCondition(&pending.NodeModuleProgress, kmmv1beta1.ModuleConditionLoading, metav1.ConditionTrue, kmmv1beta1.ModuleReasonWorkerPodRunning)
+			expectCondition(&pending.NodeModuleProgress, kmmv1beta1.ModuleConditionLoaded, metav1.ConditionFalse, kmmv1beta1.ModuleReasonWorkerPodRunning)
+			Expect(apimeta.FindStatusCondition(pending.Conditions, kmmv1beta1.ModuleConditionFailed)).To(BeNil())
+		})
+
+		It("should report the failure of the worker with the Pod name, restart count and error", func() {
+			const message = `{"action":"load","exitCode":1,"errorCategory":"ModuleNotFound","error":"module not found in modules.dep"}`
+
+			pod := loaderPod(v1.PodStatus{
+				Phase: v1.PodRunning,
+				ContainerStatuses: []v1.ContainerStatus{
+					{
+						Name:         workerContainerName,
+						RestartCount: 3,
+						LastTerminationState: v1.ContainerState{
+							Terminated: &v1.ContainerStateTerminated{ExitCode: 1, Message: message},
+						},
+					},
+				},
+			})
+
+			syncPods(pod)
+
+			Expect(nmcObj.Status.Modules).To(BeEmpty())
+
+			progress := &nmcObj.Status.PendingModules[0].NodeModuleProgress
+			expectCondition(progress, kmmv1beta1.ModuleConditionLoading, metav1.ConditionTrue, kmmv1beta1.ModuleReasonWorkerPodRunning)
+			expectCondition(progress, kmmv1beta1.ModuleConditionFailed, metav1.ConditionTrue, kmmv1beta1.ModuleReasonModprobeFailed)
+			Expect(progress.WorkerPodName).To(Equal(podName))
+			Expect(progress.WorkerRestartCount).To(BeEquivalentTo(3))
+			Expect(progress.LastError).To(Equal("module not found in modules.dep"))
+		})
+
+		It("should report an image pull failure", func() {
+			pod := loaderPod(v1.PodStatus{
+				Phase: v1.PodPending,
+				InitContainerStatuses: []v1.ContainerStatus{
+					{
+						Name:         initContainerName,
+						RestartCount: 2,
+						LastTerminationState: v1.ContainerState{
+							Terminated: &v1.ContainerStateTerminated{ExitCode: 1},
+						},
+					},
+				},
+			})
+
+			syncPods(pod)
+
+			progress := &nmcObj.Status.PendingModules[0].NodeModuleProgress
+			expectCondition(progress, kmmv1beta1.ModuleConditionLoading, metav1.ConditionTrue, kmmv1beta1.ModuleReasonWorkerPodPending)
+			expectCondition(progress, kmmv1beta1.ModuleConditionFailed, metav1.ConditionTrue, kmmv1beta1.ModuleReasonImagePullFailed)
+			Expect(progress.WorkerRestartCount).To(BeEquivalentTo(2))
+			Expect(progress.LastError).To(Equal("image-extractor container exited with code 1"))
+		})
+
+		It("should report a running unloader Pod without changing the Loaded condition", func() {
+			nmcObj.Spec.Modules = nil
+			nmcObj.Status.Modules = []kmmv1beta1.NodeModuleStatus{
+				{
+					ModuleItem: kmmv1beta1.ModuleItem{Name: modName, Namespace: modNamespace},
+					Config:     kmmv1beta1.ModuleConfig{ContainerImage: "some-image"},
+					NodeModuleProgress: kmmv1beta1.NodeModuleProgress{
+						Conditions: []metav1.Condition{
+							{Type: kmmv1beta1.ModuleConditionLoaded, Status: metav1.ConditionTrue, Reason: kmmv1beta1.ModuleReasonWorkerPodSucceeded},
+						},
+					},
+				},
+			}
+
+			pod := loaderPod(v1.PodStatus{Phase: v1.PodRunning})
+			pod.Labels[actionLabelKey] = WorkerActionUnload
+
+			syncPods(pod)
+
+			Expect(nmcObj.Status.PendingModules).To(BeEmpty())
+
+			progress := &nmcObj.Status.Modules[0].NodeModuleProgress
+			expectCondition(progress, kmmv1beta1.ModuleConditionUnloading, metav1.ConditionTrue, kmmv1beta1.ModuleReasonWorkerPodRunning)
+			expectCondition(progress, kmmv1beta1.ModuleConditionLoaded, metav1.ConditionTrue, kmmv1beta1.ModuleReasonWorkerPodSucceeded)
+		})
+
+		It("should move the progress of a module that was never loaded to its status once it is loaded", func() {
+			nmcObj.Status.PendingModules = []kmmv1beta1.NodeModulePendingStatus{
+				{
+					Name:      modName,
+					Namespace: modNamespace,
+					NodeModuleProgress: kmmv1beta1.NodeModuleProgress{
+						Conditions: []metav1.Condition{
+							{Type: kmmv1beta1.ModuleConditionFailed, Status: metav1.ConditionTrue, Reason: kmmv1beta1.ModuleReasonImagePullFailed},
+						},
+						WorkerRestartCount: 2,
+						LastError:          "image-extractor container exited with code 1",
+					},
+				},
+			}
+
+			pod := loaderPod(v1.PodStatus{
+				Phase: v1.PodSucceeded,
+				ContainerStatuses: []v1.ContainerStatus{
+					{
+						Name: workerContainerName,
+						State: v1.ContainerState{
+							Terminated: &v1.ContainerStateTerminated{FinishedAt: metav1.Now()},
+						},
+					},
+				},
+			})
+			pod.Annotations = map[string]string{configAnnotationKey: "containerImage: some-image"}
+
+			pm.EXPECT().DeletePod(ctx, &pod)
+
+			syncPods(pod)
+
+			Expect(nmcObj.Status.PendingModules).To(BeEmpty())
+			Expect(nmcObj.Status.Modules).To(HaveLen(1))
+
+			status := &nmcObj.Status.Modules[0]
+			Expect(status.Config).To(Equal(kmmv1beta1.ModuleConfig{ContainerImage: "some-image"}))
+			expectCondition(&status.NodeModuleProgress, kmmv1beta1.ModuleConditionLoaded, metav1.ConditionTrue, kmmv1beta1.ModuleReasonWorkerPodSucceeded)
+			expectCondition(&status.NodeModuleProgress, kmmv1beta1.ModuleConditionFailed, metav1.ConditionFalse, kmmv1beta1.ModuleReasonWorkerPodSucceeded)
+			Expect(status.WorkerRestartCount).To(BeZero())
+			Expect(status.LastError).To(BeEmpty())
+		})
+
+		It("should remove the pending entry of a module that is not configured anymore", func() {
+			nmcObj.Spec.Modules = nil
+			nmcObj.Status.PendingModules = []kmmv1beta1.NodeModulePendingStatus{
+				{
+					Name:      modName,
+					Namespace: modNamespace,
+					NodeModuleProgress: kmmv1beta1.NodeModuleProgress{
+						Conditions: []metav1.Condition{
+							{Type: kmmv1beta1.ModuleConditionFailed, Status: metav1.ConditionTrue, Reason: kmmv1beta1.ModuleReasonImagePullFailed},
+						},
+					},
+				},
+			}
+
+			syncPods()
+
+			Expect(nmcObj.Status.PendingModules).To(BeEmpty())
+		})
+
+		DescribeTable(
+			"should map the worker result to the reason of the Failed condition",
+			func(res kmmv1beta1.WorkerResult, reason string) {
+				Expect(workerFailureReason(&res)).To(Equal(reason))
+			},
+			Entry(
+				"firmware",
+				kmmv1beta1.WorkerResult{Action: worker.ActionLoad, ErrorCategory: worker.CategoryFirmwareCopyFailed},
+				kmmv1beta1.ModuleReasonFirmwareCopyFailed,
+			),
+			Entry(
+				"parameters",
+				kmmv1beta1.WorkerResult{Action: worker.ActionSetParameters, ErrorCategory: worker.CategoryParameterReadOnly},
+				kmmv1beta1.ModuleReasonParametersUpdateFailed,
+			),
+			Entry(
+				"modprobe",
+				kmmv1beta1.WorkerResult{Action: worker.ActionUnload, ErrorCategory: worker.CategoryModuleInUse},
+				kmmv1beta1.ModuleReasonModprobeFailed,
+			),
+		)
+	})
+})
+
+var _ = Describe("nmcReconcilerHelperImpl_RemovePodFinalizers", func() {
+	const nodeName = "node-name"
+
+	var (
+		ctx = context.TODO()
+
+		kubeClient *testclient.MockClient
+		pm         *MockpodManager
+		wh         nmcReconcilerHelper
+	)
+
+	BeforeEach(func() {
+		ctrl := gomock.NewController(GinkgoT())
+		kubeClient = testclient.NewMockClient(ctrl)
+		pm = NewMockpodManager(ctrl)
+		wh = newNMCReconcilerHelper(kubeClient, pm, nil, nil, config.DriftDetection{})
+	})
+
+	It("should do nothing if no pods are present", func() {
+		pm.EXPECT().ListWorkerPodsOnNode(ctx, nodeName)
+
+		Expect(
+			wh.RemovePodFinalizers(ctx, nodeName),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+	It("should patch to remove the finalizer if it is set", func() {
+		const (
+			name      = "my-pod"
+			namespace = "my-namespace"
+		)
+
+		podWithFinalizer := v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       name,
+				Namespace:  namespace,
+				Finalizers: []string{nodeModulesConfigFinalizer},
+			},
+		}
+
+		podWithoutFinalizer := podWithFinalizer
+		podWithoutFinalizer.Finalizers = []string{}
+
+		gomock.InOrder(
+			pm.EXPECT().ListWorkerPodsOnNode(ctx, nodeName).Return([]v1.Pod{podWithFinalizer, {}}, nil),
+			kubeClient.EXPECT().Patch(ctx, &podWithoutFinalizer, gomock.Any()),
+		)
+
+		Expect(
+			wh.RemovePodFinalizers(ctx, nodeName),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+})
+
+const (
+	moduleName      = "my-module"
+	moduleNamespace = "my-module-namespace"
+	nodeName        = "node-name"
+)
+
+var kernelModuleLabelName = utils.GetKernelModuleReadyNodeLabel(moduleNamespace, moduleName)
+
+var _ = Describe("nmcReconcilerHelperImpl_UpdateNodeLabels", func() {
+	var (
+		ctx             context.Context
+		client          *testclient.MockClient
+		fakeRecorder    *record.FakeRecorder
+		n               *node.MockNode
+		nmc             kmmv1beta1.NodeModulesConfig
+		wh              nmcReconcilerHelper
+		mlph            *MocklabelPreparationHelper
+		firstLabelName  string
+		secondLabelName string
+	)
+
+	BeforeEach(func() {
+		ctx = context.TODO()
+		ctrl := gomock.NewController(GinkgoT())
+		client = testclient.NewMockClient(ctrl)
+		nmc = kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "nmcName"},
+			Spec: kmmv1beta1.NodeModulesConfigSpec{
+				Modules: []kmmv1beta1.NodeModuleSpec{
+					{
+						ModuleItem: kmmv1beta1.ModuleItem{
+							Namespace: nsFirst,
+							Name:      nameFirst,
+						},
+						Config: kmmv1beta1.ModuleConfig{},
+					},
+					{
+						ModuleItem: kmmv1beta1.ModuleItem{
+							Namespace: nsSecond,
+							Name:      nameSecond,
+						},
+						Config: kmmv1beta1.ModuleConfig{},
+					},
+				},
+			},
+		}
+		fakeRecorder = record.NewFakeRecorder(10)
+		n = node.NewMockNode(ctrl)
+		wh = newNMCReconcilerHelper(client, nil, fakeRecorder, n, config.DriftDetection{})
+		mlph = NewMocklabelPreparationHelper(ctrl)
+		wh = &nmcReconcilerHelperImpl{
+			client:   client,
+			pm:       nil,
 			recorder: fakeRecorder,
 			nodeAPI:  n,
 			lph:      mlph,
@@ -1293,6 +2619,49 @@ var _ = Describe("nmcReconcilerHelperImpl_UpdateNodeLabels", func() {
 		err := client.Get(ctx, types.NamespacedName{Name: nmc.Name}, &v1.Node{})
 		Expect(err).To(HaveOccurred())
 	})
+	It("should remove the label of drifted modules", func() {
+		node := v1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{
+					firstLabelName: "",
+				},
+				Name: nodeName,
+			},
+		}
+
+		first := types.NamespacedName{Namespace: nsFirst, Name: nameFirst}
+
+		nmc.Status.Modules = []kmmv1beta1.NodeModuleStatus{
+			{
+				ModuleItem: kmmv1beta1.ModuleItem{Namespace: nsFirst, Name: nameFirst},
+				NodeModuleProgress: kmmv1beta1.NodeModuleProgress{
+					Conditions: []metav1.Condition{
+						{Type: kmmv1beta1.ModuleConditionDrifted, Status: metav1.ConditionTrue, Reason: kmmv1beta1.ModuleReasonModuleNotLoaded},
+					},
+				},
+			},
+		}
+
+		nodeLabels := sets.New(first)
+		specLabels := map[types.NamespacedName]kmmv1beta1.ModuleConfig{first: {}}
+		emptyMap := map[types.NamespacedName]kmmv1beta1.ModuleConfig{}
+
+		gomock.InOrder(
+			mlph.EXPECT().getNodeKernelModuleReadyLabels(node).Return(nodeLabels),
+			mlph.EXPECT().getDeprecatedKernelModuleReadyLabels(node).Return(sets.Set[string]{}),
+			mlph.EXPECT().getSpecLabelsAndTheirConfigs(&nmc).Return(specLabels),
+			mlph.EXPECT().getStatusLabelsAndTheirConfigs(&nmc).Return(map[types.NamespacedName]kmmv1beta1.ModuleConfig{first: {}}),
+			mlph.EXPECT().removeOrphanedLabels(nodeLabels, specLabels, emptyMap),
+			mlph.EXPECT().addEqualLabels(nodeLabels, specLabels, emptyMap),
+			n.EXPECT().UpdateLabels(ctx, &node, nil, []string{firstLabelName}),
+		)
+
+		loaded, unloaded, err := wh.UpdateNodeLabels(ctx, &nmc, &node)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(loaded).To(BeEmpty())
+		Expect(unloaded).To(Equal([]types.NamespacedName{first}))
+	})
+
 	It("Should fail patching node after change in labels", func() {
 		node := v1.Node{
 			ObjectMeta: metav1.ObjectMeta{
@@ -1368,7 +2737,7 @@ var _ = Describe("nmcReconcilerHelperImpl_RecordEvents", func() {
 		ctrl := gomock.NewController(GinkgoT())
 		client = testclient.NewMockClient(ctrl)
 		fakeRecorder = record.NewFakeRecorder(10)
-		wh = newNMCReconcilerHelper(client, nil, fakeRecorder, nil)
+		wh = newNMCReconcilerHelper(client, nil, fakeRecorder, nil, config.DriftDetection{})
 	})
 
 	closeAndGetAllEvents := func(events chan string) []string {
@@ -1678,6 +3047,79 @@ var _ = Describe("podManagerImpl_CreateLoaderPod", func() {
 		)
 	})
 
+	It("should apply the worker settings from the operator configuration", func() {
+		nms := &kmmv1beta1.NodeModuleSpec{
+			ModuleItem: mi,
+			Config:     moduleConfigToUse,
+		}
+
+		workerCfg := *workerCfg
+		workerCfg.PriorityClassName = "system-node-critical"
+		workerCfg.Annotations = map[string]string{"some-annotation": "some-value"}
+		workerCfg.Labels = map[string]string{
+			"some-label":              "some-value",
+			constants.ModuleNameLabel: "overridden",
+		}
+		workerCfg.Resources = &v1.ResourceRequirements{
+			Requests: v1.ResourceList{v1.ResourceMemory: resource.MustParse("256Mi")},
+			Limits:   v1.ResourceList{v1.ResourceMemory: resource.MustParse("1Gi")},
+		}
+		workerCfg.Tolerations = []v1.Toleration{
+			{Key: "some-key", Operator: v1.TolerationOpExists},
+		}
+		workerCfg.SeccompProfile = &v1.SeccompProfile{Type: v1.SeccompProfileTypeRuntimeDefault}
+
+		gomock.InOrder(
+			caHelper.EXPECT().GetClusterCA(ctx, namespace).Return(clusterCACM, nil),
+			caHelper.EXPECT().GetServiceCA(ctx, namespace).Return(serviceCACM, nil),
+		)
+
+		pm := &podManagerImpl{
+			caHelper:    caHelper,
+			client:      client,
+			scheme:      scheme,
+			workerImage: workerImage,
+			workerCfg:   &workerCfg,
+		}
+
+		pod, err := pm.baseWorkerPod(ctx, nmc, &nms.ModuleItem, &nms.Config)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(pod.Annotations).To(Equal(workerCfg.Annotations))
+		Expect(pod.Labels).To(HaveKeyWithValue("some-label", "some-value"))
+		Expect(pod.Labels).To(HaveKeyWithValue(constants.ModuleNameLabel, moduleName))
+		Expect(pod.Spec.PriorityClassName).To(Equal("system-node-critical"))
+		Expect(pod.Spec.Tolerations).To(Equal(append(moduleConfigToUse.Tolerations, workerCfg.Tolerations...)))
+		Expect(pod.Spec.SecurityContext).To(Equal(&v1.PodSecurityContext{SeccompProfile: workerCfg.SeccompProfile}))
+		Expect(pod.Spec.InitContainers[0].Resources).To(Equal(*workerCfg.Resources))
+		Expect(pod.Spec.Containers[0].Resources).To(Equal(*workerCfg.Resources))
+	})
+
+	It("should compute a different hash if the resources change", func() {
+		pod := v1.Pod{
+			Spec: v1.PodSpec{
+				Containers: []v1.Container{
+					{
+						Name: workerContainerName,
+						Resources: v1.ResourceRequirements{
+							Limits: v1.ResourceList{v1.ResourceMemory: resource.MustParse("128Mi")},
+						},
+					},
+				},
+			},
+		}
+
+		hash1, err := podHash(&pod)
+		Expect(err).NotTo(HaveOccurred())
+
+		pod.Spec.Containers[0].Resources.Limits[v1.ResourceMemory] = resource.MustParse("256Mi")
+
+		hash2, err := podHash(&pod)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(hash1).NotTo(Equal(hash2))
+	})
+
 	It("imagePullSecret should not be defined, if missing from ModuleItem", func() {
 		mi.ImageRepoSecret = nil
 		nms := &kmmv1beta1.NodeModuleSpec{
@@ -1706,7 +3148,7 @@ var _ = Describe("podManagerImpl_CreateLoaderPod", func() {
 			SELinuxOptions: &v1.SELinuxOptions{Type: workerCfg.SELinuxType},
 		}
 
-		hash, err := hashstructure.Hash(expected, hashstructure.FormatV2, nil)
+		hash, err := podHash(expected)
 		Expect(err).NotTo(HaveOccurred())
 
 		expected.Annotations[hashAnnotationKey] = fmt.Sprintf("%d", hash)
@@ -1777,39 +3219,281 @@ var _ = Describe("podManagerImpl_CreateLoaderPod", func() {
 				}
 			}
 
-			hash, err := hashstructure.Hash(expected, hashstructure.FormatV2, nil)
+			hash, err := podHash(expected)
 			Expect(err).NotTo(HaveOccurred())
 
-			expected.Annotations[hashAnnotationKey] = fmt.Sprintf("%d", hash)
+			expected.Annotations[hashAnnotationKey] = fmt.Sprintf("%d", hash)
+
+			gomock.InOrder(
+				caHelper.EXPECT().GetClusterCA(ctx, namespace).Return(clusterCACM, nil),
+				caHelper.EXPECT().GetServiceCA(ctx, namespace).Return(serviceCACM, nil),
+				client.EXPECT().Create(ctx, cmpmock.DiffEq(expected)),
+			)
+
+			workerCfg := *workerCfg
+			workerCfg.FirmwareHostPath = firmwareHostPath
+
+			pm := &podManagerImpl{
+				caHelper:    caHelper,
+				client:      client,
+				scheme:      scheme,
+				workerImage: workerImage,
+				workerCfg:   &workerCfg,
+			}
+
+			Expect(
+				pm.CreateLoaderPod(ctx, nmc, nms),
+			).NotTo(
+				HaveOccurred(),
+			)
+		},
+		Entry("firmwareHostPath not set, firmware loading not requested", nil, false),
+		Entry("firmwareHostPath set to empty string, firmware loading not requested", ptr.To(""), false),
+		Entry("firmwareHostPath set, firmware loading not requested", ptr.To("some-path"), false),
+		Entry("firmwareHostPath set , firmware loading requested", ptr.To("some-path"), true),
+	)
+
+	It("should pull the image from the worker if pullImages is set", func() {
+		const globalPullSecretHostPath = "/var/lib/kubelet/config.json"
+
+		firmwareHostPath := ptr.To("some-path")
+		moduleConfigToUse.Modprobe.FirmwarePath = "/firmware-path"
+
+		nms := &kmmv1beta1.NodeModuleSpec{
+			ModuleItem: mi,
+			Config:     moduleConfigToUse,
+		}
+
+		expected := getBaseWorkerPod("load", nmc, firmwareHostPath, true, true, mi.ImageRepoSecret)
+
+		hostPathFile := v1.HostPathFile
+
+		// The image-extractor container runs the worker image, so the worker binary does not need to be copied.
+		expected.Spec.InitContainers = []v1.Container{
+			{
+				Name:  "image-extractor",
+				Image: workerImage,
+				Args:  []string{"image", "pull", "/etc/kmm-worker/config.yaml"},
+				Resources: v1.ResourceRequirements{
+					Limits:   limits,
+					Requests: requests,
+				},
+				VolumeMounts: []v1.VolumeMount{
+					{
+						Name:      volNameConfig,
+						MountPath: "/etc/kmm-worker",
+						ReadOnly:  true,
+					},
+					{
+						Name:      "etc-containers",
+						MountPath: "/etc/containers",
+						ReadOnly:  true,
+					},
+					{
+						Name:      "trusted-ca",
+						MountPath: "/etc/pki/tls/certs",
+						ReadOnly:  true,
+					},
+					{
+						Name:      volNameTmp,
+						MountPath: sharedFilesDir,
+					},
+					{
+						Name:      "image-repo-secret",
+						MountPath: "/var/run/kmm/pull-secrets",
+						ReadOnly:  true,
+					},
+					{
+						Name:      "global-pull-secret",
+						MountPath: "/var/lib/kubelet/config.json",
+						ReadOnly:  true,
+					},
+				},
+			},
+		}
+
+		// The worker Pod template sets the modules-order and firmware volumes after the base Pod is created.
+		n := len(expected.Spec.Volumes)
+		tail := append([]v1.Volume{}, expected.Spec.Volumes[n-2:]...)
+
+		expected.Spec.Volumes = append(
+			expected.Spec.Volumes[:n-3],
+			v1.Volume{
+				Name: "image-repo-secret",
+				VolumeSource: v1.VolumeSource{
+					Secret: &v1.SecretVolumeSource{SecretName: irsName},
+				},
+			},
+			v1.Volume{
+				Name: "global-pull-secret",
+				VolumeSource: v1.VolumeSource{
+					HostPath: &v1.HostPathVolumeSource{
+						Path: globalPullSecretHostPath,
+						Type: &hostPathFile,
+					},
+				},
+			},
+		)
+		expected.Spec.Volumes = append(expected.Spec.Volumes, tail...)
+
+		container, _ := podcmd.FindContainerByName(expected, "worker")
+		Expect(container).NotTo(BeNil())
+
+		container.SecurityContext = &v1.SecurityContext{
+			Privileged: ptr.To(true),
+		}
+
+		hash, err := podHash(expected)
+		Expect(err).NotTo(HaveOccurred())
+
+		expected.Annotations[hashAnnotationKey] = fmt.Sprintf("%d", hash)
+
+		gomock.InOrder(
+			caHelper.EXPECT().GetClusterCA(ctx, namespace).Return(clusterCACM, nil),
+			caHelper.EXPECT().GetServiceCA(ctx, namespace).Return(serviceCACM, nil),
+			client.EXPECT().Create(ctx, cmpmock.DiffEq(expected)),
+		)
+
+		workerCfg := *workerCfg
+		workerCfg.FirmwareHostPath = firmwareHostPath
+		workerCfg.PullImages = true
+		workerCfg.GlobalPullSecretHostPath = ptr.To(globalPullSecretHostPath)
+
+		pm := &podManagerImpl{
+			caHelper:    caHelper,
+			client:      client,
+			scheme:      scheme,
+			workerImage: workerImage,
+			workerCfg:   &workerCfg,
+		}
+
+		Expect(
+			pm.CreateLoaderPod(ctx, nmc, nms),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+	DescribeTable(
+		"should mount what the worker needs to verify module signatures",
+		func(sv *kmmv1beta1.SignatureVerificationSpec, expectedVolume v1.Volume, expectedPrivileged bool) {
+			moduleConfigToUse.Modprobe.SignatureVerification = sv
+
+			nms := &kmmv1beta1.NodeModuleSpec{
+				ModuleItem: mi,
+				Config:     moduleConfigToUse,
+			}
+
+			var created *v1.Pod
 
 			gomock.InOrder(
 				caHelper.EXPECT().GetClusterCA(ctx, namespace).Return(clusterCACM, nil),
 				caHelper.EXPECT().GetServiceCA(ctx, namespace).Return(serviceCACM, nil),
-				client.EXPECT().Create(ctx, cmpmock.DiffEq(expected)),
+				client.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(
+					func(_ context.Context, obj ctrlclient.Object, _ ...ctrlclient.CreateOption) error {
+						created = obj.(*v1.Pod)
+						return nil
+					},
+				),
 			)
 
-			workerCfg := *workerCfg
-			workerCfg.FirmwareHostPath = firmwareHostPath
-
-			pm := &podManagerImpl{
-				caHelper:    caHelper,
-				client:      client,
-				scheme:      scheme,
-				workerImage: workerImage,
-				workerCfg:   &workerCfg,
-			}
+			pm := newPodManager(client, workerImage, scheme, caHelper, workerCfg)
 
 			Expect(
 				pm.CreateLoaderPod(ctx, nmc, nms),
 			).NotTo(
 				HaveOccurred(),
 			)
+
+			Expect(created.Spec.Volumes).To(ContainElement(expectedVolume))
+
+			container, _ := podcmd.FindContainerByName(created, workerContainerName)
+			Expect(container).NotTo(BeNil())
+			Expect(container.VolumeMounts).To(ContainElement(HaveField("Name", expectedVolume.Name)))
+			Expect(ptr.Deref(container.SecurityContext.Privileged, false)).To(Equal(expectedPrivileged))
 		},
-		Entry("firmwareHostPath not set, firmware loading not requested", nil, false),
-		Entry("firmwareHostPath set to empty string, firmware loading not requested", ptr.To(""), false),
-		Entry("firmwareHostPath set, firmware loading not requested", ptr.To("some-path"), false),
-		Entry("firmwareHostPath set , firmware loading requested", ptr.To("some-path"), true),
+		Entry(
+			"certificate secret",
+			&kmmv1beta1.SignatureVerificationSpec{CertSecret: &v1.LocalObjectReference{Name: "signing-cert"}},
+			v1.Volume{
+				Name: "signature-cert",
+				VolumeSource: v1.VolumeSource{
+					Secret: &v1.SecretVolumeSource{
+						SecretName: "signing-cert",
+						Items:      []v1.KeyToPath{{Key: "cert", Path: "cert"}},
+					},
+				},
+			},
+			false,
+		),
+		Entry(
+			"UEFI keys",
+			&kmmv1beta1.SignatureVerificationSpec{UEFIKeys: true},
+			v1.Volume{
+				Name: "sys-firmware",
+				VolumeSource: v1.VolumeSource{
+					HostPath: &v1.HostPathVolumeSource{
+						Path: "/sys/firmware",
+						Type: ptr.To(v1.HostPathDirectory),
+					},
+				},
+			},
+			true,
+		),
 	)
+
+	It("should mount the host's modprobe.d directory if the in-tree modules are blacklisted", func() {
+		moduleConfigToUse.InTreeModulesToRemove = []string{"intree"}
+		moduleConfigToUse.BlacklistInTreeModules = true
+
+		nms := &kmmv1beta1.NodeModuleSpec{
+			ModuleItem: mi,
+			Config:     moduleConfigToUse,
+		}
+
+		var created *v1.Pod
+
+		gomock.InOrder(
+			caHelper.EXPECT().GetClusterCA(ctx, namespace).Return(clusterCACM, nil),
+			caHelper.EXPECT().GetServiceCA(ctx, namespace).Return(serviceCACM, nil),
+			client.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(
+				func(_ context.Context, obj ctrlclient.Object, _ ...ctrlclient.CreateOption) error {
+					created = obj.(*v1.Pod)
+					return nil
+				},
+			),
+		)
+
+		pm := newPodManager(client, workerImage, scheme, caHelper, workerCfg)
+
+		Expect(
+			pm.CreateLoaderPod(ctx, nmc, nms),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(created.Spec.Volumes).To(
+			ContainElement(v1.Volume{
+				Name: "host-modprobe-d",
+				VolumeSource: v1.VolumeSource{
+					HostPath: &v1.HostPathVolumeSource{
+						Path: "/etc/modprobe.d",
+						Type: ptr.To(v1.HostPathDirectoryOrCreate),
+					},
+				},
+			}),
+		)
+
+		container, _ := podcmd.FindContainerByName(created, workerContainerName)
+		Expect(container).NotTo(BeNil())
+		Expect(container.Args).To(
+			ContainElements("--"+worker.FlagBlacklistPath, "/var/run/kmm/modprobe.d/kmm-"+namespace+"-"+moduleName+".conf"),
+		)
+		Expect(container.VolumeMounts).To(
+			ContainElement(v1.VolumeMount{Name: "host-modprobe-d", MountPath: worker.HostModprobeConfDir}),
+		)
+		Expect(ptr.Deref(container.SecurityContext.Privileged, false)).To(BeTrue())
+	})
 })
 
 var _ = Describe("podManagerImpl_CreateUnloaderPod", func() {
@@ -1885,7 +3569,7 @@ var _ = Describe("podManagerImpl_CreateUnloaderPod", func() {
 			SELinuxOptions: &v1.SELinuxOptions{Type: workerCfg.SELinuxType},
 		}
 
-		hash, err := hashstructure.Hash(expected, hashstructure.FormatV2, nil)
+		hash, err := podHash(expected)
 		Expect(err).NotTo(HaveOccurred())
 
 		expected.Annotations[hashAnnotationKey] = fmt.Sprintf("%d", hash)
@@ -1907,6 +3591,291 @@ var _ = Describe("podManagerImpl_CreateUnloaderPod", func() {
 			HaveOccurred(),
 		)
 	})
+
+	It("should share the host PID namespace if the worker waits for the module to be unused", func() {
+		status.Config.Modprobe.FirmwarePath = ""
+		status.Config.Modprobe.UnloadWaitTimeout = &metav1.Duration{Duration: time.Minute}
+
+		var created *v1.Pod
+
+		gomock.InOrder(
+			caHelper.EXPECT().GetClusterCA(ctx, namespace).Return(clusterCACM, nil),
+			caHelper.EXPECT().GetServiceCA(ctx, namespace).Return(serviceCACM, nil),
+			client.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(
+				func(_ context.Context, obj ctrlclient.Object, _ ...ctrlclient.CreateOption) error {
+					created = obj.(*v1.Pod)
+					return nil
+				},
+			),
+		)
+
+		pm := newPodManager(client, workerImage, scheme, caHelper, workerCfg)
+
+		Expect(
+			pm.CreateUnloaderPod(ctx, nmc, status),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(created.Spec.HostPID).To(BeTrue())
+
+		container, _ := podcmd.FindContainerByName(created, workerContainerName)
+		Expect(container).NotTo(BeNil())
+		Expect(container.SecurityContext).To(Equal(&v1.SecurityContext{Privileged: ptr.To(true)}))
+	})
+	It("should remove the blacklist if the in-tree modules were blacklisted", func() {
+		status.Config.Modprobe.FirmwarePath = ""
+		status.Config.BlacklistInTreeModules = true
+
+		var created *v1.Pod
+
+		gomock.InOrder(
+			caHelper.EXPECT().GetClusterCA(ctx, namespace).Return(clusterCACM, nil),
+			caHelper.EXPECT().GetServiceCA(ctx, namespace).Return(serviceCACM, nil),
+			client.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(
+				func(_ context.Context, obj ctrlclient.Object, _ ...ctrlclient.CreateOption) error {
+					created = obj.(*v1.Pod)
+					return nil
+				},
+			),
+		)
+
+		pm := newPodManager(client, workerImage, scheme, caHelper, workerCfg)
+
+		Expect(
+			pm.CreateUnloaderPod(ctx, nmc, status),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		container, _ := podcmd.FindContainerByName(created, workerContainerName)
+		Expect(container).NotTo(BeNil())
+		Expect(container.Args).To(
+			ContainElements("--"+worker.FlagBlacklistPath, "/var/run/kmm/modprobe.d/kmm-"+namespace+"-"+moduleName+".conf"),
+		)
+		Expect(container.VolumeMounts).To(ContainElement(HaveField("Name", "host-modprobe-d")))
+		Expect(container.SecurityContext).To(Equal(&v1.SecurityContext{Privileged: ptr.To(true)}))
+	})
+
+	DescribeTable(
+		"should restore the in-tree modules that were removed only if configured",
+		func(restore bool, removed []string, expectFlag bool) {
+			status.Config.Modprobe.FirmwarePath = ""
+			status.Config.RestoreInTreeModulesOnUnload = restore
+			status.RemovedInTreeModules = removed
+
+			var created *v1.Pod
+
+			gomock.InOrder(
+				caHelper.EXPECT().GetClusterCA(ctx, namespace).Return(clusterCACM, nil),
+				caHelper.EXPECT().GetServiceCA(ctx, namespace).Return(serviceCACM, nil),
+				client.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(
+					func(_ context.Context, obj ctrlclient.Object, _ ...ctrlclient.CreateOption) error {
+						created = obj.(*v1.Pod)
+						return nil
+					},
+				),
+			)
+
+			pm := newPodManager(client, workerImage, scheme, caHelper, workerCfg)
+
+			Expect(
+				pm.CreateUnloaderPod(ctx, nmc, status),
+			).NotTo(
+				HaveOccurred(),
+			)
+
+			container, _ := podcmd.FindContainerByName(created, workerContainerName)
+			Expect(container).NotTo(BeNil())
+
+			m := ContainElements("--"+worker.FlagRestoreInTreeModules, "intree1,intree2")
+			if !expectFlag {
+				m = Not(ContainElement("--" + worker.FlagRestoreInTreeModules))
+			}
+
+			Expect(container.Args).To(m)
+		},
+		Entry("enabled with removed modules", true, []string{"intree1", "intree2"}, true),
+		Entry("enabled without removed modules", true, nil, false),
+		Entry("disabled", false, []string{"intree1", "intree2"}, false),
+	)
+})
+
+var _ = Describe("podManagerImpl_CreateParametersUpdaterPod", func() {
+	It("should create a privileged Pod that does not pull the kmod image", func() {
+		ctrl := gomock.NewController(GinkgoT())
+		client := testclient.NewMockClient(ctrl)
+		caHelper := ca.NewMockHelper(ctrl)
+		ctx := context.TODO()
+
+		nmcObj := &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+		}
+
+		spec := &kmmv1beta1.NodeModuleSpec{
+			ModuleItem: kmmv1beta1.ModuleItem{
+				Name:               moduleName,
+				Namespace:          namespace,
+				ServiceAccountName: serviceAccountName,
+			},
+			Config: moduleConfig,
+		}
+
+		var created *v1.Pod
+
+		gomock.InOrder(
+			caHelper.EXPECT().GetClusterCA(ctx, namespace).Return(clusterCACM, nil),
+			caHelper.EXPECT().GetServiceCA(ctx, namespace).Return(serviceCACM, nil),
+			client.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(
+				func(_ context.Context, obj ctrlclient.Object, _ ...ctrlclient.CreateOption) error {
+					created = obj.(*v1.Pod)
+					return nil
+				},
+			),
+		)
+
+		pm := newPodManager(client, workerImage, scheme, caHelper, workerCfg)
+
+		Expect(
+			pm.CreateParametersUpdaterPod(ctx, nmcObj, spec),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(created.Labels).To(HaveKeyWithValue(actionLabelKey, WorkerActionSetParameters))
+		Expect(created.Annotations).To(HaveKey(hashAnnotationKey))
+		Expect(created.Spec.InitContainers).To(BeEmpty())
+		Expect(created.Spec.RestartPolicy).To(Equal(v1.RestartPolicyNever))
+
+		container, _ := podcmd.FindContainerByName(created, workerContainerName)
+		Expect(container).NotTo(BeNil())
+		Expect(container.Args).To(Equal([]string{"kmod", "set-params", configFullPath}))
+		Expect(container.SecurityContext).To(Equal(&v1.SecurityContext{Privileged: ptr.To(true)}))
+	})
+})
+
+var _ = Describe("podManagerImpl_CreateDriftCheckPod", func() {
+	It("should create an unprivileged Pod that does not pull the kmod image", func() {
+		ctrl := gomock.NewController(GinkgoT())
+		client := testclient.NewMockClient(ctrl)
+		caHelper := ca.NewMockHelper(ctrl)
+		ctx := context.TODO()
+
+		nmcObj := &kmmv1beta1.NodeModulesConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nmcName},
+		}
+
+		spec := &kmmv1beta1.NodeModuleSpec{
+			ModuleItem: kmmv1beta1.ModuleItem{
+				Name:               moduleName,
+				Namespace:          namespace,
+				ServiceAccountName: serviceAccountName,
+			},
+			Config: moduleConfig,
+		}
+
+		var created *v1.Pod
+
+		gomock.InOrder(
+			caHelper.EXPECT().GetClusterCA(ctx, namespace).Return(clusterCACM, nil),
+			caHelper.EXPECT().GetServiceCA(ctx, namespace).Return(serviceCACM, nil),
+			client.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(
+				func(_ context.Context, obj ctrlclient.Object, _ ...ctrlclient.CreateOption) error {
+					created = obj.(*v1.Pod)
+					return nil
+				},
+			),
+		)
+
+		pm := newPodManager(client, workerImage, scheme, caHelper, workerCfg)
+
+		Expect(
+			pm.CreateDriftCheckPod(ctx, nmcObj, spec),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(created.Labels).To(HaveKeyWithValue(actionLabelKey, WorkerActionCheck))
+		Expect(created.Annotations).To(HaveKey(hashAnnotationKey))
+		Expect(created.Spec.InitContainers).To(BeEmpty())
+		Expect(created.Spec.RestartPolicy).To(Equal(v1.RestartPolicyNever))
+
+		container, _ := podcmd.FindContainerByName(created, workerContainerName)
+		Expect(container).NotTo(BeNil())
+		Expect(container.Args).To(Equal([]string{"kmod", "check", configFullPath}))
+		Expect(container.SecurityContext.Privileged).To(BeNil())
+	})
+})
+
+var _ = Describe("nextDriftCheck", func() {
+	now := time.Now()
+
+	It("should return the zero time if no module is loaded", func() {
+		nmcObj := &kmmv1beta1.NodeModulesConfig{
+			Status: kmmv1beta1.NodeModulesConfigStatus{
+				PendingModules: []kmmv1beta1.NodeModulePendingStatus{
+					{Name: "never-loaded"},
+				},
+			},
+		}
+
+		Expect(nextDriftCheck(nmcObj, time.Minute)).To(BeZero())
+	})
+
+	It("should return the earliest check", func() {
+		nmcObj := &kmmv1beta1.NodeModulesConfig{
+			Status: kmmv1beta1.NodeModulesConfigStatus{
+				Modules: []kmmv1beta1.NodeModuleStatus{
+					{
+						Config:             kmmv1beta1.ModuleConfig{ContainerImage: "some-image"},
+						LastTransitionTime: metav1.NewTime(now.Add(-time.Hour)),
+						LastDriftCheckTime: &metav1.Time{Time: now.Add(-time.Minute)},
+					},
+					{
+						Config:             kmmv1beta1.ModuleConfig{ContainerImage: "some-image"},
+						LastTransitionTime: metav1.NewTime(now.Add(-2 * time.Minute)),
+					},
+				},
+			},
+		}
+
+		Expect(nextDriftCheck(nmcObj, 10*time.Minute)).To(BeTemporally("==", now.Add(8*time.Minute)))
+	})
+})
+
+var _ = Describe("onlyParametersChanged", func() {
+	config := func(params ...string) *kmmv1beta1.ModuleConfig {
+		return &kmmv1beta1.ModuleConfig{
+			ContainerImage: "some-image",
+			Modprobe:       kmmv1beta1.ModprobeSpec{ModuleName: "test", Parameters: params},
+		}
+	}
+
+	It("should return true if parameters were only changed or added", func() {
+		Expect(onlyParametersChanged(config("a=2", "b=1"), config("a=1"))).To(BeTrue())
+	})
+
+	It("should treat dashes and underscores as equivalent", func() {
+		Expect(onlyParametersChanged(config("some-param=2"), config("some_param=1"))).To(BeTrue())
+	})
+
+	It("should return false if a parameter was removed", func() {
+		Expect(onlyParametersChanged(config("a=1"), config("a=1", "b=1"))).To(BeFalse())
+	})
+
+	It("should return false if another field changed", func() {
+		spec := config("a=2")
+		spec.ContainerImage = "other-image"
+
+		Expect(onlyParametersChanged(spec, config("a=1"))).To(BeFalse())
+	})
+
+	It("should return false if rawArgs are used", func() {
+		spec := config("a=2")
+		spec.Modprobe.RawArgs = &kmmv1beta1.ModprobeArgs{Load: []string{"a"}}
+
+		Expect(onlyParametersChanged(spec, config("a=1"))).To(BeFalse())
+	})
 })
 
 var _ = Describe("podManagerImpl_DeletePod", func() {
@@ -2041,22 +4010,11 @@ modprobe:
 `
 	modulesOrderValue := `softdep a pre: b
 softdep b pre: c
-`
-
-	var initContainerArg = `
-mkdir -p /tmp/dir/lib/modules;
-cp -R /dir/lib/modules/kernel-version /tmp/dir/lib/modules;
-`
-
-	const initContainerArgFirmwareAddition = `
-mkdir -p /tmp/firmware-path;
-cp -R /firmware-path/* /tmp/firmware-path;
 `
 
 	args := []string{"kmod", subcommand, "/etc/kmm-worker/config.yaml"}
 	if withFirmware {
 		args = append(args, "--firmware-path", *firmwareHostPath)
-		initContainerArg = strings.Join([]string{initContainerArg, initContainerArgFirmwareAddition}, "")
 	} else {
 		configAnnotationValue = strings.ReplaceAll(configAnnotationValue, "firmwarePath: /firmware-path\n  ", "")
 	}
@@ -2078,21 +4036,46 @@ cp -R /firmware-path/* /tmp/firmware-path;
 		},
 		Spec: v1.PodSpec{
 			InitContainers: []v1.Container{
+				{
+					Name:  "worker-binary",
+					Image: workerImage,
+					Args:  []string{"image", "copy-binary", "/var/run/kmm/bin/worker"},
+					Resources: v1.ResourceRequirements{
+						Limits:   limits,
+						Requests: requests,
+					},
+					VolumeMounts: []v1.VolumeMount{
+						{
+							Name:      "worker-bin",
+							MountPath: "/var/run/kmm/bin",
+						},
+					},
+				},
 				{
 					Name:            "image-extractor",
 					Image:           "container image",
 					ImagePullPolicy: v1.PullIfNotPresent,
-					Command:         []string{"/bin/sh", "-c"},
-					Args:            []string{initContainerArg},
+					Command:         []string{"/var/run/kmm/bin/worker"},
+					Args:            []string{"image", "extract", "/etc/kmm-worker/config.yaml"},
 					Resources: v1.ResourceRequirements{
 						Limits:   limits,
 						Requests: requests,
 					},
 					VolumeMounts: []v1.VolumeMount{
+						{
+							Name:      volNameConfig,
+							MountPath: "/etc/kmm-worker",
+							ReadOnly:  true,
+						},
 						{
 							Name:      volNameTmp,
 							MountPath: sharedFilesDir,
 						},
+						{
+							Name:      "worker-bin",
+							MountPath: "/var/run/kmm/bin",
+							ReadOnly:  true,
+						},
 					},
 				},
 			},
@@ -2228,6 +4211,12 @@ cp -R /firmware-path/* /tmp/firmware-path;
 						EmptyDir: &v1.EmptyDirVolumeSource{},
 					},
 				},
+				{
+					Name: "worker-bin",
+					VolumeSource: v1.VolumeSource{
+						EmptyDir: &v1.EmptyDirVolumeSource{},
+					},
+				},
 				{
 					Name: volNameModulesOrder,
 					VolumeSource: v1.VolumeSource{
@@ -0,0 +1,167 @@
+package kernel
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// operators are ordered so that two-character operators are matched before their one-character prefixes.
+var operators = []string{">=", "<=", "!=", ">", "<", "="}
+
+type constraint struct {
+	op      string
+	version *Version
+}
+
+func (c constraint) matches(v *Version) bool {
+	cmp := v.Compare(c.version)
+
+	switch c.op {
+	case ">=":
+		return cmp >= 0
+	case "<=":
+		return cmp <= 0
+	case "!=":
+		return cmp != 0
+	case ">":
+		return cmp > 0
+	case "<":
+		return cmp < 0
+	default:
+		return cmp == 0
+	}
+}
+
+// Range is a set of kernel versions defined by comma-separated constraints such as ">=5.14.0-284, <5.14.0-400".
+// A kernel is in the range if it satisfies all constraints.
+// Kernels must also have the flavour of the versions in the constraints, and their architecture if they have one.
+type Range struct {
+	constraints []constraint
+	arch        string
+	flavour     string
+}
+
+// ParseRange parses version constraints.
+// It returns an error if the constraints do not agree on the flavour or on the architecture, or if no version can
+// satisfy all of them.
+func ParseRange(s string) (*Range, error) {
+	r := &Range{}
+
+	for i, term := range strings.Split(s, ",") {
+		term = strings.TrimSpace(term)
+
+		idx := -1
+
+		for j, op := range operators {
+			if strings.HasPrefix(term, op) {
+				idx = j
+				break
+			}
+		}
+
+		if idx == -1 {
+			return nil, fmt.Errorf("%q: expected one of the operators %s", term, strings.Join(operators, " "))
+		}
+
+		op := operators[idx]
+
+		v, err := ParseVersion(strings.TrimSpace(strings.TrimPrefix(term, op)))
+		if err != nil {
+			return nil, err
+		}
+
+		if i == 0 {
+			r.arch = v.Arch
+			r.flavour = v.Flavour
+		} else if v.Arch != r.arch || v.Flavour != r.flavour {
+			return nil, fmt.Errorf("%q: all versions must have the same architecture and flavour", term)
+		}
+
+		r.constraints = append(r.constraints, constraint{op: op, version: v})
+	}
+
+	if !satisfiable(r.constraints) {
+		return nil, errors.New("no version satisfies all constraints")
+	}
+
+	return r, nil
+}
+
+// Contains returns true if v is in r.
+func (r *Range) Contains(v *Version) bool {
+	if v.Flavour != r.flavour || (r.arch != "" && v.Arch != r.arch) {
+		return false
+	}
+
+	for _, c := range r.constraints {
+		if !c.matches(v) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Overlaps returns true if at least one kernel version is in both r and other.
+func (r *Range) Overlaps(other *Range) bool {
+	if r.flavour != other.flavour || (r.arch != "" && other.arch != "" && r.arch != other.arch) {
+		return false
+	}
+
+	return satisfiable(append(slices.Clone(r.constraints), other.constraints...))
+}
+
+// satisfiable returns true if at least one version satisfies all constraints.
+// There is always a version between two different versions, as more release numbers can be added to the lower one.
+func satisfiable(constraints []constraint) bool {
+	var (
+		lower, upper                   *Version
+		lowerInclusive, upperInclusive bool
+	)
+
+	for _, c := range constraints {
+		v := c.version
+
+		if c.op == ">=" || c.op == ">" || c.op == "=" {
+			inclusive := c.op != ">"
+
+			if lower == nil {
+				lower, lowerInclusive = v, inclusive
+			} else if cmp := v.Compare(lower); cmp > 0 || (cmp == 0 && !inclusive) {
+				lower, lowerInclusive = v, inclusive
+			}
+		}
+
+		if c.op == "<=" || c.op == "<" || c.op == "=" {
+			inclusive := c.op != "<"
+
+			if upper == nil {
+				upper, upperInclusive = v, inclusive
+			} else if cmp := v.Compare(upper); cmp < 0 || (cmp == 0 && !inclusive) {
+				upper, upperInclusive = v, inclusive
+			}
+		}
+	}
+
+	if lower == nil || upper == nil {
+		return true
+	}
+
+	switch cmp := lower.Compare(upper); {
+	case cmp < 0:
+		return true
+	case cmp > 0 || !lowerInclusive || !upperInclusive:
+		return false
+	}
+
+	// The range holds a single version; check that it is not excluded.
+	for _, c := range constraints {
+		if c.op == "!=" && lower.Compare(c.version) == 0 {
+			return false
+		}
+	}
+
+	return true
+}
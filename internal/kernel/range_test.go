@@ -0,0 +1,72 @@
+package kernel
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ParseRange", func() {
+	DescribeTable(
+		"should return an error for invalid ranges",
+		func(s string) {
+			_, err := ParseRange(s)
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("empty", ""),
+		Entry("empty constraint", ">=5.14.0-284,"),
+		Entry("missing operator", "5.14.0-284"),
+		Entry("invalid version", ">=5"),
+		Entry("different flavours", ">=5.14.0-284, <5.14.0-400+rt"),
+		Entry("different architectures", ">=5.14.0-284.x86_64, <5.14.0-400.aarch64"),
+		Entry("empty interval", ">=5.14.0-400, <5.14.0-284"),
+		Entry("excluded bounds", ">5.14.0-284, <=5.14.0-284"),
+		Entry("excluded version", "=5.14.0-284, !=5.14.0-284"),
+	)
+})
+
+var _ = Describe("Range_Contains", func() {
+	DescribeTable(
+		"should match kernels",
+		func(r, kernel string, expected bool) {
+			kr, err := ParseRange(r)
+			Expect(err).NotTo(HaveOccurred())
+
+			v, err := ParseVersion(kernel)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(kr.Contains(v)).To(Equal(expected))
+		},
+		Entry(nil, ">=5.14.0-284, <5.14.0-400", "5.14.0-284.30.1.el9_2.x86_64", true),
+		Entry(nil, ">=5.14.0-284, <5.14.0-400", "5.14.0-70.13.1.el9_0.x86_64", false),
+		Entry(nil, ">=5.14.0-284, <5.14.0-400", "5.14.0-427.13.1.el9_4.x86_64", false),
+		Entry("other flavour", ">=5.14.0-284, <5.14.0-400", "5.14.0-362.8.1.el9_3.x86_64+rt", false),
+		Entry("same flavour", ">=5.14.0-284+rt", "5.14.0-362.8.1.el9_3.x86_64+rt", true),
+		Entry("same architecture", ">=5.14.0-284.aarch64", "5.14.0-362.8.1.el9_3.aarch64", true),
+		Entry("other architecture", ">=5.14.0-284.aarch64", "5.14.0-362.8.1.el9_3.x86_64", false),
+		Entry("excluded version", ">=5.15.0-91-generic, !=5.15.0-92-generic", "5.15.0-92-generic", false),
+		Entry("exact version", "=6.6.7", "6.6.7", true),
+	)
+})
+
+var _ = Describe("Range_Overlaps", func() {
+	DescribeTable(
+		"should detect overlapping ranges",
+		func(a, b string, expected bool) {
+			ra, err := ParseRange(a)
+			Expect(err).NotTo(HaveOccurred())
+
+			rb, err := ParseRange(b)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(ra.Overlaps(rb)).To(Equal(expected))
+			Expect(rb.Overlaps(ra)).To(Equal(expected))
+		},
+		Entry(nil, ">=5.14.0-284, <5.14.0-400", ">=5.14.0-362", true),
+		Entry(nil, ">=5.14.0-284, <5.14.0-400", ">=5.14.0-400", false),
+		Entry(nil, ">=5.14.0-284, <=5.14.0-400", ">=5.14.0-400", true),
+		Entry(nil, ">=5.14.0-284, <5.14.0-400", ">=5.14.0-284+rt, <5.14.0-400+rt", false),
+		Entry(nil, ">=5.14.0-284.x86_64", ">=5.14.0-284.aarch64", false),
+		Entry(nil, ">=5.14.0-284.x86_64", "<5.14.0-300", true),
+		Entry(nil, "<=5.14.0-284", ">=5.14.0-284, !=5.14.0-284.0", false),
+	)
+})
@@ -0,0 +1,117 @@
+package kernel
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+var knownArchs = sets.New("aarch64", "amd64", "arm64", "armhf", "i686", "ppc64le", "riscv64", "s390x", "x86_64")
+
+// Version is a parsed kernel release, as reported by `uname -r`.
+type Version struct {
+	// Base holds the upstream version numbers, such as 5, 14 and 0 in 5.14.0-284.30.1.el9_2.x86_64.
+	Base []uint64
+	// Release holds the leading numbers of the distribution release, such as 284, 30 and 1 in
+	// 5.14.0-284.30.1.el9_2.x86_64 or 91 in 5.15.0-91-generic.
+	Release []uint64
+	// Arch is the architecture at the end of the release, such as x86_64.
+	// It is empty if the release does not include one.
+	Arch string
+	// Flavour is the variant of the kernel, such as rt or 64k in 5.14.0-362.8.1.el9_3.x86_64+rt, or generic in
+	// 5.15.0-91-generic.
+	// It is empty for the default kernel of RHEL and upstream releases.
+	Flavour string
+}
+
+// ParseVersion parses RHEL, Ubuntu and upstream kernel releases.
+func ParseVersion(s string) (*Version, error) {
+	rest, flavour, _ := strings.Cut(s, "+")
+	base, release, _ := strings.Cut(rest, "-")
+
+	v := &Version{Flavour: flavour}
+
+	parts := strings.Split(base, ".")
+	if len(parts) < 2 {
+		return nil, fmt.Errorf("%q: expected at least a major and a minor version", s)
+	}
+
+	for _, p := range parts {
+		n, err := strconv.ParseUint(p, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("%q: invalid version number %q", s, p)
+		}
+
+		v.Base = append(v.Base, n)
+	}
+
+	fields := strings.FieldsFunc(release, func(r rune) bool {
+		return r == '.' || r == '-'
+	})
+
+	for len(fields) > 0 {
+		n, err := strconv.ParseUint(fields[0], 10, 32)
+		if err != nil {
+			break
+		}
+
+		v.Release = append(v.Release, n)
+		fields = fields[1:]
+	}
+
+	if len(fields) == 0 {
+		return v, nil
+	}
+
+	if last := fields[len(fields)-1]; knownArchs.Has(last) {
+		v.Arch = last
+		fields = fields[:len(fields)-1]
+	}
+
+	// Ubuntu-like releases end with the flavour, such as 5.15.0-91-generic or 6.5.0-1015-aws.
+	if len(fields) > 0 && v.Flavour == "" {
+		last := fields[len(fields)-1]
+
+		if _, err := strconv.ParseUint(last, 10, 32); err != nil && strings.Contains(release, "-"+last) {
+			v.Flavour = last
+		}
+	}
+
+	return v, nil
+}
+
+// Compare returns -1, 0 or +1 depending on whether v is lower than, equal to or greater than other.
+// Only the base and release numbers are compared; missing numbers count as 0, so 5.14.0-284 is lower than
+// 5.14.0-284.30.1.
+func (v *Version) Compare(other *Version) int {
+	if c := compareNumbers(v.Base, other.Base); c != 0 {
+		return c
+	}
+
+	return compareNumbers(v.Release, other.Release)
+}
+
+func compareNumbers(a, b []uint64) int {
+	for i := 0; i < max(len(a), len(b)); i++ {
+		var x, y uint64
+
+		if i < len(a) {
+			x = a[i]
+		}
+
+		if i < len(b) {
+			y = b[i]
+		}
+
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,85 @@
+package kernel
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ParseVersion", func() {
+	DescribeTable(
+		"should parse kernel releases",
+		func(s string, expected Version) {
+			v, err := ParseVersion(s)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*v).To(Equal(expected))
+		},
+		Entry(
+			"RHEL",
+			"5.14.0-284.30.1.el9_2.x86_64",
+			Version{Base: []uint64{5, 14, 0}, Release: []uint64{284, 30, 1}, Arch: "x86_64"},
+		),
+		Entry(
+			"RHEL realtime",
+			"5.14.0-362.8.1.el9_3.x86_64+rt",
+			Version{Base: []uint64{5, 14, 0}, Release: []uint64{362, 8, 1}, Arch: "x86_64", Flavour: "rt"},
+		),
+		Entry(
+			"RHEL 64k",
+			"5.14.0-427.13.1.el9_4.aarch64+64k",
+			Version{Base: []uint64{5, 14, 0}, Release: []uint64{427, 13, 1}, Arch: "aarch64", Flavour: "64k"},
+		),
+		Entry(
+			"Ubuntu",
+			"5.15.0-91-generic",
+			Version{Base: []uint64{5, 15, 0}, Release: []uint64{91}, Flavour: "generic"},
+		),
+		Entry(
+			"Debian",
+			"6.1.0-13-cloud-amd64",
+			Version{Base: []uint64{6, 1, 0}, Release: []uint64{13}, Arch: "amd64", Flavour: "cloud"},
+		),
+		Entry(
+			"upstream",
+			"6.6.7",
+			Version{Base: []uint64{6, 6, 7}},
+		),
+		Entry(
+			"upstream release candidate",
+			"6.7.0-rc1",
+			Version{Base: []uint64{6, 7, 0}},
+		),
+	)
+
+	DescribeTable(
+		"should return an error for invalid releases",
+		func(s string) {
+			_, err := ParseVersion(s)
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("empty", ""),
+		Entry("major only", "6"),
+		Entry("non-numeric base", "6.x.0"),
+		Entry("empty number", "6..0"),
+	)
+})
+
+var _ = Describe("Version_Compare", func() {
+	DescribeTable(
+		"should compare the version numbers",
+		func(a, b string, expected int) {
+			va, err := ParseVersion(a)
+			Expect(err).NotTo(HaveOccurred())
+
+			vb, err := ParseVersion(b)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(va.Compare(vb)).To(Equal(expected))
+		},
+		Entry(nil, "5.14.0-284.30.1.el9_2.x86_64", "5.14.0-284", 1),
+		Entry(nil, "5.14.0-284", "5.14.0-284.0", 0),
+		Entry(nil, "5.14.0-284.30.1.el9_2.x86_64", "5.14.0-400", -1),
+		Entry(nil, "6.1", "6.1.0", 0),
+		Entry(nil, "6.1.0", "5.14.0-284", 1),
+		Entry(nil, "5.15.0-91-generic", "5.15.0-100-generic", -1),
+	)
+})
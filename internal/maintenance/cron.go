@@ -0,0 +1,194 @@
+package maintenance
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxSearchYears is how far in the future the next activation of a schedule is searched for.
+// Schedules that do not activate within that period, such as "0 0 30 2 *", never activate.
+const maxSearchYears = 5
+
+type cronField struct {
+	name     string
+	min, max int
+}
+
+var (
+	minuteField     = cronField{name: "minute", min: 0, max: 59}
+	hourField       = cronField{name: "hour", min: 0, max: 23}
+	dayOfMonthField = cronField{name: "day of month", min: 1, max: 31}
+	monthField      = cronField{name: "month", min: 1, max: 12}
+	// 7 is accepted as an alias for Sunday.
+	dayOfWeekField = cronField{name: "day of week", min: 0, max: 7}
+)
+
+// cronSchedule is a parsed cron expression; each field holds one bit per allowed value.
+type cronSchedule struct {
+	minute, hour, dayOfMonth, month, dayOfWeek uint64
+
+	// Like cron, if both the day of month and the day of week are restricted, a day matches if either matches.
+	dayOfMonthStar, dayOfWeekStar bool
+}
+
+// parseCron parses a standard cron expression with five fields: minute, hour, day of month, month and day of week.
+// Each field is a comma-separated list of "*", values or ranges, optionally followed by a step such as "*/15".
+func parseCron(expr string) (*cronSchedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("expected 5 fields, got %d", len(fields))
+	}
+
+	s := &cronSchedule{
+		dayOfMonthStar: strings.HasPrefix(fields[2], "*"),
+		dayOfWeekStar:  strings.HasPrefix(fields[4], "*"),
+	}
+
+	var err error
+
+	if s.minute, err = minuteField.parse(fields[0]); err != nil {
+		return nil, err
+	}
+
+	if s.hour, err = hourField.parse(fields[1]); err != nil {
+		return nil, err
+	}
+
+	if s.dayOfMonth, err = dayOfMonthField.parse(fields[2]); err != nil {
+		return nil, err
+	}
+
+	if s.month, err = monthField.parse(fields[3]); err != nil {
+		return nil, err
+	}
+
+	if s.dayOfWeek, err = dayOfWeekField.parse(fields[4]); err != nil {
+		return nil, err
+	}
+
+	if s.dayOfWeek&(1<<7) != 0 {
+		s.dayOfWeek |= 1
+	}
+
+	return s, nil
+}
+
+func (f cronField) parse(value string) (uint64, error) {
+	var bits uint64
+
+	for _, item := range strings.Split(value, ",") {
+		b, err := f.parseItem(item)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s %q: %v", f.name, value, err)
+		}
+
+		bits |= b
+	}
+
+	return bits, nil
+}
+
+func (f cronField) parseItem(item string) (uint64, error) {
+	rangeExpr, stepExpr, hasStep := strings.Cut(item, "/")
+
+	step := 1
+
+	if hasStep {
+		var err error
+
+		if step, err = strconv.Atoi(stepExpr); err != nil || step < 1 {
+			return 0, fmt.Errorf("invalid step %q", stepExpr)
+		}
+	}
+
+	first, last := f.min, f.max
+
+	switch lo, hi, isRange := strings.Cut(rangeExpr, "-"); {
+	case rangeExpr == "*":
+	case isRange:
+		var err error
+
+		if first, err = f.parseValue(lo); err != nil {
+			return 0, err
+		}
+
+		if last, err = f.parseValue(hi); err != nil {
+			return 0, err
+		}
+
+		if first > last {
+			return 0, fmt.Errorf("invalid range %q", rangeExpr)
+		}
+	default:
+		var err error
+
+		if first, err = f.parseValue(rangeExpr); err != nil {
+			return 0, err
+		}
+
+		// "a/n" means every n starting at a, like "a-max/n".
+		if !hasStep {
+			last = first
+		}
+	}
+
+	var bits uint64
+
+	for v := first; v <= last; v += step {
+		bits |= 1 << v
+	}
+
+	return bits, nil
+}
+
+func (f cronField) parseValue(s string) (int, error) {
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+
+	if v < f.min || v > f.max {
+		return 0, fmt.Errorf("value %d out of range [%d, %d]", v, f.min, f.max)
+	}
+
+	return v, nil
+}
+
+func (s *cronSchedule) matchesDay(t time.Time) bool {
+	dom := s.dayOfMonth&(1<<t.Day()) != 0
+	dow := s.dayOfWeek&(1<<t.Weekday()) != 0
+
+	if s.dayOfMonthStar || s.dayOfWeekStar {
+		return dom && dow
+	}
+
+	return dom || dow
+}
+
+// next returns the first time strictly after t, truncated to the minute, at which s activates.
+// It returns the zero time if s does not activate within maxSearchYears.
+func (s *cronSchedule) next(t time.Time) time.Time {
+	loc := t.Location()
+
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(maxSearchYears, 0, 0)
+
+	for t.Before(limit) {
+		switch {
+		case s.month&(1<<t.Month()) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !s.matchesDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case s.hour&(1<<t.Hour()) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case s.minute&(1<<t.Minute()) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+
+	return time.Time{}
+}
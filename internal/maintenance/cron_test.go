@@ -0,0 +1,63 @@
+package maintenance
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("parseCron", func() {
+	DescribeTable(
+		"should reject invalid expressions",
+		func(expr string) {
+			_, err := parseCron(expr)
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("too few fields", "0 2 * *"),
+		Entry("too many fields", "0 2 * * * *"),
+		Entry("minute out of range", "60 2 * * *"),
+		Entry("day of month out of range", "0 2 0 * *"),
+		Entry("inverted range", "0 5-2 * * *"),
+		Entry("invalid step", "*/0 * * * *"),
+		Entry("names", "0 2 * * SAT"),
+	)
+})
+
+var _ = Describe("cronSchedule_next", func() {
+	// Friday 2024-03-01 10:30 UTC
+	now := time.Date(2024, time.March, 1, 10, 30, 0, 0, time.UTC)
+
+	DescribeTable(
+		"should return the next activation",
+		func(expr string, expected time.Time) {
+			s, err := parseCron(expr)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(s.next(now)).To(Equal(expected))
+		},
+		Entry("every minute", "* * * * *", time.Date(2024, time.March, 1, 10, 31, 0, 0, time.UTC)),
+		Entry("steps", "*/20 * * * *", time.Date(2024, time.March, 1, 10, 40, 0, 0, time.UTC)),
+		Entry("later today", "0 22 * * *", time.Date(2024, time.March, 1, 22, 0, 0, 0, time.UTC)),
+		Entry("tomorrow", "0 2 * * *", time.Date(2024, time.March, 2, 2, 0, 0, 0, time.UTC)),
+		Entry("next Saturday or Sunday", "0 2 * * 6,7", time.Date(2024, time.March, 2, 2, 0, 0, 0, time.UTC)),
+		Entry("range of days of week", "0 2 * * 1-3", time.Date(2024, time.March, 4, 2, 0, 0, 0, time.UTC)),
+		Entry("day of month or day of week", "0 2 15 * 1", time.Date(2024, time.March, 4, 2, 0, 0, 0, time.UTC)),
+		Entry("leap day", "0 0 29 2 *", time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)),
+		Entry("never", "0 0 30 2 *", time.Time{}),
+	)
+
+	It("should use the location of the time", func() {
+		loc, err := time.LoadLocation("Asia/Kolkata")
+		Expect(err).NotTo(HaveOccurred())
+
+		s, err := parseCron("0 2 * * *")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(
+			s.next(now.In(loc)),
+		).To(
+			BeTemporally("==", time.Date(2024, time.March, 2, 2, 0, 0, 0, loc)),
+		)
+	})
+})
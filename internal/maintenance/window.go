@@ -0,0 +1,84 @@
+package maintenance
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	// Embed the time zone database, as the operator image may not ship it.
+	_ "time/tzdata"
+
+	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
+)
+
+// Window is a parsed MaintenanceWindow.
+type Window struct {
+	schedule *cronSchedule
+	duration time.Duration
+	location *time.Location
+}
+
+// Parse validates mw and returns the corresponding Window.
+func Parse(mw *kmmv1beta1.MaintenanceWindow) (*Window, error) {
+	schedule, err := parseCron(mw.Schedule)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schedule %q: %v", mw.Schedule, err)
+	}
+
+	if mw.Duration.Duration <= 0 {
+		return nil, errors.New("the duration must be positive")
+	}
+
+	location := time.UTC
+
+	if mw.TimeZone != "" {
+		if location, err = time.LoadLocation(mw.TimeZone); err != nil {
+			return nil, fmt.Errorf("invalid time zone %q: %v", mw.TimeZone, err)
+		}
+	}
+
+	w := &Window{
+		schedule: schedule,
+		duration: mw.Duration.Duration,
+		location: location,
+	}
+
+	if w.schedule.next(time.Now().In(location)).IsZero() {
+		return nil, fmt.Errorf("the schedule %q never opens the window", mw.Schedule)
+	}
+
+	return w, nil
+}
+
+// State returns true if w is open at t.
+// It also returns the time at which w closes if it is open, or opens if it is closed.
+func (w *Window) State(t time.Time) (bool, time.Time) {
+	t = t.In(w.location)
+
+	// The window is open if it opened during the last duration.
+	if start := w.schedule.next(t.Add(-w.duration)); !start.IsZero() && !start.After(t) {
+		return true, start.Add(w.duration)
+	}
+
+	return false, w.schedule.next(t)
+}
+
+// IsOpen returns true if mw is nil or open at t.
+// If mw is closed, it also returns the time at which it opens.
+func IsOpen(mw *kmmv1beta1.MaintenanceWindow, t time.Time) (bool, time.Time, error) {
+	if mw == nil {
+		return true, time.Time{}, nil
+	}
+
+	w, err := Parse(mw)
+	if err != nil {
+		return false, time.Time{}, err
+	}
+
+	open, next := w.State(t)
+	if open {
+		return true, time.Time{}, nil
+	}
+
+	return false, next, nil
+}
@@ -0,0 +1,103 @@
+package maintenance
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Parse", func() {
+	DescribeTable(
+		"should validate the window",
+		func(mw kmmv1beta1.MaintenanceWindow, valid bool) {
+			_, err := Parse(&mw)
+
+			if valid {
+				Expect(err).NotTo(HaveOccurred())
+			} else {
+				Expect(err).To(HaveOccurred())
+			}
+		},
+		Entry(
+			"valid",
+			kmmv1beta1.MaintenanceWindow{Schedule: "0 2 * * 6", Duration: metav1.Duration{Duration: time.Hour}, TimeZone: "Europe/Paris"},
+			true,
+		),
+		Entry("invalid schedule", kmmv1beta1.MaintenanceWindow{Schedule: "0 2 * *", Duration: metav1.Duration{Duration: time.Hour}}, false),
+		Entry("no duration", kmmv1beta1.MaintenanceWindow{Schedule: "0 2 * * 6"}, false),
+		Entry(
+			"invalid time zone",
+			kmmv1beta1.MaintenanceWindow{Schedule: "0 2 * * 6", Duration: metav1.Duration{Duration: time.Hour}, TimeZone: "Mars/Olympus"},
+			false,
+		),
+		Entry("never opens", kmmv1beta1.MaintenanceWindow{Schedule: "0 0 31 4 *", Duration: metav1.Duration{Duration: time.Hour}}, false),
+	)
+})
+
+var _ = Describe("Window_State", func() {
+	// Saturday at 2am in Paris, for 2 hours
+	mw := &kmmv1beta1.MaintenanceWindow{
+		Schedule: "0 2 * * 6",
+		Duration: metav1.Duration{Duration: 2 * time.Hour},
+		TimeZone: "Europe/Paris",
+	}
+
+	paris, err := time.LoadLocation("Europe/Paris")
+	Expect(err).NotTo(HaveOccurred())
+
+	DescribeTable(
+		"should return the state of the window",
+		func(t time.Time, expectedOpen bool, expectedNext time.Time) {
+			w, err := Parse(mw)
+			Expect(err).NotTo(HaveOccurred())
+
+			open, next := w.State(t)
+			Expect(open).To(Equal(expectedOpen))
+			Expect(next).To(BeTemporally("==", expectedNext))
+		},
+		Entry(
+			"before the window",
+			time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC),
+			false,
+			time.Date(2024, time.March, 2, 2, 0, 0, 0, paris),
+		),
+		Entry(
+			"when the window opens",
+			time.Date(2024, time.March, 2, 2, 0, 0, 0, paris),
+			true,
+			time.Date(2024, time.March, 2, 4, 0, 0, 0, paris),
+		),
+		Entry(
+			"during the window",
+			time.Date(2024, time.March, 2, 3, 59, 0, 0, paris),
+			true,
+			time.Date(2024, time.March, 2, 4, 0, 0, 0, paris),
+		),
+		Entry(
+			"when the window closes",
+			time.Date(2024, time.March, 2, 4, 0, 0, 0, paris),
+			false,
+			time.Date(2024, time.March, 9, 2, 0, 0, 0, paris),
+		),
+	)
+})
+
+var _ = Describe("IsOpen", func() {
+	It("should return true if there is no window", func() {
+		open, _, err := IsOpen(nil, time.Now())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(open).To(BeTrue())
+	})
+
+	It("should return the opening time if the window is closed", func() {
+		mw := &kmmv1beta1.MaintenanceWindow{Schedule: "0 2 * * *", Duration: metav1.Duration{Duration: time.Hour}}
+
+		open, next, err := IsOpen(mw, time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(open).To(BeFalse())
+		Expect(next).To(BeTemporally("==", time.Date(2024, time.March, 2, 2, 0, 0, 0, time.UTC)))
+	})
+})
@@ -0,0 +1,117 @@
+package modgraph
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+var ErrCycle = errors.New("dependency cycle")
+
+// Graph holds kernel modules and the dependencies between them.
+type Graph struct {
+	// names holds the modules in the order in which they were declared.
+	names []string
+	// pre holds, for each module, the modules that must be loaded before it.
+	pre map[string]sets.Set[string]
+	// post holds, for each module, the modules that must be loaded after it.
+	post map[string]sets.Set[string]
+}
+
+// New returns the Graph described by nodes.
+// It returns an error if a module is declared twice or if a dependency references an undeclared module.
+func New(nodes []kmmv1beta1.ModuleGraphNode) (*Graph, error) {
+	g := Graph{
+		names: make([]string, 0, len(nodes)),
+		pre:   make(map[string]sets.Set[string], len(nodes)),
+		post:  make(map[string]sets.Set[string], len(nodes)),
+	}
+
+	for _, n := range nodes {
+		if n.Name == "" {
+			return nil, errors.New("module name cannot be empty")
+		}
+
+		if _, ok := g.pre[n.Name]; ok {
+			return nil, fmt.Errorf("%q: duplicate module", n.Name)
+		}
+
+		g.names = append(g.names, n.Name)
+		g.pre[n.Name] = sets.New[string]()
+		g.post[n.Name] = sets.New[string]()
+	}
+
+	for _, n := range nodes {
+		for _, p := range n.Pre {
+			if _, ok := g.pre[p]; !ok {
+				return nil, fmt.Errorf("%q: pre dependency %q is not a module of the graph", n.Name, p)
+			}
+
+			g.pre[n.Name].Insert(p)
+			g.post[p].Insert(n.Name)
+		}
+
+		for _, p := range n.Post {
+			if _, ok := g.pre[p]; !ok {
+				return nil, fmt.Errorf("%q: post dependency %q is not a module of the graph", n.Name, p)
+			}
+
+			g.pre[p].Insert(n.Name)
+			g.post[n.Name].Insert(p)
+		}
+	}
+
+	return &g, nil
+}
+
+// Pre returns the modules that must be loaded right before name.
+func (g *Graph) Pre(name string) []string {
+	return sets.List(g.pre[name])
+}
+
+// Post returns the modules that must be loaded right after name.
+func (g *Graph) Post(name string) []string {
+	return sets.List(g.post[name])
+}
+
+// LoadOrder returns the modules in an order that satisfies all dependencies.
+// Among the modules that can be loaded, the first declared one comes first.
+// It returns an error wrapping ErrCycle if the dependencies contain a cycle.
+func (g *Graph) LoadOrder() ([]string, error) {
+	order := make([]string, 0, len(g.names))
+	done := sets.New[string]()
+
+	for len(order) < len(g.names) {
+		progress := false
+
+		for _, n := range g.names {
+			if done.Has(n) || !done.IsSuperset(g.pre[n]) {
+				continue
+			}
+
+			order = append(order, n)
+			done.Insert(n)
+			progress = true
+
+			// restart from the first declared module
+			break
+		}
+
+		if !progress {
+			remaining := make([]string, 0, len(g.names)-len(order))
+
+			for _, n := range g.names {
+				if !done.Has(n) {
+					remaining = append(remaining, n)
+				}
+			}
+
+			return nil, fmt.Errorf("%w between modules %s", ErrCycle, strings.Join(remaining, ", "))
+		}
+	}
+
+	return order, nil
+}
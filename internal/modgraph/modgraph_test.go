@@ -0,0 +1,67 @@
+package modgraph
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
+)
+
+var _ = Describe("New", func() {
+	DescribeTable(
+		"should return an error for invalid graphs",
+		func(nodes []kmmv1beta1.ModuleGraphNode, expected string) {
+			_, err := New(nodes)
+			Expect(err).To(MatchError(ContainSubstring(expected)))
+		},
+		Entry("empty name", []kmmv1beta1.ModuleGraphNode{{Name: ""}}, "cannot be empty"),
+		Entry("duplicate module", []kmmv1beta1.ModuleGraphNode{{Name: "a"}, {Name: "a"}}, `"a": duplicate module`),
+		Entry(
+			"unknown pre dependency",
+			[]kmmv1beta1.ModuleGraphNode{{Name: "a", Pre: []string{"b"}}},
+			`"a": pre dependency "b" is not a module of the graph`,
+		),
+		Entry(
+			"unknown post dependency",
+			[]kmmv1beta1.ModuleGraphNode{{Name: "a", Post: []string{"b"}}},
+			`"a": post dependency "b" is not a module of the graph`,
+		),
+	)
+})
+
+var _ = Describe("Graph_LoadOrder", func() {
+	It("should order the modules according to their pre and post dependencies", func() {
+		g, err := New([]kmmv1beta1.ModuleGraphNode{
+			{Name: "stack", Pre: []string{"transport_a", "transport_b"}},
+			{Name: "transport_a", Pre: []string{"core"}},
+			{Name: "transport_b"},
+			{Name: "core", Post: []string{"transport_b"}},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(g.LoadOrder()).To(Equal([]string{"core", "transport_a", "transport_b", "stack"}))
+		Expect(g.Pre("stack")).To(Equal([]string{"transport_a", "transport_b"}))
+		Expect(g.Pre("transport_b")).To(Equal([]string{"core"}))
+		Expect(g.Post("core")).To(Equal([]string{"transport_a", "transport_b"}))
+	})
+
+	It("should keep the declaration order of independent modules", func() {
+		g, err := New([]kmmv1beta1.ModuleGraphNode{{Name: "b"}, {Name: "a"}})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(g.LoadOrder()).To(Equal([]string{"b", "a"}))
+	})
+
+	It("should return an error if there is a cycle", func() {
+		g, err := New([]kmmv1beta1.ModuleGraphNode{
+			{Name: "a"},
+			{Name: "b", Pre: []string{"c"}, Post: []string{"d"}},
+			{Name: "c"},
+			{Name: "d", Post: []string{"c"}},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = g.LoadOrder()
+		Expect(err).To(MatchError(ErrCycle))
+		Expect(err).To(MatchError(ContainSubstring("between modules b, c, d")))
+	})
+})
@@ -0,0 +1,196 @@
+package modinfo
+
+import (
+	"bytes"
+	"compress/gzip"
+	"debug/elf"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+)
+
+var (
+	ErrInvalidModule    = errors.New("invalid kernel module file")
+	ErrVermagicMismatch = errors.New("module vermagic does not match the kernel")
+)
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	xzMagic   = []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// Parameter describes a module parameter as declared in the .modinfo section.
+type Parameter struct {
+	Name        string
+	Type        string
+	Description string
+}
+
+// ModuleInfo holds the information that the kernel build stores in the .modinfo section of a module.
+type ModuleInfo struct {
+	Name       string
+	Vermagic   string
+	SrcVersion string
+	License    string
+	Depends    []string
+	Parameters []Parameter
+}
+
+// KernelRelease returns the kernel release the module was built for, which is the first field of its vermagic.
+func (mi *ModuleInfo) KernelRelease() string {
+	fields := strings.Fields(mi.Vermagic)
+	if len(fields) == 0 {
+		return ""
+	}
+
+	return fields[0]
+}
+
+// CheckKernel returns an error wrapping ErrVermagicMismatch if the module was not built for kernelVersion.
+func (mi *ModuleInfo) CheckKernel(kernelVersion string) error {
+	if release := mi.KernelRelease(); release != kernelVersion {
+		return fmt.Errorf(
+			"%w: module %s was built for kernel %q (vermagic %q), not %q",
+			ErrVermagicMismatch,
+			mi.Name,
+			release,
+			strings.TrimSpace(mi.Vermagic),
+			kernelVersion,
+		)
+	}
+
+	return nil
+}
+
+// ReadFile returns the information stored in the kernel module file at path.
+// Files compressed with gzip, xz or zstd are decompressed first.
+func ReadFile(path string) (*ModuleInfo, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read %s: %v", path, err)
+	}
+
+	mi, err := Parse(b)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return mi, nil
+}
+
+// Parse returns the information stored in the .modinfo section of a kernel module, which may be compressed.
+func Parse(data []byte) (*ModuleInfo, error) {
+	b, err := Decompress(data)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := elf.NewFile(bytes.NewReader(b))
+	if err != nil {
+		return nil, fmt.Errorf("%w: not an ELF file: %v", ErrInvalidModule, err)
+	}
+	defer f.Close()
+
+	section := f.Section(".modinfo")
+	if section == nil {
+		return nil, fmt.Errorf("%w: no .modinfo section", ErrInvalidModule)
+	}
+
+	sd, err := section.Data()
+	if err != nil {
+		return nil, fmt.Errorf("%w: could not read the .modinfo section: %v", ErrInvalidModule, err)
+	}
+
+	return parseSection(sd), nil
+}
+
+// Decompress returns the uncompressed content of a module file.
+// Data that is not compressed with a supported algorithm is returned as is.
+func Decompress(data []byte) ([]byte, error) {
+	var (
+		r   io.Reader
+		err error
+	)
+
+	switch {
+	case bytes.HasPrefix(data, xzMagic):
+		r, err = xz.NewReader(bytes.NewReader(data))
+	case bytes.HasPrefix(data, zstdMagic):
+		var zr *zstd.Decoder
+
+		if zr, err = zstd.NewReader(bytes.NewReader(data)); err == nil {
+			defer zr.Close()
+			r = zr
+		}
+	case bytes.HasPrefix(data, gzipMagic):
+		r, err = gzip.NewReader(bytes.NewReader(data))
+	default:
+		return data, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: could not create a decompressor: %v", ErrInvalidModule, err)
+	}
+
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: could not decompress: %v", ErrInvalidModule, err)
+	}
+
+	return b, nil
+}
+
+// parseSection parses the NUL-separated key=value entries of a .modinfo section.
+func parseSection(data []byte) *ModuleInfo {
+	mi := ModuleInfo{}
+
+	// parameters are declared with a parm and a parmtype entry each
+	params := make(map[string]int)
+
+	param := func(name string) *Parameter {
+		i, ok := params[name]
+		if !ok {
+			i = len(mi.Parameters)
+			params[name] = i
+			mi.Parameters = append(mi.Parameters, Parameter{Name: name})
+		}
+
+		return &mi.Parameters[i]
+	}
+
+	for _, entry := range bytes.Split(data, []byte{0}) {
+		k, v, ok := strings.Cut(string(entry), "=")
+		if !ok {
+			continue
+		}
+
+		switch k {
+		case "name":
+			mi.Name = v
+		case "vermagic":
+			mi.Vermagic = v
+		case "srcversion":
+			mi.SrcVersion = v
+		case "license":
+			mi.License = v
+		case "depends":
+			if v != "" {
+				mi.Depends = strings.Split(v, ",")
+			}
+		case "parm":
+			name, desc, _ := strings.Cut(v, ":")
+			param(name).Description = desc
+		case "parmtype":
+			name, typ, _ := strings.Cut(v, ":")
+			param(name).Type = typ
+		}
+	}
+
+	return &mi
+}
@@ -0,0 +1,102 @@
+package modinfo
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/klauspost/compress/zstd"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/ulikunitz/xz"
+)
+
+const testModule = "testdata/kmm-test.ko"
+
+var expectedModuleInfo = ModuleInfo{
+	Name:       "kmm_test",
+	Vermagic:   "5.14.0-284.el9.x86_64 SMP preempt mod_unload modversions ",
+	SrcVersion: "8F3A4E1D7C2B9A0F6E5D4C3",
+	License:    "GPL",
+	Depends:    []string{"kmm_dep"},
+	Parameters: []Parameter{
+		{Name: "debug", Type: "bool", Description: "Enable debug output"},
+		{Name: "queues", Type: "uint"},
+	},
+}
+
+var _ = Describe("ReadFile", func() {
+	compress := func(newWriter func(io.Writer) (io.WriteCloser, error)) string {
+		GinkgoHelper()
+
+		data, err := os.ReadFile(testModule)
+		Expect(err).NotTo(HaveOccurred())
+
+		buf := bytes.Buffer{}
+
+		w, err := newWriter(&buf)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = w.Write(data)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(w.Close()).To(Succeed())
+
+		path := filepath.Join(GinkgoT().TempDir(), "kmm-test.ko.compressed")
+		Expect(os.WriteFile(path, buf.Bytes(), 0644)).To(Succeed())
+
+		return path
+	}
+
+	It("should parse an uncompressed module", func() {
+		Expect(ReadFile(testModule)).To(Equal(&expectedModuleInfo))
+	})
+
+	DescribeTable(
+		"should parse compressed modules",
+		func(newWriter func(io.Writer) (io.WriteCloser, error)) {
+			Expect(ReadFile(compress(newWriter))).To(Equal(&expectedModuleInfo))
+		},
+		Entry("xz", func(w io.Writer) (io.WriteCloser, error) { return xz.NewWriter(w) }),
+		Entry("zstd", func(w io.Writer) (io.WriteCloser, error) { return zstd.NewWriter(w) }),
+		Entry("gzip", func(w io.Writer) (io.WriteCloser, error) { return gzip.NewWriter(w), nil }),
+	)
+
+	It("should return an error for files that are not kernel modules", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "kmm-test.ko")
+		Expect(os.WriteFile(path, []byte("not a module"), 0644)).To(Succeed())
+
+		_, err := ReadFile(path)
+		Expect(err).To(MatchError(ErrInvalidModule))
+	})
+
+	It("should return an error for corrupted compressed files", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "kmm-test.ko.xz")
+		Expect(os.WriteFile(path, append(xzMagic, "garbage"...), 0644)).To(Succeed())
+
+		_, err := ReadFile(path)
+		Expect(err).To(MatchError(ErrInvalidModule))
+	})
+})
+
+var _ = Describe("ModuleInfo_CheckKernel", func() {
+	It("should accept the kernel the module was built for", func() {
+		Expect(expectedModuleInfo.CheckKernel("5.14.0-284.el9.x86_64")).To(Succeed())
+	})
+
+	It("should reject other kernels", func() {
+		err := expectedModuleInfo.CheckKernel("5.14.0-362.el9.x86_64")
+
+		Expect(err).To(MatchError(ErrVermagicMismatch))
+		Expect(err).To(
+			MatchError(ContainSubstring(`module kmm_test was built for kernel "5.14.0-284.el9.x86_64"`)),
+		)
+	})
+
+	It("should reject modules without a vermagic", func() {
+		mi := ModuleInfo{Name: "kmm_test"}
+
+		Expect(mi.CheckKernel("5.14.0-284.el9.x86_64")).To(MatchError(ErrVermagicMismatch))
+	})
+})
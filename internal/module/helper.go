@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/api"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/auth"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/registry"
@@ -25,6 +26,81 @@ func IntermediateImageName(name, namespace, targetImage string) string {
 	return AppendToTag(targetImage, namespace+"_"+name+"_kmm_unsigned")
 }
 
+// GetRelevantModprobe returns the modprobe settings of a Container, with the fields set in the override of a kernel
+// mapping replaced.
+// Fields that cannot be set together replace each other: setting rawArgs clears moduleName and args, setting moduleName
+// or args clears rawArgs, and any of modulesLoadingOrder, modulesGraph and moduleFiles clears the two others.
+func GetRelevantModprobe(moduleModprobe kmmv1beta1.ModprobeSpec, mappingModprobe *kmmv1beta1.ModprobeOverride) kmmv1beta1.ModprobeSpec {
+	modprobe := *moduleModprobe.DeepCopy()
+
+	if mappingModprobe == nil {
+		return modprobe
+	}
+
+	o := mappingModprobe.DeepCopy()
+
+	if o.RawArgs != nil {
+		modprobe.ModuleName = ""
+		modprobe.Args = nil
+	}
+
+	if o.ModuleName != "" || o.Args != nil {
+		modprobe.RawArgs = nil
+	}
+
+	if o.ModulesLoadingOrder != nil || o.ModulesGraph != nil || o.ModuleFiles != nil {
+		modprobe.ModulesLoadingOrder = nil
+		modprobe.ModulesGraph = nil
+		modprobe.ModuleFiles = nil
+	}
+
+	if o.ModuleName != "" {
+		modprobe.ModuleName = o.ModuleName
+	}
+
+	if o.Parameters != nil {
+		modprobe.Parameters = o.Parameters
+	}
+
+	if o.DirName != "" {
+		modprobe.DirName = o.DirName
+	}
+
+	if o.Args != nil {
+		modprobe.Args = o.Args
+	}
+
+	if o.RawArgs != nil {
+		modprobe.RawArgs = o.RawArgs
+	}
+
+	if o.FirmwarePath != "" {
+		modprobe.FirmwarePath = o.FirmwarePath
+	}
+
+	if o.ModulesLoadingOrder != nil {
+		modprobe.ModulesLoadingOrder = o.ModulesLoadingOrder
+	}
+
+	if o.ModulesGraph != nil {
+		modprobe.ModulesGraph = o.ModulesGraph
+	}
+
+	if o.ModuleFiles != nil {
+		modprobe.ModuleFiles = o.ModuleFiles
+	}
+
+	if o.UnloadWaitTimeout != nil {
+		modprobe.UnloadWaitTimeout = o.UnloadWaitTimeout
+	}
+
+	if o.SignatureVerification != nil {
+		modprobe.SignatureVerification = o.SignatureVerification
+	}
+
+	return modprobe
+}
+
 // ShouldBeBuilt indicates whether the specified ModuleLoaderData of the
 // Module should be built or not.
 func ShouldBeBuilt(mld *api.ModuleLoaderData) bool {
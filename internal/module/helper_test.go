@@ -10,11 +10,90 @@ import (
 	gomock "go.uber.org/mock/gomock"
 	v1 "k8s.io/api/core/v1"
 
+	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/api"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/auth"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/registry"
 )
 
+var _ = Describe("GetRelevantModprobe", func() {
+	moduleModprobe := kmmv1beta1.ModprobeSpec{
+		ModuleName:   "module-name",
+		Parameters:   []string{"a=1"},
+		DirName:      "/opt",
+		FirmwarePath: "/firmware",
+		Args:         &kmmv1beta1.ModprobeArgs{Load: []string{"-v"}},
+	}
+
+	It("should return the Container settings if the mapping has no override", func() {
+		Expect(
+			GetRelevantModprobe(moduleModprobe, nil),
+		).To(
+			Equal(moduleModprobe),
+		)
+	})
+
+	It("should only replace the fields set in the override", func() {
+		override := &kmmv1beta1.ModprobeOverride{
+			ModuleName:   "module-name-rt",
+			FirmwarePath: "/firmware-rt",
+		}
+
+		expected := *moduleModprobe.DeepCopy()
+		expected.ModuleName = "module-name-rt"
+		expected.FirmwarePath = "/firmware-rt"
+
+		Expect(
+			GetRelevantModprobe(moduleModprobe, override),
+		).To(
+			Equal(expected),
+		)
+	})
+
+	It("should clear moduleName and args when the override sets rawArgs", func() {
+		rawArgs := &kmmv1beta1.ModprobeArgs{Load: []string{"load"}, Unload: []string{"unload"}}
+
+		expected := *moduleModprobe.DeepCopy()
+		expected.ModuleName = ""
+		expected.Args = nil
+		expected.RawArgs = rawArgs
+
+		Expect(
+			GetRelevantModprobe(moduleModprobe, &kmmv1beta1.ModprobeOverride{RawArgs: rawArgs}),
+		).To(
+			Equal(expected),
+		)
+	})
+
+	It("should clear rawArgs when the override sets moduleName", func() {
+		modprobe := kmmv1beta1.ModprobeSpec{
+			RawArgs: &kmmv1beta1.ModprobeArgs{Load: []string{"load"}, Unload: []string{"unload"}},
+		}
+
+		Expect(
+			GetRelevantModprobe(modprobe, &kmmv1beta1.ModprobeOverride{ModuleName: "module-name-rt"}),
+		).To(
+			Equal(kmmv1beta1.ModprobeSpec{ModuleName: "module-name-rt"}),
+		)
+	})
+
+	It("should clear the loading order when the override sets a modules graph", func() {
+		modprobe := *moduleModprobe.DeepCopy()
+		modprobe.ModulesLoadingOrder = []string{"module-name", "dep"}
+
+		graph := []kmmv1beta1.ModuleGraphNode{{Name: "module-name", Pre: []string{"dep"}}, {Name: "dep"}}
+
+		expected := *moduleModprobe.DeepCopy()
+		expected.ModulesGraph = graph
+
+		Expect(
+			GetRelevantModprobe(modprobe, &kmmv1beta1.ModprobeOverride{ModulesGraph: graph}),
+		).To(
+			Equal(expected),
+		)
+	})
+})
+
 var _ = Describe("AppendToTag", func() {
 	It("should append a tag to the image name", func() {
 		name := "some-container-image-name"
@@ -33,15 +33,17 @@ func NewKernelMapper(buildHelper build.Helper, signHelper sign.Helper) KernelMap
 
 func (k *kernelMapper) GetModuleLoaderDataForKernel(mod *kmmv1beta1.Module, kernelVersion string) (*api.ModuleLoaderData, error) {
 	mappings := mod.Spec.ModuleLoader.Container.KernelMappings
-	foundMapping, err := k.helper.findKernelMapping(mappings, kernelVersion)
+	idx, err := k.helper.findKernelMapping(mappings, kernelVersion)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find mapping for kernel %s: %w", kernelVersion, err)
 	}
-	mld, err := k.helper.prepareModuleLoaderData(foundMapping, mod, kernelVersion)
+	mld, err := k.helper.prepareModuleLoaderData(&mappings[idx], mod, kernelVersion)
 	if err != nil {
 		return nil, fmt.Errorf("failed to prepare module loader data for kernel %s: %v", kernelVersion, err)
 	}
 
+	mld.KernelMappingIndex = idx
+
 	err = k.helper.replaceTemplates(mld)
 	if err != nil {
 		return nil, fmt.Errorf("failed to replace templates in module loader data for kernel %s: %v", kernelVersion, err)
@@ -50,7 +52,7 @@ func (k *kernelMapper) GetModuleLoaderDataForKernel(mod *kmmv1beta1.Module, kern
 }
 
 type kernelMapperHelperAPI interface {
-	findKernelMapping(mappings []kmmv1beta1.KernelMapping, kernelVersion string) (*kmmv1beta1.KernelMapping, error)
+	findKernelMapping(mappings []kmmv1beta1.KernelMapping, kernelVersion string) (int, error)
 	prepareModuleLoaderData(mapping *kmmv1beta1.KernelMapping, mod *kmmv1beta1.Module, kernelVersion string) (*api.ModuleLoaderData, error)
 	replaceTemplates(mld *api.ModuleLoaderData) error
 }
@@ -67,24 +69,65 @@ func newKernelMapperHelper(buildHelper build.Helper, signHelper sign.Helper) ker
 	}
 }
 
-func (kh *kernelMapperHelper) findKernelMapping(mappings []kmmv1beta1.KernelMapping, kernelVersion string) (*kmmv1beta1.KernelMapping, error) {
-	for _, m := range mappings {
-		if m.Literal != "" && m.Literal == kernelVersion {
-			return &m, nil
-		}
+// findKernelMapping returns the index of the mapping used for kernelVersion, which is the first one returned by
+// MatchingKernelMappings.
+func (kh *kernelMapperHelper) findKernelMapping(mappings []kmmv1beta1.KernelMapping, kernelVersion string) (int, error) {
+	indexes, err := MatchingKernelMappings(mappings, kernelVersion)
+	if err != nil {
+		return -1, err
+	}
 
-		if m.Regexp == "" {
-			continue
-		}
+	if len(indexes) == 0 {
+		return -1, ErrNoMatchingKernelMapping
+	}
+
+	return indexes[0], nil
+}
 
-		if matches, err := regexp.MatchString(m.Regexp, kernelVersion); err != nil {
-			return nil, fmt.Errorf("could not match regexp %q against kernel %q: %v", m.Regexp, kernelVersion, err)
-		} else if matches {
-			return &m, nil
+// MatchingKernelMappings returns the indexes of all mappings that match kernelVersion, by order of precedence:
+// literal mappings come first, then version ranges and finally regular expressions.
+// Mappings of the same kind keep their order.
+func MatchingKernelMappings(mappings []kmmv1beta1.KernelMapping, kernelVersion string) ([]int, error) {
+	var literals, ranges, regexps []int
+
+	for i, m := range mappings {
+		switch {
+		case m.Literal != "":
+			if m.Literal == kernelVersion {
+				literals = append(literals, i)
+			}
+		case m.VersionRange != "":
+			if matches, err := matchVersionRange(m.VersionRange, kernelVersion); err != nil {
+				return nil, fmt.Errorf("could not match version range %q against kernel %q: %v", m.VersionRange, kernelVersion, err)
+			} else if matches {
+				ranges = append(ranges, i)
+			}
+		case m.Regexp != "":
+			if matches, err := regexp.MatchString(m.Regexp, kernelVersion); err != nil {
+				return nil, fmt.Errorf("could not match regexp %q against kernel %q: %v", m.Regexp, kernelVersion, err)
+			} else if matches {
+				regexps = append(regexps, i)
+			}
 		}
 	}
 
-	return nil, ErrNoMatchingKernelMapping
+	return append(append(literals, ranges...), regexps...), nil
+}
+
+// matchVersionRange returns true if kernelVersion is in versionRange.
+// Kernel versions that cannot be parsed are not in any range.
+func matchVersionRange(versionRange, kernelVersion string) (bool, error) {
+	r, err := kernel.ParseRange(versionRange)
+	if err != nil {
+		return false, err
+	}
+
+	v, err := kernel.ParseVersion(kernelVersion)
+	if err != nil {
+		return false, nil
+	}
+
+	return r.Contains(v), nil
 }
 
 func (kh *kernelMapperHelper) prepareModuleLoaderData(mapping *kmmv1beta1.KernelMapping, mod *kmmv1beta1.Module, kernelVersion string) (*api.ModuleLoaderData, error) {
@@ -134,6 +177,9 @@ func (kh *kernelMapperHelper) prepareModuleLoaderData(mapping *kmmv1beta1.Kernel
 		mld.InTreeModulesToRemove = []string{inTreeModuleToRemove}
 	}
 
+	mld.BlacklistInTreeModules = mod.Spec.ModuleLoader.Container.BlacklistInTreeModules
+	mld.RestoreInTreeModulesOnUnload = mod.Spec.ModuleLoader.Container.RestoreInTreeModulesOnUnload
+
 	mld.KernelVersion = kernelVersion
 	mld.KernelNormalizedVersion = kernel.NormalizeVersion(kernelVersion)
 	mld.Name = mod.Name
@@ -141,8 +187,10 @@ func (kh *kernelMapperHelper) prepareModuleLoaderData(mapping *kmmv1beta1.Kernel
 	mld.ImageRepoSecret = mod.Spec.ImageRepoSecret
 	mld.Selector = mod.Spec.Selector
 	mld.Tolerations = mod.Spec.Tolerations
+	mld.Drain = mod.Spec.Drain
+	mld.MaintenanceWindow = mod.Spec.MaintenanceWindow
 	mld.ServiceAccountName = mod.Spec.ModuleLoader.ServiceAccountName
-	mld.Modprobe = mod.Spec.ModuleLoader.Container.Modprobe
+	mld.Modprobe = GetRelevantModprobe(mod.Spec.ModuleLoader.Container.Modprobe, mapping.Modprobe)
 	mld.ModuleVersion = mod.Spec.ModuleLoader.Container.Version
 	mld.ImagePullPolicy = mod.Spec.ModuleLoader.Container.ImagePullPolicy
 	mld.Owner = mod
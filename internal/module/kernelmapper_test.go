@@ -39,24 +39,26 @@ var _ = Describe("GetModuleLoaderDataForKernel", func() {
 
 	It("good flow", func() {
 		mapping := kmmv1beta1.KernelMapping{}
+		mod.Spec.ModuleLoader.Container.KernelMappings = []kmmv1beta1.KernelMapping{mapping}
 		mld := api.ModuleLoaderData{KernelVersion: kernelVersion}
-		kh.EXPECT().findKernelMapping(mod.Spec.ModuleLoader.Container.KernelMappings, kernelVersion).Return(&mapping, nil)
+		kh.EXPECT().findKernelMapping(mod.Spec.ModuleLoader.Container.KernelMappings, kernelVersion).Return(0, nil)
 		kh.EXPECT().prepareModuleLoaderData(&mapping, &mod, kernelVersion).Return(&mld, nil)
 		kh.EXPECT().replaceTemplates(&mld).Return(nil)
 		res, err := km.GetModuleLoaderDataForKernel(&mod, kernelVersion)
 		Expect(err).NotTo(HaveOccurred())
 		Expect(res).To(Equal(&mld))
+		Expect(res.KernelMappingIndex).To(Equal(0))
 	})
 
 	It("failed to find kernel mapping, internal error", func() {
-		kh.EXPECT().findKernelMapping(mod.Spec.ModuleLoader.Container.KernelMappings, kernelVersion).Return(nil, fmt.Errorf("some error"))
+		kh.EXPECT().findKernelMapping(mod.Spec.ModuleLoader.Container.KernelMappings, kernelVersion).Return(-1, fmt.Errorf("some error"))
 		res, err := km.GetModuleLoaderDataForKernel(&mod, kernelVersion)
 		Expect(err).To(HaveOccurred())
 		Expect(res).To(BeNil())
 	})
 
 	It("failed to find kernel mapping, mapping not present", func() {
-		kh.EXPECT().findKernelMapping(mod.Spec.ModuleLoader.Container.KernelMappings, kernelVersion).Return(nil, ErrNoMatchingKernelMapping)
+		kh.EXPECT().findKernelMapping(mod.Spec.ModuleLoader.Container.KernelMappings, kernelVersion).Return(-1, ErrNoMatchingKernelMapping)
 		res, err := km.GetModuleLoaderDataForKernel(&mod, kernelVersion)
 		Expect(errors.Is(err, ErrNoMatchingKernelMapping)).To(BeTrue())
 		Expect(res).To(BeNil())
@@ -64,7 +66,8 @@ var _ = Describe("GetModuleLoaderDataForKernel", func() {
 
 	It("failed to merge mapping data", func() {
 		mapping := kmmv1beta1.KernelMapping{}
-		kh.EXPECT().findKernelMapping(mod.Spec.ModuleLoader.Container.KernelMappings, kernelVersion).Return(&mapping, nil)
+		mod.Spec.ModuleLoader.Container.KernelMappings = []kmmv1beta1.KernelMapping{mapping}
+		kh.EXPECT().findKernelMapping(mod.Spec.ModuleLoader.Container.KernelMappings, kernelVersion).Return(0, nil)
 		kh.EXPECT().prepareModuleLoaderData(&mapping, &mod, kernelVersion).Return(nil, fmt.Errorf("some error"))
 		res, err := km.GetModuleLoaderDataForKernel(&mod, kernelVersion)
 		Expect(err).To(HaveOccurred())
@@ -73,8 +76,9 @@ var _ = Describe("GetModuleLoaderDataForKernel", func() {
 
 	It("failed to replace templates", func() {
 		mapping := kmmv1beta1.KernelMapping{}
+		mod.Spec.ModuleLoader.Container.KernelMappings = []kmmv1beta1.KernelMapping{mapping}
 		mld := api.ModuleLoaderData{KernelVersion: kernelVersion}
-		kh.EXPECT().findKernelMapping(mod.Spec.ModuleLoader.Container.KernelMappings, kernelVersion).Return(&mapping, nil)
+		kh.EXPECT().findKernelMapping(mod.Spec.ModuleLoader.Container.KernelMappings, kernelVersion).Return(0, nil)
 		kh.EXPECT().prepareModuleLoaderData(&mapping, &mod, kernelVersion).Return(&mld, nil)
 		kh.EXPECT().replaceTemplates(&mld).Return(fmt.Errorf("some error"))
 		res, err := km.GetModuleLoaderDataForKernel(&mod, kernelVersion)
@@ -107,9 +111,9 @@ var _ = Describe("findKernelMapping", func() {
 			Literal: "1.2.3",
 		}
 
-		m, err := kh.findKernelMapping([]kmmv1beta1.KernelMapping{mapping}, kernelVersion)
+		idx, err := kh.findKernelMapping([]kmmv1beta1.KernelMapping{mapping}, kernelVersion)
 		Expect(err).NotTo(HaveOccurred())
-		Expect(m).To(Equal(&mapping))
+		Expect(idx).To(Equal(0))
 	})
 
 	It("one regexp mapping", func() {
@@ -117,9 +121,9 @@ var _ = Describe("findKernelMapping", func() {
 			Regexp: `1\..*`,
 		}
 
-		m, err := kh.findKernelMapping([]kmmv1beta1.KernelMapping{mapping}, kernelVersion)
+		idx, err := kh.findKernelMapping([]kmmv1beta1.KernelMapping{mapping}, kernelVersion)
 		Expect(err).NotTo(HaveOccurred())
-		Expect(m).To(Equal(&mapping))
+		Expect(idx).To(Equal(0))
 	})
 
 	It("should return an error if a regex is invalid", func() {
@@ -127,9 +131,32 @@ var _ = Describe("findKernelMapping", func() {
 			Regexp: "invalid)",
 		}
 
-		m, err := kh.findKernelMapping([]kmmv1beta1.KernelMapping{mapping}, kernelVersion)
+		_, err := kh.findKernelMapping([]kmmv1beta1.KernelMapping{mapping}, kernelVersion)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("one version range mapping", func() {
+		mappings := []kmmv1beta1.KernelMapping{
+			{
+				VersionRange: ">=1.3",
+			},
+			{
+				VersionRange: ">=1.2, <1.3",
+			},
+		}
+
+		idx, err := kh.findKernelMapping(mappings, kernelVersion)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(idx).To(Equal(1))
+	})
+
+	It("should return an error if a version range is invalid", func() {
+		mapping := kmmv1beta1.KernelMapping{
+			VersionRange: "1.2.3",
+		}
+
+		_, err := kh.findKernelMapping([]kmmv1beta1.KernelMapping{mapping}, kernelVersion)
 		Expect(err).To(HaveOccurred())
-		Expect(m).To(BeNil())
 	})
 
 	It("should return an error if no mapping work", func() {
@@ -142,9 +169,32 @@ var _ = Describe("findKernelMapping", func() {
 			},
 		}
 
-		m, err := kh.findKernelMapping(mappings, kernelVersion)
+		_, err := kh.findKernelMapping(mappings, kernelVersion)
 		Expect(errors.Is(err, ErrNoMatchingKernelMapping)).To(BeTrue())
-		Expect(m).To(BeNil())
+	})
+})
+
+var _ = Describe("MatchingKernelMappings", func() {
+	It("should order the matching mappings by precedence", func() {
+		mappings := []kmmv1beta1.KernelMapping{
+			{Regexp: `^1\..*`},
+			{VersionRange: ">=2.0"},
+			{VersionRange: ">=1.2"},
+			{Literal: "1.2.3"},
+			{Regexp: `^2\..*`},
+			{Regexp: `.*`},
+		}
+
+		Expect(
+			MatchingKernelMappings(mappings, "1.2.3"),
+		).To(
+			Equal([]int{3, 2, 0, 5}),
+		)
+	})
+
+	It("should return an error if a regexp is invalid", func() {
+		_, err := MatchingKernelMappings([]kmmv1beta1.KernelMapping{{Regexp: "invalid)"}}, "1.2.3")
+		Expect(err).To(HaveOccurred())
 	})
 })
 
@@ -238,6 +288,10 @@ var _ = Describe("prepareModuleLoaderData", func() {
 		if inTreeModulesToRemoveExistsInMapping {
 			mld.InTreeModulesToRemove = []string{"inTreeModule1", "inTreeModule2"}
 			mapping.InTreeModulesToRemove = []string{"inTreeModule1", "inTreeModule2"}
+			mod.Spec.ModuleLoader.Container.BlacklistInTreeModules = true
+			mld.BlacklistInTreeModules = true
+			mod.Spec.ModuleLoader.Container.RestoreInTreeModulesOnUnload = true
+			mld.RestoreInTreeModulesOnUnload = true
 		}
 
 		res, err := kh.prepareModuleLoaderData(&mapping, &mod, kernelVersion)
@@ -253,6 +307,25 @@ var _ = Describe("prepareModuleLoaderData", func() {
 		Entry("inTreeModulesToRemove in mapping", false, false, false, false, false, false, true),
 	)
 
+	It("should merge the modprobe override of the mapping into the Container's modprobe settings", func() {
+		mod.Spec.ModuleLoader.Container.Modprobe = kmmv1beta1.ModprobeSpec{
+			ModuleName: "module-name",
+			DirName:    "/opt",
+		}
+		mapping.Modprobe = &kmmv1beta1.ModprobeOverride{
+			ModuleName:   "module-name-rt",
+			FirmwarePath: "/firmware-rt",
+		}
+
+		res, err := kh.prepareModuleLoaderData(&mapping, &mod, kernelVersion)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.Modprobe).To(Equal(kmmv1beta1.ModprobeSpec{
+			ModuleName:   "module-name-rt",
+			DirName:      "/opt",
+			FirmwarePath: "/firmware-rt",
+		}))
+	})
+
 	// [TODO] remove this unit test once InTreeModuleToRemove depricated field is removed from CRD
 	DescribeTable("prepare InTreeModules based on InTreeModule", func(inTreeModuleInContainer, inTreeModuleInMapping bool, expectedInTreeModules []string) {
 		mld := api.ModuleLoaderData{
@@ -3,7 +3,7 @@
 //
 // Generated by this command:
 //
-//	mockgen -source=kernelmapper.go -package=module -destination=mock_kernelmapper.go KernelMapper,kernelMapperHelperAPI
+//	mockgen -source=kernelmapper.go -package=module -destination=mock_kernelmapper.go
 //
 // Package module is a generated GoMock package.
 package module
@@ -78,10 +78,10 @@ func (m *MockkernelMapperHelperAPI) EXPECT() *MockkernelMapperHelperAPIMockRecor
 }
 
 // findKernelMapping mocks base method.
-func (m *MockkernelMapperHelperAPI) findKernelMapping(mappings []v1beta1.KernelMapping, kernelVersion string) (*v1beta1.KernelMapping, error) {
+func (m *MockkernelMapperHelperAPI) findKernelMapping(mappings []v1beta1.KernelMapping, kernelVersion string) (int, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "findKernelMapping", mappings, kernelVersion)
-	ret0, _ := ret[0].(*v1beta1.KernelMapping)
+	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -69,6 +69,8 @@ func (h *helper) SetModuleConfig(
 	foundEntry.Config = *moduleConfig
 	foundEntry.ImageRepoSecret = mld.ImageRepoSecret
 	foundEntry.ServiceAccountName = saName
+	foundEntry.Drain = mld.Drain
+	foundEntry.MaintenanceWindow = mld.MaintenanceWindow
 
 	return nil
 }
@@ -140,3 +142,83 @@ func SetModuleStatus(statuses *[]kmmv1beta1.NodeModuleStatus, status kmmv1beta1.
 		*statuses = append(*statuses, status)
 	}
 }
+
+func FindPendingModule(pending []kmmv1beta1.NodeModulePendingStatus, moduleNamespace, moduleName string) *kmmv1beta1.NodeModulePendingStatus {
+	for i := 0; i < len(pending); i++ {
+		p := pending[i]
+
+		if p.Namespace == moduleNamespace && p.Name == moduleName {
+			return &pending[i]
+		}
+	}
+
+	return nil
+}
+
+func RemovePendingModule(pending *[]kmmv1beta1.NodeModulePendingStatus, modNamespace, modName string) {
+	if pending == nil || len(*pending) == 0 {
+		return
+	}
+
+	newPending := make([]kmmv1beta1.NodeModulePendingStatus, 0, len(*pending)-1)
+
+	for _, p := range *pending {
+		if p.Namespace != modNamespace || p.Name != modName {
+			newPending = append(newPending, p)
+		}
+	}
+
+	*pending = newPending
+}
+
+func FindWorkerResult(results []kmmv1beta1.NodeModuleWorkerResult, moduleNamespace, moduleName string) *kmmv1beta1.NodeModuleWorkerResult {
+	for i := 0; i < len(results); i++ {
+		r := results[i]
+
+		if r.Namespace == moduleNamespace && r.Name == moduleName {
+			return &results[i]
+		}
+	}
+
+	return nil
+}
+
+func RemoveWorkerResult(results *[]kmmv1beta1.NodeModuleWorkerResult, modNamespace, modName string) {
+	if results == nil || len(*results) == 0 {
+		return
+	}
+
+	newResults := make([]kmmv1beta1.NodeModuleWorkerResult, 0, len(*results)-1)
+
+	for _, r := range *results {
+		if r.Namespace != modNamespace || r.Name != modName {
+			newResults = append(newResults, r)
+		}
+	}
+
+	*results = newResults
+}
+
+func SetWorkerResult(results *[]kmmv1beta1.NodeModuleWorkerResult, result kmmv1beta1.NodeModuleWorkerResult) {
+	if results == nil {
+		return
+	}
+
+	r := FindWorkerResult(*results, result.Namespace, result.Name)
+
+	if r != nil {
+		*r = result
+	} else {
+		*results = append(*results, result)
+	}
+}
+
+// InTreeModulesToRemove returns the in-tree modules that cfg replaces.
+func InTreeModulesToRemove(cfg *kmmv1beta1.ModuleConfig) []string {
+	// [TODO] - remove handling cfg.InTreeModuleToRemove once we cease to support it
+	if cfg.InTreeModulesToRemove == nil && cfg.InTreeModuleToRemove != "" {
+		return []string{cfg.InTreeModuleToRemove}
+	}
+
+	return cfg.InTreeModulesToRemove
+}
@@ -3,6 +3,7 @@ package nmc
 import (
 	"context"
 	"fmt"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -10,6 +11,7 @@ import (
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/api"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/client"
 	"go.uber.org/mock/gomock"
+	v1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -136,6 +138,22 @@ var _ = Describe("SetModuleConfig", func() {
 		Expect(nmc.Spec.Modules[1].Config.InTreeModulesToRemove).To(Equal([]string{"in-tree-module1", "in-tree-module2"}))
 		Expect(nmc.Spec.Modules[1].ServiceAccountName).To(Equal(saName))
 	})
+
+	It("should set the drain policy and the maintenance window outside of the module config", func() {
+		nmc.Spec.Modules = nil
+
+		drain := &kmmv1beta1.DrainSpec{ResourceNames: []v1.ResourceName{"example.com/gpu"}}
+		mw := &kmmv1beta1.MaintenanceWindow{Schedule: "0 2 * * *", Duration: metav1.Duration{Duration: time.Hour}}
+		moduleConfig := kmmv1beta1.ModuleConfig{ContainerImage: "some-image"}
+		mld := api.ModuleLoaderData{Name: name, Namespace: namespace, Drain: drain, MaintenanceWindow: mw}
+
+		err := nmcHelper.SetModuleConfig(&nmc, &mld, &moduleConfig)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(nmc.Spec.Modules[0].Config).To(Equal(moduleConfig))
+		Expect(nmc.Spec.Modules[0].Drain).To(Equal(drain))
+		Expect(nmc.Spec.Modules[0].MaintenanceWindow).To(Equal(mw))
+	})
 })
 
 var _ = Describe("RemoveModuleConfig", func() {
@@ -424,3 +442,142 @@ var _ = Describe("SetModuleStatus", func() {
 		Expect(statuses[0]).To(BeComparableTo(new))
 	})
 })
+
+var _ = Describe("RemoveWorkerResult", func() {
+	const (
+		name      = "test-name"
+		namespace = "test-namespace"
+	)
+
+	It("should do nothing if the list is nil", func() {
+		RemoveWorkerResult(nil, namespace, name)
+	})
+
+	It("should remove a result if it exists in the list", func() {
+		results := []kmmv1beta1.NodeModuleWorkerResult{
+			{Namespace: namespace, Name: name},
+			{Namespace: namespace, Name: "other"},
+		}
+
+		RemoveWorkerResult(&results, namespace, name)
+
+		Expect(results).To(Equal([]kmmv1beta1.NodeModuleWorkerResult{{Namespace: namespace, Name: "other"}}))
+	})
+})
+
+var _ = Describe("SetWorkerResult", func() {
+	const (
+		name      = "test-name"
+		namespace = "test-namespace"
+	)
+
+	r := kmmv1beta1.NodeModuleWorkerResult{
+		Name:      name,
+		Namespace: namespace,
+		PodName:   "some-pod",
+		WorkerResult: kmmv1beta1.WorkerResult{
+			Action:        "load",
+			ErrorCategory: "ModuleNotFound",
+		},
+	}
+
+	It("should add an entry if the list is empty", func() {
+		results := make([]kmmv1beta1.NodeModuleWorkerResult, 0)
+
+		SetWorkerResult(&results, r)
+
+		Expect(results).To(Equal([]kmmv1beta1.NodeModuleWorkerResult{r}))
+	})
+
+	It("should update an entry if it already exists", func() {
+		results := []kmmv1beta1.NodeModuleWorkerResult{{Namespace: namespace, Name: name}}
+
+		SetWorkerResult(&results, r)
+
+		Expect(results).To(Equal([]kmmv1beta1.NodeModuleWorkerResult{r}))
+		Expect(FindWorkerResult(results, namespace, name)).To(Equal(&r))
+	})
+})
+
+var _ = Describe("InTreeModulesToRemove", func() {
+	It("should return nil if no in-tree module is replaced", func() {
+		Expect(
+			InTreeModulesToRemove(&kmmv1beta1.ModuleConfig{}),
+		).To(
+			BeNil(),
+		)
+	})
+
+	It("should return the deprecated single in-tree module", func() {
+		Expect(
+			InTreeModulesToRemove(&kmmv1beta1.ModuleConfig{InTreeModuleToRemove: "intree"}),
+		).To(
+			Equal([]string{"intree"}),
+		)
+	})
+
+	It("should prefer the list of in-tree modules", func() {
+		cfg := kmmv1beta1.ModuleConfig{
+			InTreeModuleToRemove:  "intree",
+			InTreeModulesToRemove: []string{"intree1", "intree2"},
+		}
+
+		Expect(
+			InTreeModulesToRemove(&cfg),
+		).To(
+			Equal([]string{"intree1", "intree2"}),
+		)
+	})
+})
+
+var _ = Describe("FindPendingModule", func() {
+	const (
+		name      = "test-name"
+		namespace = "test-namespace"
+	)
+
+	It("should return nil if the module is not pending", func() {
+		pending := []kmmv1beta1.NodeModulePendingStatus{{Namespace: namespace, Name: "other"}}
+
+		Expect(
+			FindPendingModule(pending, namespace, name),
+		).To(
+			BeNil(),
+		)
+	})
+
+	It("should return the entry of the module", func() {
+		pending := []kmmv1beta1.NodeModulePendingStatus{
+			{Namespace: namespace, Name: "other"},
+			{Namespace: namespace, Name: name},
+		}
+
+		Expect(
+			FindPendingModule(pending, namespace, name),
+		).To(
+			BeIdenticalTo(&pending[1]),
+		)
+	})
+})
+
+var _ = Describe("RemovePendingModule", func() {
+	const (
+		name      = "test-name"
+		namespace = "test-namespace"
+	)
+
+	It("should do nothing if the list is nil", func() {
+		RemovePendingModule(nil, namespace, name)
+	})
+
+	It("should remove the entry of the module", func() {
+		pending := []kmmv1beta1.NodeModulePendingStatus{
+			{Namespace: namespace, Name: name},
+			{Namespace: namespace, Name: "other"},
+		}
+
+		RemovePendingModule(&pending, namespace, name)
+
+		Expect(pending).To(Equal([]kmmv1beta1.NodeModulePendingStatus{{Namespace: namespace, Name: "other"}}))
+	})
+})
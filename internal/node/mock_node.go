@@ -40,6 +40,21 @@ func (m *MockNode) EXPECT() *MockNodeMockRecorder {
 	return m.recorder
 }
 
+// Cordon mocks base method.
+func (m *MockNode) Cordon(ctx context.Context, node *v1.Node, owner string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cordon", ctx, node, owner)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Cordon indicates an expected call of Cordon.
+func (mr *MockNodeMockRecorder) Cordon(ctx, node, owner any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cordon", reflect.TypeOf((*MockNode)(nil).Cordon), ctx, node, owner)
+}
+
 // GetNodesListBySelector mocks base method.
 func (m *MockNode) GetNodesListBySelector(ctx context.Context, selector map[string]string, tolerations []v1.Toleration) ([]v1.Node, error) {
 	m.ctrl.T.Helper()
@@ -98,6 +113,21 @@ func (mr *MockNodeMockRecorder) NodeBecomeReadyAfter(node, checkTime any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeBecomeReadyAfter", reflect.TypeOf((*MockNode)(nil).NodeBecomeReadyAfter), node, checkTime)
 }
 
+// Uncordon mocks base method.
+func (m *MockNode) Uncordon(ctx context.Context, node *v1.Node, owner string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Uncordon", ctx, node, owner)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Uncordon indicates an expected call of Uncordon.
+func (mr *MockNodeMockRecorder) Uncordon(ctx, node, owner any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Uncordon", reflect.TypeOf((*MockNode)(nil).Uncordon), ctx, node, owner)
+}
+
 // UpdateLabels mocks base method.
 func (m *MockNode) UpdateLabels(ctx context.Context, node *v1.Node, toBeAdded, toBeRemoved []string) error {
 	m.ctrl.T.Helper()
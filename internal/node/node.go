@@ -3,9 +3,14 @@ package node
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/constants"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/meta"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
@@ -18,6 +23,8 @@ type Node interface {
 	GetNumTargetedNodes(ctx context.Context, selector map[string]string, tolerations []v1.Toleration) (int, error)
 	UpdateLabels(ctx context.Context, node *v1.Node, toBeAdded, toBeRemoved []string) error
 	NodeBecomeReadyAfter(node *v1.Node, checkTime metav1.Time) bool
+	Cordon(ctx context.Context, node *v1.Node, owner string) (bool, error)
+	Uncordon(ctx context.Context, node *v1.Node, owner string) (bool, error)
 }
 
 type node struct {
@@ -39,6 +46,10 @@ func (n *node) IsNodeSchedulable(node *v1.Node, tolerations []v1.Toleration) boo
 		}
 	}
 	for _, taint := range node.Spec.Taints {
+		// Nodes cordoned by KMM to reload a module remain targeted.
+		if taint.Key == v1.TaintNodeUnschedulable && cordonOwners(node).Len() > 0 {
+			continue
+		}
 		if taint.Effect == v1.TaintEffectNoSchedule {
 			return false
 		}
@@ -96,6 +107,77 @@ func (n *node) NodeBecomeReadyAfter(node *v1.Node, timestamp metav1.Time) bool {
 	return false
 }
 
+// Cordon marks node as unschedulable on behalf of owner, typically the namespace/name of a module that needs to
+// drain the node.
+// Nodes that were cordoned by something else than KMM are left untouched.
+// It returns true if owner was added to the modules that keep node cordoned.
+func (n *node) Cordon(ctx context.Context, node *v1.Node, owner string) (bool, error) {
+	owners := cordonOwners(node)
+
+	if owners.Has(owner) || (node.Spec.Unschedulable && owners.Len() == 0) {
+		return false, nil
+	}
+
+	patchFrom := client.MergeFrom(node.DeepCopy())
+
+	setCordonOwners(node, owners.Insert(owner))
+	node.Spec.Unschedulable = true
+
+	if err := n.client.Patch(ctx, node, patchFrom); err != nil {
+		return false, fmt.Errorf("could not cordon node %s: %v", node.Name, err)
+	}
+
+	return true, nil
+}
+
+// Uncordon removes owner from the modules that keep node cordoned, and marks node as schedulable if no other module
+// keeps it cordoned.
+// It returns true if owner had cordoned node.
+func (n *node) Uncordon(ctx context.Context, node *v1.Node, owner string) (bool, error) {
+	owners := cordonOwners(node)
+
+	if !owners.Has(owner) {
+		return false, nil
+	}
+
+	patchFrom := client.MergeFrom(node.DeepCopy())
+
+	owners.Delete(owner)
+	setCordonOwners(node, owners)
+
+	if owners.Len() == 0 {
+		node.Spec.Unschedulable = false
+	}
+
+	if err := n.client.Patch(ctx, node, patchFrom); err != nil {
+		return false, fmt.Errorf("could not uncordon node %s: %v", node.Name, err)
+	}
+
+	return true, nil
+}
+
+func cordonOwners(node *v1.Node) sets.Set[string] {
+	owners := sets.New[string]()
+
+	if v := node.GetAnnotations()[constants.NodeCordonedForModulesAnnotation]; v != "" {
+		owners.Insert(strings.Split(v, ",")...)
+	}
+
+	return owners
+}
+
+func setCordonOwners(node *v1.Node, owners sets.Set[string]) {
+	if owners.Len() == 0 {
+		delete(node.Annotations, constants.NodeCordonedForModulesAnnotation)
+		return
+	}
+
+	list := owners.UnsortedList()
+	sort.Strings(list)
+
+	meta.SetAnnotation(node, constants.NodeCordonedForModulesAnnotation, strings.Join(list, ","))
+}
+
 func addLabels(node *v1.Node, labels []string) {
 	for _, label := range labels {
 		meta.SetLabel(
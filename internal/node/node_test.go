@@ -6,6 +6,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/client"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/constants"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/utils"
 	"go.uber.org/mock/gomock"
 	v1 "k8s.io/api/core/v1"
@@ -65,6 +66,29 @@ var _ = Describe("IsNodeSchedulable", func() {
 	})
 })
 
+var _ = Describe("IsNodeSchedulable for nodes cordoned by KMM", func() {
+	It("should ignore the unschedulable taint only if KMM cordoned the node", func() {
+		node := v1.Node{
+			Spec: v1.NodeSpec{
+				Taints: []v1.Taint{
+					{
+						Key:    v1.TaintNodeUnschedulable,
+						Effect: v1.TaintEffectNoSchedule,
+					},
+				},
+			},
+		}
+
+		n := NewNode(nil)
+
+		Expect(n.IsNodeSchedulable(&node, nil)).To(BeFalse())
+
+		node.SetAnnotations(map[string]string{constants.NodeCordonedForModulesAnnotation: "ns/mod"})
+
+		Expect(n.IsNodeSchedulable(&node, nil)).To(BeTrue())
+	})
+})
+
 var _ = Describe("GetNodesListBySelector", func() {
 	var (
 		ctrl *gomock.Controller
@@ -358,3 +382,135 @@ var _ = Describe("removeLabels", func() {
 		Expect(node.Labels).ToNot(HaveKey(loadedKernelModuleReadyNodeLabel))
 	})
 })
+
+var _ = Describe("Cordon", func() {
+	var (
+		ctx  context.Context
+		clnt *client.MockClient
+		n    Node
+	)
+
+	BeforeEach(func() {
+		ctx = context.TODO()
+		clnt = client.NewMockClient(gomock.NewController(GinkgoT()))
+		n = NewNode(clnt)
+	})
+
+	It("should cordon the node and record the owner", func() {
+		node := v1.Node{}
+
+		clnt.EXPECT().Patch(ctx, &node, gomock.Any())
+
+		cordoned, err := n.Cordon(ctx, &node, "ns/mod")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cordoned).To(BeTrue())
+		Expect(node.Spec.Unschedulable).To(BeTrue())
+		Expect(node.Annotations).To(HaveKeyWithValue(constants.NodeCordonedForModulesAnnotation, "ns/mod"))
+	})
+
+	It("should add the owner to the ones that already cordoned the node", func() {
+		node := v1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{constants.NodeCordonedForModulesAnnotation: "ns/other"},
+			},
+			Spec: v1.NodeSpec{Unschedulable: true},
+		}
+
+		clnt.EXPECT().Patch(ctx, &node, gomock.Any())
+
+		cordoned, err := n.Cordon(ctx, &node, "ns/mod")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cordoned).To(BeTrue())
+		Expect(node.Annotations).To(HaveKeyWithValue(constants.NodeCordonedForModulesAnnotation, "ns/mod,ns/other"))
+	})
+
+	It("should do nothing if the owner already cordoned the node", func() {
+		node := v1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{constants.NodeCordonedForModulesAnnotation: "ns/mod"},
+			},
+			Spec: v1.NodeSpec{Unschedulable: true},
+		}
+
+		cordoned, err := n.Cordon(ctx, &node, "ns/mod")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cordoned).To(BeFalse())
+	})
+
+	It("should do nothing if the node was cordoned by something else", func() {
+		node := v1.Node{
+			Spec: v1.NodeSpec{Unschedulable: true},
+		}
+
+		cordoned, err := n.Cordon(ctx, &node, "ns/mod")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cordoned).To(BeFalse())
+		Expect(node.Annotations).To(BeEmpty())
+	})
+
+	It("should return an error if the node cannot be patched", func() {
+		clnt.EXPECT().Patch(ctx, gomock.Any(), gomock.Any()).Return(fmt.Errorf("some error"))
+
+		_, err := n.Cordon(ctx, &v1.Node{}, "ns/mod")
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("Uncordon", func() {
+	var (
+		ctx  context.Context
+		clnt *client.MockClient
+		n    Node
+	)
+
+	BeforeEach(func() {
+		ctx = context.TODO()
+		clnt = client.NewMockClient(gomock.NewController(GinkgoT()))
+		n = NewNode(clnt)
+	})
+
+	It("should uncordon the node if the owner was the last one", func() {
+		node := v1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{constants.NodeCordonedForModulesAnnotation: "ns/mod"},
+			},
+			Spec: v1.NodeSpec{Unschedulable: true},
+		}
+
+		clnt.EXPECT().Patch(ctx, &node, gomock.Any())
+
+		uncordoned, err := n.Uncordon(ctx, &node, "ns/mod")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(uncordoned).To(BeTrue())
+		Expect(node.Spec.Unschedulable).To(BeFalse())
+		Expect(node.Annotations).NotTo(HaveKey(constants.NodeCordonedForModulesAnnotation))
+	})
+
+	It("should keep the node cordoned for other owners", func() {
+		node := v1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{constants.NodeCordonedForModulesAnnotation: "ns/mod,ns/other"},
+			},
+			Spec: v1.NodeSpec{Unschedulable: true},
+		}
+
+		clnt.EXPECT().Patch(ctx, &node, gomock.Any())
+
+		uncordoned, err := n.Uncordon(ctx, &node, "ns/mod")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(uncordoned).To(BeTrue())
+		Expect(node.Spec.Unschedulable).To(BeTrue())
+		Expect(node.Annotations).To(HaveKeyWithValue(constants.NodeCordonedForModulesAnnotation, "ns/other"))
+	})
+
+	It("should do nothing if the owner did not cordon the node", func() {
+		node := v1.Node{
+			Spec: v1.NodeSpec{Unschedulable: true},
+		}
+
+		uncordoned, err := n.Uncordon(ctx, &node, "ns/mod")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(uncordoned).To(BeFalse())
+		Expect(node.Spec.Unschedulable).To(BeTrue())
+	})
+})
@@ -10,6 +10,7 @@ import (
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/api"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/auth"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/build"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/modinfo"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/module"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/registry"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/sign"
@@ -126,7 +127,7 @@ func newPreflightHelper(client client.Client, buildAPI build.Manager, signAPI si
 func (p *preflightHelper) verifyImage(ctx context.Context, mld *api.ModuleLoaderData) (bool, string) {
 	log := ctrlruntime.LoggerFrom(ctx)
 	image := mld.ContainerImage
-	moduleFileName := mld.Modprobe.ModuleName + ".ko"
+	moduleName := mld.Modprobe.ModuleName
 	baseDir := mld.Modprobe.DirName
 	kernelVersion := mld.KernelVersion
 
@@ -145,16 +146,41 @@ func (p *preflightHelper) verifyImage(ctx context.Context, mld *api.ModuleLoader
 		}
 
 		// check kernel module file present in the directory of the kernel lib modules
-		if p.registryAPI.VerifyModuleExists(layer, baseDir, kernelVersion, moduleFileName) {
-			return true, fmt.Sprintf(VerificationStatusReasonVerified, "image accessible and verified")
+		path, data, err := p.registryAPI.GetModuleFromLayer(layer, baseDir, kernelVersion, moduleName)
+		if err != nil {
+			log.Info("layer from image could not be read", "layer", digests[i], "image", image, "error", err)
+			continue
+		}
+
+		if path != "" {
+			return verifyModuleFile(ctx, image, path, data, kernelVersion)
 		}
-		log.V(1).Info("module is not present in the current layer", "image", image, "module file name", moduleFileName, "kernel", kernelVersion, "dir", baseDir)
+		log.V(1).Info("module is not present in the current layer", "image", image, "module name", moduleName, "kernel", kernelVersion, "dir", baseDir)
 	}
 
-	log.Info("driver for kernel is not present in the image", "baseDir", baseDir, "kernel", kernelVersion, "moduleFileName", moduleFileName, "image", image)
+	log.Info("driver for kernel is not present in the image", "baseDir", baseDir, "kernel", kernelVersion, "moduleName", moduleName, "image", image)
 	return false, fmt.Sprintf("image %s does not contain kernel module for kernel %s on any layer", image, kernelVersion)
 }
 
+// verifyModuleFile checks that the kernel module file found in the image can be loaded by kernelVersion.
+func verifyModuleFile(ctx context.Context, image, path string, data []byte, kernelVersion string) (bool, string) {
+	log := ctrlruntime.LoggerFrom(ctx)
+
+	info, err := modinfo.Parse(data)
+	if err != nil {
+		log.Info("could not read the module information", "image", image, "path", path, "error", err)
+		return false, fmt.Sprintf("image %s: could not read the module information of %s: %v", image, path, err)
+	}
+
+	if err = info.CheckKernel(kernelVersion); err != nil {
+		log.Info("module is not compatible with the kernel", "image", image, "path", path, "vermagic", info.Vermagic, "kernel", kernelVersion)
+		return false, fmt.Sprintf("image %s: %s: %v", image, path, err)
+	}
+
+	log.V(1).Info("module is compatible with the kernel", "image", image, "path", path, "vermagic", info.Vermagic, "depends", info.Depends)
+	return true, fmt.Sprintf(VerificationStatusReasonVerified, "image accessible and verified")
+}
+
 func (p *preflightHelper) verifyBuild(ctx context.Context, pv *v1beta2.PreflightValidation, mld *api.ModuleLoaderData) (bool, string) {
 	log := ctrlruntime.LoggerFrom(ctx)
 	// at this stage we know that eiher mapping Build or Container build are defined
@@ -3,6 +3,7 @@ package preflight
 import (
 	context "context"
 	"fmt"
+	"os"
 	"testing"
 
 	v1stream "github.com/google/go-containerregistry/pkg/v1/stream"
@@ -208,9 +209,21 @@ var _ = Describe("preflightHelper_verifyImage", func() {
 		authGetter      *auth.MockRegistryAuthGetter
 		clnt            *client.MockClient
 		ph              *preflightHelper
+		moduleData      []byte
+	)
+
+	const (
+		// moduleKernelVersion is the kernel in the vermagic of testdata/kmm-test.ko
+		moduleKernelVersion = "5.14.0-284.el9.x86_64"
+		modulePath          = "opt/lib/modules/" + moduleKernelVersion + "/simple-kmod.ko"
 	)
 
 	BeforeEach(func() {
+		var err error
+
+		moduleData, err = os.ReadFile("testdata/kmm-test.ko")
+		Expect(err).NotTo(HaveOccurred())
+
 		ctrl = gomock.NewController(GinkgoT())
 		clnt = client.NewMockClient(ctrl)
 		mockRegistryAPI = registry.NewMockRegistry(ctrl)
@@ -232,7 +245,7 @@ var _ = Describe("preflightHelper_verifyImage", func() {
 		mld := api.ModuleLoaderData{
 			ContainerImage: containerImage,
 			Modprobe:       mod.Spec.ModuleLoader.Container.Modprobe,
-			KernelVersion:  kernelVersion,
+			KernelVersion:  moduleKernelVersion,
 		}
 		digests := []string{"digest0", "digest1"}
 		repoConfig := &registry.RepoPullConfig{}
@@ -241,7 +254,86 @@ var _ = Describe("preflightHelper_verifyImage", func() {
 			mockAuthFactory.EXPECT().NewRegistryAuthGetterFrom(&mld).Return(authGetter),
 			mockRegistryAPI.EXPECT().GetLayersDigests(context.Background(), containerImage, gomock.Any(), gomock.Any()).Return(digests, repoConfig, nil),
 			mockRegistryAPI.EXPECT().GetLayerByDigest(digests[1], repoConfig).Return(&digestLayer, nil),
-			mockRegistryAPI.EXPECT().VerifyModuleExists(&digestLayer, "/opt", kernelVersion, "simple-kmod.ko").Return(true),
+			mockRegistryAPI.EXPECT().GetModuleFromLayer(&digestLayer, "/opt", moduleKernelVersion, "simple-kmod").Return(modulePath, moduleData, nil),
+		)
+
+		res, message := ph.verifyImage(context.Background(), &mld)
+
+		Expect(res).To(BeTrue())
+		Expect(message).To(Equal(fmt.Sprintf(VerificationStatusReasonVerified, "image accessible and verified")))
+	})
+
+	It("kernel module built for another kernel", func() {
+		mld := api.ModuleLoaderData{
+			ContainerImage: containerImage,
+			Modprobe:       mod.Spec.ModuleLoader.Container.Modprobe,
+			KernelVersion:  "5.14.0-362.el9.x86_64",
+		}
+		digests := []string{"digest0"}
+		repoConfig := &registry.RepoPullConfig{}
+		digestLayer := v1stream.Layer{}
+		gomock.InOrder(
+			mockAuthFactory.EXPECT().NewRegistryAuthGetterFrom(&mld).Return(authGetter),
+			mockRegistryAPI.EXPECT().GetLayersDigests(context.Background(), containerImage, gomock.Any(), gomock.Any()).Return(digests, repoConfig, nil),
+			mockRegistryAPI.EXPECT().GetLayerByDigest(digests[0], repoConfig).Return(&digestLayer, nil),
+			mockRegistryAPI.EXPECT().GetModuleFromLayer(&digestLayer, "/opt", "5.14.0-362.el9.x86_64", "simple-kmod").Return(modulePath, moduleData, nil),
+		)
+
+		res, message := ph.verifyImage(context.Background(), &mld)
+
+		Expect(res).To(BeFalse())
+		Expect(message).To(
+			Equal(
+				fmt.Sprintf(
+					`image %s: %s: module vermagic does not match the kernel: module kmm_test was built for kernel "%s" (vermagic "%s SMP preempt mod_unload modversions"), not "5.14.0-362.el9.x86_64"`,
+					containerImage,
+					modulePath,
+					moduleKernelVersion,
+					moduleKernelVersion,
+				),
+			),
+		)
+	})
+
+	It("kernel module file is not valid", func() {
+		mld := api.ModuleLoaderData{
+			ContainerImage: containerImage,
+			Modprobe:       mod.Spec.ModuleLoader.Container.Modprobe,
+			KernelVersion:  moduleKernelVersion,
+		}
+		digests := []string{"digest0"}
+		repoConfig := &registry.RepoPullConfig{}
+		digestLayer := v1stream.Layer{}
+		gomock.InOrder(
+			mockAuthFactory.EXPECT().NewRegistryAuthGetterFrom(&mld).Return(authGetter),
+			mockRegistryAPI.EXPECT().GetLayersDigests(context.Background(), containerImage, gomock.Any(), gomock.Any()).Return(digests, repoConfig, nil),
+			mockRegistryAPI.EXPECT().GetLayerByDigest(digests[0], repoConfig).Return(&digestLayer, nil),
+			mockRegistryAPI.EXPECT().GetModuleFromLayer(&digestLayer, "/opt", moduleKernelVersion, "simple-kmod").Return(modulePath, []byte("some data"), nil),
+		)
+
+		res, message := ph.verifyImage(context.Background(), &mld)
+
+		Expect(res).To(BeFalse())
+		Expect(message).To(HavePrefix(fmt.Sprintf("image %s: could not read the module information of %s", containerImage, modulePath)))
+	})
+
+	It("failed to read a layer, module present in a lower layer", func() {
+		mld := api.ModuleLoaderData{
+			ContainerImage: containerImage,
+			Modprobe:       mod.Spec.ModuleLoader.Container.Modprobe,
+			KernelVersion:  moduleKernelVersion,
+		}
+		digests := []string{"digest0", "digest1"}
+		repoConfig := &registry.RepoPullConfig{}
+		digestLayer0 := v1stream.Layer{}
+		digestLayer1 := v1stream.Layer{}
+		gomock.InOrder(
+			mockAuthFactory.EXPECT().NewRegistryAuthGetterFrom(&mld).Return(authGetter),
+			mockRegistryAPI.EXPECT().GetLayersDigests(context.Background(), containerImage, gomock.Any(), gomock.Any()).Return(digests, repoConfig, nil),
+			mockRegistryAPI.EXPECT().GetLayerByDigest(digests[1], repoConfig).Return(&digestLayer1, nil),
+			mockRegistryAPI.EXPECT().GetModuleFromLayer(&digestLayer1, "/opt", moduleKernelVersion, "simple-kmod").Return("", nil, fmt.Errorf("some error")),
+			mockRegistryAPI.EXPECT().GetLayerByDigest(digests[0], repoConfig).Return(&digestLayer0, nil),
+			mockRegistryAPI.EXPECT().GetModuleFromLayer(&digestLayer0, "/opt", moduleKernelVersion, "simple-kmod").Return(modulePath, moduleData, nil),
 		)
 
 		res, message := ph.verifyImage(context.Background(), &mld)
@@ -250,6 +342,28 @@ var _ = Describe("preflightHelper_verifyImage", func() {
 		Expect(message).To(Equal(fmt.Sprintf(VerificationStatusReasonVerified, "image accessible and verified")))
 	})
 
+	It("failed to read the only layer", func() {
+		mld := api.ModuleLoaderData{
+			ContainerImage: containerImage,
+			Modprobe:       mod.Spec.ModuleLoader.Container.Modprobe,
+			KernelVersion:  kernelVersion,
+		}
+		digests := []string{"digest0"}
+		repoConfig := &registry.RepoPullConfig{}
+		digestLayer := v1stream.Layer{}
+		gomock.InOrder(
+			mockAuthFactory.EXPECT().NewRegistryAuthGetterFrom(&mld).Return(authGetter),
+			mockRegistryAPI.EXPECT().GetLayersDigests(context.Background(), containerImage, gomock.Any(), gomock.Any()).Return(digests, repoConfig, nil),
+			mockRegistryAPI.EXPECT().GetLayerByDigest(digests[0], repoConfig).Return(&digestLayer, nil),
+			mockRegistryAPI.EXPECT().GetModuleFromLayer(&digestLayer, "/opt", kernelVersion, "simple-kmod").Return("", nil, fmt.Errorf("some error")),
+		)
+
+		res, message := ph.verifyImage(context.Background(), &mld)
+
+		Expect(res).To(BeFalse())
+		Expect(message).To(Equal(fmt.Sprintf("image %s does not contain kernel module for kernel %s on any layer", containerImage, kernelVersion)))
+	})
+
 	It("get layers digest failed", func() {
 		mld := api.ModuleLoaderData{
 			ContainerImage: containerImage,
@@ -299,7 +413,7 @@ var _ = Describe("preflightHelper_verifyImage", func() {
 			mockAuthFactory.EXPECT().NewRegistryAuthGetterFrom(&mld).Return(authGetter),
 			mockRegistryAPI.EXPECT().GetLayersDigests(context.Background(), containerImage, gomock.Any(), gomock.Any()).Return(digests, repoConfig, nil),
 			mockRegistryAPI.EXPECT().GetLayerByDigest(digests[0], repoConfig).Return(&digestLayer, nil),
-			mockRegistryAPI.EXPECT().VerifyModuleExists(&digestLayer, "/opt", kernelVersion, "simple-kmod.ko").Return(false),
+			mockRegistryAPI.EXPECT().GetModuleFromLayer(&digestLayer, "/opt", kernelVersion, "simple-kmod").Return("", nil, nil),
 		)
 
 		res, message := ph.verifyImage(context.Background(), &mld)
@@ -102,6 +102,22 @@ func (mr *MockRegistryMockRecorder) GetLayersDigests(ctx, image, tlsOptions, reg
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLayersDigests", reflect.TypeOf((*MockRegistry)(nil).GetLayersDigests), ctx, image, tlsOptions, registryAuthGetter)
 }
 
+// GetModuleFromLayer mocks base method.
+func (m *MockRegistry) GetModuleFromLayer(layer v1.Layer, pathPrefix, kernelVersion, moduleName string) (string, []byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetModuleFromLayer", layer, pathPrefix, kernelVersion, moduleName)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].([]byte)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetModuleFromLayer indicates an expected call of GetModuleFromLayer.
+func (mr *MockRegistryMockRecorder) GetModuleFromLayer(layer, pathPrefix, kernelVersion, moduleName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetModuleFromLayer", reflect.TypeOf((*MockRegistry)(nil).GetModuleFromLayer), layer, pathPrefix, kernelVersion, moduleName)
+}
+
 // ImageExists mocks base method.
 func (m *MockRegistry) ImageExists(ctx context.Context, image string, tlsOptions *v1beta1.TLSOptions, registryAuthGetter auth.RegistryAuthGetter) (bool, error) {
 	m.ctrl.T.Helper()
@@ -131,17 +147,3 @@ func (mr *MockRegistryMockRecorder) LastLayer(ctx, image, po, registryAuthGetter
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LastLayer", reflect.TypeOf((*MockRegistry)(nil).LastLayer), ctx, image, po, registryAuthGetter)
 }
-
-// VerifyModuleExists mocks base method.
-func (m *MockRegistry) VerifyModuleExists(layer v1.Layer, pathPrefix, kernelVersion, moduleFileName string) bool {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "VerifyModuleExists", layer, pathPrefix, kernelVersion, moduleFileName)
-	ret0, _ := ret[0].(bool)
-	return ret0
-}
-
-// VerifyModuleExists indicates an expected call of VerifyModuleExists.
-func (mr *MockRegistryMockRecorder) VerifyModuleExists(layer, pathPrefix, kernelVersion, moduleFileName any) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyModuleExists", reflect.TypeOf((*MockRegistry)(nil).VerifyModuleExists), layer, pathPrefix, kernelVersion, moduleFileName)
-}
@@ -25,6 +25,9 @@ const (
 	modulesLocationPath = "lib/modules"
 )
 
+// moduleFileExtensions are the extensions of the kernel module files that the kernel tools can load.
+var moduleFileExtensions = []string{".ko", ".ko.xz", ".ko.zst", ".ko.gz"}
+
 type DriverToolkitEntry struct {
 	ImageURL            string `json:"imageURL"`
 	KernelFullVersion   string `json:"kernelFullVersion"`
@@ -41,7 +44,10 @@ type RepoPullConfig struct {
 
 type Registry interface {
 	ImageExists(ctx context.Context, image string, tlsOptions *kmmv1beta1.TLSOptions, registryAuthGetter auth.RegistryAuthGetter) (bool, error)
-	VerifyModuleExists(layer v1.Layer, pathPrefix, kernelVersion, moduleFileName string) bool
+	// GetModuleFromLayer returns the path and the content of the file of moduleName, uncompressed or compressed with
+	// one of the extensions supported by the kernel, in the modules directory of kernelVersion under pathPrefix.
+	// It returns an empty path if the layer does not contain the file.
+	GetModuleFromLayer(layer v1.Layer, pathPrefix, kernelVersion, moduleName string) (string, []byte, error)
 	GetLayersDigests(ctx context.Context, image string, tlsOptions *kmmv1beta1.TLSOptions, registryAuthGetter auth.RegistryAuthGetter) ([]string, *RepoPullConfig, error)
 	GetLayerByDigest(digest string, pullConfig *RepoPullConfig) (v1.Layer, error)
 	LastLayer(ctx context.Context, image string, po *kmmv1beta1.TLSOptions, registryAuthGetter auth.RegistryAuthGetter) (v1.Layer, error)
@@ -94,18 +100,45 @@ func (r *registry) LastLayer(ctx context.Context, image string, po *kmmv1beta1.T
 	return r.GetLayerByDigest(digests[len(digests)-1], repoConfig)
 }
 
-func (r *registry) VerifyModuleExists(layer v1.Layer, pathPrefix, kernelVersion, moduleFileName string) bool {
+func (r *registry) GetModuleFromLayer(layer v1.Layer, pathPrefix, kernelVersion, moduleName string) (string, []byte, error) {
 	// in layers headers, there is no root prefix
-	fullPath := filepath.Join(strings.TrimPrefix(pathPrefix, "/"), modulesLocationPath, kernelVersion, moduleFileName)
+	dir := filepath.Join(strings.TrimPrefix(pathPrefix, "/"), modulesLocationPath, kernelVersion)
+
+	paths := make(map[string]bool, len(moduleFileExtensions))
+
+	for _, ext := range moduleFileExtensions {
+		paths[filepath.Join(dir, moduleName+ext)] = true
+	}
 
-	// if getHeaderReaderFromLayer does not return an error, it means that the file exists in the layer,
-	// and that's all the indication that we need
-	_, readerCloser, err := r.getHeaderReaderFromLayer(layer, fullPath)
+	layerreader, err := layer.Uncompressed()
 	if err != nil {
-		return false
+		return "", nil, fmt.Errorf("failed to get layerreader from layer: %v", err)
+	}
+	defer layerreader.Close()
+
+	tr := tar.NewReader(layerreader)
+
+	for {
+		header, err := tr.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return "", nil, nil
+			}
+
+			return "", nil, fmt.Errorf("failed to get next entry from targz: %w", err)
+		}
+
+		if !paths[header.Name] {
+			continue
+		}
+
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to read %s from the layer: %v", header.Name, err)
+		}
+
+		return header.Name, data, nil
 	}
-	readerCloser.Close()
-	return true
 }
 
 func (r *registry) GetDigest(ctx context.Context, image string, tlsOptions *kmmv1beta1.TLSOptions, registryAuthGetter auth.RegistryAuthGetter) (string, error) {
@@ -320,7 +320,7 @@ var _ = Describe("GetLayersDigests", func() {
 	)
 })
 
-var _ = Describe("VerifyModuleExists", func() {
+var _ = Describe("GetModuleFromLayer", func() {
 	reg := NewRegistry()
 
 	It("file is not present", func() {
@@ -328,18 +328,26 @@ var _ = Describe("VerifyModuleExists", func() {
 		layer, err := prepareLayer(fileName, []byte("some data"))
 		Expect(err).ToNot(HaveOccurred())
 
-		res := reg.VerifyModuleExists(layer, "", "somekernel", "module_name.ko")
-		Expect(res).To(BeFalse())
-	})
-
-	It("file is present", func() {
-		const fileName = "opt/lib/modules/somekernel/module_name.ko"
-		layer, err := prepareLayer(fileName, []byte("some data"))
+		path, data, err := reg.GetModuleFromLayer(layer, "", "somekernel", "module_name")
 		Expect(err).ToNot(HaveOccurred())
-
-		res := reg.VerifyModuleExists(layer, "/opt", "somekernel", "module_name.ko")
-		Expect(res).To(BeTrue())
+		Expect(path).To(BeEmpty())
+		Expect(data).To(BeNil())
 	})
+
+	DescribeTable("file is present",
+		func(fileName string) {
+			layer, err := prepareLayer(fileName, []byte("some data"))
+			Expect(err).ToNot(HaveOccurred())
+
+			path, data, err := reg.GetModuleFromLayer(layer, "/opt", "somekernel", "module_name")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(path).To(Equal(fileName))
+			Expect(data).To(Equal([]byte("some data")))
+		},
+		Entry("uncompressed", "opt/lib/modules/somekernel/module_name.ko"),
+		Entry("xz", "opt/lib/modules/somekernel/module_name.ko.xz"),
+		Entry("zstd", "opt/lib/modules/somekernel/module_name.ko.zst"),
+	)
 })
 
 var _ = Describe("GetDigest", func() {
@@ -51,17 +51,3 @@ func (mr *MockFSHelperMockRecorder) FileExists(root, fileRegex any) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FileExists", reflect.TypeOf((*MockFSHelper)(nil).FileExists), root, fileRegex)
 }
-
-// RemoveSrcFilesFromDst mocks base method.
-func (m *MockFSHelper) RemoveSrcFilesFromDst(srcDir, dstDir string) error {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RemoveSrcFilesFromDst", srcDir, dstDir)
-	ret0, _ := ret[0].(error)
-	return ret0
-}
-
-// RemoveSrcFilesFromDst indicates an expected call of RemoveSrcFilesFromDst.
-func (mr *MockFSHelperMockRecorder) RemoveSrcFilesFromDst(srcDir, dstDir any) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveSrcFilesFromDst", reflect.TypeOf((*MockFSHelper)(nil).RemoveSrcFilesFromDst), srcDir, dstDir)
-}
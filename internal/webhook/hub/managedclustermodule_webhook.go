@@ -37,7 +37,8 @@ type ManagedClusterModuleValidator struct {
 func NewManagedClusterModuleValidator(logger logr.Logger) *ManagedClusterModuleValidator {
 	return &ManagedClusterModuleValidator{
 		logger: logger,
-		m:      webhook.NewModuleValidator(logger),
+		// The nodes of managed clusters are not visible from the hub.
+		m: webhook.NewModuleValidator(logger, nil),
 	}
 }
 
@@ -20,14 +20,24 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/go-logr/logr"
 	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/kernel"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/maintenance"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/modgraph"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/module"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/sets"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
@@ -36,10 +46,14 @@ const maxCombinedLength = 40
 
 type ModuleValidator struct {
 	logger logr.Logger
+	reader client.Reader
 }
 
-func NewModuleValidator(logger logr.Logger) *ModuleValidator {
-	return &ModuleValidator{logger: logger}
+// NewModuleValidator returns a ModuleValidator that uses reader to list the nodes targeted by Modules with strict
+// kernel mappings.
+// If reader is nil, strict kernel mappings are not checked.
+func NewModuleValidator(logger logr.Logger, reader client.Reader) *ModuleValidator {
+	return &ModuleValidator{logger: logger, reader: reader}
 }
 
 func (m *ModuleValidator) SetupWebhookWithManager(mgr ctrl.Manager) error {
@@ -62,7 +76,12 @@ func (m *ModuleValidator) ValidateCreate(ctx context.Context, obj runtime.Object
 
 	m.logger.Info("Validating Module creation", "name", mod.Name, "namespace", mod.Namespace)
 
-	return validateModule(mod)
+	warnings, err := validateModule(mod)
+	if err != nil {
+		return warnings, err
+	}
+
+	return warnings, m.validateStrictKernelMappings(ctx, mod)
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
@@ -86,7 +105,54 @@ func (m *ModuleValidator) ValidateUpdate(ctx context.Context, oldObj, newObj run
 		}
 	}
 
-	return validateModule(newMod)
+	warnings, err := validateModule(newMod)
+	if err != nil {
+		return warnings, err
+	}
+
+	return warnings, m.validateStrictKernelMappings(ctx, newMod)
+}
+
+// validateStrictKernelMappings returns an error if the Module has strict kernel mappings and several of them match the
+// kernel of a node matching its selector.
+func (m *ModuleValidator) validateStrictKernelMappings(ctx context.Context, mod *kmmv1beta1.Module) error {
+	if !mod.Spec.ModuleLoader.Container.StrictKernelMappings || m.reader == nil {
+		return nil
+	}
+
+	nodes := v1.NodeList{}
+
+	if err := m.reader.List(ctx, &nodes, client.MatchingLabels(mod.Spec.Selector)); err != nil {
+		return fmt.Errorf("could not list the nodes matching the selector: %v", err)
+	}
+
+	checked := sets.New[string]()
+
+	for _, n := range nodes.Items {
+		kernelVersion := strings.TrimSuffix(n.Status.NodeInfo.KernelVersion, "+")
+
+		if checked.Has(kernelVersion) {
+			continue
+		}
+
+		checked.Insert(kernelVersion)
+
+		indexes, err := module.MatchingKernelMappings(mod.Spec.ModuleLoader.Container.KernelMappings, kernelVersion)
+		if err != nil {
+			return err
+		}
+
+		if len(indexes) > 1 {
+			return fmt.Errorf(
+				"kernel %s of node %s matches kernelMappings %v; only one mapping may match a kernel when strictKernelMappings is set",
+				kernelVersion,
+				n.Name,
+				indexes,
+			)
+		}
+	}
+
+	return nil
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type
@@ -109,7 +175,113 @@ func validateModule(mod *kmmv1beta1.Module) (admission.Warnings, error) {
 		return nil, fmt.Errorf("failed to validate kernel mappings: %v", err)
 	}
 
-	return nil, validateModprobe(mod.Spec.ModuleLoader.Container.Modprobe)
+	if err := validateModprobe(mod.Spec.ModuleLoader.Container.Modprobe); err != nil {
+		return nil, err
+	}
+
+	for idx, km := range mod.Spec.ModuleLoader.Container.KernelMappings {
+		if km.Modprobe == nil {
+			continue
+		}
+
+		modprobe := module.GetRelevantModprobe(mod.Spec.ModuleLoader.Container.Modprobe, km.Modprobe)
+
+		if err := validateModprobe(modprobe); err != nil {
+			return nil, fmt.Errorf("invalid modprobe override at kernelMappings[%d]: %v", idx, err)
+		}
+	}
+
+	if rs := mod.Spec.RolloutStrategy; rs != nil {
+		if err := validateRolloutStrategy(*rs); err != nil {
+			return nil, fmt.Errorf("invalid rollout strategy: %v", err)
+		}
+	}
+
+	if drain := mod.Spec.Drain; drain != nil {
+		if err := validateDrain(*drain); err != nil {
+			return nil, fmt.Errorf("invalid drain policy: %v", err)
+		}
+	}
+
+	if mw := mod.Spec.MaintenanceWindow; mw != nil {
+		if _, err := maintenance.Parse(mw); err != nil {
+			return nil, fmt.Errorf("invalid maintenance window: %v", err)
+		}
+	}
+
+	return overlappingVersionRanges(mod.Spec.ModuleLoader.Container.KernelMappings), nil
+}
+
+// overlappingVersionRanges returns a warning for each pair of kernel mappings whose version ranges overlap.
+// The version ranges must be valid.
+func overlappingVersionRanges(mappings []kmmv1beta1.KernelMapping) admission.Warnings {
+	var (
+		indexes  []int
+		ranges   []*kernel.Range
+		warnings admission.Warnings
+	)
+
+	for i, km := range mappings {
+		if km.VersionRange == "" {
+			continue
+		}
+
+		r, err := kernel.ParseRange(km.VersionRange)
+		if err != nil {
+			continue
+		}
+
+		for j, other := range ranges {
+			if r.Overlaps(other) {
+				warnings = append(
+					warnings,
+					fmt.Sprintf(
+						"kernelMappings[%d] and kernelMappings[%d] have overlapping version ranges; kernels in both use kernelMappings[%d]",
+						indexes[j],
+						i,
+						indexes[j],
+					),
+				)
+			}
+		}
+
+		indexes = append(indexes, i)
+		ranges = append(ranges, r)
+	}
+
+	return warnings
+}
+
+func validateDrain(drain kmmv1beta1.DrainSpec) error {
+	if len(drain.ResourceNames) == 0 && drain.PodSelector == nil {
+		return errors.New("at least one of resourceNames and podSelector must be set")
+	}
+
+	if drain.PodSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(drain.PodSelector); err != nil {
+			return fmt.Errorf("podSelector: %v", err)
+		}
+	}
+
+	return nil
+}
+
+func validateRolloutStrategy(rs kmmv1beta1.RolloutStrategy) error {
+	if rs.MaxUnavailable == nil {
+		return nil
+	}
+
+	// scaling a percentage of 100 nodes returns the percentage itself
+	value, err := intstr.GetScaledValueFromIntOrPercent(rs.MaxUnavailable, 100, true)
+	if err != nil {
+		return fmt.Errorf("maxUnavailable: %v", err)
+	}
+
+	if value <= 0 {
+		return fmt.Errorf("maxUnavailable must be greater than 0, got %s", rs.MaxUnavailable.String())
+	}
+
+	return nil
 }
 
 func validateImageFormat(img string) error {
@@ -134,18 +306,32 @@ func validateModuleLoaderContainerSpec(container kmmv1beta1.ModuleLoaderContaine
 	}
 
 	for idx, km := range container.KernelMappings {
-		if km.Regexp != "" && km.Literal != "" {
-			return fmt.Errorf("regexp and literal are mutually exclusive properties at kernelMappings[%d]", idx)
+		matchers := 0
+
+		for _, m := range []string{km.Regexp, km.Literal, km.VersionRange} {
+			if m != "" {
+				matchers++
+			}
+		}
+
+		if matchers > 1 {
+			return fmt.Errorf("regexp, literal and versionRange are mutually exclusive properties at kernelMappings[%d]", idx)
 		}
 
-		if km.Regexp == "" && km.Literal == "" {
-			return fmt.Errorf("regexp or literal must be set at kernelMappings[%d]", idx)
+		if matchers == 0 {
+			return fmt.Errorf("regexp, literal or versionRange must be set at kernelMappings[%d]", idx)
 		}
 
 		if _, err := regexp.Compile(km.Regexp); err != nil {
 			return fmt.Errorf("invalid regexp at index %d: %v", idx, err)
 		}
 
+		if km.VersionRange != "" {
+			if _, err := kernel.ParseRange(km.VersionRange); err != nil {
+				return fmt.Errorf("invalid versionRange at index %d: %v", idx, err)
+			}
+		}
+
 		if kmImg := km.ContainerImage; kmImg == "" {
 			if container.ContainerImage == "" {
 				return fmt.Errorf("missing spec.moduleLoader.container.kernelMappings[%d].containerImage", idx)
@@ -167,6 +353,74 @@ func validateModuleLoaderContainerSpec(container kmmv1beta1.ModuleLoaderContaine
 	return nil
 }
 
+func validateModulesGraph(modprobe kmmv1beta1.ModprobeSpec) error {
+	if modprobe.ModuleName == "" {
+		return errors.New("moduleName must be set")
+	}
+
+	if modprobe.ModulesLoadingOrder != nil {
+		return errors.New("a loading order cannot be defined as well")
+	}
+
+	g, err := modgraph.New(modprobe.ModulesGraph)
+	if err != nil {
+		return err
+	}
+
+	idx := slices.IndexFunc(modprobe.ModulesGraph, func(n kmmv1beta1.ModuleGraphNode) bool {
+		return n.Name == modprobe.ModuleName
+	})
+
+	if idx == -1 {
+		return fmt.Errorf("moduleName %q is not a module of the graph", modprobe.ModuleName)
+	}
+
+	if modprobe.ModulesGraph[idx].Parameters != nil {
+		return fmt.Errorf("%q: the parameters of moduleName must be set in modprobe.parameters", modprobe.ModuleName)
+	}
+
+	_, err = g.LoadOrder()
+
+	return err
+}
+
+func validateModuleFiles(modprobe kmmv1beta1.ModprobeSpec) error {
+	if modprobe.ModuleName == "" {
+		return errors.New("moduleName must be set")
+	}
+
+	if modprobe.ModulesLoadingOrder != nil || modprobe.ModulesGraph != nil {
+		return errors.New("a loading order or a modules graph cannot be defined as well")
+	}
+
+	if len(modprobe.ModuleFiles) == 0 {
+		return errors.New("at least one file must be defined")
+	}
+
+	s := sets.New[string]()
+
+	for _, f := range modprobe.ModuleFiles {
+		if !filepath.IsLocal(f) {
+			return fmt.Errorf("%q: the path must be relative to the modules directory", f)
+		}
+
+		if s.Has(f) {
+			return fmt.Errorf("%q: duplicate file", f)
+		}
+
+		s.Insert(f)
+	}
+
+	last, _, _ := strings.Cut(filepath.Base(modprobe.ModuleFiles[len(modprobe.ModuleFiles)-1]), ".ko")
+
+	// the kernel treats dashes and underscores in module names the same way
+	if strings.ReplaceAll(last, "-", "_") != strings.ReplaceAll(modprobe.ModuleName, "-", "_") {
+		return fmt.Errorf("the last file must be the module %q", modprobe.ModuleName)
+	}
+
+	return nil
+}
+
 func validateModprobe(modprobe kmmv1beta1.ModprobeSpec) error {
 	moduleName := modprobe.ModuleName
 	moduleNameDefined := moduleName != ""
@@ -205,5 +459,37 @@ func validateModprobe(modprobe kmmv1beta1.ModprobeSpec) error {
 		}
 	}
 
+	if modprobe.ModulesGraph != nil {
+		if err := validateModulesGraph(modprobe); err != nil {
+			return fmt.Errorf("invalid modules graph: %v", err)
+		}
+	}
+
+	if modprobe.ModuleFiles != nil {
+		if err := validateModuleFiles(modprobe); err != nil {
+			return fmt.Errorf("invalid module files: %v", err)
+		}
+	}
+
+	if sv := modprobe.SignatureVerification; sv != nil {
+		if !moduleNameDefined {
+			return errors.New("if signature verification is enabled, moduleName must be set")
+		}
+
+		if (sv.CertSecret != nil) == sv.UEFIKeys {
+			return errors.New("exactly one of certSecret and uefiKeys must be set for signature verification")
+		}
+	}
+
+	if modprobe.UnloadWaitTimeout != nil {
+		if !moduleNameDefined {
+			return errors.New("if an unload wait timeout is defined, moduleName must be set")
+		}
+
+		if modprobe.UnloadWaitTimeout.Duration < 0 {
+			return errors.New("the unload wait timeout cannot be negative")
+		}
+	}
+
 	return nil
 }
@@ -18,12 +18,22 @@ package webhook
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/client"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/utils"
+	"go.uber.org/mock/gomock"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 func getLengthAfterSlash(s string) int {
@@ -52,7 +62,7 @@ var (
 		},
 	}
 
-	moduleWebhook = NewModuleValidator(GinkgoLogr)
+	moduleWebhook = NewModuleValidator(GinkgoLogr, nil)
 )
 
 var _ = Describe("maxCombinedLength", func() {
@@ -178,7 +188,39 @@ var _ = Describe("validateModuleLoaderContainerSpec", func() {
 			validateModuleLoaderContainerSpec(containerSpec),
 		).To(
 			MatchError(
-				ContainSubstring("regexp and literal are mutually exclusive properties at kernelMappings"),
+				ContainSubstring("regexp, literal and versionRange are mutually exclusive properties at kernelMappings"),
+			),
+		)
+	})
+
+	It("should fail when versionRange and regex are set", func() {
+		containerSpec := kmmv1beta1.ModuleLoaderContainerSpec{
+			KernelMappings: []kmmv1beta1.KernelMapping{
+				{Regexp: "^valid-regexp$", VersionRange: ">=5.14.0-284"},
+			},
+		}
+
+		Expect(
+			validateModuleLoaderContainerSpec(containerSpec),
+		).To(
+			MatchError(
+				ContainSubstring("regexp, literal and versionRange are mutually exclusive properties at kernelMappings"),
+			),
+		)
+	})
+
+	It("should fail when an invalid versionRange is found", func() {
+		containerSpec := kmmv1beta1.ModuleLoaderContainerSpec{
+			KernelMappings: []kmmv1beta1.KernelMapping{
+				{VersionRange: ">=5.14.0-400, <5.14.0-284"},
+			},
+		}
+
+		Expect(
+			validateModuleLoaderContainerSpec(containerSpec),
+		).To(
+			MatchError(
+				ContainSubstring("invalid versionRange"),
 			),
 		)
 	})
@@ -194,7 +236,7 @@ var _ = Describe("validateModuleLoaderContainerSpec", func() {
 			validateModuleLoaderContainerSpec(containerSpec),
 		).To(
 			MatchError(
-				ContainSubstring("regexp or literal must be set at kernelMappings"),
+				ContainSubstring("regexp, literal or versionRange must be set at kernelMappings"),
 			),
 		)
 	})
@@ -398,6 +440,197 @@ var _ = Describe("validateModprobe", func() {
 			HaveOccurred(),
 		)
 	})
+
+	It("should pass when ModulesGraph is valid", func() {
+		modprobe := kmmv1beta1.ModprobeSpec{
+			ModuleName: "stack",
+			ModulesGraph: []kmmv1beta1.ModuleGraphNode{
+				{Name: "stack", Pre: []string{"transport_a", "transport_b"}},
+				{Name: "transport_a", Pre: []string{"core"}, Parameters: []string{"a=1"}},
+				{Name: "transport_b", Pre: []string{"core"}},
+				{Name: "core"},
+			},
+		}
+
+		Expect(
+			validateModprobe(modprobe),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+	DescribeTable(
+		"should fail when ModulesGraph is invalid",
+		func(modprobe kmmv1beta1.ModprobeSpec, expected string) {
+			Expect(
+				validateModprobe(modprobe),
+			).To(
+				MatchError(ContainSubstring(expected)),
+			)
+		},
+		Entry(
+			"moduleName not in the graph",
+			kmmv1beta1.ModprobeSpec{
+				ModuleName:   "module-name",
+				ModulesGraph: []kmmv1beta1.ModuleGraphNode{{Name: "a"}},
+			},
+			`moduleName "module-name" is not a module of the graph`,
+		),
+		Entry(
+			"loading order defined as well",
+			kmmv1beta1.ModprobeSpec{
+				ModuleName:          "module-name",
+				ModulesLoadingOrder: []string{"module-name", "a"},
+				ModulesGraph:        []kmmv1beta1.ModuleGraphNode{{Name: "module-name"}},
+			},
+			"a loading order cannot be defined as well",
+		),
+		Entry(
+			"parameters set for moduleName",
+			kmmv1beta1.ModprobeSpec{
+				ModuleName:   "module-name",
+				ModulesGraph: []kmmv1beta1.ModuleGraphNode{{Name: "module-name", Parameters: []string{"a=1"}}},
+			},
+			"the parameters of moduleName must be set in modprobe.parameters",
+		),
+		Entry(
+			"unknown dependency",
+			kmmv1beta1.ModprobeSpec{
+				ModuleName:   "module-name",
+				ModulesGraph: []kmmv1beta1.ModuleGraphNode{{Name: "module-name", Pre: []string{"a"}}},
+			},
+			`pre dependency "a" is not a module of the graph`,
+		),
+		Entry(
+			"cycle",
+			kmmv1beta1.ModprobeSpec{
+				ModuleName: "module-name",
+				ModulesGraph: []kmmv1beta1.ModuleGraphNode{
+					{Name: "module-name", Pre: []string{"a"}},
+					{Name: "a", Pre: []string{"module-name"}},
+				},
+			},
+			"dependency cycle between modules module-name, a",
+		),
+	)
+
+	It("should pass when ModuleFiles are valid", func() {
+		modprobe := kmmv1beta1.ModprobeSpec{
+			ModuleName:  "kmm_stack",
+			ModuleFiles: []string{"kernel/drivers/kmm-core.ko", "extra/kmm-stack.ko.xz"},
+		}
+
+		Expect(
+			validateModprobe(modprobe),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+	DescribeTable(
+		"should fail when ModuleFiles are invalid",
+		func(modprobe kmmv1beta1.ModprobeSpec, expected string) {
+			Expect(
+				validateModprobe(modprobe),
+			).To(
+				MatchError(ContainSubstring(expected)),
+			)
+		},
+		Entry(
+			"empty list",
+			kmmv1beta1.ModprobeSpec{ModuleName: "module-name", ModuleFiles: []string{}},
+			"at least one file must be defined",
+		),
+		Entry(
+			"modules graph defined as well",
+			kmmv1beta1.ModprobeSpec{
+				ModuleName:   "module-name",
+				ModulesGraph: []kmmv1beta1.ModuleGraphNode{{Name: "module-name"}},
+				ModuleFiles:  []string{"module-name.ko"},
+			},
+			"a loading order or a modules graph cannot be defined as well",
+		),
+		Entry(
+			"absolute path",
+			kmmv1beta1.ModprobeSpec{ModuleName: "module-name", ModuleFiles: []string{"/lib/modules/module-name.ko"}},
+			"the path must be relative to the modules directory",
+		),
+		Entry(
+			"path outside of the modules directory",
+			kmmv1beta1.ModprobeSpec{ModuleName: "module-name", ModuleFiles: []string{"../module-name.ko"}},
+			"the path must be relative to the modules directory",
+		),
+		Entry(
+			"duplicate file",
+			kmmv1beta1.ModprobeSpec{ModuleName: "module-name", ModuleFiles: []string{"module-name.ko", "module-name.ko"}},
+			"duplicate file",
+		),
+		Entry(
+			"last file is not moduleName",
+			kmmv1beta1.ModprobeSpec{ModuleName: "module-name", ModuleFiles: []string{"module-name.ko", "other.ko"}},
+			`the last file must be the module "module-name"`,
+		),
+	)
+
+	It("should fail when signature verification is enabled but moduleName is empty", func() {
+		modprobe := kmmv1beta1.ModprobeSpec{
+			RawArgs:               &kmmv1beta1.ModprobeArgs{Load: []string{"a"}, Unload: []string{"b"}},
+			SignatureVerification: &kmmv1beta1.SignatureVerificationSpec{},
+		}
+
+		Expect(
+			validateModprobe(modprobe),
+		).To(
+			HaveOccurred(),
+		)
+	})
+
+	DescribeTable(
+		"should require exactly one key source for signature verification",
+		func(sv kmmv1beta1.SignatureVerificationSpec, errExpected bool) {
+			modprobe := kmmv1beta1.ModprobeSpec{ModuleName: "module-name", SignatureVerification: &sv}
+
+			if errExpected {
+				Expect(validateModprobe(modprobe)).To(MatchError(ContainSubstring("exactly one of certSecret and uefiKeys")))
+			} else {
+				Expect(validateModprobe(modprobe)).To(Succeed())
+			}
+		},
+		Entry("none", kmmv1beta1.SignatureVerificationSpec{}, true),
+		Entry("certSecret", kmmv1beta1.SignatureVerificationSpec{CertSecret: &v1.LocalObjectReference{Name: "cert"}}, false),
+		Entry("uefiKeys", kmmv1beta1.SignatureVerificationSpec{UEFIKeys: true}, false),
+		Entry(
+			"both",
+			kmmv1beta1.SignatureVerificationSpec{CertSecret: &v1.LocalObjectReference{Name: "cert"}, UEFIKeys: true},
+			true,
+		),
+	)
+
+	It("should fail when the unload wait timeout is negative", func() {
+		modprobe := kmmv1beta1.ModprobeSpec{
+			ModuleName:        "module-name",
+			UnloadWaitTimeout: &metav1.Duration{Duration: -time.Second},
+		}
+
+		Expect(
+			validateModprobe(modprobe),
+		).To(
+			HaveOccurred(),
+		)
+	})
+
+	It("should fail when the unload wait timeout is defined but moduleName is empty", func() {
+		modprobe := kmmv1beta1.ModprobeSpec{
+			RawArgs:           &kmmv1beta1.ModprobeArgs{Load: []string{"a"}, Unload: []string{"b"}},
+			UnloadWaitTimeout: &metav1.Duration{Duration: time.Minute},
+		}
+
+		Expect(
+			validateModprobe(modprobe),
+		).To(
+			HaveOccurred(),
+		)
+	})
 })
 
 var _ = Describe("validateModule", func() {
@@ -422,6 +655,196 @@ var _ = Describe("validateModule", func() {
 		Entry("not too long", "name", "ns", false),
 		Entry("too long", chars21, chars21, true),
 	)
+
+	It("should fail when the rollout strategy is invalid", func() {
+		mod := validModule
+		mod.Spec.RolloutStrategy = &kmmv1beta1.RolloutStrategy{
+			MaxUnavailable: ptr.To(intstr.FromInt32(0)),
+		}
+
+		_, err := validateModule(&mod)
+		Expect(err).To(MatchError(ContainSubstring("invalid rollout strategy")))
+	})
+
+	DescribeTable(
+		"should validate the maintenance window",
+		func(mw kmmv1beta1.MaintenanceWindow, errExpected bool) {
+			mod := validModule
+			mod.Spec.MaintenanceWindow = &mw
+
+			_, err := validateModule(&mod)
+
+			if errExpected {
+				Expect(err).To(MatchError(ContainSubstring("invalid maintenance window")))
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+			}
+		},
+		Entry(
+			"valid",
+			kmmv1beta1.MaintenanceWindow{Schedule: "0 2 * * 6", Duration: metav1.Duration{Duration: time.Hour}, TimeZone: "Europe/Paris"},
+			false,
+		),
+		Entry("invalid schedule", kmmv1beta1.MaintenanceWindow{Schedule: "0 25 * * *", Duration: metav1.Duration{Duration: time.Hour}}, true),
+		Entry("no duration", kmmv1beta1.MaintenanceWindow{Schedule: "0 2 * * *"}, true),
+		Entry(
+			"invalid time zone",
+			kmmv1beta1.MaintenanceWindow{Schedule: "0 2 * * *", Duration: metav1.Duration{Duration: time.Hour}, TimeZone: "Mars/Olympus"},
+			true,
+		),
+	)
+
+	DescribeTable(
+		"should validate the modprobe overrides of kernel mappings",
+		func(override *kmmv1beta1.ModprobeOverride, errExpected bool) {
+			mod := validModule
+			mod.Spec.ModuleLoader.Container.KernelMappings = []kmmv1beta1.KernelMapping{
+				{Regexp: "valid-regexp", ContainerImage: "image-url:mytag"},
+				{Literal: "5.14.0-362.8.1.el9_3.x86_64+rt", ContainerImage: "image-url:mytag", Modprobe: override},
+			}
+
+			_, err := validateModule(&mod)
+
+			if errExpected {
+				Expect(err).To(MatchError(ContainSubstring("invalid modprobe override at kernelMappings[1]")))
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+			}
+		},
+		Entry("no override", nil, false),
+		Entry("valid override", &kmmv1beta1.ModprobeOverride{ModuleName: "mod-name-rt", FirmwarePath: "/firmware-rt"}, false),
+		Entry(
+			"rawArgs replacing the Container's moduleName",
+			&kmmv1beta1.ModprobeOverride{RawArgs: &kmmv1beta1.ModprobeArgs{Load: []string{"load"}, Unload: []string{"unload"}}},
+			false,
+		),
+		Entry(
+			"rawArgs set with a moduleName",
+			&kmmv1beta1.ModprobeOverride{
+				ModuleName: "mod-name-rt",
+				RawArgs:    &kmmv1beta1.ModprobeArgs{Load: []string{"load"}, Unload: []string{"unload"}},
+			},
+			true,
+		),
+		Entry("modulesLoadingOrder not starting with the moduleName", &kmmv1beta1.ModprobeOverride{ModulesLoadingOrder: []string{"a", "b"}}, true),
+	)
+
+	DescribeTable(
+		"should accept overrides replacing fields of the Container that cannot be set together with them",
+		func(modprobe kmmv1beta1.ModprobeSpec, override *kmmv1beta1.ModprobeOverride) {
+			mod := validModule
+			mod.Spec.ModuleLoader.Container.Modprobe = modprobe
+			mod.Spec.ModuleLoader.Container.KernelMappings = []kmmv1beta1.KernelMapping{
+				{Literal: "5.14.0-362.8.1.el9_3.x86_64+rt", ContainerImage: "image-url:mytag", Modprobe: override},
+			}
+
+			_, err := validateModule(&mod)
+			Expect(err).NotTo(HaveOccurred())
+		},
+		Entry(
+			"rawArgs replacing moduleName and args",
+			kmmv1beta1.ModprobeSpec{ModuleName: "mod-name", Args: &kmmv1beta1.ModprobeArgs{Load: []string{"-v"}}},
+			&kmmv1beta1.ModprobeOverride{RawArgs: &kmmv1beta1.ModprobeArgs{Load: []string{"load"}, Unload: []string{"unload"}}},
+		),
+		Entry(
+			"moduleName replacing rawArgs",
+			kmmv1beta1.ModprobeSpec{RawArgs: &kmmv1beta1.ModprobeArgs{Load: []string{"load"}, Unload: []string{"unload"}}},
+			&kmmv1beta1.ModprobeOverride{ModuleName: "mod-name-rt"},
+		),
+		Entry(
+			"modulesGraph replacing modulesLoadingOrder",
+			kmmv1beta1.ModprobeSpec{ModuleName: "mod-name", ModulesLoadingOrder: []string{"mod-name", "dep"}},
+			&kmmv1beta1.ModprobeOverride{
+				ModulesGraph: []kmmv1beta1.ModuleGraphNode{{Name: "mod-name", Pre: []string{"dep"}}, {Name: "dep"}},
+			},
+		),
+		Entry(
+			"moduleFiles replacing modulesLoadingOrder",
+			kmmv1beta1.ModprobeSpec{ModuleName: "mod-name", ModulesLoadingOrder: []string{"mod-name", "dep"}},
+			&kmmv1beta1.ModprobeOverride{ModuleFiles: []string{"extra/dep.ko", "extra/mod-name.ko"}},
+		),
+		Entry(
+			"modulesLoadingOrder replacing modulesGraph",
+			kmmv1beta1.ModprobeSpec{
+				ModuleName:   "mod-name",
+				ModulesGraph: []kmmv1beta1.ModuleGraphNode{{Name: "mod-name", Pre: []string{"dep"}}, {Name: "dep"}},
+			},
+			&kmmv1beta1.ModprobeOverride{ModulesLoadingOrder: []string{"mod-name", "dep"}},
+		),
+	)
+
+	It("should warn about overlapping version ranges", func() {
+		mod := validModule
+		mod.Spec.ModuleLoader.Container.KernelMappings = []kmmv1beta1.KernelMapping{
+			{VersionRange: ">=5.14.0-284, <5.14.0-400", ContainerImage: "image-url:mytag"},
+			{VersionRange: ">=5.14.0-284+rt, <5.14.0-400+rt", ContainerImage: "image-url:mytag"},
+			{Regexp: "valid-regexp", ContainerImage: "image-url:mytag"},
+			{VersionRange: ">=5.14.0-362", ContainerImage: "image-url:mytag"},
+		}
+
+		warnings, err := validateModule(&mod)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(ConsistOf(
+			"kernelMappings[0] and kernelMappings[3] have overlapping version ranges; kernels in both use kernelMappings[0]",
+		))
+	})
+})
+
+var _ = Describe("validateDrain", func() {
+	DescribeTable(
+		"should work as expected",
+		func(drain kmmv1beta1.DrainSpec, errExpected bool) {
+			err := validateDrain(drain)
+
+			if errExpected {
+				Expect(err).To(HaveOccurred())
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+			}
+		},
+		Entry("empty", kmmv1beta1.DrainSpec{}, true),
+		Entry("resource names", kmmv1beta1.DrainSpec{ResourceNames: []v1.ResourceName{"example.com/gpu"}}, false),
+		Entry(
+			"valid Pod selector",
+			kmmv1beta1.DrainSpec{
+				PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "gpu-app"}},
+			},
+			false,
+		),
+		Entry(
+			"invalid Pod selector",
+			kmmv1beta1.DrainSpec{
+				PodSelector: &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{Key: "app", Operator: "Unknown"},
+					},
+				},
+			},
+			true,
+		),
+	)
+})
+
+var _ = Describe("validateRolloutStrategy", func() {
+	DescribeTable(
+		"should work as expected",
+		func(maxUnavailable *intstr.IntOrString, errExpected bool) {
+			err := validateRolloutStrategy(kmmv1beta1.RolloutStrategy{MaxUnavailable: maxUnavailable})
+
+			if errExpected {
+				Expect(err).To(HaveOccurred())
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+			}
+		},
+		Entry("maxUnavailable not set", nil, false),
+		Entry("positive number", ptr.To(intstr.FromInt32(2)), false),
+		Entry("positive percentage", ptr.To(intstr.FromString("25%")), false),
+		Entry("zero", ptr.To(intstr.FromInt32(0)), true),
+		Entry("negative number", ptr.To(intstr.FromInt32(-1)), true),
+		Entry("zero percent", ptr.To(intstr.FromString("0%")), true),
+		Entry("not a percentage", ptr.To(intstr.FromString("some-nodes")), true),
+	)
 })
 
 var _ = Describe("ValidateCreate", func() {
@@ -456,6 +879,94 @@ var _ = Describe("ValidateCreate", func() {
 	})
 })
 
+var _ = Describe("validateStrictKernelMappings", func() {
+	var (
+		ctx  = context.TODO()
+		clnt *client.MockClient
+		mv   *ModuleValidator
+		mod  kmmv1beta1.Module
+	)
+
+	BeforeEach(func() {
+		clnt = client.NewMockClient(gomock.NewController(GinkgoT()))
+		mv = NewModuleValidator(GinkgoLogr, clnt)
+
+		mod = *validModule.DeepCopy()
+		mod.Spec.Selector = map[string]string{"key": "value"}
+		mod.Spec.ModuleLoader.Container.StrictKernelMappings = true
+		mod.Spec.ModuleLoader.Container.KernelMappings = []kmmv1beta1.KernelMapping{
+			{Literal: "5.14.0-284.30.1.el9_2.x86_64", ContainerImage: "image-url:mytag"},
+			{VersionRange: ">=5.14.0-284, <5.14.0-400", ContainerImage: "image-url:mytag"},
+			{Regexp: `^6\..*`, ContainerImage: "image-url:mytag"},
+		}
+	})
+
+	listNodes := func(kernelVersions ...string) *gomock.Call {
+		return clnt.
+			EXPECT().
+			List(ctx, &v1.NodeList{}, ctrlclient.MatchingLabels{"key": "value"}).
+			DoAndReturn(func(_ context.Context, nl *v1.NodeList, _ ...ctrlclient.ListOption) error {
+				for i, kv := range kernelVersions {
+					nl.Items = append(nl.Items, v1.Node{
+						ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("node%d", i)},
+						Status: v1.NodeStatus{
+							NodeInfo: v1.NodeSystemInfo{KernelVersion: kv},
+						},
+					})
+				}
+
+				return nil
+			})
+	}
+
+	It("should not list nodes if strict kernel mappings are not enabled", func() {
+		mod.Spec.ModuleLoader.Container.StrictKernelMappings = false
+
+		Expect(
+			mv.validateStrictKernelMappings(ctx, &mod),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+	It("should fail if the nodes could not be listed", func() {
+		listNodes().Return(errors.New("some error"))
+
+		Expect(
+			mv.validateStrictKernelMappings(ctx, &mod),
+		).To(
+			HaveOccurred(),
+		)
+	})
+
+	It("should pass if each kernel matches one mapping", func() {
+		listNodes("5.14.0-362.8.1.el9_3.x86_64", "6.6.7", "4.18.0-513.5.1.el8_9.x86_64")
+
+		Expect(
+			mv.validateStrictKernelMappings(ctx, &mod),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+	It("should fail if a kernel matches several mappings", func() {
+		listNodes("6.6.7", "5.14.0-284.30.1.el9_2.x86_64")
+
+		Expect(
+			mv.validateStrictKernelMappings(ctx, &mod),
+		).To(
+			MatchError(ContainSubstring("kernel 5.14.0-284.30.1.el9_2.x86_64 of node node1 matches kernelMappings [0 1]")),
+		)
+	})
+
+	It("should be enforced by ValidateCreate", func() {
+		listNodes("5.14.0-284.30.1.el9_2.x86_64")
+
+		_, err := mv.ValidateCreate(ctx, &mod)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
 var _ = Describe("ValidateUpdate", func() {
 	ctx := context.TODO()
 
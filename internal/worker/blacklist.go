@@ -0,0 +1,70 @@
+package worker
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/nmc"
+)
+
+const blacklistHeader = "# Managed by Kernel Module Management; any change will be overwritten.\n"
+
+// blacklistContent returns a modprobe configuration that prevents modules from being loaded through their aliases.
+func blacklistContent(modules []string) []byte {
+	buf := bytes.NewBufferString(blacklistHeader)
+
+	for _, m := range modules {
+		fmt.Fprintf(buf, "blacklist %s\n", m)
+	}
+
+	return buf.Bytes()
+}
+
+func (w *worker) WriteBlacklist(cfg *kmmv1beta1.ModuleConfig, path string) error {
+	modules := nmc.InTreeModulesToRemove(cfg)
+	if len(modules) == 0 {
+		return w.RemoveBlacklist(path)
+	}
+
+	content := blacklistContent(modules)
+
+	current, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("could not read %s: %v", path, err)
+	}
+
+	if !bytes.Equal(current, content) {
+		w.logger.Info("Writing the in-tree modules blacklist", "path", path, "modules", modules)
+
+		if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("could not create the directory of %s: %v", path, err)
+		}
+
+		err = writeFileAtomically(path, 0644, func(wr io.Writer) error {
+			_, err := wr.Write(content)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	w.result.BlacklistedModules = modules
+
+	return nil
+}
+
+func (w *worker) RemoveBlacklist(path string) error {
+	w.logger.Info("Removing the in-tree modules blacklist", "path", path)
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("could not remove %s: %v", path, err)
+	}
+
+	return nil
+}
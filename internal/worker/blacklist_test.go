@@ -0,0 +1,83 @@
+package worker
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
+)
+
+var _ = Describe("worker_WriteBlacklist", func() {
+	var (
+		path string
+		w    Worker
+	)
+
+	BeforeEach(func() {
+		path = filepath.Join(GinkgoT().TempDir(), "modprobe.d", "kmm-ns-name.conf")
+		w = NewWorker(nil, nil, nil, nil, nil, nil, nil, GinkgoLogr)
+	})
+
+	It("should blacklist the in-tree modules and record them in the result", func() {
+		cfg := kmmv1beta1.ModuleConfig{InTreeModulesToRemove: []string{"intree1", "intree2"}}
+
+		Expect(w.WriteBlacklist(&cfg, path)).To(Succeed())
+
+		Expect(os.ReadFile(path)).To(
+			BeEquivalentTo(blacklistHeader + "blacklist intree1\nblacklist intree2\n"),
+		)
+
+		Expect(w.Result().BlacklistedModules).To(Equal([]string{"intree1", "intree2"}))
+	})
+
+	It("should use the deprecated InTreeModuleToRemove", func() {
+		cfg := kmmv1beta1.ModuleConfig{InTreeModuleToRemove: "intree"}
+
+		Expect(w.WriteBlacklist(&cfg, path)).To(Succeed())
+
+		Expect(os.ReadFile(path)).To(BeEquivalentTo(blacklistHeader + "blacklist intree\n"))
+	})
+
+	It("should overwrite a modified file", func() {
+		Expect(os.MkdirAll(filepath.Dir(path), 0755)).To(Succeed())
+		Expect(os.WriteFile(path, []byte("blacklist other\n"), 0644)).To(Succeed())
+
+		cfg := kmmv1beta1.ModuleConfig{InTreeModulesToRemove: []string{"intree"}}
+
+		Expect(w.WriteBlacklist(&cfg, path)).To(Succeed())
+
+		Expect(os.ReadFile(path)).To(BeEquivalentTo(blacklistHeader + "blacklist intree\n"))
+	})
+
+	It("should remove the file if there is no in-tree module to remove", func() {
+		Expect(os.MkdirAll(filepath.Dir(path), 0755)).To(Succeed())
+		Expect(os.WriteFile(path, []byte("blacklist other\n"), 0644)).To(Succeed())
+
+		Expect(w.WriteBlacklist(&kmmv1beta1.ModuleConfig{}, path)).To(Succeed())
+
+		Expect(path).NotTo(BeAnExistingFile())
+		Expect(w.Result().BlacklistedModules).To(BeEmpty())
+	})
+})
+
+var _ = Describe("worker_RemoveBlacklist", func() {
+	w := NewWorker(nil, nil, nil, nil, nil, nil, nil, GinkgoLogr)
+
+	It("should remove the file", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "kmm-ns-name.conf")
+		Expect(os.WriteFile(path, []byte(blacklistHeader), 0644)).To(Succeed())
+
+		Expect(w.RemoveBlacklist(path)).To(Succeed())
+		Expect(path).NotTo(BeAnExistingFile())
+	})
+
+	It("should not return an error if the file does not exist", func() {
+		Expect(
+			w.RemoveBlacklist(filepath.Join(GinkgoT().TempDir(), "kmm-ns-name.conf")),
+		).To(
+			Succeed(),
+		)
+	})
+})
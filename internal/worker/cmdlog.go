@@ -11,10 +11,16 @@ import (
 	"github.com/go-logr/logr"
 )
 
+// maxCapturedLines is the number of lines that CommandLogger keeps in memory for each stream.
+const maxCapturedLines = 50
+
 type CommandLogger struct {
 	logger         logr.Logger
 	stdErr, stdOut io.Reader
 	wg             *sync.WaitGroup
+
+	linesMutex sync.Mutex
+	lines      map[string][]string
 }
 
 func NewCommandLogger(cmd *exec.Cmd, logger logr.Logger) (*CommandLogger, error) {
@@ -33,6 +39,7 @@ func NewCommandLogger(cmd *exec.Cmd, logger logr.Logger) (*CommandLogger, error)
 		stdErr: stderr,
 		stdOut: stdout,
 		wg:     &sync.WaitGroup{},
+		lines:  make(map[string][]string, 2),
 	}
 
 	return &cl, nil
@@ -67,10 +74,45 @@ func (cl *CommandLogger) write(r io.Reader, name string, errs chan<- error) {
 	s := bufio.NewScanner(r)
 
 	for s.Scan() {
-		logger.Info(s.Text())
+		line := s.Text()
+
+		logger.Info(line)
+		cl.capture(name, line)
 	}
 
 	if err := s.Err(); err != nil {
 		errs <- err
 	}
 }
+
+func (cl *CommandLogger) capture(name, line string) {
+	cl.linesMutex.Lock()
+	defer cl.linesMutex.Unlock()
+
+	lines := append(cl.lines[name], line)
+
+	if len(lines) > maxCapturedLines {
+		lines = lines[len(lines)-maxCapturedLines:]
+	}
+
+	cl.lines[name] = lines
+}
+
+func (cl *CommandLogger) capturedLines(name string) []string {
+	cl.linesMutex.Lock()
+	defer cl.linesMutex.Unlock()
+
+	return append([]string{}, cl.lines[name]...)
+}
+
+// StderrLines returns the last lines written by the command on stderr.
+// It should only be called after Wait has returned.
+func (cl *CommandLogger) StderrLines() []string {
+	return cl.capturedLines("stderr")
+}
+
+// StdoutLines returns the last lines written by the command on stdout.
+// It should only be called after Wait has returned.
+func (cl *CommandLogger) StdoutLines() []string {
+	return cl.capturedLines("stdout")
+}
@@ -90,6 +90,9 @@ var _ = Describe("CommandLogger_Wait", func() {
 		expected := map[string]string{"stderr": stderrMsg, "stdout": stdoutMsg}
 
 		Expect(msgs).To(Equal(expected))
+
+		Expect(cl.StderrLines()).To(Equal([]string{stderrMsg}))
+		Expect(cl.StdoutLines()).To(Equal([]string{stdoutMsg}))
 	})
 })
 
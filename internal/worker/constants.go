@@ -1,10 +1,19 @@
 package worker
 
 const (
-	FlagFirmwarePath = "firmware-path"
+	FlagBlacklistPath        = "blacklist-path"
+	FlagFirmwarePath         = "firmware-path"
+	FlagNativeLoader         = "native-loader"
+	FlagRestoreInTreeModules = "restore-in-tree-modules"
 
 	FirmwareClassPathLocation = "/sys/module/firmware_class/parameters/path"
+	HostModprobeConfDir       = "/var/run/kmm/modprobe.d"
 	ImagesDir                 = "/var/run/kmm/images"
 	PullSecretsDir            = "/var/run/kmm/pull-secrets"
+	SignatureCertDir          = "/var/run/kmm/signature-cert"
+	SignatureCertPath         = SignatureCertDir + "/cert"
 	GlobalPullSecretPath      = "/var/lib/kubelet/config.json"
+	SharedFilesDir            = "/tmp"
+	WorkerBinDir              = "/var/run/kmm/bin"
+	WorkerBinaryPath          = WorkerBinDir + "/worker"
 )
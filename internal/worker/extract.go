@@ -0,0 +1,160 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/go-logr/logr"
+)
+
+//go:generate mockgen -source=extract.go -package=worker -destination=mock_extract.go
+
+// FileExtractor copies the files needed by the worker out of the kmod image.
+// It runs from the worker binary copied into the image-extractor container, so that the kmod image does not need a
+// shell or any other tool.
+type FileExtractor interface {
+	// CopyExecutable copies the running binary to dst.
+	CopyExecutable(dst string) error
+	// Extract copies paths, that are located under srcRoot, into dstDir while keeping their location.
+	Extract(srcRoot, dstDir string, paths []string) error
+}
+
+type fileExtractor struct {
+	executable func() (string, error)
+	logger     logr.Logger
+}
+
+func NewFileExtractor(logger logr.Logger) FileExtractor {
+	return &fileExtractor{
+		executable: os.Executable,
+		logger:     logger,
+	}
+}
+
+func (f *fileExtractor) CopyExecutable(dst string) error {
+	src, err := f.executable()
+	if err != nil {
+		return fmt.Errorf("could not determine the path of the running binary: %v", err)
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("could not open %s: %v", src, err)
+	}
+	defer in.Close()
+
+	if err = os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return fmt.Errorf("could not create the parent directory of %s: %v", dst, err)
+	}
+
+	f.logger.Info("Copying the worker binary", "source", src, "destination", dst)
+
+	return writeFileAtomically(dst, 0755, func(w io.Writer) error {
+		_, err := io.Copy(w, in)
+		return err
+	})
+}
+
+func (f *fileExtractor) Extract(srcRoot, dstDir string, paths []string) error {
+	for _, p := range paths {
+		rel := cleanArchivePath(p)
+
+		dst, err := safeJoin(dstDir, rel)
+		if err != nil {
+			return err
+		}
+
+		// Like cp -R, follow the requested path itself if it is a symbolic link, but none of the links below it.
+		src, err := filepath.EvalSymlinks(filepath.Join(srcRoot, rel))
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("%s does not exist in the kmod image", p)
+			}
+
+			return fmt.Errorf("could not resolve %s: %v", p, err)
+		}
+
+		f.logger.Info("Extracting files", "source", src, "destination", dst)
+
+		if err = copyTree(src, dst); err != nil {
+			return fmt.Errorf("could not copy %s: %v", p, err)
+		}
+	}
+
+	return nil
+}
+
+// copyTree copies the file or directory src to dst.
+// Symbolic links are copied as links; devices, FIFOs and sockets are skipped.
+func copyTree(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(dst, rel)
+
+		switch {
+		case d.IsDir():
+			if err = os.MkdirAll(target, 0755); err != nil {
+				return fmt.Errorf("could not create directory %s: %v", target, err)
+			}
+		case d.Type()&fs.ModeSymlink != 0:
+			linkTarget, err := os.Readlink(path)
+			if err != nil {
+				return fmt.Errorf("could not read the link %s: %v", path, err)
+			}
+
+			if err = os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return fmt.Errorf("could not create the parent directory of %s: %v", target, err)
+			}
+
+			// Never write through an existing file, in case the extraction is retried.
+			if err = os.Remove(target); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("could not remove existing file %s: %v", target, err)
+			}
+
+			if err = os.Symlink(linkTarget, target); err != nil {
+				return fmt.Errorf("could not create symlink %s: %v", target, err)
+			}
+		case d.Type().IsRegular():
+			fi, err := d.Info()
+			if err != nil {
+				return fmt.Errorf("could not stat %s: %v", path, err)
+			}
+
+			if err = os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return fmt.Errorf("could not create the parent directory of %s: %v", target, err)
+			}
+
+			if err = copyFile(path, target, fi.Mode().Perm()); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func copyFile(src, dst string, mode os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("could not open %s: %v", src, err)
+	}
+	defer in.Close()
+
+	if err = os.Remove(dst); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("could not remove existing file %s: %v", dst, err)
+	}
+
+	return writeFile(dst, in, mode)
+}
@@ -0,0 +1,95 @@
+package worker
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("fileExtractor_CopyExecutable", func() {
+	It("should copy the running binary", func() {
+		dir := GinkgoT().TempDir()
+		src := filepath.Join(dir, "worker")
+
+		Expect(os.WriteFile(src, []byte("binary"), 0600)).To(Succeed())
+
+		fe := &fileExtractor{
+			executable: func() (string, error) { return src, nil },
+			logger:     GinkgoLogr,
+		}
+
+		dst := filepath.Join(dir, "bin", "worker")
+
+		Expect(fe.CopyExecutable(dst)).To(Succeed())
+
+		fi, err := os.Stat(dst)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fi.Mode().Perm()).To(Equal(os.FileMode(0755)))
+		Expect(os.ReadFile(dst)).To(Equal([]byte("binary")))
+	})
+})
+
+var _ = Describe("fileExtractor_Extract", func() {
+	var (
+		fe      FileExtractor
+		srcRoot string
+		dstDir  string
+	)
+
+	BeforeEach(func() {
+		fe = NewFileExtractor(GinkgoLogr)
+		srcRoot = GinkgoT().TempDir()
+		dstDir = GinkgoT().TempDir()
+	})
+
+	It("should return an error if a path does not exist", func() {
+		Expect(
+			fe.Extract(srcRoot, dstDir, []string{"/opt/lib/modules/5.14.0"}),
+		).To(
+			MatchError("/opt/lib/modules/5.14.0 does not exist in the kmod image"),
+		)
+	})
+
+	It("should copy the requested paths only", func() {
+		modulesDir := filepath.Join(srcRoot, "opt with spaces", "lib", "modules", "5.14.0")
+		firmwareDir := filepath.Join(srcRoot, "firmware")
+
+		Expect(os.MkdirAll(filepath.Join(modulesDir, "extra"), 0755)).To(Succeed())
+		Expect(os.MkdirAll(firmwareDir, 0755)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(srcRoot, "other"), 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(modulesDir, "extra", "kmm_ci_a.ko"), []byte("ko"), 0644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(firmwareDir, "fw.bin"), []byte("fw"), 0600)).To(Succeed())
+		Expect(os.Symlink("fw.bin", filepath.Join(firmwareDir, "fw-link.bin"))).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(srcRoot, "other", "file"), []byte("other"), 0644)).To(Succeed())
+
+		Expect(
+			fe.Extract(srcRoot, dstDir, []string{"/opt with spaces/lib/modules/5.14.0", "/firmware"}),
+		).To(
+			Succeed(),
+		)
+
+		Expect(
+			os.ReadFile(filepath.Join(dstDir, "opt with spaces", "lib", "modules", "5.14.0", "extra", "kmm_ci_a.ko")),
+		).To(
+			Equal([]byte("ko")),
+		)
+
+		fi, err := os.Stat(filepath.Join(dstDir, "firmware", "fw.bin"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fi.Mode().Perm()).To(Equal(os.FileMode(0600)))
+
+		Expect(os.Readlink(filepath.Join(dstDir, "firmware", "fw-link.bin"))).To(Equal("fw.bin"))
+		Expect(filepath.Join(dstDir, "other")).NotTo(BeAnExistingFile())
+	})
+
+	It("should be possible to run it again", func() {
+		Expect(os.MkdirAll(filepath.Join(srcRoot, "firmware"), 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(srcRoot, "firmware", "fw.bin"), []byte("fw"), 0644)).To(Succeed())
+		Expect(os.Symlink("fw.bin", filepath.Join(srcRoot, "firmware", "fw-link.bin"))).To(Succeed())
+
+		Expect(fe.Extract(srcRoot, dstDir, []string{"/firmware"})).To(Succeed())
+		Expect(fe.Extract(srcRoot, dstDir, []string{"/firmware"})).To(Succeed())
+	})
+})
@@ -0,0 +1,499 @@
+package worker
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/go-logr/logr"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/utils"
+	"golang.org/x/sys/unix"
+)
+
+//go:generate mockgen -source=firmware.go -package=worker -destination=mock_firmware.go
+
+const (
+	// firmwareManifestsDir is the directory, relative to the firmware host path, holding the firmware manifests.
+	firmwareManifestsDir = ".kmm-firmware"
+
+	firmwareLockFileName = ".lock"
+)
+
+// FirmwareFile is a file installed in the firmware host path.
+type FirmwareFile struct {
+	// Path is relative to the firmware host path.
+	Path string `json:"path"`
+	// SHA256 is the checksum of regular files.
+	SHA256 string `json:"sha256,omitempty"`
+	// LinkTarget is the target of symbolic links.
+	LinkTarget string `json:"linkTarget,omitempty"`
+}
+
+// FirmwareManifest lists the firmware files installed for a kernel module.
+type FirmwareManifest struct {
+	Module string         `json:"module"`
+	Files  []FirmwareFile `json:"files"`
+}
+
+type FirmwareInstaller interface {
+	// Install copies the firmware files found in srcDir into dstDir and records them in the manifest of the module.
+	Install(module, srcDir, dstDir string) error
+	// Remove deletes from dstDir the firmware files installed for the module, unless they were modified since or are
+	// also used by another module.
+	// If the module has no manifest, only the files identical to those found in srcDir are deleted.
+	Remove(module, srcDir, dstDir string) error
+}
+
+type firmwareInstaller struct {
+	logger logr.Logger
+}
+
+func NewFirmwareInstaller(logger logr.Logger) FirmwareInstaller {
+	return &firmwareInstaller{logger: logger}
+}
+
+func (f *firmwareInstaller) Install(module, srcDir, dstDir string) error {
+	unlock, err := lockFirmwareDir(dstDir)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	previous, err := readFirmwareManifest(firmwareManifestPath(dstDir, module))
+	if err != nil {
+		return err
+	}
+
+	others := f.otherModulesFiles(dstDir, module)
+
+	owned := make(map[string]FirmwareFile)
+
+	if previous != nil {
+		for _, ff := range previous.Files {
+			owned[ff.Path] = ff
+		}
+	}
+
+	manifest := FirmwareManifest{Module: normalizeModuleName(module), Files: make([]FirmwareFile, 0)}
+
+	err = filepath.WalkDir(srcDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(srcDir, path)
+		if err != nil {
+			return err
+		}
+
+		dst := filepath.Join(dstDir, rel)
+
+		if d.IsDir() {
+			return os.MkdirAll(dst, 0755)
+		}
+
+		ff, err := sourceFirmwareFile(path, rel)
+		if err != nil {
+			return err
+		}
+
+		existing, err := currentFirmwareFile(dst, rel)
+		if err != nil {
+			return err
+		}
+
+		_, ownedByUs := owned[rel]
+		_, ownedByOthers := others[rel]
+
+		switch {
+		case existing == nil:
+		case *existing == *ff && !ownedByUs && !ownedByOthers:
+			f.logger.Info("Identical firmware file already present on the host; not tracking it", "file", dst)
+			return nil
+		case *existing != *ff && ownedByOthers:
+			f.logger.Info(
+				utils.WarnString("Overwriting a firmware file installed by another module"),
+				"file", dst,
+				"modules", others[rel],
+			)
+		case *existing != *ff && !ownedByUs:
+			f.logger.Info(utils.WarnString("Overwriting a firmware file not installed by KMM"), "file", dst)
+		}
+
+		f.logger.Info("Installing firmware file", "file", dst)
+
+		if err = installFirmwareFile(path, dst, d); err != nil {
+			return err
+		}
+
+		manifest.Files = append(manifest.Files, *ff)
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("could not install the firmware from %s into %s: %v", srcDir, dstDir, err)
+	}
+
+	if err = writeFirmwareManifest(firmwareManifestPath(dstDir, module), &manifest); err != nil {
+		return err
+	}
+
+	if previous == nil {
+		return nil
+	}
+
+	installed := make(map[string]bool, len(manifest.Files))
+
+	for _, ff := range manifest.Files {
+		installed[ff.Path] = true
+	}
+
+	stale := make([]FirmwareFile, 0)
+
+	for _, ff := range previous.Files {
+		if !installed[ff.Path] {
+			stale = append(stale, ff)
+		}
+	}
+
+	return f.removeFiles(dstDir, stale, others)
+}
+
+func (f *firmwareInstaller) Remove(module, srcDir, dstDir string) error {
+	unlock, err := lockFirmwareDir(dstDir)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	manifestPath := firmwareManifestPath(dstDir, module)
+
+	manifest, err := readFirmwareManifest(manifestPath)
+	if err != nil {
+		return err
+	}
+
+	var files []FirmwareFile
+
+	if manifest != nil {
+		files = manifest.Files
+	} else {
+		f.logger.Info("No firmware manifest found; removing the files identical to those in the image", "module", module)
+
+		if files, err = sourceFirmwareFiles(srcDir); err != nil {
+			return fmt.Errorf("could not list the firmware files in %s: %v", srcDir, err)
+		}
+	}
+
+	if err = f.removeFiles(dstDir, files, f.otherModulesFiles(dstDir, module)); err != nil {
+		return err
+	}
+
+	if err = os.Remove(manifestPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("could not remove the firmware manifest %s: %v", manifestPath, err)
+	}
+
+	return nil
+}
+
+// removeFiles deletes the files that still match their manifest entry and are not used by other modules.
+func (f *firmwareInstaller) removeFiles(dstDir string, files []FirmwareFile, others map[string][]string) error {
+	errs := make([]error, 0)
+
+	for _, ff := range files {
+		dst := filepath.Join(dstDir, ff.Path)
+
+		if modules, ok := others[ff.Path]; ok {
+			f.logger.Info("Firmware file used by other modules; keeping it", "file", dst, "modules", modules)
+			continue
+		}
+
+		current, err := currentFirmwareFile(dst, ff.Path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if current == nil {
+			continue
+		}
+
+		if *current != ff {
+			f.logger.Info(utils.WarnString("Firmware file was modified since it was installed; keeping it"), "file", dst)
+			continue
+		}
+
+		f.logger.Info("Removing firmware file", "file", dst)
+
+		if err = os.Remove(dst); err != nil {
+			errs = append(errs, fmt.Errorf("could not remove %s: %v", dst, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// otherModulesFiles returns the modules using each file, according to the manifests of all modules except module.
+func (f *firmwareInstaller) otherModulesFiles(dstDir, module string) map[string][]string {
+	dir := filepath.Join(dstDir, firmwareManifestsDir)
+	own := filepath.Base(firmwareManifestPath(dstDir, module))
+
+	files := make(map[string][]string)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		f.logger.Info(utils.WarnString("could not read the firmware manifests"), "dir", dir, "error", err)
+		return files
+	}
+
+	for _, e := range entries {
+		if e.Name() == own || filepath.Ext(e.Name()) != ".json" {
+			continue
+		}
+
+		m, err := readFirmwareManifest(filepath.Join(dir, e.Name()))
+		if err != nil {
+			f.logger.Info(utils.WarnString("could not read a firmware manifest"), "error", err)
+			continue
+		}
+
+		if m == nil {
+			// removed in the meantime
+			continue
+		}
+
+		for _, ff := range m.Files {
+			files[ff.Path] = append(files[ff.Path], m.Module)
+		}
+	}
+
+	return files
+}
+
+func firmwareManifestPath(dstDir, module string) string {
+	return filepath.Join(dstDir, firmwareManifestsDir, normalizeModuleName(module)+".json")
+}
+
+// lockFirmwareDir prevents concurrent workers from updating the firmware files and manifests of dstDir.
+func lockFirmwareDir(dstDir string) (func(), error) {
+	dir := filepath.Join(dstDir, firmwareManifestsDir)
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("could not create %s: %v", dir, err)
+	}
+
+	path := filepath.Join(dir, firmwareLockFileName)
+
+	fd, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("could not open %s: %v", path, err)
+	}
+
+	if err = unix.Flock(int(fd.Fd()), unix.LOCK_EX); err != nil {
+		fd.Close()
+		return nil, fmt.Errorf("could not lock %s: %v", path, err)
+	}
+
+	return func() { fd.Close() }, nil
+}
+
+// readFirmwareManifest returns nil if the manifest does not exist.
+func readFirmwareManifest(path string) (*FirmwareManifest, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("could not read the firmware manifest %s: %v", path, err)
+	}
+
+	m := FirmwareManifest{}
+
+	if err = json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("could not unmarshal the firmware manifest %s: %v", path, err)
+	}
+
+	return &m, nil
+}
+
+func writeFirmwareManifest(path string, m *FirmwareManifest) error {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("could not marshal the firmware manifest: %v", err)
+	}
+
+	return writeFileAtomically(path, 0644, func(w io.Writer) error {
+		_, err := w.Write(b)
+		return err
+	})
+}
+
+// sourceFirmwareFiles returns the files found in srcDir.
+func sourceFirmwareFiles(srcDir string) ([]FirmwareFile, error) {
+	files := make([]FirmwareFile, 0)
+
+	err := filepath.WalkDir(srcDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		rel, err := filepath.Rel(srcDir, path)
+		if err != nil {
+			return err
+		}
+
+		ff, err := sourceFirmwareFile(path, rel)
+		if err != nil {
+			return err
+		}
+
+		files = append(files, *ff)
+
+		return nil
+	})
+
+	return files, err
+}
+
+func sourceFirmwareFile(path, rel string) (*FirmwareFile, error) {
+	ff, err := currentFirmwareFile(path, rel)
+	if err != nil {
+		return nil, err
+	}
+
+	if ff == nil {
+		return nil, fmt.Errorf("%s: %v", path, os.ErrNotExist)
+	}
+
+	return ff, nil
+}
+
+// currentFirmwareFile describes the file at path, or returns nil if it does not exist.
+func currentFirmwareFile(path, rel string) (*FirmwareFile, error) {
+	fi, err := os.Lstat(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("could not stat %s: %v", path, err)
+	}
+
+	ff := FirmwareFile{Path: rel}
+
+	switch {
+	case fi.Mode()&os.ModeSymlink != 0:
+		if ff.LinkTarget, err = os.Readlink(path); err != nil {
+			return nil, fmt.Errorf("could not read the link %s: %v", path, err)
+		}
+	case fi.Mode().IsRegular():
+		if ff.SHA256, err = fileSHA256(path); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("%s: unsupported file type %s", path, fi.Mode().Type())
+	}
+
+	return &ff, nil
+}
+
+func fileSHA256(path string) (string, error) {
+	fd, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("could not open %s: %v", path, err)
+	}
+	defer fd.Close()
+
+	h := sha256.New()
+
+	if _, err = io.Copy(h, fd); err != nil {
+		return "", fmt.Errorf("could not read %s: %v", path, err)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// installFirmwareFile writes src to a temporary file next to dst, and renames it into place so that the kernel never
+// reads a partially written file.
+func installFirmwareFile(src, dst string, d fs.DirEntry) error {
+	if d.Type()&fs.ModeSymlink != 0 {
+		target, err := os.Readlink(src)
+		if err != nil {
+			return fmt.Errorf("could not read the link %s: %v", src, err)
+		}
+
+		tmp := tempPath(dst)
+
+		if err = os.Symlink(target, tmp); err != nil {
+			return fmt.Errorf("could not create the link %s: %v", tmp, err)
+		}
+
+		if err = os.Rename(tmp, dst); err != nil {
+			os.Remove(tmp)
+			return fmt.Errorf("could not rename %s to %s: %v", tmp, dst, err)
+		}
+
+		return nil
+	}
+
+	fi, err := d.Info()
+	if err != nil {
+		return fmt.Errorf("could not stat %s: %v", src, err)
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("could not open %s: %v", src, err)
+	}
+	defer in.Close()
+
+	return writeFileAtomically(dst, fi.Mode().Perm(), func(w io.Writer) error {
+		_, err := io.Copy(w, in)
+		return err
+	})
+}
+
+func writeFileAtomically(path string, perm os.FileMode, write func(io.Writer) error) error {
+	dir, base := filepath.Split(path)
+
+	fd, err := os.CreateTemp(dir, "."+base+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("could not create a temporary file in %s: %v", dir, err)
+	}
+
+	tmp := fd.Name()
+
+	err = write(fd)
+	if err == nil {
+		err = fd.Chmod(perm)
+	}
+	if err == nil {
+		err = fd.Sync()
+	}
+	if closeErr := fd.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+
+	if err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("could not write %s: %v", path, err)
+	}
+
+	return nil
+}
+
+func tempPath(path string) string {
+	dir, base := filepath.Split(path)
+
+	return filepath.Join(dir, fmt.Sprintf(".%s.%d.tmp", base, os.Getpid()))
+}
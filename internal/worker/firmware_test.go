@@ -0,0 +1,136 @@
+package worker
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("firmwareInstaller", func() {
+	const (
+		sha256Data1 = "2836a57b3d5aace52d0e543e74a855ad5b59534e20c0abd8bd30452b7aeeb3eb"
+		sha256Data2 = "68367e230b2fdd7c3324a467bda2247f7ee39814f5f06f3542e3d3f638bef274"
+	)
+
+	var (
+		fi     FirmwareInstaller
+		srcDir string
+		dstDir string
+	)
+
+	writeFile := func(path, content string) {
+		GinkgoHelper()
+
+		Expect(os.MkdirAll(filepath.Dir(path), 0755)).To(Succeed())
+		Expect(os.WriteFile(path, []byte(content), 0644)).To(Succeed())
+	}
+
+	readFile := func(path string) string {
+		GinkgoHelper()
+
+		b, err := os.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+
+		return string(b)
+	}
+
+	BeforeEach(func() {
+		fi = NewFirmwareInstaller(GinkgoLogr)
+		srcDir = GinkgoT().TempDir()
+		dstDir = GinkgoT().TempDir()
+
+		writeFile(filepath.Join(srcDir, "fw1.bin"), "data 1")
+		writeFile(filepath.Join(srcDir, "subdir", "fw2.bin"), "data 2")
+		Expect(os.Symlink("fw1.bin", filepath.Join(srcDir, "fw-link.bin"))).To(Succeed())
+	})
+
+	It("should install the files and record them in the manifest", func() {
+		Expect(fi.Install("kmm-test", srcDir, dstDir)).To(Succeed())
+
+		Expect(readFile(filepath.Join(dstDir, "fw1.bin"))).To(Equal("data 1"))
+		Expect(readFile(filepath.Join(dstDir, "subdir", "fw2.bin"))).To(Equal("data 2"))
+		Expect(os.Readlink(filepath.Join(dstDir, "fw-link.bin"))).To(Equal("fw1.bin"))
+
+		Expect(
+			readFirmwareManifest(filepath.Join(dstDir, firmwareManifestsDir, "kmm_test.json")),
+		).To(
+			Equal(&FirmwareManifest{
+				Module: "kmm_test",
+				Files: []FirmwareFile{
+					{Path: "fw-link.bin", LinkTarget: "fw1.bin"},
+					{Path: "fw1.bin", SHA256: sha256Data1},
+					{Path: "subdir/fw2.bin", SHA256: sha256Data2},
+				},
+			}),
+		)
+
+		tmpFiles, err := filepath.Glob(filepath.Join(dstDir, ".*.tmp"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tmpFiles).To(BeEmpty())
+	})
+
+	It("should remove only the files that were not modified", func() {
+		Expect(fi.Install("kmm_test", srcDir, dstDir)).To(Succeed())
+
+		writeFile(filepath.Join(dstDir, "subdir", "fw2.bin"), "modified")
+
+		Expect(fi.Remove("kmm_test", srcDir, dstDir)).To(Succeed())
+
+		Expect(filepath.Join(dstDir, "fw1.bin")).NotTo(BeAnExistingFile())
+
+		_, err := os.Lstat(filepath.Join(dstDir, "fw-link.bin"))
+		Expect(err).To(MatchError(os.ErrNotExist))
+
+		Expect(readFile(filepath.Join(dstDir, "subdir", "fw2.bin"))).To(Equal("modified"))
+		Expect(filepath.Join(dstDir, firmwareManifestsDir, "kmm_test.json")).NotTo(BeAnExistingFile())
+	})
+
+	It("should not remove identical files that were already present on the host", func() {
+		writeFile(filepath.Join(dstDir, "fw1.bin"), "data 1")
+
+		Expect(fi.Install("kmm_test", srcDir, dstDir)).To(Succeed())
+		Expect(fi.Remove("kmm_test", srcDir, dstDir)).To(Succeed())
+
+		Expect(readFile(filepath.Join(dstDir, "fw1.bin"))).To(Equal("data 1"))
+		Expect(filepath.Join(dstDir, "subdir", "fw2.bin")).NotTo(BeAnExistingFile())
+	})
+
+	It("should keep the files shipped by another module", func() {
+		otherSrcDir := GinkgoT().TempDir()
+		writeFile(filepath.Join(otherSrcDir, "fw1.bin"), "data 1")
+
+		Expect(fi.Install("kmm_test", srcDir, dstDir)).To(Succeed())
+		Expect(fi.Install("kmm_other", otherSrcDir, dstDir)).To(Succeed())
+
+		Expect(fi.Remove("kmm_test", srcDir, dstDir)).To(Succeed())
+		Expect(readFile(filepath.Join(dstDir, "fw1.bin"))).To(Equal("data 1"))
+		Expect(filepath.Join(dstDir, "subdir", "fw2.bin")).NotTo(BeAnExistingFile())
+
+		Expect(fi.Remove("kmm_other", otherSrcDir, dstDir)).To(Succeed())
+		Expect(filepath.Join(dstDir, "fw1.bin")).NotTo(BeAnExistingFile())
+	})
+
+	It("should remove the files that are not shipped anymore when installing again", func() {
+		Expect(fi.Install("kmm_test", srcDir, dstDir)).To(Succeed())
+
+		Expect(os.RemoveAll(filepath.Join(srcDir, "subdir"))).To(Succeed())
+		writeFile(filepath.Join(srcDir, "fw1.bin"), "data 2")
+
+		Expect(fi.Install("kmm_test", srcDir, dstDir)).To(Succeed())
+
+		Expect(readFile(filepath.Join(dstDir, "fw1.bin"))).To(Equal("data 2"))
+		Expect(filepath.Join(dstDir, "subdir", "fw2.bin")).NotTo(BeAnExistingFile())
+	})
+
+	It("should only remove the files identical to the image ones if there is no manifest", func() {
+		writeFile(filepath.Join(dstDir, "fw1.bin"), "data 1")
+		writeFile(filepath.Join(dstDir, "subdir", "fw2.bin"), "host data")
+
+		Expect(fi.Remove("kmm_test", srcDir, dstDir)).To(Succeed())
+
+		Expect(filepath.Join(dstDir, "fw1.bin")).NotTo(BeAnExistingFile())
+		Expect(readFile(filepath.Join(dstDir, "subdir", "fw2.bin"))).To(Equal("host data"))
+	})
+})
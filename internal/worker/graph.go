@@ -0,0 +1,133 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/modgraph"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// modulesGraphOrder returns the graph of cfg and the order in which its modules must be loaded.
+func modulesGraphOrder(cfg *kmmv1beta1.ModuleConfig) (*modgraph.Graph, []string, error) {
+	g, err := modgraph.New(cfg.Modprobe.ModulesGraph)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: invalid modules graph: %v", ErrInvalidConfig, err)
+	}
+
+	order, err := g.LoadOrder()
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: invalid modules graph: %v", ErrInvalidConfig, err)
+	}
+
+	return g, order, nil
+}
+
+// addModuleResult records the outcome of the operation for one module of the graph.
+func (w *worker) addModuleResult(name, state string, err error) {
+	mr := kmmv1beta1.ModuleResult{Name: name, State: state}
+
+	if err != nil {
+		mr.Error = err.Error()
+	}
+
+	w.result.ModuleResults = append(w.result.ModuleResults, mr)
+}
+
+// loadModulesGraph loads the modules of cfg's graph one by one, in an order that satisfies their dependencies.
+// A module is not loaded if a module it depends on could not be loaded, but the other modules are.
+func (w *worker) loadModulesGraph(ctx context.Context, cfg *kmmv1beta1.ModuleConfig) error {
+	g, order, err := modulesGraphOrder(cfg)
+	if err != nil {
+		return err
+	}
+
+	w.logger.Info("Loading modules graph", "order", order)
+
+	params := make(map[string][]string, len(cfg.Modprobe.ModulesGraph))
+
+	for _, n := range cfg.Modprobe.ModulesGraph {
+		params[n.Name] = n.Parameters
+	}
+
+	params[cfg.Modprobe.ModuleName] = cfg.Modprobe.Parameters
+
+	failed := sets.New[string]()
+	errs := make([]error, 0, len(order))
+
+	for _, name := range order {
+		if blocking := failed.Intersection(sets.New(g.Pre(name)...)); blocking.Len() > 0 {
+			failed.Insert(name)
+			w.addModuleResult(name, ModuleStateSkipped, fmt.Errorf("depends on %s", strings.Join(sets.List(blocking), ", ")))
+			continue
+		}
+
+		args := []string{"-vd", filepath.Join(SharedFilesDir, cfg.Modprobe.DirName)}
+
+		if cfg.Modprobe.Args != nil {
+			args = append(args, cfg.Modprobe.Args.Load...)
+		}
+
+		args = append(args, name)
+		args = append(args, params[name]...)
+
+		if err = w.runModprobe(ctx, args...); err != nil {
+			failed.Insert(name)
+			w.addModuleResult(name, ModuleStateFailed, err)
+			errs = append(errs, fmt.Errorf("could not load module %s: %w", name, err))
+			continue
+		}
+
+		w.addModuleResult(name, ModuleStateLoaded, nil)
+	}
+
+	return errors.Join(errs...)
+}
+
+// unloadModulesGraph unloads the modules of cfg's graph one by one, in the reverse order of loadModulesGraph.
+// A module is not unloaded if a module that depends on it could not be unloaded, but the other modules are.
+func (w *worker) unloadModulesGraph(ctx context.Context, cfg *kmmv1beta1.ModuleConfig) error {
+	g, order, err := modulesGraphOrder(cfg)
+	if err != nil {
+		return err
+	}
+
+	slices.Reverse(order)
+
+	w.logger.Info("Unloading modules graph", "order", order)
+
+	failed := sets.New[string]()
+	errs := make([]error, 0, len(order))
+
+	for _, name := range order {
+		if blocking := failed.Intersection(sets.New(g.Post(name)...)); blocking.Len() > 0 {
+			failed.Insert(name)
+			w.addModuleResult(name, ModuleStateSkipped, fmt.Errorf("needed by %s", strings.Join(sets.List(blocking), ", ")))
+			continue
+		}
+
+		args := []string{"-rvd", filepath.Join(SharedFilesDir, cfg.Modprobe.DirName)}
+
+		if cfg.Modprobe.Args != nil {
+			args = append(args, cfg.Modprobe.Args.Unload...)
+		}
+
+		args = append(args, name)
+
+		if err = w.runModprobe(ctx, args...); err != nil {
+			failed.Insert(name)
+			w.addModuleResult(name, ModuleStateFailed, err)
+			errs = append(errs, fmt.Errorf("could not unload module %s: %w", name, err))
+			continue
+		}
+
+		w.addModuleResult(name, ModuleStateUnloaded, nil)
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,288 @@
+package worker
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/utils"
+)
+
+//go:generate mockgen -source=image.go -package=worker -destination=mock_image.go
+
+type PullOptions struct {
+	Insecure bool
+	Keychain authn.Keychain
+	// Paths holds the paths of the directories to extract from the image.
+	// Files outside those directories are skipped.
+	Paths []string
+}
+
+type PullResult struct {
+	// Reference is the pull source the image was fetched from, pinned by digest.
+	Reference string
+}
+
+type ImagePuller interface {
+	PullAndExtract(ctx context.Context, imageName, dstDir string, opts PullOptions) (*PullResult, error)
+}
+
+type imagePuller struct {
+	logger logr.Logger
+	mr     MirrorResolver
+	pull   func(src string, opt ...crane.Option) (v1.Image, error)
+}
+
+func NewImagePuller(mr MirrorResolver, logger logr.Logger) ImagePuller {
+	return &imagePuller{
+		logger: logger,
+		mr:     mr,
+		pull:   crane.Pull,
+	}
+}
+
+// PullAndExtract fetches imageName, trying all pull sources configured for it in registries.conf in order, and
+// extracts opts.Paths from the image's flattened filesystem into dstDir.
+func (i *imagePuller) PullAndExtract(ctx context.Context, imageName, dstDir string, opts PullOptions) (*PullResult, error) {
+	refs, err := i.mr.GetAllReferences(imageName)
+	if err != nil {
+		return nil, fmt.Errorf("could not get the pull sources for %s: %v", imageName, err)
+	}
+
+	craneOpts := []crane.Option{
+		crane.WithContext(ctx),
+		crane.WithPlatform(&v1.Platform{OS: runtime.GOOS, Architecture: runtime.GOARCH}),
+	}
+
+	if opts.Keychain != nil {
+		craneOpts = append(craneOpts, crane.WithAuthFromKeychain(opts.Keychain))
+	}
+
+	if opts.Insecure {
+		craneOpts = append(craneOpts, crane.Insecure)
+	}
+
+	errs := make([]error, 0, len(refs))
+
+	for _, ref := range refs {
+		logger := i.logger.WithValues("pull source", ref)
+
+		logger.Info("Pulling image")
+
+		img, err := i.pull(ref, craneOpts...)
+		if err != nil {
+			logger.Info(utils.WarnString("Could not pull image; trying the next pull source"), "error", err)
+			errs = append(errs, fmt.Errorf("%s: %v", ref, err))
+			continue
+		}
+
+		digest, err := img.Digest()
+		if err != nil {
+			return nil, fmt.Errorf("could not get the digest of %s: %v", ref, err)
+		}
+
+		pinned, err := pinnedReference(ref, digest.String(), opts.Insecure)
+		if err != nil {
+			return nil, err
+		}
+
+		logger.Info("Extracting image", "digest", digest.String(), "paths", opts.Paths, "destination", dstDir)
+
+		if err = extractImage(img, dstDir, opts.Paths); err != nil {
+			return nil, fmt.Errorf("could not extract %s: %v", pinned, err)
+		}
+
+		return &PullResult{Reference: pinned}, nil
+	}
+
+	return nil, fmt.Errorf("could not pull %s from any pull source: %w", imageName, errors.Join(errs...))
+}
+
+// ModuleImagePaths returns the paths of the image that are needed to load or unload the module described by cfg.
+func ModuleImagePaths(cfg *kmmv1beta1.ModuleConfig) []string {
+	paths := []string{
+		filepath.Join(cfg.Modprobe.DirName, "lib", "modules", cfg.KernelVersion),
+	}
+
+	if cfg.Modprobe.FirmwarePath != "" {
+		paths = append(paths, cfg.Modprobe.FirmwarePath)
+	}
+
+	return paths
+}
+
+func pinnedReference(ref, digest string, insecure bool) (string, error) {
+	var nameOpts []name.Option
+
+	if insecure {
+		nameOpts = append(nameOpts, name.Insecure)
+	}
+
+	r, err := name.ParseReference(ref, nameOpts...)
+	if err != nil {
+		return "", fmt.Errorf("could not parse %s: %v", ref, err)
+	}
+
+	return r.Context().Name() + "@" + digest, nil
+}
+
+func extractImage(img v1.Image, dstDir string, paths []string) error {
+	rc := mutate.Extract(img)
+	defer rc.Close()
+
+	return extractTar(tar.NewReader(rc), dstDir, paths)
+}
+
+// extractTar writes the entries of tr that are located under one of paths into dstDir.
+// Entries that would be written outside of dstDir are rejected.
+func extractTar(tr *tar.Reader, dstDir string, paths []string) error {
+	prefixes := make([]string, 0, len(paths))
+
+	for _, p := range paths {
+		prefixes = append(prefixes, cleanArchivePath(p))
+	}
+
+	// The flattened image lists the upper layers first, so hard links may come before their target.
+	hardLinks := make(map[string]string)
+
+	for {
+		hdr, err := tr.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			return fmt.Errorf("could not read the next archive entry: %v", err)
+		}
+
+		entryPath := cleanArchivePath(hdr.Name)
+
+		if !hasAnyPrefix(entryPath, prefixes) {
+			continue
+		}
+
+		dst, err := safeJoin(dstDir, entryPath)
+		if err != nil {
+			return err
+		}
+
+		if err = os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			return fmt.Errorf("could not create the parent directory of %s: %v", dst, err)
+		}
+
+		if hdr.Typeflag != tar.TypeDir {
+			// Never write through an existing file, in case the extraction is retried.
+			if err = os.Remove(dst); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("could not remove existing file %s: %v", dst, err)
+			}
+		}
+
+		mode := hdr.FileInfo().Mode().Perm()
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err = os.MkdirAll(dst, mode|0700); err != nil {
+				return fmt.Errorf("could not create directory %s: %v", dst, err)
+			}
+		case tar.TypeReg:
+			if err = writeFile(dst, tr, mode); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			if err = os.Symlink(hdr.Linkname, dst); err != nil {
+				return fmt.Errorf("could not create symlink %s: %v", dst, err)
+			}
+		case tar.TypeLink:
+			hardLinks[dst] = cleanArchivePath(hdr.Linkname)
+		default:
+			// Devices, FIFOs and the like are not expected in kmod images.
+			continue
+		}
+	}
+
+	for dst, linkname := range hardLinks {
+		target, err := safeJoin(dstDir, linkname)
+		if err != nil {
+			return err
+		}
+
+		if err = os.Link(target, dst); err != nil {
+			return fmt.Errorf("could not create hard link %s: %v", dst, err)
+		}
+	}
+
+	return nil
+}
+
+func writeFile(path string, r io.Reader, mode os.FileMode) error {
+	fd, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode)
+	if err != nil {
+		return fmt.Errorf("could not create %s: %v", path, err)
+	}
+	defer fd.Close()
+
+	if _, err = io.Copy(fd, r); err != nil {
+		return fmt.Errorf("could not write %s: %v", path, err)
+	}
+
+	return fd.Close()
+}
+
+// cleanArchivePath returns p relative to the root of the archive.
+func cleanArchivePath(p string) string {
+	return strings.TrimPrefix(filepath.Clean("/"+p), "/")
+}
+
+func hasAnyPrefix(p string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if prefix == "" || p == prefix || strings.HasPrefix(p, prefix+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// safeJoin joins dir and rel, and makes sure that none of the parent directories of the result is a symlink, so that
+// the archive cannot make us write outside of dir.
+func safeJoin(dir, rel string) (string, error) {
+	cur := dir
+
+	parts := strings.Split(rel, "/")
+
+	for idx, part := range parts {
+		cur = filepath.Join(cur, part)
+
+		if idx == len(parts)-1 {
+			break
+		}
+
+		fi, err := os.Lstat(cur)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+
+			return "", fmt.Errorf("could not stat %s: %v", cur, err)
+		}
+
+		if fi.Mode()&os.ModeSymlink != 0 {
+			return "", fmt.Errorf("%s: refusing to extract through symlink %s", rel, cur)
+		}
+	}
+
+	return cur, nil
+}
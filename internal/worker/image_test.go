@@ -0,0 +1,241 @@
+package worker
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
+	"go.uber.org/mock/gomock"
+)
+
+func makeTestLayer(headers ...*tar.Header) v1.Layer {
+	GinkgoHelper()
+
+	buf := bytes.Buffer{}
+	tw := tar.NewWriter(&buf)
+
+	for _, hdr := range headers {
+		content := []byte(hdr.Name)
+
+		if hdr.Typeflag == tar.TypeReg {
+			hdr.Size = int64(len(content))
+		}
+
+		Expect(tw.WriteHeader(hdr)).To(Succeed())
+
+		if hdr.Typeflag == tar.TypeReg {
+			_, err := tw.Write(content)
+			Expect(err).NotTo(HaveOccurred())
+		}
+	}
+
+	Expect(tw.Close()).To(Succeed())
+
+	layer, err := tarball.LayerFromOpener(func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(buf.Bytes())), nil
+	})
+	Expect(err).NotTo(HaveOccurred())
+
+	return layer
+}
+
+func makeTestImage() v1.Image {
+	GinkgoHelper()
+
+	img, err := mutate.AppendLayers(
+		empty.Image,
+		makeTestLayer(
+			&tar.Header{Name: "opt/", Typeflag: tar.TypeDir, Mode: 0755},
+			&tar.Header{Name: "opt/lib/modules/5.14.0/kmm.ko", Typeflag: tar.TypeReg, Mode: 0644},
+			&tar.Header{Name: "opt/lib/modules/5.14.0/removed.ko", Typeflag: tar.TypeReg, Mode: 0644},
+			&tar.Header{Name: "opt/lib/modules/6.0.0/kmm.ko", Typeflag: tar.TypeReg, Mode: 0644},
+			&tar.Header{Name: "firmware/fw.bin", Typeflag: tar.TypeReg, Mode: 0644},
+			&tar.Header{Name: "etc/passwd", Typeflag: tar.TypeReg, Mode: 0644},
+		),
+		makeTestLayer(
+			&tar.Header{Name: "opt/lib/modules/5.14.0/.wh.removed.ko", Typeflag: tar.TypeReg, Mode: 0644},
+			&tar.Header{Name: "opt/lib/modules/5.14.0/symlink.ko", Typeflag: tar.TypeSymlink, Linkname: "kmm.ko"},
+			&tar.Header{Name: "opt/lib/modules/5.14.0/hardlink.ko", Typeflag: tar.TypeLink, Linkname: "opt/lib/modules/5.14.0/kmm.ko"},
+		),
+	)
+	Expect(err).NotTo(HaveOccurred())
+
+	return img
+}
+
+var _ = Describe("imagePuller_PullAndExtract", func() {
+	const (
+		image   = "registry.example.com/ns/kmod:tag"
+		mirror0 = "mirror0.example.com/ns/kmod:tag"
+		mirror1 = "mirror1.example.com/ns/kmod:tag"
+	)
+
+	var (
+		dstDir string
+		mr     *MockMirrorResolver
+		ip     *imagePuller
+		pulled []string
+		img    v1.Image
+	)
+
+	BeforeEach(func() {
+		ctrl := gomock.NewController(GinkgoT())
+		mr = NewMockMirrorResolver(ctrl)
+		dstDir = GinkgoT().TempDir()
+		pulled = nil
+		img = makeTestImage()
+
+		ip = &imagePuller{
+			logger: GinkgoLogr,
+			mr:     mr,
+			pull: func(src string, _ ...crane.Option) (v1.Image, error) {
+				pulled = append(pulled, src)
+
+				if src == mirror1 {
+					return img, nil
+				}
+
+				return nil, errors.New("unreachable")
+			},
+		}
+	})
+
+	ctx := context.TODO()
+
+	opts := PullOptions{Paths: []string{"/opt/lib/modules/5.14.0", "/firmware"}}
+
+	It("should return an error if the pull sources cannot be determined", func() {
+		mr.EXPECT().GetAllReferences(image).Return(nil, errors.New("some error"))
+
+		_, err := ip.PullAndExtract(ctx, image, dstDir, opts)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should return an error if no pull source works", func() {
+		mr.EXPECT().GetAllReferences(image).Return([]string{image, mirror0}, nil)
+
+		_, err := ip.PullAndExtract(ctx, image, dstDir, opts)
+		Expect(err).To(MatchError(ContainSubstring("unreachable")))
+		Expect(pulled).To(Equal([]string{image, mirror0}))
+	})
+
+	It("should try the pull sources in order and extract only the requested paths", func() {
+		mr.EXPECT().GetAllReferences(image).Return([]string{mirror0, mirror1, image}, nil)
+
+		digest, err := img.Digest()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(
+			ip.PullAndExtract(ctx, image, dstDir, opts),
+		).To(
+			Equal(&PullResult{Reference: "mirror1.example.com/ns/kmod@" + digest.String()}),
+		)
+
+		Expect(pulled).To(Equal([]string{mirror0, mirror1}))
+
+		var files []string
+
+		err = filepath.WalkDir(dstDir, func(path string, d os.DirEntry, err error) error {
+			if err == nil && !d.IsDir() {
+				rel, _ := filepath.Rel(dstDir, path)
+				files = append(files, rel)
+			}
+
+			return err
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(files).To(ConsistOf(
+			"firmware/fw.bin",
+			"opt/lib/modules/5.14.0/hardlink.ko",
+			"opt/lib/modules/5.14.0/kmm.ko",
+			"opt/lib/modules/5.14.0/symlink.ko",
+		))
+
+		Expect(
+			os.ReadFile(filepath.Join(dstDir, "opt/lib/modules/5.14.0/symlink.ko")),
+		).To(
+			BeEquivalentTo("opt/lib/modules/5.14.0/kmm.ko"),
+		)
+	})
+})
+
+var _ = Describe("extractTar", func() {
+	writeArchive := func(headers ...*tar.Header) *tar.Reader {
+		GinkgoHelper()
+
+		buf := bytes.Buffer{}
+		tw := tar.NewWriter(&buf)
+
+		for _, hdr := range headers {
+			Expect(tw.WriteHeader(hdr)).To(Succeed())
+		}
+
+		Expect(tw.Close()).To(Succeed())
+
+		return tar.NewReader(&buf)
+	}
+
+	It("should not write outside of the destination directory", func() {
+		dstDir := GinkgoT().TempDir()
+
+		tr := writeArchive(
+			&tar.Header{Name: "opt/escape", Typeflag: tar.TypeSymlink, Linkname: "/"},
+			&tar.Header{Name: "opt/escape/tmp/file", Typeflag: tar.TypeReg, Mode: 0644},
+		)
+
+		Expect(
+			extractTar(tr, dstDir, []string{"/opt"}),
+		).To(
+			MatchError(ContainSubstring("refusing to extract through symlink")),
+		)
+	})
+
+	It("should keep entries with parent references inside the destination directory", func() {
+		dstDir := GinkgoT().TempDir()
+
+		tr := writeArchive(
+			&tar.Header{Name: "../../opt/file", Typeflag: tar.TypeReg, Mode: 0644},
+		)
+
+		Expect(
+			extractTar(tr, dstDir, []string{"/opt"}),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(filepath.Join(dstDir, "opt", "file")).To(BeARegularFile())
+	})
+})
+
+var _ = Describe("ModuleImagePaths", func() {
+	It("should return the modules directory for the kernel", func() {
+		cfg := kmmv1beta1.ModuleConfig{
+			KernelVersion: "5.14.0",
+			Modprobe:      kmmv1beta1.ModprobeSpec{DirName: "/opt"},
+		}
+
+		Expect(ModuleImagePaths(&cfg)).To(Equal([]string{"/opt/lib/modules/5.14.0"}))
+	})
+
+	It("should also return the firmware directory if set", func() {
+		cfg := kmmv1beta1.ModuleConfig{
+			KernelVersion: "5.14.0",
+			Modprobe:      kmmv1beta1.ModprobeSpec{DirName: "/opt", FirmwarePath: "/firmware"},
+		}
+
+		Expect(ModuleImagePaths(&cfg)).To(Equal([]string{"/opt/lib/modules/5.14.0", "/firmware"}))
+	})
+})
@@ -0,0 +1,125 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/utils"
+)
+
+// ensureModulesDep generates the modules.dep file of the image's modules directory if it is missing or does not list
+// all the module files; modprobe cannot find modules that are not listed there.
+// The directory is a copy of the image in the worker's shared volume, so the image itself is never modified.
+// Failing to generate the file is not fatal, as modprobe reports a more relevant error if it needs it.
+func (w *worker) ensureModulesDep(cfg *kmmv1beta1.ModuleConfig) {
+	dir := imageModulesDir(cfg)
+
+	if _, err := os.Stat(dir); err != nil {
+		w.logger.V(1).Info("Cannot access the image's modules directory; not checking modules.dep", "directory", dir, "error", err)
+		return
+	}
+
+	stale, err := modulesDepStale(dir)
+	if err != nil {
+		w.logger.Info(utils.WarnString("could not check if modules.dep is up to date"), "directory", dir, "error", err)
+		return
+	}
+
+	if !stale {
+		return
+	}
+
+	w.logger.Info("modules.dep is missing or incomplete; generating it", "directory", dir)
+
+	if err = generateModulesDep(dir); err != nil {
+		w.logger.Info(utils.WarnString("could not generate modules.dep"), "directory", dir, "error", err)
+	}
+}
+
+// runInsmod loads a single module file and records the outcome in the worker's result.
+func (w *worker) runInsmod(ctx context.Context, path string, params ...string) error {
+	res, err := w.mr.Insmod(ctx, path, params...)
+
+	w.recordRun(append([]string{"insmod", path}, params...), res)
+
+	return err
+}
+
+// runRmmod unloads a single module and records the outcome in the worker's result.
+func (w *worker) runRmmod(ctx context.Context, name string) error {
+	res, err := w.mr.Rmmod(ctx, name)
+
+	w.recordRun([]string{"rmmod", name}, res)
+
+	return err
+}
+
+// loadModuleFiles loads cfg's module files one by one, in the configured order.
+// Parameters are only passed to the last file, which is the configured module.
+// Files that are already loaded are skipped; loading stops at the first file that cannot be loaded.
+func (w *worker) loadModuleFiles(ctx context.Context, cfg *kmmv1beta1.ModuleConfig) error {
+	dir := imageModulesDir(cfg)
+	files := cfg.Modprobe.ModuleFiles
+
+	w.logger.Info("Loading module files", "directory", dir, "files", files)
+
+	for i, f := range files {
+		name := moduleNameFromPath(f)
+
+		var params []string
+
+		if i == len(files)-1 {
+			params = cfg.Modprobe.Parameters
+		}
+
+		if err := w.runInsmod(ctx, filepath.Join(dir, f), params...); err != nil {
+			if errors.Is(err, ErrAlreadyLoaded) {
+				w.logger.Info("Module already loaded", "name", name)
+				w.addModuleResult(name, ModuleStateLoaded, nil)
+				continue
+			}
+
+			w.addModuleResult(name, ModuleStateFailed, err)
+
+			return fmt.Errorf("could not load module file %s: %w", f, err)
+		}
+
+		w.addModuleResult(name, ModuleStateLoaded, nil)
+	}
+
+	return nil
+}
+
+// unloadModuleFiles unloads cfg's module files one by one, in the reverse order of loadModuleFiles.
+// Like modprobe -r, failing to unload a dependency of the configured module, which may be used by other modules, is
+// not an error.
+func (w *worker) unloadModuleFiles(ctx context.Context, cfg *kmmv1beta1.ModuleConfig) error {
+	files := slices.Clone(cfg.Modprobe.ModuleFiles)
+	slices.Reverse(files)
+
+	w.logger.Info("Unloading module files", "files", files)
+
+	for i, f := range files {
+		name := moduleNameFromPath(f)
+
+		if err := w.runRmmod(ctx, name); err != nil {
+			if i == 0 {
+				w.addModuleResult(name, ModuleStateFailed, err)
+				return fmt.Errorf("could not unload module %s: %w", name, err)
+			}
+
+			w.logger.Info("Could not unload dependency; it is probably still in use", "name", name, "error", err)
+			w.addModuleResult(name, ModuleStateSkipped, err)
+			continue
+		}
+
+		w.addModuleResult(name, ModuleStateUnloaded, nil)
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/utils"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// removedModules returns the modules out of requested that modprobe reported as removed.
+func removedModules(requested, removed []string) []string {
+	removedSet := sets.New[string]()
+
+	for _, m := range removed {
+		removedSet.Insert(normalizeModuleName(m))
+	}
+
+	var res []string
+
+	for _, m := range requested {
+		if removedSet.Has(normalizeModuleName(m)) {
+			res = append(res, m)
+		}
+	}
+
+	return res
+}
+
+func (w *worker) RestoreInTreeModules(ctx context.Context, modules []string) error {
+	errs := make([]error, 0, len(modules))
+
+	for _, m := range modules {
+		w.logger.Info("Restoring in-tree module", "name", m)
+
+		// No -d flag: load the module from the host's modules tree.
+		res, err := w.mr.Run(ctx, "-v", m)
+		if err != nil {
+			w.logger.Info(utils.WarnString("could not restore in-tree module"), "name", m, "error", err)
+
+			if res != nil {
+				w.logger.Info("modprobe output", "stderr", res.Stderr)
+			}
+
+			errs = append(errs, fmt.Errorf("could not load in-tree module %s: %w", m, err))
+
+			continue
+		}
+
+		w.result.RestoredInTreeModules = append(w.result.RestoredInTreeModules, m)
+	}
+
+	err := errors.Join(errs...)
+	if err != nil {
+		w.result.InTreeModulesRestoreError = err.Error()
+	}
+
+	return err
+}
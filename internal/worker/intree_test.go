@@ -0,0 +1,71 @@
+package worker
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("removedModules", func() {
+	It("should return the requested modules that were removed", func() {
+		Expect(
+			removedModules([]string{"intree-a", "intree_b", "intree_c"}, []string{"intree_a", "dep", "intree_b"}),
+		).To(
+			Equal([]string{"intree-a", "intree_b"}),
+		)
+	})
+
+	It("should return nil if no requested module was removed", func() {
+		Expect(removedModules([]string{"intree"}, []string{"dep"})).To(BeNil())
+	})
+})
+
+var _ = Describe("worker_RestoreInTreeModules", func() {
+	var (
+		mr *MockModprobeRunner
+		w  Worker
+	)
+
+	BeforeEach(func() {
+		ctrl := gomock.NewController(GinkgoT())
+		mr = NewMockModprobeRunner(ctrl)
+		w = NewWorker(mr, nil, nil, nil, nil, nil, nil, GinkgoLogr)
+	})
+
+	ctx := context.TODO()
+
+	It("should load the modules from the host's modules tree", func() {
+		gomock.InOrder(
+			mr.EXPECT().Run(ctx, "-v", "intree1"),
+			mr.EXPECT().Run(ctx, "-v", "intree2"),
+		)
+
+		Expect(
+			w.RestoreInTreeModules(ctx, []string{"intree1", "intree2"}),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(w.Result().RestoredInTreeModules).To(Equal([]string{"intree1", "intree2"}))
+		Expect(w.Result().InTreeModulesRestoreError).To(BeEmpty())
+	})
+
+	It("should try all modules and report the failures", func() {
+		gomock.InOrder(
+			mr.EXPECT().Run(ctx, "-v", "intree1").Return(&ModprobeResult{ExitCode: 1}, errors.New("random error")),
+			mr.EXPECT().Run(ctx, "-v", "intree2"),
+		)
+
+		Expect(
+			w.RestoreInTreeModules(ctx, []string{"intree1", "intree2"}),
+		).To(
+			MatchError(ContainSubstring("could not load in-tree module intree1: random error")),
+		)
+
+		Expect(w.Result().RestoredInTreeModules).To(Equal([]string{"intree2"}))
+		Expect(w.Result().InTreeModulesRestoreError).To(ContainSubstring("intree1"))
+	})
+})
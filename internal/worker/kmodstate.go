@@ -0,0 +1,317 @@
+package worker
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/modinfo"
+	"golang.org/x/sys/unix"
+)
+
+//go:generate mockgen -source=kmodstate.go -package=worker -destination=mock_kmodstate.go
+
+const (
+	procDir         = "/proc"
+	procModulesPath = "/proc/modules"
+)
+
+// ModuleState is the state of a kernel module as reported by /proc/modules and /sys/module/<name>.
+type ModuleState struct {
+	Name       string   `json:"name"`
+	Loaded     bool     `json:"loaded"`
+	InitState  string   `json:"initState,omitempty"`
+	RefCount   int      `json:"refCount"`
+	Size       int      `json:"size,omitempty"`
+	SrcVersion string   `json:"srcVersion,omitempty"`
+	Taint      string   `json:"taint,omitempty"`
+	UsedBy     []string `json:"usedBy,omitempty"`
+	// Holders are the modules listed in /sys/module/<name>/holders.
+	Holders []string `json:"holders,omitempty"`
+}
+
+// KmodStatus is the state of the kernel module described by a ModuleConfig.
+type KmodStatus struct {
+	ModuleState
+
+	// ExpectedSrcVersion is the srcversion of the .ko file shipped in the image, if available.
+	ExpectedSrcVersion string `json:"expectedSrcVersion,omitempty"`
+}
+
+type ModuleStateReader interface {
+	// GetBootID returns the identifier of the current boot of the node, which changes every time it reboots.
+	GetBootID() (string, error)
+	GetModuleState(name string) (*ModuleState, error)
+	GetImageSrcVersion(modulesDir, name string) (string, error)
+	// GetImageModuleInfo returns the .modinfo content of the module file found in modulesDir through modules.dep.
+	GetImageModuleInfo(modulesDir, name string) (*modinfo.ModuleInfo, error)
+	// GetModuleProcesses returns the processes, as "pid (command)", that have a device registered by the module open.
+	GetModuleProcesses(name string) ([]string, error)
+}
+
+type moduleStateReader struct {
+	procDir         string
+	procModulesPath string
+	sysModuleDir    string
+}
+
+func NewModuleStateReader() ModuleStateReader {
+	return &moduleStateReader{
+		procDir:         procDir,
+		procModulesPath: procModulesPath,
+		sysModuleDir:    sysModuleDir,
+	}
+}
+
+func (m *moduleStateReader) GetBootID() (string, error) {
+	path := filepath.Join(m.procDir, "sys", "kernel", "random", "boot_id")
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("could not read %s: %v", path, err)
+	}
+
+	return strings.TrimSpace(string(b)), nil
+}
+
+// GetModuleState returns the state of the module in the running kernel.
+// If the module is not loaded, the returned ModuleState has Loaded set to false.
+func (m *moduleStateReader) GetModuleState(name string) (*ModuleState, error) {
+	name = normalizeModuleName(name)
+
+	state := ModuleState{Name: name}
+
+	fd, err := os.Open(m.procModulesPath)
+	if err != nil {
+		return nil, fmt.Errorf("could not open %s: %v", m.procModulesPath, err)
+	}
+	defer fd.Close()
+
+	s := bufio.NewScanner(fd)
+
+	for s.Scan() {
+		// name size refcount deps state offset [taint]
+		fields := strings.Fields(s.Text())
+		if len(fields) < 5 || fields[0] != name {
+			continue
+		}
+
+		state.Loaded = true
+
+		if state.Size, err = strconv.Atoi(fields[1]); err != nil {
+			return nil, fmt.Errorf("%s: invalid size %q for module %s: %v", m.procModulesPath, fields[1], name, err)
+		}
+
+		for _, dep := range strings.Split(fields[3], ",") {
+			if dep != "" && dep != "-" {
+				state.UsedBy = append(state.UsedBy, dep)
+			}
+		}
+
+		break
+	}
+
+	if err = s.Err(); err != nil {
+		return nil, fmt.Errorf("could not read %s: %v", m.procModulesPath, err)
+	}
+
+	if !state.Loaded {
+		return &state, nil
+	}
+
+	moduleDir := filepath.Join(m.sysModuleDir, name)
+
+	if state.InitState, err = readSysfsValue(filepath.Join(moduleDir, "initstate")); err != nil {
+		return nil, err
+	}
+
+	refcnt, err := readSysfsValue(filepath.Join(moduleDir, "refcnt"))
+	if err != nil {
+		return nil, err
+	}
+
+	if refcnt != "" {
+		if state.RefCount, err = strconv.Atoi(refcnt); err != nil {
+			return nil, fmt.Errorf("invalid refcnt %q for module %s: %v", refcnt, name, err)
+		}
+	}
+
+	if state.SrcVersion, err = readSysfsValue(filepath.Join(moduleDir, "srcversion")); err != nil {
+		return nil, err
+	}
+
+	if state.Taint, err = readSysfsValue(filepath.Join(moduleDir, "taint")); err != nil {
+		return nil, err
+	}
+
+	holdersDir := filepath.Join(moduleDir, "holders")
+
+	entries, err := os.ReadDir(holdersDir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("could not read %s: %v", holdersDir, err)
+	}
+
+	for _, e := range entries {
+		state.Holders = append(state.Holders, e.Name())
+	}
+
+	return &state, nil
+}
+
+// GetModuleProcesses looks for processes that have a character or block device open whose major number was
+// registered under the name of the module in /proc/devices.
+// Only the processes visible in the PID namespace of the caller are found.
+// Processes that cannot be inspected, for example because they exited, are ignored.
+func (m *moduleStateReader) GetModuleProcesses(name string) ([]string, error) {
+	majors, err := m.readDeviceMajors(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(majors) == 0 {
+		return nil, nil
+	}
+
+	entries, err := os.ReadDir(m.procDir)
+	if err != nil {
+		return nil, fmt.Errorf("could not read %s: %v", m.procDir, err)
+	}
+
+	var processes []string
+
+	for _, e := range entries {
+		if _, err = strconv.Atoi(e.Name()); err != nil {
+			continue
+		}
+
+		pidDir := filepath.Join(m.procDir, e.Name())
+
+		fds, err := os.ReadDir(filepath.Join(pidDir, "fd"))
+		if err != nil {
+			continue
+		}
+
+		for _, fd := range fds {
+			st := unix.Stat_t{}
+
+			if err = unix.Stat(filepath.Join(pidDir, "fd", fd.Name()), &st); err != nil {
+				continue
+			}
+
+			if !majors[deviceMajor{fileType: st.Mode & unix.S_IFMT, major: unix.Major(uint64(st.Rdev))}] {
+				continue
+			}
+
+			comm, _ := readSysfsValue(filepath.Join(pidDir, "comm"))
+
+			processes = append(processes, fmt.Sprintf("%s (%s)", e.Name(), comm))
+
+			break
+		}
+	}
+
+	return processes, nil
+}
+
+type deviceMajor struct {
+	fileType uint32
+	major    uint32
+}
+
+// readDeviceMajors returns the device majors registered under the name of the module in /proc/devices.
+func (m *moduleStateReader) readDeviceMajors(name string) (map[deviceMajor]bool, error) {
+	path := filepath.Join(m.procDir, "devices")
+
+	fd, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not open %s: %v", path, err)
+	}
+	defer fd.Close()
+
+	name = normalizeModuleName(name)
+
+	majors := make(map[deviceMajor]bool)
+
+	var fileType uint32
+
+	s := bufio.NewScanner(fd)
+
+	for s.Scan() {
+		line := s.Text()
+
+		switch line {
+		case "Character devices:":
+			fileType = unix.S_IFCHR
+			continue
+		case "Block devices:":
+			fileType = unix.S_IFBLK
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if fileType == 0 || len(fields) != 2 || normalizeModuleName(fields[1]) != name {
+			continue
+		}
+
+		major, err := strconv.ParseUint(fields[0], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid major %q: %v", path, fields[0], err)
+		}
+
+		majors[deviceMajor{fileType: fileType, major: uint32(major)}] = true
+	}
+
+	if err = s.Err(); err != nil {
+		return nil, fmt.Errorf("could not read %s: %v", path, err)
+	}
+
+	return majors, nil
+}
+
+// GetImageSrcVersion returns the srcversion of the module file found in modulesDir through modules.dep.
+// It returns an empty string if the module was built without a srcversion.
+func (m *moduleStateReader) GetImageSrcVersion(modulesDir, name string) (string, error) {
+	info, err := m.GetImageModuleInfo(modulesDir, name)
+	if err != nil {
+		return "", err
+	}
+
+	return info.SrcVersion, nil
+}
+
+func (m *moduleStateReader) GetImageModuleInfo(modulesDir, name string) (*modinfo.ModuleInfo, error) {
+	deps, err := readModulesDep(modulesDir)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the module dependencies: %v", err)
+	}
+
+	md, ok := deps[normalizeModuleName(name)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrModuleNotFound, name)
+	}
+
+	info, err := modinfo.ReadFile(filepath.Join(modulesDir, md.Path))
+	if err != nil {
+		return nil, fmt.Errorf("could not read the module information: %v", err)
+	}
+
+	return info, nil
+}
+
+// readSysfsValue returns the trimmed content of a sysfs attribute, or an empty string if it does not exist.
+func readSysfsValue(path string) (string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", nil
+		}
+
+		return "", fmt.Errorf("could not read %s: %v", path, err)
+	}
+
+	return strings.TrimSpace(string(b)), nil
+}
@@ -0,0 +1,203 @@
+package worker
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const testModulesDir = "testdata/modules/lib/modules/5.14.0-284.el9.x86_64"
+
+var _ = Describe("moduleStateReader_GetBootID", func() {
+	It("should return the boot ID without the trailing newline", func() {
+		msr := &moduleStateReader{procDir: GinkgoT().TempDir()}
+
+		dir := filepath.Join(msr.procDir, "sys", "kernel", "random")
+		Expect(os.MkdirAll(dir, 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "boot_id"), []byte("0f2a5c3e-7d1b-4b8e-9a6f-2c4d8e1b3a5f\n"), 0644)).To(Succeed())
+
+		Expect(
+			msr.GetBootID(),
+		).To(
+			Equal("0f2a5c3e-7d1b-4b8e-9a6f-2c4d8e1b3a5f"),
+		)
+	})
+
+	It("should return an error if the boot ID cannot be read", func() {
+		msr := &moduleStateReader{procDir: GinkgoT().TempDir()}
+
+		_, err := msr.GetBootID()
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("moduleStateReader_GetModuleState", func() {
+	const procModules = `nvme 49152 3 - Live 0x0000000000000000
+kmm_test 16384 1 kmm_user1,kmm_user2, Live 0x0000000000000000 (OE)
+`
+
+	var msr *moduleStateReader
+
+	BeforeEach(func() {
+		tmpDir := GinkgoT().TempDir()
+
+		msr = &moduleStateReader{
+			procModulesPath: filepath.Join(tmpDir, "modules"),
+			sysModuleDir:    filepath.Join(tmpDir, "sys"),
+		}
+
+		Expect(os.WriteFile(msr.procModulesPath, []byte(procModules), 0644)).To(Succeed())
+	})
+
+	It("should return an error if /proc/modules cannot be read", func() {
+		msr.procModulesPath = "/non/existent/path"
+
+		_, err := msr.GetModuleState("kmm_test")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should return a not loaded state if the module is absent", func() {
+		Expect(
+			msr.GetModuleState("kmm-other"),
+		).To(
+			Equal(&ModuleState{Name: "kmm_other"}),
+		)
+	})
+
+	It("should read /proc/modules and sysfs", func() {
+		moduleDir := filepath.Join(msr.sysModuleDir, "kmm_test")
+		Expect(os.MkdirAll(moduleDir, 0755)).To(Succeed())
+
+		files := map[string]string{
+			"initstate":  "live\n",
+			"refcnt":     "2\n",
+			"srcversion": "8F3A4E1D7C2B9A0F6E5D4C3\n",
+			"taint":      "OE\n",
+		}
+
+		for name, content := range files {
+			Expect(os.WriteFile(filepath.Join(moduleDir, name), []byte(content), 0644)).To(Succeed())
+		}
+
+		Expect(os.MkdirAll(filepath.Join(moduleDir, "holders", "kmm_user1"), 0755)).To(Succeed())
+
+		Expect(
+			msr.GetModuleState("kmm-test"),
+		).To(
+			Equal(&ModuleState{
+				Name:       "kmm_test",
+				Loaded:     true,
+				InitState:  "live",
+				RefCount:   2,
+				Size:       16384,
+				SrcVersion: "8F3A4E1D7C2B9A0F6E5D4C3",
+				Taint:      "OE",
+				UsedBy:     []string{"kmm_user1", "kmm_user2"},
+				Holders:    []string{"kmm_user1"},
+			}),
+		)
+	})
+
+	It("should return an error if refcnt is invalid", func() {
+		moduleDir := filepath.Join(msr.sysModuleDir, "kmm_test")
+		Expect(os.MkdirAll(moduleDir, 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(moduleDir, "refcnt"), []byte("abc"), 0644)).To(Succeed())
+
+		_, err := msr.GetModuleState("kmm_test")
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("moduleStateReader_GetModuleProcesses", func() {
+	// /dev/null is the character device 1:3
+	const procDevices = `Character devices:
+  1 kmm-test
+  4 tty
+
+Block devices:
+  1 ramdisk
+`
+
+	var msr *moduleStateReader
+
+	BeforeEach(func() {
+		msr = &moduleStateReader{procDir: GinkgoT().TempDir()}
+
+		Expect(os.WriteFile(filepath.Join(msr.procDir, "devices"), []byte(procDevices), 0644)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(msr.procDir, "self"), 0755)).To(Succeed())
+
+		regularFile := filepath.Join(msr.procDir, "regular")
+		Expect(os.WriteFile(regularFile, nil, 0644)).To(Succeed())
+
+		processes := map[string]string{
+			"123": "/dev/null",
+			"456": regularFile,
+		}
+
+		for pid, target := range processes {
+			fdDir := filepath.Join(msr.procDir, pid, "fd")
+			Expect(os.MkdirAll(fdDir, 0755)).To(Succeed())
+			Expect(os.Symlink(target, filepath.Join(fdDir, "3"))).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(msr.procDir, pid, "comm"), []byte("app-"+pid+"\n"), 0644)).To(Succeed())
+		}
+	})
+
+	It("should return an error if /proc/devices cannot be read", func() {
+		msr.procDir = "/non/existent/path"
+
+		_, err := msr.GetModuleProcesses("kmm_test")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should return the processes that have a device of the module open", func() {
+		Expect(
+			msr.GetModuleProcesses("kmm_test"),
+		).To(
+			Equal([]string{"123 (app-123)"}),
+		)
+	})
+
+	It("should return nothing if the module did not register a device", func() {
+		Expect(
+			msr.GetModuleProcesses("kmm_other"),
+		).To(
+			BeEmpty(),
+		)
+	})
+})
+
+var _ = Describe("moduleStateReader_GetImageSrcVersion", func() {
+	msr := NewModuleStateReader()
+
+	It("should return the srcversion of the module file", func() {
+		Expect(
+			msr.GetImageSrcVersion(testModulesDir, "kmm_test"),
+		).To(
+			Equal("8F3A4E1D7C2B9A0F6E5D4C3"),
+		)
+	})
+
+	It("should return an error if the module is not in modules.dep", func() {
+		_, err := msr.GetImageSrcVersion(testModulesDir, "kmm_other")
+		Expect(err).To(MatchError(ErrModuleNotFound))
+	})
+
+	It("should return an error if modules.dep does not exist", func() {
+		_, err := msr.GetImageSrcVersion("/non/existent/path", "kmm_test")
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("moduleStateReader_GetImageModuleInfo", func() {
+	msr := NewModuleStateReader()
+
+	It("should return the .modinfo content of the module file", func() {
+		info, err := msr.GetImageModuleInfo(testModulesDir, "kmm-test")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info.Name).To(Equal("kmm_test"))
+		Expect(info.KernelRelease()).To(Equal("5.14.0-284.el9.x86_64"))
+		Expect(info.Depends).To(Equal([]string{"kmm_dep"}))
+	})
+})
@@ -0,0 +1,221 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+//go:generate mockgen -source=kmsg.go -package=worker -destination=mock_kmsg.go
+
+const (
+	kmsgPath = "/dev/kmsg"
+
+	// maxKmsgRecordSize is the size of the largest record returned by a single read of /dev/kmsg.
+	maxKmsgRecordSize = 8192
+
+	// maxKmsgRecords is the maximum number of records kept by a capture; older records are dropped.
+	maxKmsgRecords = 1000
+)
+
+// KmsgRecord is a record of the kernel log, as read from /dev/kmsg.
+type KmsgRecord struct {
+	Priority  int
+	Sequence  uint64
+	Timestamp time.Duration
+	Message   string
+}
+
+func (r KmsgRecord) String() string {
+	return fmt.Sprintf("[%12.6f] %s", r.Timestamp.Seconds(), r.Message)
+}
+
+type KmsgCapture interface {
+	// Stop returns the records written to the kernel log since the capture was started.
+	Stop() ([]KmsgRecord, error)
+}
+
+type KmsgWatcher interface {
+	// Start begins capturing the records written to the kernel log from now on.
+	Start() (KmsgCapture, error)
+}
+
+type kmsgWatcher struct {
+	open func() (io.ReadCloser, error)
+}
+
+// NewKmsgWatcher returns a KmsgWatcher that reads /dev/kmsg.
+func NewKmsgWatcher() KmsgWatcher {
+	return &kmsgWatcher{open: openDevKmsg}
+}
+
+func (k *kmsgWatcher) Start() (KmsgCapture, error) {
+	src, err := k.open()
+	if err != nil {
+		return nil, fmt.Errorf("could not open the kernel log: %v", err)
+	}
+
+	return &kmsgCapture{src: src}, nil
+}
+
+type kmsgCapture struct {
+	src io.ReadCloser
+}
+
+func (k *kmsgCapture) Stop() ([]KmsgRecord, error) {
+	defer k.src.Close()
+
+	buf := make([]byte, maxKmsgRecordSize)
+	data := make([]byte, 0, maxKmsgRecordSize)
+
+	for {
+		n, err := k.src.Read(buf)
+
+		data = append(data, buf[:n]...)
+
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			return nil, fmt.Errorf("could not read the kernel log: %v", err)
+		}
+	}
+
+	records := parseKmsgRecords(string(data))
+
+	if len(records) > maxKmsgRecords {
+		records = records[len(records)-maxKmsgRecords:]
+	}
+
+	return records, nil
+}
+
+// parseKmsgRecords parses records in the /dev/kmsg format:
+//
+//	priority,sequence,timestamp,flags[,...];message
+//	 KEY=value
+//
+// Continuation lines holding the record's dictionary and malformed lines are ignored.
+func parseKmsgRecords(data string) []KmsgRecord {
+	records := make([]KmsgRecord, 0)
+
+	for _, line := range strings.Split(data, "\n") {
+		if line == "" || strings.HasPrefix(line, " ") {
+			continue
+		}
+
+		prefix, msg, ok := strings.Cut(line, ";")
+		if !ok {
+			continue
+		}
+
+		fields := strings.Split(prefix, ",")
+		if len(fields) < 3 {
+			continue
+		}
+
+		pri, err := strconv.Atoi(fields[0])
+		if err != nil {
+			continue
+		}
+
+		seq, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+
+		usec, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			continue
+		}
+
+		records = append(records, KmsgRecord{
+			// the upper bits hold the facility
+			Priority:  pri & 7,
+			Sequence:  seq,
+			Timestamp: time.Duration(usec) * time.Microsecond,
+			Message:   msg,
+		})
+	}
+
+	return records
+}
+
+// filterKmsgRecords returns the records that mention one of the modules.
+// Messages printed by a driver are prefixed with its name, which usually is the name of its module.
+func filterKmsgRecords(records []KmsgRecord, modules []string) []KmsgRecord {
+	patterns := make([]string, 0, 2*len(modules))
+
+	for _, m := range modules {
+		if m == "" {
+			continue
+		}
+
+		name := normalizeModuleName(m)
+
+		patterns = append(patterns, name, strings.ReplaceAll(name, "_", "-"))
+	}
+
+	filtered := make([]KmsgRecord, 0)
+
+	for _, r := range records {
+		for _, p := range patterns {
+			if strings.Contains(r.Message, p) {
+				filtered = append(filtered, r)
+				break
+			}
+		}
+	}
+
+	return filtered
+}
+
+// devKmsg reads /dev/kmsg without blocking; it returns io.EOF once all available records were read.
+type devKmsg struct {
+	fd int
+}
+
+func openDevKmsg() (io.ReadCloser, error) {
+	fd, err := unix.Open(kmsgPath, unix.O_RDONLY|unix.O_NONBLOCK|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, fmt.Errorf("could not open %s: %v", kmsgPath, err)
+	}
+
+	// Skip the records that were written before we started.
+	if _, err = unix.Seek(fd, 0, unix.SEEK_END); err != nil {
+		unix.Close(fd)
+		return nil, fmt.Errorf("could not seek to the end of %s: %v", kmsgPath, err)
+	}
+
+	return &devKmsg{fd: fd}, nil
+}
+
+func (d *devKmsg) Read(p []byte) (int, error) {
+	for {
+		n, err := unix.Read(d.fd, p)
+
+		switch {
+		case errors.Is(err, unix.EAGAIN):
+			return 0, io.EOF
+		case errors.Is(err, unix.EINTR), errors.Is(err, unix.EPIPE):
+			// EPIPE means that records were overwritten before we could read them; carry on with the next ones.
+			continue
+		case err != nil:
+			return 0, err
+		case n == 0:
+			return 0, io.EOF
+		}
+
+		return n, nil
+	}
+}
+
+func (d *devKmsg) Close() error {
+	return unix.Close(d.fd)
+}
@@ -0,0 +1,86 @@
+package worker
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// newFakeKmsgWatcher returns a KmsgWatcher whose captures return the records in kmsg, in the /dev/kmsg format.
+func newFakeKmsgWatcher(kmsg string) KmsgWatcher {
+	return &kmsgWatcher{
+		open: func() (io.ReadCloser, error) {
+			return io.NopCloser(strings.NewReader(kmsg)), nil
+		},
+	}
+}
+
+const testKmsg = `6,1001,5000000,-;kmm_test: loading out-of-tree module taints kernel.
+4,1002,5000100,-;kmm_test: module verification failed: signature and/or required key missing - tainting kernel
+ SUBSYSTEM=module
+6,1003,5000200,-;e1000e 0000:00:1f.6 eno1: NIC Link is Up 1000 Mbps Full Duplex
+3,1004,5000300,c;kmm-dep: disagrees about version of symbol module_layout
+malformed line
+`
+
+var _ = Describe("kmsgCapture_Stop", func() {
+	It("should return the parsed records", func() {
+		kc, err := newFakeKmsgWatcher(testKmsg).Start()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(
+			kc.Stop(),
+		).To(
+			Equal([]KmsgRecord{
+				{Priority: 6, Sequence: 1001, Timestamp: 5 * time.Second, Message: "kmm_test: loading out-of-tree module taints kernel."},
+				{
+					Priority:  4,
+					Sequence:  1002,
+					Timestamp: 5*time.Second + 100*time.Microsecond,
+					Message:   "kmm_test: module verification failed: signature and/or required key missing - tainting kernel",
+				},
+				{Priority: 6, Sequence: 1003, Timestamp: 5*time.Second + 200*time.Microsecond, Message: "e1000e 0000:00:1f.6 eno1: NIC Link is Up 1000 Mbps Full Duplex"},
+				{Priority: 3, Sequence: 1004, Timestamp: 5*time.Second + 300*time.Microsecond, Message: "kmm-dep: disagrees about version of symbol module_layout"},
+			}),
+		)
+	})
+
+	It("should return an error if the kernel log cannot be opened", func() {
+		kw := &kmsgWatcher{
+			open: func() (io.ReadCloser, error) {
+				return nil, errors.New("permission denied")
+			},
+		}
+
+		_, err := kw.Start()
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("filterKmsgRecords", func() {
+	records := parseKmsgRecords(testKmsg)
+
+	It("should keep the records mentioning one of the modules", func() {
+		Expect(
+			filterKmsgRecords(records, []string{"kmm-test", "kmm_dep", ""}),
+		).To(
+			Equal([]KmsgRecord{records[0], records[1], records[3]}),
+		)
+	})
+
+	It("should return an empty slice if no module is given", func() {
+		Expect(filterKmsgRecords(records, nil)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("KmsgRecord_String", func() {
+	It("should print the timestamp like dmesg", func() {
+		r := KmsgRecord{Timestamp: 5*time.Second + 100*time.Microsecond, Message: "kmm_test: some message"}
+
+		Expect(r.String()).To(Equal("[    5.000100] kmm_test: some message"))
+	})
+})
@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: extract.go
+//
+// Generated by this command:
+//
+//	mockgen -source=extract.go -package=worker -destination=mock_extract.go
+//
+// Package worker is a generated GoMock package.
+package worker
+
+import (
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockFileExtractor is a mock of FileExtractor interface.
+type MockFileExtractor struct {
+	ctrl     *gomock.Controller
+	recorder *MockFileExtractorMockRecorder
+}
+
+// MockFileExtractorMockRecorder is the mock recorder for MockFileExtractor.
+type MockFileExtractorMockRecorder struct {
+	mock *MockFileExtractor
+}
+
+// NewMockFileExtractor creates a new mock instance.
+func NewMockFileExtractor(ctrl *gomock.Controller) *MockFileExtractor {
+	mock := &MockFileExtractor{ctrl: ctrl}
+	mock.recorder = &MockFileExtractorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFileExtractor) EXPECT() *MockFileExtractorMockRecorder {
+	return m.recorder
+}
+
+// CopyExecutable mocks base method.
+func (m *MockFileExtractor) CopyExecutable(dst string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CopyExecutable", dst)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CopyExecutable indicates an expected call of CopyExecutable.
+func (mr *MockFileExtractorMockRecorder) CopyExecutable(dst any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyExecutable", reflect.TypeOf((*MockFileExtractor)(nil).CopyExecutable), dst)
+}
+
+// Extract mocks base method.
+func (m *MockFileExtractor) Extract(srcRoot, dstDir string, paths []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Extract", srcRoot, dstDir, paths)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Extract indicates an expected call of Extract.
+func (mr *MockFileExtractorMockRecorder) Extract(srcRoot, dstDir, paths any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Extract", reflect.TypeOf((*MockFileExtractor)(nil).Extract), srcRoot, dstDir, paths)
+}
@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: firmware.go
+//
+// Generated by this command:
+//
+//	mockgen -source=firmware.go -package=worker -destination=mock_firmware.go
+//
+// Package worker is a generated GoMock package.
+package worker
+
+import (
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockFirmwareInstaller is a mock of FirmwareInstaller interface.
+type MockFirmwareInstaller struct {
+	ctrl     *gomock.Controller
+	recorder *MockFirmwareInstallerMockRecorder
+}
+
+// MockFirmwareInstallerMockRecorder is the mock recorder for MockFirmwareInstaller.
+type MockFirmwareInstallerMockRecorder struct {
+	mock *MockFirmwareInstaller
+}
+
+// NewMockFirmwareInstaller creates a new mock instance.
+func NewMockFirmwareInstaller(ctrl *gomock.Controller) *MockFirmwareInstaller {
+	mock := &MockFirmwareInstaller{ctrl: ctrl}
+	mock.recorder = &MockFirmwareInstallerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFirmwareInstaller) EXPECT() *MockFirmwareInstallerMockRecorder {
+	return m.recorder
+}
+
+// Install mocks base method.
+func (m *MockFirmwareInstaller) Install(module, srcDir, dstDir string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Install", module, srcDir, dstDir)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Install indicates an expected call of Install.
+func (mr *MockFirmwareInstallerMockRecorder) Install(module, srcDir, dstDir any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Install", reflect.TypeOf((*MockFirmwareInstaller)(nil).Install), module, srcDir, dstDir)
+}
+
+// Remove mocks base method.
+func (m *MockFirmwareInstaller) Remove(module, srcDir, dstDir string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Remove", module, srcDir, dstDir)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Remove indicates an expected call of Remove.
+func (mr *MockFirmwareInstallerMockRecorder) Remove(module, srcDir, dstDir any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Remove", reflect.TypeOf((*MockFirmwareInstaller)(nil).Remove), module, srcDir, dstDir)
+}
@@ -0,0 +1,114 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: kmodstate.go
+//
+// Generated by this command:
+//
+//	mockgen -source=kmodstate.go -package=worker -destination=mock_kmodstate.go
+//
+// Package worker is a generated GoMock package.
+package worker
+
+import (
+	reflect "reflect"
+
+	modinfo "github.com/rh-ecosystem-edge/kernel-module-management/internal/modinfo"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockModuleStateReader is a mock of ModuleStateReader interface.
+type MockModuleStateReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockModuleStateReaderMockRecorder
+}
+
+// MockModuleStateReaderMockRecorder is the mock recorder for MockModuleStateReader.
+type MockModuleStateReaderMockRecorder struct {
+	mock *MockModuleStateReader
+}
+
+// NewMockModuleStateReader creates a new mock instance.
+func NewMockModuleStateReader(ctrl *gomock.Controller) *MockModuleStateReader {
+	mock := &MockModuleStateReader{ctrl: ctrl}
+	mock.recorder = &MockModuleStateReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockModuleStateReader) EXPECT() *MockModuleStateReaderMockRecorder {
+	return m.recorder
+}
+
+// GetBootID mocks base method.
+func (m *MockModuleStateReader) GetBootID() (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBootID")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBootID indicates an expected call of GetBootID.
+func (mr *MockModuleStateReaderMockRecorder) GetBootID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBootID", reflect.TypeOf((*MockModuleStateReader)(nil).GetBootID))
+}
+
+// GetImageModuleInfo mocks base method.
+func (m *MockModuleStateReader) GetImageModuleInfo(modulesDir, name string) (*modinfo.ModuleInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImageModuleInfo", modulesDir, name)
+	ret0, _ := ret[0].(*modinfo.ModuleInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetImageModuleInfo indicates an expected call of GetImageModuleInfo.
+func (mr *MockModuleStateReaderMockRecorder) GetImageModuleInfo(modulesDir, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImageModuleInfo", reflect.TypeOf((*MockModuleStateReader)(nil).GetImageModuleInfo), modulesDir, name)
+}
+
+// GetImageSrcVersion mocks base method.
+func (m *MockModuleStateReader) GetImageSrcVersion(modulesDir, name string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImageSrcVersion", modulesDir, name)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetImageSrcVersion indicates an expected call of GetImageSrcVersion.
+func (mr *MockModuleStateReaderMockRecorder) GetImageSrcVersion(modulesDir, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImageSrcVersion", reflect.TypeOf((*MockModuleStateReader)(nil).GetImageSrcVersion), modulesDir, name)
+}
+
+// GetModuleProcesses mocks base method.
+func (m *MockModuleStateReader) GetModuleProcesses(name string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetModuleProcesses", name)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetModuleProcesses indicates an expected call of GetModuleProcesses.
+func (mr *MockModuleStateReaderMockRecorder) GetModuleProcesses(name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetModuleProcesses", reflect.TypeOf((*MockModuleStateReader)(nil).GetModuleProcesses), name)
+}
+
+// GetModuleState mocks base method.
+func (m *MockModuleStateReader) GetModuleState(name string) (*ModuleState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetModuleState", name)
+	ret0, _ := ret[0].(*ModuleState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetModuleState indicates an expected call of GetModuleState.
+func (mr *MockModuleStateReaderMockRecorder) GetModuleState(name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetModuleState", reflect.TypeOf((*MockModuleStateReader)(nil).GetModuleState), name)
+}
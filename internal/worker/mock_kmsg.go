@@ -0,0 +1,91 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: kmsg.go
+//
+// Generated by this command:
+//
+//	mockgen -source=kmsg.go -package=worker -destination=mock_kmsg.go
+//
+// Package worker is a generated GoMock package.
+package worker
+
+import (
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockKmsgCapture is a mock of KmsgCapture interface.
+type MockKmsgCapture struct {
+	ctrl     *gomock.Controller
+	recorder *MockKmsgCaptureMockRecorder
+}
+
+// MockKmsgCaptureMockRecorder is the mock recorder for MockKmsgCapture.
+type MockKmsgCaptureMockRecorder struct {
+	mock *MockKmsgCapture
+}
+
+// NewMockKmsgCapture creates a new mock instance.
+func NewMockKmsgCapture(ctrl *gomock.Controller) *MockKmsgCapture {
+	mock := &MockKmsgCapture{ctrl: ctrl}
+	mock.recorder = &MockKmsgCaptureMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockKmsgCapture) EXPECT() *MockKmsgCaptureMockRecorder {
+	return m.recorder
+}
+
+// Stop mocks base method.
+func (m *MockKmsgCapture) Stop() ([]KmsgRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stop")
+	ret0, _ := ret[0].([]KmsgRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Stop indicates an expected call of Stop.
+func (mr *MockKmsgCaptureMockRecorder) Stop() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockKmsgCapture)(nil).Stop))
+}
+
+// MockKmsgWatcher is a mock of KmsgWatcher interface.
+type MockKmsgWatcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockKmsgWatcherMockRecorder
+}
+
+// MockKmsgWatcherMockRecorder is the mock recorder for MockKmsgWatcher.
+type MockKmsgWatcherMockRecorder struct {
+	mock *MockKmsgWatcher
+}
+
+// NewMockKmsgWatcher creates a new mock instance.
+func NewMockKmsgWatcher(ctrl *gomock.Controller) *MockKmsgWatcher {
+	mock := &MockKmsgWatcher{ctrl: ctrl}
+	mock.recorder = &MockKmsgWatcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockKmsgWatcher) EXPECT() *MockKmsgWatcherMockRecorder {
+	return m.recorder
+}
+
+// Start mocks base method.
+func (m *MockKmsgWatcher) Start() (KmsgCapture, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Start")
+	ret0, _ := ret[0].(KmsgCapture)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Start indicates an expected call of Start.
+func (mr *MockKmsgWatcherMockRecorder) Start() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockKmsgWatcher)(nil).Start))
+}
@@ -38,16 +38,52 @@ func (m *MockModprobeRunner) EXPECT() *MockModprobeRunnerMockRecorder {
 	return m.recorder
 }
 
+// Insmod mocks base method.
+func (m *MockModprobeRunner) Insmod(ctx context.Context, path string, params ...string) (*ModprobeResult, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, path}
+	for _, a := range params {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Insmod", varargs...)
+	ret0, _ := ret[0].(*ModprobeResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Insmod indicates an expected call of Insmod.
+func (mr *MockModprobeRunnerMockRecorder) Insmod(ctx, path any, params ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, path}, params...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Insmod", reflect.TypeOf((*MockModprobeRunner)(nil).Insmod), varargs...)
+}
+
+// Rmmod mocks base method.
+func (m *MockModprobeRunner) Rmmod(ctx context.Context, name string) (*ModprobeResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rmmod", ctx, name)
+	ret0, _ := ret[0].(*ModprobeResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Rmmod indicates an expected call of Rmmod.
+func (mr *MockModprobeRunnerMockRecorder) Rmmod(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rmmod", reflect.TypeOf((*MockModprobeRunner)(nil).Rmmod), ctx, name)
+}
+
 // Run mocks base method.
-func (m *MockModprobeRunner) Run(ctx context.Context, args ...string) error {
+func (m *MockModprobeRunner) Run(ctx context.Context, args ...string) (*ModprobeResult, error) {
 	m.ctrl.T.Helper()
 	varargs := []any{ctx}
 	for _, a := range args {
 		varargs = append(varargs, a)
 	}
 	ret := m.ctrl.Call(m, "Run", varargs...)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(*ModprobeResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // Run indicates an expected call of Run.
@@ -0,0 +1,53 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: params.go
+//
+// Generated by this command:
+//
+//	mockgen -source=params.go -package=worker -destination=mock_params.go
+//
+// Package worker is a generated GoMock package.
+package worker
+
+import (
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockModuleParametersWriter is a mock of ModuleParametersWriter interface.
+type MockModuleParametersWriter struct {
+	ctrl     *gomock.Controller
+	recorder *MockModuleParametersWriterMockRecorder
+}
+
+// MockModuleParametersWriterMockRecorder is the mock recorder for MockModuleParametersWriter.
+type MockModuleParametersWriterMockRecorder struct {
+	mock *MockModuleParametersWriter
+}
+
+// NewMockModuleParametersWriter creates a new mock instance.
+func NewMockModuleParametersWriter(ctrl *gomock.Controller) *MockModuleParametersWriter {
+	mock := &MockModuleParametersWriter{ctrl: ctrl}
+	mock.recorder = &MockModuleParametersWriterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockModuleParametersWriter) EXPECT() *MockModuleParametersWriterMockRecorder {
+	return m.recorder
+}
+
+// WriteParameters mocks base method.
+func (m *MockModuleParametersWriter) WriteParameters(module string, params []string) (map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteParameters", module, params)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WriteParameters indicates an expected call of WriteParameters.
+func (mr *MockModuleParametersWriterMockRecorder) WriteParameters(module, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteParameters", reflect.TypeOf((*MockModuleParametersWriter)(nil).WriteParameters), module, params)
+}
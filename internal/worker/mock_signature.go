@@ -0,0 +1,53 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: signature.go
+//
+// Generated by this command:
+//
+//	mockgen -source=signature.go -package=worker -destination=mock_signature.go
+//
+// Package worker is a generated GoMock package.
+package worker
+
+import (
+	reflect "reflect"
+
+	v1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSignatureVerifier is a mock of SignatureVerifier interface.
+type MockSignatureVerifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockSignatureVerifierMockRecorder
+}
+
+// MockSignatureVerifierMockRecorder is the mock recorder for MockSignatureVerifier.
+type MockSignatureVerifierMockRecorder struct {
+	mock *MockSignatureVerifier
+}
+
+// NewMockSignatureVerifier creates a new mock instance.
+func NewMockSignatureVerifier(ctrl *gomock.Controller) *MockSignatureVerifier {
+	mock := &MockSignatureVerifier{ctrl: ctrl}
+	mock.recorder = &MockSignatureVerifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSignatureVerifier) EXPECT() *MockSignatureVerifierMockRecorder {
+	return m.recorder
+}
+
+// VerifyModules mocks base method.
+func (m *MockSignatureVerifier) VerifyModules(paths []string, spec *v1beta1.SignatureVerificationSpec) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyModules", paths, spec)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyModules indicates an expected call of VerifyModules.
+func (mr *MockSignatureVerifierMockRecorder) VerifyModules(paths, spec any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyModules", reflect.TypeOf((*MockSignatureVerifier)(nil).VerifyModules), paths, spec)
+}
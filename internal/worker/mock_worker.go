@@ -39,6 +39,35 @@ func (m *MockWorker) EXPECT() *MockWorkerMockRecorder {
 	return m.recorder
 }
 
+// CheckKmod mocks base method.
+func (m *MockWorker) CheckKmod(cfg *v1beta1.ModuleConfig) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckKmod", cfg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckKmod indicates an expected call of CheckKmod.
+func (mr *MockWorkerMockRecorder) CheckKmod(cfg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckKmod", reflect.TypeOf((*MockWorker)(nil).CheckKmod), cfg)
+}
+
+// GetKmodStatus mocks base method.
+func (m *MockWorker) GetKmodStatus(cfg *v1beta1.ModuleConfig) (*KmodStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetKmodStatus", cfg)
+	ret0, _ := ret[0].(*KmodStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetKmodStatus indicates an expected call of GetKmodStatus.
+func (mr *MockWorkerMockRecorder) GetKmodStatus(cfg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKmodStatus", reflect.TypeOf((*MockWorker)(nil).GetKmodStatus), cfg)
+}
+
 // LoadKmod mocks base method.
 func (m *MockWorker) LoadKmod(ctx context.Context, cfg *v1beta1.ModuleConfig, firmwareMountPath string) error {
 	m.ctrl.T.Helper()
@@ -53,6 +82,48 @@ func (mr *MockWorkerMockRecorder) LoadKmod(ctx, cfg, firmwareMountPath any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadKmod", reflect.TypeOf((*MockWorker)(nil).LoadKmod), ctx, cfg, firmwareMountPath)
 }
 
+// RemoveBlacklist mocks base method.
+func (m *MockWorker) RemoveBlacklist(path string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveBlacklist", path)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveBlacklist indicates an expected call of RemoveBlacklist.
+func (mr *MockWorkerMockRecorder) RemoveBlacklist(path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveBlacklist", reflect.TypeOf((*MockWorker)(nil).RemoveBlacklist), path)
+}
+
+// RestoreInTreeModules mocks base method.
+func (m *MockWorker) RestoreInTreeModules(ctx context.Context, modules []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreInTreeModules", ctx, modules)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreInTreeModules indicates an expected call of RestoreInTreeModules.
+func (mr *MockWorkerMockRecorder) RestoreInTreeModules(ctx, modules any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreInTreeModules", reflect.TypeOf((*MockWorker)(nil).RestoreInTreeModules), ctx, modules)
+}
+
+// Result mocks base method.
+func (m *MockWorker) Result() *v1beta1.WorkerResult {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Result")
+	ret0, _ := ret[0].(*v1beta1.WorkerResult)
+	return ret0
+}
+
+// Result indicates an expected call of Result.
+func (mr *MockWorkerMockRecorder) Result() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Result", reflect.TypeOf((*MockWorker)(nil).Result))
+}
+
 // SetFirmwareClassPath mocks base method.
 func (m *MockWorker) SetFirmwareClassPath(value string) error {
 	m.ctrl.T.Helper()
@@ -67,6 +138,20 @@ func (mr *MockWorkerMockRecorder) SetFirmwareClassPath(value any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetFirmwareClassPath", reflect.TypeOf((*MockWorker)(nil).SetFirmwareClassPath), value)
 }
 
+// SetParameters mocks base method.
+func (m *MockWorker) SetParameters(cfg *v1beta1.ModuleConfig) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetParameters", cfg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetParameters indicates an expected call of SetParameters.
+func (mr *MockWorkerMockRecorder) SetParameters(cfg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetParameters", reflect.TypeOf((*MockWorker)(nil).SetParameters), cfg)
+}
+
 // UnloadKmod mocks base method.
 func (m *MockWorker) UnloadKmod(ctx context.Context, cfg *v1beta1.ModuleConfig, firmwareMountPath string) error {
 	m.ctrl.T.Helper()
@@ -80,3 +165,17 @@ func (mr *MockWorkerMockRecorder) UnloadKmod(ctx, cfg, firmwareMountPath any) *g
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnloadKmod", reflect.TypeOf((*MockWorker)(nil).UnloadKmod), ctx, cfg, firmwareMountPath)
 }
+
+// WriteBlacklist mocks base method.
+func (m *MockWorker) WriteBlacklist(cfg *v1beta1.ModuleConfig, path string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteBlacklist", cfg, path)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WriteBlacklist indicates an expected call of WriteBlacklist.
+func (mr *MockWorkerMockRecorder) WriteBlacklist(cfg, path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteBlacklist", reflect.TypeOf((*MockWorker)(nil).WriteBlacklist), cfg, path)
+}
@@ -0,0 +1,253 @@
+package worker
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/modinfo"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	modulesDepFileName    = "modules.dep"
+	modulesDepBinFileName = "modules.dep.bin"
+)
+
+type moduleDep struct {
+	// Path is the path of the module file, relative to the modules.dep directory.
+	Path string
+	// Deps holds the paths of the modules this module depends on, in the order listed by depmod.
+	Deps []string
+}
+
+// moduleNameFromPath returns the normalized name of a kernel module from its file path.
+// For example, /lib/modules/5.14.0/kernel/drivers/net/my-mod.ko.xz becomes my_mod.
+func moduleNameFromPath(path string) string {
+	name := filepath.Base(path)
+
+	for _, ext := range []string{".gz", ".xz", ".zst"} {
+		name = strings.TrimSuffix(name, ext)
+	}
+
+	return normalizeModuleName(strings.TrimSuffix(name, ".ko"))
+}
+
+// normalizeModuleName replaces dashes with underscores, as the kernel does.
+func normalizeModuleName(name string) string {
+	return strings.ReplaceAll(name, "-", "_")
+}
+
+// readModulesDep parses the modules.dep file located in dir and returns its entries indexed by normalized module name.
+func readModulesDep(dir string) (map[string]moduleDep, error) {
+	path := filepath.Join(dir, modulesDepFileName)
+
+	fd, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not open %s: %w", path, err)
+	}
+	defer fd.Close()
+
+	entries := make(map[string]moduleDep)
+
+	s := bufio.NewScanner(fd)
+
+	for lineNum := 1; s.Scan(); lineNum++ {
+		line := strings.TrimSpace(s.Text())
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		modPath, deps, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("%s:%d: missing colon", path, lineNum)
+		}
+
+		entries[moduleNameFromPath(modPath)] = moduleDep{
+			Path: modPath,
+			Deps: strings.Fields(deps),
+		}
+	}
+
+	if err = s.Err(); err != nil {
+		return nil, fmt.Errorf("could not read %s: %v", path, err)
+	}
+
+	return entries, nil
+}
+
+// isModuleFile returns true if path has the extension of a, possibly compressed, kernel module file.
+func isModuleFile(path string) bool {
+	for _, ext := range []string{".ko", ".ko.gz", ".ko.xz", ".ko.zst"} {
+		if strings.HasSuffix(path, ext) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// findModuleFiles returns the paths, relative to dir, of all kernel module files under dir.
+func findModuleFiles(dir string) ([]string, error) {
+	var paths []string
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() || !isModuleFile(path) {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		paths = append(paths, rel)
+
+		return nil
+	})
+
+	return paths, err
+}
+
+// modulesDepStale returns true if dir has no modules.dep file, or if a kernel module file under dir is not listed in
+// it; this is typically the case for images that ship module files without running depmod.
+func modulesDepStale(dir string) (bool, error) {
+	deps, err := readModulesDep(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return true, nil
+		}
+
+		return false, err
+	}
+
+	listed := sets.New[string]()
+
+	for _, md := range deps {
+		listed.Insert(md.Path)
+	}
+
+	paths, err := findModuleFiles(dir)
+	if err != nil {
+		return false, fmt.Errorf("could not look for module files in %s: %v", dir, err)
+	}
+
+	for _, p := range paths {
+		if !listed.Has(p) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// generateModulesDep writes the modules.dep file of dir, like depmod does, from the dependencies that the kernel
+// build records in the .modinfo section of each module file.
+// Dependencies that are not shipped under dir are expected to be loaded already and are not listed.
+// Any modules.dep.bin index is removed, as modprobe would prefer it to the new modules.dep file.
+func generateModulesDep(dir string) error {
+	paths, err := findModuleFiles(dir)
+	if err != nil {
+		return fmt.Errorf("could not look for module files in %s: %v", dir, err)
+	}
+
+	pathByName := make(map[string]string, len(paths))
+	depends := make(map[string][]string, len(paths))
+
+	for _, p := range paths {
+		info, err := modinfo.ReadFile(filepath.Join(dir, p))
+		if err != nil {
+			return fmt.Errorf("could not read the module information: %v", err)
+		}
+
+		name := moduleNameFromPath(p)
+
+		if _, ok := pathByName[name]; ok {
+			// like depmod, only consider the first file for a module name
+			continue
+		}
+
+		pathByName[name] = p
+		depends[name] = info.Depends
+	}
+
+	buf := bytes.Buffer{}
+
+	// sort for a stable output
+	sort.Strings(paths)
+
+	for _, p := range paths {
+		name := moduleNameFromPath(p)
+
+		if pathByName[name] != p {
+			continue
+		}
+
+		// depmod lists dependencies so that the last one must be loaded first.
+		var ordered []string
+
+		visited := sets.New(name)
+
+		var visit func(string)
+
+		visit = func(n string) {
+			for _, d := range depends[n] {
+				d = normalizeModuleName(d)
+
+				if visited.Has(d) {
+					continue
+				}
+
+				visited.Insert(d)
+
+				visit(d)
+
+				if dp, ok := pathByName[d]; ok {
+					ordered = append(ordered, dp)
+				}
+			}
+		}
+
+		visit(name)
+
+		// visit puts dependencies before the modules that need them
+		slices.Reverse(ordered)
+
+		fmt.Fprintf(&buf, "%s:", p)
+
+		for _, d := range ordered {
+			fmt.Fprintf(&buf, " %s", d)
+		}
+
+		buf.WriteString("\n")
+	}
+
+	path := filepath.Join(dir, modulesDepFileName)
+
+	err = writeFileAtomically(path, 0644, func(w io.Writer) error {
+		_, err := w.Write(buf.Bytes())
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = os.Remove(filepath.Join(dir, modulesDepBinFileName)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("could not remove the outdated %s: %v", modulesDepBinFileName, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package worker
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// copyModDepTestData copies the module files of testdata/moddep, which have no modules.dep, to dir.
+func copyModDepTestData(dir string) {
+	GinkgoHelper()
+
+	err := filepath.WalkDir("testdata/moddep", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		rel, err := filepath.Rel("testdata/moddep", path)
+		if err != nil {
+			return err
+		}
+
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		if err = os.MkdirAll(filepath.Dir(filepath.Join(dir, rel)), 0755); err != nil {
+			return err
+		}
+
+		return os.WriteFile(filepath.Join(dir, rel), b, 0644)
+	})
+	Expect(err).NotTo(HaveOccurred())
+}
+
+var _ = Describe("modulesDepStale", func() {
+	var dir string
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		copyModDepTestData(dir)
+	})
+
+	It("should return true if modules.dep is missing", func() {
+		Expect(modulesDepStale(dir)).To(BeTrue())
+	})
+
+	It("should return true if a module file is not listed in modules.dep", func() {
+		const modulesDep = `kernel/drivers/kmm-core.ko:
+extra/kmm-transport.ko: kernel/drivers/kmm-core.ko
+`
+
+		Expect(os.WriteFile(filepath.Join(dir, modulesDepFileName), []byte(modulesDep), 0644)).To(Succeed())
+
+		Expect(modulesDepStale(dir)).To(BeTrue())
+	})
+
+	It("should return false if all module files are listed in modules.dep", func() {
+		Expect(modulesDepStale(testModulesDir)).To(BeFalse())
+	})
+
+	It("should return an error if modules.dep cannot be parsed", func() {
+		Expect(os.WriteFile(filepath.Join(dir, modulesDepFileName), []byte("no colon\n"), 0644)).To(Succeed())
+
+		_, err := modulesDepStale(dir)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("generateModulesDep", func() {
+	It("should list all modules with their dependencies, the first to load last", func() {
+		dir := GinkgoT().TempDir()
+		copyModDepTestData(dir)
+
+		Expect(os.WriteFile(filepath.Join(dir, modulesDepBinFileName), []byte("outdated"), 0644)).To(Succeed())
+
+		Expect(generateModulesDep(dir)).To(Succeed())
+
+		Expect(
+			os.ReadFile(filepath.Join(dir, modulesDepFileName)),
+		).To(
+			BeEquivalentTo(`extra/kmm-stack.ko: extra/kmm-transport.ko kernel/drivers/kmm-core.ko
+extra/kmm-transport.ko: kernel/drivers/kmm-core.ko
+kernel/drivers/kmm-core.ko:
+`),
+		)
+
+		Expect(filepath.Join(dir, modulesDepBinFileName)).NotTo(BeAnExistingFile())
+		Expect(modulesDepStale(dir)).To(BeFalse())
+
+		deps, err := readModulesDep(dir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deps).To(HaveKeyWithValue("kmm_stack", moduleDep{
+			Path: "extra/kmm-stack.ko",
+			Deps: []string{"extra/kmm-transport.ko", "kernel/drivers/kmm-core.ko"},
+		}))
+	})
+
+	It("should return an error if a module file cannot be parsed", func() {
+		dir := GinkgoT().TempDir()
+
+		Expect(os.WriteFile(filepath.Join(dir, "broken.ko"), []byte("not an ELF file"), 0644)).To(Succeed())
+
+		Expect(generateModulesDep(dir)).NotTo(Succeed())
+	})
+})
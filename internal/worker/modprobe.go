@@ -4,14 +4,30 @@ import (
 	"context"
 	"fmt"
 	"os/exec"
+	"strings"
 
 	"github.com/go-logr/logr"
 )
 
 //go:generate mockgen -source=modprobe.go -package=worker -destination=mock_modprobe.go
 
+// ModprobeResult describes the outcome of a modprobe invocation.
+type ModprobeResult struct {
+	ExitCode int
+	// Stderr holds the last lines printed by modprobe on stderr.
+	Stderr []string
+	// Modules holds the names of the modules that were inserted or removed.
+	Modules []string
+
+	stdout []string
+}
+
 type ModprobeRunner interface {
-	Run(ctx context.Context, args ...string) error
+	Run(ctx context.Context, args ...string) (*ModprobeResult, error)
+	// Insmod loads the module file at path, without resolving its dependencies.
+	Insmod(ctx context.Context, path string, params ...string) (*ModprobeResult, error)
+	// Rmmod unloads a module, without unloading its dependencies.
+	Rmmod(ctx context.Context, name string) (*ModprobeResult, error)
 }
 
 type modprobeRunnerImpl struct {
@@ -22,27 +38,121 @@ func NewModprobeRunner(logger logr.Logger) ModprobeRunner {
 	return &modprobeRunnerImpl{logger: logger}
 }
 
-func (mr *modprobeRunnerImpl) Run(ctx context.Context, args ...string) error {
-	cmd := exec.CommandContext(ctx, "modprobe", args...)
+func (mr *modprobeRunnerImpl) Run(ctx context.Context, args ...string) (*ModprobeResult, error) {
+	res, err := mr.run(ctx, "modprobe", args...)
+	if res != nil {
+		res.Modules = modulesFromVerboseOutput(res.stdout)
+	}
 
-	cl, err := NewCommandLogger(cmd, mr.logger.WithName("modprobe"))
+	return res, err
+}
+
+func (mr *modprobeRunnerImpl) Insmod(ctx context.Context, path string, params ...string) (*ModprobeResult, error) {
+	res, err := mr.run(ctx, "insmod", append([]string{path}, params...)...)
+	if err == nil {
+		res.Modules = []string{moduleNameFromPath(path)}
+	}
+
+	return res, err
+}
+
+func (mr *modprobeRunnerImpl) Rmmod(ctx context.Context, name string) (*ModprobeResult, error) {
+	res, err := mr.run(ctx, "rmmod", name)
+	if err == nil {
+		res.Modules = []string{normalizeModuleName(name)}
+	}
+
+	return res, err
+}
+
+// run runs one of the kmod binaries and maps the error messages it prints to typed errors.
+func (mr *modprobeRunnerImpl) run(ctx context.Context, binary string, args ...string) (*ModprobeResult, error) {
+	cmd := exec.CommandContext(ctx, binary, args...)
+
+	cl, err := NewCommandLogger(cmd, mr.logger.WithName(binary))
 	if err != nil {
-		return fmt.Errorf("could not create a command logger: %v", err)
+		return nil, fmt.Errorf("could not create a command logger: %v", err)
 	}
 
-	mr.logger.Info("Running modprobe", "command", cmd.String())
+	mr.logger.Info("Running "+binary, "command", cmd.String())
 
 	if err = cmd.Start(); err != nil {
-		return fmt.Errorf("could not start modprobe: %v", err)
+		return nil, fmt.Errorf("could not start %s: %v", binary, err)
 	}
 
 	if err = cl.Wait(); err != nil {
-		return fmt.Errorf("error while waiting on the command logger: %v", err)
+		return nil, fmt.Errorf("error while waiting on the command logger: %v", err)
 	}
 
-	if err = cmd.Wait(); err != nil {
-		return fmt.Errorf("error while waiting on the command: %v", err)
+	err = cmd.Wait()
+
+	res := ModprobeResult{
+		ExitCode: cmd.ProcessState.ExitCode(),
+		Stderr:   cl.StderrLines(),
+		stdout:   cl.StdoutLines(),
+	}
+
+	if err != nil {
+		if sentinel := modprobeStderrError(res.Stderr); sentinel != nil {
+			err = fmt.Errorf("%w: %w", sentinel, err)
+		}
+
+		return &res, fmt.Errorf("error while waiting on the command: %w", err)
+	}
+
+	return &res, nil
+}
+
+// modulesFromVerboseOutput returns the names of the modules that modprobe -v reported as inserted or removed.
+func modulesFromVerboseOutput(lines []string) []string {
+	var modules []string
+
+	for _, l := range lines {
+		fields := strings.Fields(l)
+		if len(fields) < 2 {
+			continue
+		}
+
+		switch fields[0] {
+		case "insmod":
+			modules = append(modules, moduleNameFromPath(fields[1]))
+		case "rmmod":
+			modules = append(modules, normalizeModuleName(fields[1]))
+		}
+	}
+
+	return modules
+}
+
+// modprobeStderrError maps the error messages printed by modprobe to typed errors.
+// It returns nil if no known message was found.
+func modprobeStderrError(lines []string) error {
+	messages := []struct {
+		substr string
+		err    error
+	}{
+		{substr: "not found in directory", err: ErrModuleNotFound},
+		{substr: "Key was rejected by service", err: ErrKeyRejected},
+		{substr: "Exec format error", err: ErrInvalidModuleFormat},
+		{substr: "Unknown symbol in module", err: ErrUnknownSymbol},
+		{substr: "Invalid argument", err: ErrInvalidParameters},
+		{substr: "is in use", err: ErrModuleInUse},
+		{substr: "Resource temporarily unavailable", err: ErrModuleInUse},
+		{substr: "File exists", err: ErrAlreadyLoaded},
+		{substr: "is not in kernel", err: ErrModuleNotLoaded},
+		{substr: "is not currently loaded", err: ErrModuleNotLoaded},
+	}
+
+	var found error
+
+	// The last message is usually the most relevant.
+	for _, l := range lines {
+		for _, m := range messages {
+			if strings.Contains(l, m.substr) {
+				found = m.err
+			}
+		}
 	}
 
-	return nil
+	return found
 }
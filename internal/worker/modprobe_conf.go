@@ -0,0 +1,139 @@
+package worker
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// modprobeConfDir is where modprobe reads its configuration in the worker container.
+// The software dependencies derived from modulesLoadingOrder are mounted there.
+const modprobeConfDir = "/etc/modprobe.d"
+
+type softDep struct {
+	// Pre holds the modules to load before the module, in order.
+	Pre []string
+	// Post holds the modules to load after the module, in order.
+	Post []string
+}
+
+// modprobeConfig holds the subset of modprobe.d(5) directives understood by the native modprobe runner.
+type modprobeConfig struct {
+	// Options holds the parameters of each module, indexed by normalized module name.
+	Options map[string][]string
+	// SoftDeps holds the software dependencies of each module, indexed by normalized module name.
+	SoftDeps map[string]softDep
+}
+
+// readModprobeConfig parses the *.conf files of dir in lexical order, like modprobe.
+// It returns an error wrapping ErrUnsupportedArgument for directives that the native runner cannot apply, such as
+// install and remove commands; blacklist and alias directives only affect lookups by alias and are ignored.
+// A missing directory results in an empty configuration.
+func readModprobeConfig(dir string) (*modprobeConfig, error) {
+	cfg := modprobeConfig{
+		Options:  make(map[string][]string),
+		SoftDeps: make(map[string]softDep),
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return &cfg, nil
+		}
+
+		return nil, fmt.Errorf("could not read %s: %v", dir, err)
+	}
+
+	names := make([]string, 0, len(entries))
+
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".conf") {
+			names = append(names, e.Name())
+		}
+	}
+
+	sort.Strings(names)
+
+	for _, n := range names {
+		if err = cfg.readFile(filepath.Join(dir, n)); err != nil {
+			return nil, err
+		}
+	}
+
+	return &cfg, nil
+}
+
+func (mc *modprobeConfig) readFile(path string) error {
+	fd, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("could not open %s: %v", path, err)
+	}
+	defer fd.Close()
+
+	s := bufio.NewScanner(fd)
+
+	var line string
+
+	for lineNum := 1; s.Scan(); lineNum++ {
+		// A trailing backslash continues the directive on the next line.
+		if l := strings.TrimSpace(s.Text()); strings.HasSuffix(l, `\`) {
+			line += strings.TrimSuffix(l, `\`) + " "
+			continue
+		} else {
+			line += l
+		}
+
+		fields := strings.Fields(line)
+		line = ""
+
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		if len(fields) < 2 {
+			return fmt.Errorf("%s:%d: %q requires a module name", path, lineNum, fields[0])
+		}
+
+		name := normalizeModuleName(fields[1])
+
+		switch fields[0] {
+		case "alias", "blacklist":
+		case "options":
+			mc.Options[name] = append(mc.Options[name], fields[2:]...)
+		case "softdep":
+			sd := mc.SoftDeps[name]
+
+			var target *[]string
+
+			for _, f := range fields[2:] {
+				switch f {
+				case "pre:":
+					target = &sd.Pre
+				case "post:":
+					target = &sd.Post
+				default:
+					if target == nil {
+						return fmt.Errorf("%s:%d: expected pre: or post: before %q", path, lineNum, f)
+					}
+
+					*target = append(*target, normalizeModuleName(f))
+				}
+			}
+
+			mc.SoftDeps[name] = sd
+		default:
+			return fmt.Errorf("%w: %s:%d: %s directive", ErrUnsupportedArgument, path, lineNum, fields[0])
+		}
+	}
+
+	if err = s.Err(); err != nil {
+		return fmt.Errorf("could not read %s: %v", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package worker
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("readModprobeConfig", func() {
+	var dir string
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+	})
+
+	writeConf := func(name, contents string) {
+		GinkgoHelper()
+
+		Expect(os.WriteFile(filepath.Join(dir, name), []byte(contents), 0644)).To(Succeed())
+	}
+
+	It("should return an empty configuration if the directory does not exist", func() {
+		Expect(
+			readModprobeConfig(filepath.Join(dir, "does-not-exist")),
+		).To(
+			Equal(&modprobeConfig{Options: map[string][]string{}, SoftDeps: map[string]softDep{}}),
+		)
+	})
+
+	It("should parse options and softdep directives of .conf files in order", func() {
+		writeConf("b.conf", "options mod-a key1=value1\nsoftdep mod_a post: mod-d\n")
+		writeConf("a.conf", `# comment
+softdep mod-a pre: mod_b \
+  mod_c
+options mod_a key0=value0
+
+blacklist mod_z
+alias some-alias mod_a
+`)
+		writeConf("ignored.txt", "install mod_a /bin/true\n")
+
+		Expect(
+			readModprobeConfig(dir),
+		).To(
+			Equal(&modprobeConfig{
+				Options: map[string][]string{
+					"mod_a": {"key0=value0", "key1=value1"},
+				},
+				SoftDeps: map[string]softDep{
+					"mod_a": {Pre: []string{"mod_b", "mod_c"}, Post: []string{"mod_d"}},
+				},
+			}),
+		)
+	})
+
+	It("should return ErrUnsupportedArgument for install commands", func() {
+		writeConf("a.conf", "install mod_a /bin/true\n")
+
+		_, err := readModprobeConfig(dir)
+		Expect(err).To(MatchError(ErrUnsupportedArgument))
+	})
+
+	It("should return an error for softdep modules not preceded by pre: or post:", func() {
+		writeConf("a.conf", "softdep mod_a mod_b\n")
+
+		_, err := readModprobeConfig(dir)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,530 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"golang.org/x/sys/unix"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+var (
+	ErrAlreadyLoaded       = errors.New("module already loaded")
+	ErrInvalidModuleFormat = errors.New("invalid module format")
+	ErrInvalidParameters   = errors.New("invalid module parameters")
+	ErrKeyRejected         = errors.New("module signature rejected by the kernel")
+	ErrModuleInUse         = errors.New("module is in use")
+	ErrModuleNotFound      = errors.New("module not found in modules.dep")
+	ErrModuleNotLoaded     = errors.New("module is not loaded")
+	ErrUnknownSymbol       = errors.New("unknown symbol in module or unknown parameter")
+	ErrUnsupportedArgument = errors.New("unsupported modprobe argument")
+)
+
+const sysModuleDir = "/sys/module"
+
+type nativeModprobeRunner struct {
+	confDir      string
+	deleteModule func(name string, flags int) error
+	finitModule  func(fd int, params string, flags int) error
+	logger       logr.Logger
+	sysModuleDir string
+	uname        func(*unix.Utsname) error
+}
+
+// NewNativeModprobeRunner returns a ModprobeRunner that does not depend on the modprobe binary.
+// It understands the subset of modprobe arguments generated by the worker, resolves dependencies from modules.dep,
+// applies the options and softdep directives of /etc/modprobe.d and uses the finit_module and delete_module syscalls
+// to load and unload modules.
+func NewNativeModprobeRunner(logger logr.Logger) ModprobeRunner {
+	return &nativeModprobeRunner{
+		confDir:      modprobeConfDir,
+		deleteModule: unix.DeleteModule,
+		finitModule:  unix.FinitModule,
+		logger:       logger,
+		sysModuleDir: sysModuleDir,
+		uname:        unix.Uname,
+	}
+}
+
+type modprobeInvocation struct {
+	all       bool
+	baseDir   string
+	firstTime bool
+	remove    bool
+	verbose   bool
+
+	// names holds the modules to load or unload.
+	names []string
+	// params holds the parameters to pass to the single module being loaded.
+	params []string
+}
+
+func parseModprobeArgs(args []string) (*modprobeInvocation, error) {
+	inv := modprobeInvocation{baseDir: "/"}
+
+	var positional []string
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		switch {
+		case arg == "--":
+			positional = append(positional, args[i+1:]...)
+			i = len(args)
+		case strings.HasPrefix(arg, "--"):
+			name, value, hasValue := strings.Cut(strings.TrimPrefix(arg, "--"), "=")
+
+			switch name {
+			case "all":
+				inv.all = true
+			case "dirname":
+				if !hasValue {
+					if i+1 >= len(args) {
+						return nil, fmt.Errorf("%w: --dirname requires a value", ErrUnsupportedArgument)
+					}
+
+					i++
+					value = args[i]
+				}
+
+				inv.baseDir = value
+			case "first-time":
+				inv.firstTime = true
+			case "quiet":
+			case "remove":
+				inv.remove = true
+			case "verbose":
+				inv.verbose = true
+			default:
+				return nil, fmt.Errorf("%w: %s", ErrUnsupportedArgument, arg)
+			}
+		case strings.HasPrefix(arg, "-") && len(arg) > 1:
+			flags := arg[1:]
+
+			for j := 0; j < len(flags); j++ {
+				switch flags[j] {
+				case 'a':
+					inv.all = true
+				case 'd':
+					// -d consumes the rest of this argument, or the next one.
+					if rest := flags[j+1:]; rest != "" {
+						inv.baseDir = rest
+					} else if i+1 < len(args) {
+						i++
+						inv.baseDir = args[i]
+					} else {
+						return nil, fmt.Errorf("%w: -d requires a value", ErrUnsupportedArgument)
+					}
+
+					j = len(flags)
+				case 'q':
+				case 'r':
+					inv.remove = true
+				case 'v':
+					inv.verbose = true
+				default:
+					return nil, fmt.Errorf("%w: -%c", ErrUnsupportedArgument, flags[j])
+				}
+			}
+		default:
+			positional = append(positional, arg)
+		}
+	}
+
+	if len(positional) == 0 {
+		return nil, errors.New("no module name specified")
+	}
+
+	// Like modprobe, all positional arguments are module names when removing or when --all is set.
+	// Otherwise, the first one is the module name and the remaining ones are its parameters.
+	if inv.remove || inv.all {
+		inv.names = positional
+	} else {
+		inv.names = positional[:1]
+		inv.params = positional[1:]
+	}
+
+	return &inv, nil
+}
+
+func (nmr *nativeModprobeRunner) Run(ctx context.Context, args ...string) (*ModprobeResult, error) {
+	res := ModprobeResult{}
+
+	if err := nmr.run(ctx, &res, args); err != nil {
+		// mimic modprobe's exit code
+		res.ExitCode = 1
+		return &res, err
+	}
+
+	return &res, nil
+}
+
+func (nmr *nativeModprobeRunner) Insmod(ctx context.Context, path string, params ...string) (*ModprobeResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := nmr.insertFile(nmr.logger, &modprobeInvocation{verbose: true}, path, strings.Join(params, " ")); err != nil {
+		// mimic insmod's exit code
+		return &ModprobeResult{ExitCode: 1}, err
+	}
+
+	return &ModprobeResult{Modules: []string{moduleNameFromPath(path)}}, nil
+}
+
+func (nmr *nativeModprobeRunner) Rmmod(ctx context.Context, name string) (*ModprobeResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	name = normalizeModuleName(name)
+
+	nmr.logger.Info("rmmod", "name", name)
+
+	if err := nmr.deleteModule(name, unix.O_NONBLOCK); err != nil {
+		if errors.Is(err, unix.ENOENT) {
+			err = fmt.Errorf("%w: %s", ErrModuleNotLoaded, name)
+		} else {
+			err = fmt.Errorf("could not remove %s: %w", name, syscallError(err))
+		}
+
+		// mimic rmmod's exit code
+		return &ModprobeResult{ExitCode: 1}, err
+	}
+
+	return &ModprobeResult{Modules: []string{name}}, nil
+}
+
+func (nmr *nativeModprobeRunner) run(ctx context.Context, res *ModprobeResult, args []string) error {
+	inv, err := parseModprobeArgs(args)
+	if err != nil {
+		return fmt.Errorf("could not parse modprobe arguments %v: %w", args, err)
+	}
+
+	kernelVersion, err := nmr.kernelVersion()
+	if err != nil {
+		return fmt.Errorf("could not determine the kernel version: %v", err)
+	}
+
+	modulesDir := filepath.Join(inv.baseDir, "lib", "modules", kernelVersion)
+
+	logger := nmr.logger.WithValues("modules directory", modulesDir)
+
+	logger.Info("Running native modprobe", "args", args)
+
+	deps, err := readModulesDep(modulesDir)
+	if err != nil {
+		// modules.dep is only strictly required when loading
+		if !inv.remove {
+			return fmt.Errorf("could not read the module dependencies: %v", err)
+		}
+
+		logger.Info("Could not read modules.dep; dependencies will not be removed", "error", err)
+	}
+
+	conf, err := readModprobeConfig(nmr.confDir)
+	if err != nil {
+		return fmt.Errorf("could not read the modprobe configuration: %w", err)
+	}
+
+	op := nativeOperation{
+		conf:       conf,
+		deps:       deps,
+		inv:        inv,
+		logger:     logger,
+		modulesDir: modulesDir,
+		res:        res,
+		visited:    sets.New[string](),
+	}
+
+	for _, name := range inv.names {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+
+		if inv.remove {
+			err = nmr.removeWithSoftDeps(&op, normalizeModuleName(name), true)
+		} else {
+			err = nmr.insertWithSoftDeps(&op, normalizeModuleName(name), true)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// nativeOperation holds the state shared by the modules loaded or unloaded by one modprobe invocation.
+type nativeOperation struct {
+	conf       *modprobeConfig
+	deps       map[string]moduleDep
+	inv        *modprobeInvocation
+	logger     logr.Logger
+	modulesDir string
+	res        *ModprobeResult
+	// visited holds the modules already processed, so that cyclic software dependencies terminate.
+	visited sets.Set[string]
+}
+
+// insertWithSoftDeps loads the pre software dependencies of a module, then the module, then its post software
+// dependencies.
+// requested is true for modules passed on the command line; only they get the command-line parameters and are subject
+// to --first-time.
+func (nmr *nativeModprobeRunner) insertWithSoftDeps(op *nativeOperation, name string, requested bool) error {
+	if op.visited.Has(name) {
+		return nil
+	}
+
+	op.visited.Insert(name)
+
+	sd := op.conf.SoftDeps[name]
+
+	for _, pre := range sd.Pre {
+		if err := nmr.insertWithSoftDeps(op, pre, false); err != nil {
+			return fmt.Errorf("could not load soft dependency %s of %s: %w", pre, name, err)
+		}
+	}
+
+	if err := nmr.insert(op, name, requested); err != nil {
+		return err
+	}
+
+	for _, post := range sd.Post {
+		if err := nmr.insertWithSoftDeps(op, post, false); err != nil {
+			return fmt.Errorf("could not load soft dependency %s of %s: %w", post, name, err)
+		}
+	}
+
+	return nil
+}
+
+func (nmr *nativeModprobeRunner) insert(op *nativeOperation, name string, requested bool) error {
+	var (
+		inv    = op.inv
+		logger = op.logger
+		res    = op.res
+	)
+
+	md, ok := op.deps[name]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrModuleNotFound, name)
+	}
+
+	// depmod lists dependencies so that the last one must be loaded first.
+	for i := len(md.Deps) - 1; i >= 0; i-- {
+		depPath := md.Deps[i]
+		depName := moduleNameFromPath(depPath)
+
+		if nmr.isLoaded(depName) {
+			logger.V(1).Info("Dependency already loaded", "name", depName)
+			continue
+		}
+
+		err := nmr.insertFile(logger, inv, filepath.Join(op.modulesDir, depPath), strings.Join(op.conf.Options[depName], " "))
+		if err != nil {
+			if errors.Is(err, ErrAlreadyLoaded) {
+				continue
+			}
+
+			return fmt.Errorf("could not load dependency %s of %s: %w", depName, name, err)
+		}
+
+		res.Modules = append(res.Modules, depName)
+	}
+
+	firstTime := requested && inv.firstTime
+
+	if nmr.isLoaded(name) {
+		if firstTime {
+			return fmt.Errorf("%w: %s", ErrAlreadyLoaded, name)
+		}
+
+		logger.Info("Module already loaded", "name", name)
+		return nil
+	}
+
+	// Like modprobe, command-line parameters come after those of the configuration so that they take precedence.
+	params := slices.Clone(op.conf.Options[name])
+
+	if requested {
+		params = append(params, inv.params...)
+	}
+
+	err := nmr.insertFile(logger, inv, filepath.Join(op.modulesDir, md.Path), strings.Join(params, " "))
+	if err != nil {
+		if errors.Is(err, ErrAlreadyLoaded) && !firstTime {
+			logger.Info("Module already loaded", "name", name)
+			return nil
+		}
+
+		return err
+	}
+
+	res.Modules = append(res.Modules, name)
+
+	return nil
+}
+
+func (nmr *nativeModprobeRunner) insertFile(logger logr.Logger, inv *modprobeInvocation, path, params string) error {
+	if inv.verbose {
+		logger.Info("insmod", "path", path, "params", params)
+	}
+
+	fd, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("could not open %s: %v", path, err)
+	}
+	defer fd.Close()
+
+	flags := 0
+
+	if ext := filepath.Ext(path); ext != ".ko" {
+		// Let the kernel decompress the module; requires CONFIG_MODULE_DECOMPRESS.
+		flags |= unix.MODULE_INIT_COMPRESSED_FILE
+	}
+
+	if err = nmr.finitModule(int(fd.Fd()), params, flags); err != nil {
+		return fmt.Errorf("could not insert %s: %w", path, syscallError(err))
+	}
+
+	return nil
+}
+
+// removeWithSoftDeps unloads the post software dependencies of a module, then the module, then its pre software
+// dependencies.
+// Like modprobe -r, failures to unload software dependencies are ignored, as other modules may still use them.
+func (nmr *nativeModprobeRunner) removeWithSoftDeps(op *nativeOperation, name string, requested bool) error {
+	if op.visited.Has(name) {
+		return nil
+	}
+
+	op.visited.Insert(name)
+
+	sd := op.conf.SoftDeps[name]
+
+	removeSoftDeps := func(softDeps []string) {
+		for i := len(softDeps) - 1; i >= 0; i-- {
+			if err := nmr.removeWithSoftDeps(op, softDeps[i], false); err != nil {
+				op.logger.V(1).Info("Could not remove soft dependency", "name", softDeps[i], "module", name, "error", err)
+			}
+		}
+	}
+
+	removeSoftDeps(sd.Post)
+
+	if err := nmr.remove(op, name, requested); err != nil {
+		return err
+	}
+
+	removeSoftDeps(sd.Pre)
+
+	return nil
+}
+
+func (nmr *nativeModprobeRunner) remove(op *nativeOperation, name string, requested bool) error {
+	var (
+		firstTime = requested && op.inv.firstTime
+		inv       = op.inv
+		logger    = op.logger
+		res       = op.res
+	)
+
+	// Like modprobe -r, succeed without doing anything if the module is not loaded, unless --first-time is set.
+	if !nmr.isLoaded(name) {
+		if firstTime {
+			return fmt.Errorf("%w: %s", ErrModuleNotLoaded, name)
+		}
+
+		logger.Info("Module not loaded", "name", name)
+		return nil
+	}
+
+	if inv.verbose {
+		logger.Info("rmmod", "name", name)
+	}
+
+	if err := nmr.deleteModule(name, unix.O_NONBLOCK); err != nil {
+		if errors.Is(err, unix.ENOENT) {
+			if firstTime {
+				return fmt.Errorf("%w: %s", ErrModuleNotLoaded, name)
+			}
+
+			logger.Info("Module not loaded", "name", name)
+			return nil
+		}
+
+		return fmt.Errorf("could not remove %s: %w", name, syscallError(err))
+	}
+
+	res.Modules = append(res.Modules, name)
+
+	// Like modprobe -r, try to remove dependencies that are not used anymore.
+	for _, depPath := range op.deps[name].Deps {
+		depName := moduleNameFromPath(depPath)
+
+		if !nmr.isLoaded(depName) {
+			continue
+		}
+
+		if inv.verbose {
+			logger.Info("rmmod", "name", depName)
+		}
+
+		if err := nmr.deleteModule(depName, unix.O_NONBLOCK); err != nil {
+			logger.V(1).Info("Could not remove dependency; it is probably still in use", "name", depName, "error", err)
+			continue
+		}
+
+		res.Modules = append(res.Modules, depName)
+	}
+
+	return nil
+}
+
+// isLoaded returns true if a loadable (not built-in) module is present in the kernel.
+func (nmr *nativeModprobeRunner) isLoaded(name string) bool {
+	_, err := os.Stat(filepath.Join(nmr.sysModuleDir, name, "initstate"))
+	return err == nil
+}
+
+func (nmr *nativeModprobeRunner) kernelVersion() (string, error) {
+	u := unix.Utsname{}
+
+	if err := nmr.uname(&u); err != nil {
+		return "", err
+	}
+
+	return unix.ByteSliceToString(u.Release[:]), nil
+}
+
+// syscallError maps errnos returned by finit_module and delete_module to typed errors.
+func syscallError(err error) error {
+	var target error
+
+	switch {
+	case errors.Is(err, unix.EEXIST):
+		target = ErrAlreadyLoaded
+	case errors.Is(err, unix.ENOEXEC):
+		target = ErrInvalidModuleFormat
+	case errors.Is(err, unix.EINVAL):
+		target = ErrInvalidParameters
+	case errors.Is(err, unix.EKEYREJECTED):
+		target = ErrKeyRejected
+	case errors.Is(err, unix.EBUSY), errors.Is(err, unix.EWOULDBLOCK):
+		target = ErrModuleInUse
+	case errors.Is(err, unix.ENOENT):
+		target = ErrUnknownSymbol
+	default:
+		return err
+	}
+
+	return fmt.Errorf("%w: %w", target, err)
+}
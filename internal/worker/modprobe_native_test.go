@@ -0,0 +1,404 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"golang.org/x/sys/unix"
+)
+
+var _ = Describe("parseModprobeArgs", func() {
+	DescribeTable(
+		"should parse arguments correctly",
+		func(args []string, expected *modprobeInvocation) {
+			Expect(
+				parseModprobeArgs(args),
+			).To(
+				Equal(expected),
+			)
+		},
+		Entry(
+			"load with dirname",
+			[]string{"-vd", "/tmp/opt", "mod", "key0=value0", "key1=value1"},
+			&modprobeInvocation{baseDir: "/tmp/opt", verbose: true, names: []string{"mod"}, params: []string{"key0=value0", "key1=value1"}},
+		),
+		Entry(
+			"unload with dirname",
+			[]string{"-rvd", "/tmp/opt", "mod"},
+			&modprobeInvocation{baseDir: "/tmp/opt", remove: true, verbose: true, names: []string{"mod"}},
+		),
+		Entry(
+			"unload several in-tree modules",
+			[]string{"-rv", "intree1", "intree2"},
+			&modprobeInvocation{baseDir: "/", remove: true, verbose: true, names: []string{"intree1", "intree2"}},
+		),
+		Entry(
+			"long options",
+			[]string{"--dirname=/opt", "--first-time", "--all", "--quiet", "mod1", "mod2"},
+			&modprobeInvocation{baseDir: "/opt", all: true, firstTime: true, names: []string{"mod1", "mod2"}},
+		),
+		Entry(
+			"attached dirname",
+			[]string{"-d/opt", "mod"},
+			&modprobeInvocation{baseDir: "/opt", names: []string{"mod"}, params: []string{}},
+		),
+	)
+
+	It("should return an error for unsupported arguments", func() {
+		_, err := parseModprobeArgs([]string{"--show-depends", "mod"})
+		Expect(err).To(MatchError(ErrUnsupportedArgument))
+	})
+
+	It("should return an error if -d has no value", func() {
+		_, err := parseModprobeArgs([]string{"-d"})
+		Expect(err).To(MatchError(ErrUnsupportedArgument))
+	})
+
+	It("should return an error if no module is specified", func() {
+		_, err := parseModprobeArgs([]string{"-v"})
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("nativeModprobeRunner_Run", func() {
+	const kernelVersion = "5.14.0-284.el9.x86_64"
+
+	var (
+		baseDir    string
+		confDir    string
+		modulesDir string
+		nmr        *nativeModprobeRunner
+		inserted   []string
+		removed    []string
+		finitErr   map[string]error
+		deleteErr  map[string]error
+	)
+
+	setLoaded := func(names ...string) {
+		GinkgoHelper()
+
+		for _, n := range names {
+			dir := filepath.Join(nmr.sysModuleDir, n)
+			Expect(os.MkdirAll(dir, 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "initstate"), []byte("live\n"), 0644)).To(Succeed())
+		}
+	}
+
+	BeforeEach(func() {
+		baseDir = GinkgoT().TempDir()
+		confDir = GinkgoT().TempDir()
+		modulesDir = filepath.Join(baseDir, "lib", "modules", kernelVersion)
+		inserted = nil
+		removed = nil
+		finitErr = make(map[string]error)
+		deleteErr = make(map[string]error)
+
+		Expect(os.MkdirAll(filepath.Join(modulesDir, "extra"), 0755)).To(Succeed())
+
+		for _, f := range []string{"mod-a.ko", "mod_b.ko", "mod_c.ko.xz", "mod_d.ko", "mod_e.ko"} {
+			Expect(os.WriteFile(filepath.Join(modulesDir, "extra", f), []byte(f), 0644)).To(Succeed())
+		}
+
+		const modulesDep = `extra/mod-a.ko: extra/mod_b.ko extra/mod_c.ko.xz
+extra/mod_b.ko: extra/mod_c.ko.xz
+extra/mod_c.ko.xz:
+extra/mod_d.ko:
+extra/mod_e.ko:
+`
+
+		Expect(os.WriteFile(filepath.Join(modulesDir, "modules.dep"), []byte(modulesDep), 0644)).To(Succeed())
+
+		nmr = &nativeModprobeRunner{
+			confDir: confDir,
+			deleteModule: func(name string, flags int) error {
+				Expect(flags).To(Equal(unix.O_NONBLOCK))
+				removed = append(removed, name)
+
+				if err := deleteErr[name]; err != nil {
+					return err
+				}
+
+				return os.RemoveAll(filepath.Join(nmr.sysModuleDir, name))
+			},
+			finitModule: func(fd int, params string, flags int) error {
+				path, err := os.Readlink(fmt.Sprintf("/proc/self/fd/%d", fd))
+				Expect(err).NotTo(HaveOccurred())
+
+				rel, err := filepath.Rel(modulesDir, path)
+				Expect(err).NotTo(HaveOccurred())
+
+				if filepath.Ext(path) == ".ko" {
+					Expect(flags).To(Equal(0))
+				} else {
+					Expect(flags).To(Equal(unix.MODULE_INIT_COMPRESSED_FILE))
+				}
+
+				inserted = append(inserted, rel+" "+params)
+				return finitErr[rel]
+			},
+			logger:       GinkgoLogr,
+			sysModuleDir: GinkgoT().TempDir(),
+			uname: func(u *unix.Utsname) error {
+				copy(u.Release[:], kernelVersion)
+				return nil
+			},
+		}
+	})
+
+	ctx := context.TODO()
+
+	It("should load dependencies first, then the module with its parameters", func() {
+		Expect(
+			nmr.Run(ctx, "-vd", baseDir, "mod-a", "key0=value0", "key1=value1"),
+		).To(
+			Equal(&ModprobeResult{Modules: []string{"mod_c", "mod_b", "mod_a"}}),
+		)
+
+		Expect(inserted).To(Equal([]string{
+			"extra/mod_c.ko.xz ",
+			"extra/mod_b.ko ",
+			"extra/mod-a.ko key0=value0 key1=value1",
+		}))
+	})
+
+	It("should skip dependencies that are already loaded", func() {
+		setLoaded("mod_c")
+
+		Expect(
+			nmr.Run(ctx, "-vd", baseDir, "mod_a"),
+		).To(
+			Equal(&ModprobeResult{Modules: []string{"mod_b", "mod_a"}}),
+		)
+
+		Expect(inserted).To(Equal([]string{"extra/mod_b.ko ", "extra/mod-a.ko "}))
+	})
+
+	It("should do nothing if the module is already loaded", func() {
+		setLoaded("mod_a", "mod_b", "mod_c")
+
+		Expect(
+			nmr.Run(ctx, "-vd", baseDir, "mod_a"),
+		).To(
+			Equal(&ModprobeResult{}),
+		)
+
+		Expect(inserted).To(BeEmpty())
+	})
+
+	It("should return ErrAlreadyLoaded if --first-time is set and the module is loaded", func() {
+		setLoaded("mod_a", "mod_b", "mod_c")
+
+		_, err := nmr.Run(ctx, "--first-time", "-vd", baseDir, "mod_a")
+		Expect(err).To(MatchError(ErrAlreadyLoaded))
+	})
+
+	It("should apply the options and soft dependencies of the modprobe configuration", func() {
+		const conf = `softdep mod_a pre: mod_d post: mod_e
+softdep mod_d pre: mod_e
+options mod_a key0=value0
+options mod_c key2=value2
+`
+
+		Expect(os.WriteFile(filepath.Join(confDir, "softdep.conf"), []byte(conf), 0644)).To(Succeed())
+
+		Expect(
+			nmr.Run(ctx, "-vd", baseDir, "mod_a", "key1=value1"),
+		).To(
+			Equal(&ModprobeResult{Modules: []string{"mod_e", "mod_d", "mod_c", "mod_b", "mod_a"}}),
+		)
+
+		Expect(inserted).To(Equal([]string{
+			"extra/mod_e.ko ",
+			"extra/mod_d.ko ",
+			"extra/mod_c.ko.xz key2=value2",
+			"extra/mod_b.ko ",
+			"extra/mod-a.ko key0=value0 key1=value1",
+		}))
+	})
+
+	It("should return the error of a soft dependency", func() {
+		Expect(os.WriteFile(filepath.Join(confDir, "softdep.conf"), []byte("softdep mod_a pre: mod_z\n"), 0644)).To(Succeed())
+
+		_, err := nmr.Run(ctx, "-vd", baseDir, "mod_a")
+		Expect(err).To(MatchError(ErrModuleNotFound))
+		Expect(inserted).To(BeEmpty())
+	})
+
+	It("should return ErrUnsupportedArgument if the modprobe configuration has unsupported directives", func() {
+		Expect(os.WriteFile(filepath.Join(confDir, "custom.conf"), []byte("install mod_a /bin/true\n"), 0644)).To(Succeed())
+
+		_, err := nmr.Run(ctx, "-vd", baseDir, "mod_a")
+		Expect(err).To(MatchError(ErrUnsupportedArgument))
+		Expect(inserted).To(BeEmpty())
+	})
+
+	It("should return ErrModuleNotFound if the module is not in modules.dep", func() {
+		_, err := nmr.Run(ctx, "-vd", baseDir, "mod_z")
+		Expect(err).To(MatchError(ErrModuleNotFound))
+	})
+
+	It("should return an error if modules.dep is missing", func() {
+		Expect(
+			os.Remove(filepath.Join(modulesDir, "modules.dep")),
+		).To(
+			Succeed(),
+		)
+
+		_, err := nmr.Run(ctx, "-vd", baseDir, "mod_a")
+		Expect(err).To(HaveOccurred())
+	})
+
+	DescribeTable(
+		"should map finit_module errors to typed errors",
+		func(errno unix.Errno, expected error) {
+			finitErr["extra/mod-a.ko"] = errno
+
+			res, err := nmr.Run(ctx, "-vd", baseDir, "mod_a")
+			Expect(err).To(MatchError(expected))
+			Expect(err).To(MatchError(errno))
+			Expect(res.ExitCode).To(Equal(1))
+		},
+		Entry(nil, unix.ENOENT, ErrUnknownSymbol),
+		Entry(nil, unix.ENOEXEC, ErrInvalidModuleFormat),
+		Entry(nil, unix.EINVAL, ErrInvalidParameters),
+		Entry(nil, unix.EKEYREJECTED, ErrKeyRejected),
+	)
+
+	It("should return the dependency error", func() {
+		finitErr["extra/mod_c.ko.xz"] = unix.ENOEXEC
+
+		_, err := nmr.Run(ctx, "-vd", baseDir, "mod_a")
+		Expect(err).To(MatchError(ErrInvalidModuleFormat))
+
+		Expect(inserted).To(HaveLen(1))
+	})
+
+	It("should remove the module and its unused dependencies", func() {
+		setLoaded("mod_a", "mod_b", "mod_c")
+		deleteErr["mod_c"] = unix.EWOULDBLOCK
+
+		Expect(
+			nmr.Run(ctx, "-rvd", baseDir, "mod-a"),
+		).To(
+			Equal(&ModprobeResult{Modules: []string{"mod_a", "mod_b"}}),
+		)
+
+		Expect(removed).To(Equal([]string{"mod_a", "mod_b", "mod_c"}))
+	})
+
+	It("should remove the soft dependencies of the module, ignoring their errors", func() {
+		const conf = `softdep mod_a pre: mod_d post: mod_e
+softdep mod_d pre: mod_c
+`
+
+		Expect(os.WriteFile(filepath.Join(confDir, "softdep.conf"), []byte(conf), 0644)).To(Succeed())
+
+		setLoaded("mod_a", "mod_b", "mod_c", "mod_d", "mod_e")
+		deleteErr["mod_e"] = unix.EBUSY
+
+		Expect(
+			nmr.Run(ctx, "-rvd", baseDir, "mod_a"),
+		).To(
+			Equal(&ModprobeResult{Modules: []string{"mod_a", "mod_b", "mod_c", "mod_d"}}),
+		)
+
+		Expect(removed).To(Equal([]string{"mod_e", "mod_a", "mod_b", "mod_c", "mod_d"}))
+	})
+
+	It("should remove several modules from the host tree", func() {
+		nmr.uname = func(u *unix.Utsname) error {
+			copy(u.Release[:], "does-not-exist")
+			return nil
+		}
+
+		setLoaded("intree1", "intree2")
+
+		Expect(
+			nmr.Run(ctx, "-rv", "intree1", "intree2"),
+		).To(
+			Equal(&ModprobeResult{Modules: []string{"intree1", "intree2"}}),
+		)
+
+		Expect(removed).To(Equal([]string{"intree1", "intree2"}))
+	})
+
+	It("should do nothing if the module to remove is not loaded", func() {
+		Expect(
+			nmr.Run(ctx, "-rvd", baseDir, "mod_a"),
+		).To(
+			Equal(&ModprobeResult{}),
+		)
+
+		Expect(removed).To(BeEmpty())
+	})
+
+	It("should do nothing if the module was unloaded concurrently", func() {
+		setLoaded("mod_a")
+		deleteErr["mod_a"] = unix.ENOENT
+
+		Expect(
+			nmr.Run(ctx, "-rvd", baseDir, "mod_a"),
+		).To(
+			Equal(&ModprobeResult{}),
+		)
+	})
+
+	It("should return ErrModuleNotLoaded if --first-time is set and the module is not loaded", func() {
+		_, err := nmr.Run(ctx, "--first-time", "-rvd", baseDir, "mod_a")
+		Expect(err).To(MatchError(ErrModuleNotLoaded))
+	})
+
+	It("should return ErrModuleInUse if the module is busy", func() {
+		setLoaded("mod_a")
+		deleteErr["mod_a"] = unix.EBUSY
+
+		_, err := nmr.Run(ctx, "-rvd", baseDir, "mod_a")
+		Expect(err).To(MatchError(ErrModuleInUse))
+	})
+
+	It("should return an error for unsupported arguments", func() {
+		_, err := nmr.Run(ctx, "--show-depends", "mod_a")
+		Expect(err).To(MatchError(ErrUnsupportedArgument))
+	})
+
+	It("should insert a single file without its dependencies", func() {
+		Expect(
+			nmr.Insmod(ctx, filepath.Join(modulesDir, "extra", "mod-a.ko"), "key0=value0", "key1=value1"),
+		).To(
+			Equal(&ModprobeResult{Modules: []string{"mod_a"}}),
+		)
+
+		Expect(inserted).To(Equal([]string{"extra/mod-a.ko key0=value0 key1=value1"}))
+	})
+
+	It("should return ErrAlreadyLoaded if the file is already inserted", func() {
+		finitErr["extra/mod_b.ko"] = unix.EEXIST
+
+		res, err := nmr.Insmod(ctx, filepath.Join(modulesDir, "extra", "mod_b.ko"))
+		Expect(err).To(MatchError(ErrAlreadyLoaded))
+		Expect(res.ExitCode).To(Equal(1))
+	})
+
+	It("should remove a single module without its dependencies", func() {
+		setLoaded("mod_a", "mod_b", "mod_c")
+
+		Expect(
+			nmr.Rmmod(ctx, "mod-a"),
+		).To(
+			Equal(&ModprobeResult{Modules: []string{"mod_a"}}),
+		)
+
+		Expect(removed).To(Equal([]string{"mod_a"}))
+	})
+
+	It("should return ErrModuleNotLoaded if the module to remove is not loaded", func() {
+		deleteErr["mod_a"] = unix.ENOENT
+
+		_, err := nmr.Rmmod(ctx, "mod_a")
+		Expect(err).To(MatchError(ErrModuleNotLoaded))
+	})
+})
@@ -0,0 +1,106 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+//go:generate mockgen -source=params.go -package=worker -destination=mock_params.go
+
+var (
+	ErrParameterReadOnly = errors.New("module parameter is not writable at runtime")
+	ErrUnknownParameter  = errors.New("unknown module parameter")
+)
+
+type ModuleParametersWriter interface {
+	// WriteParameters writes params, in the modprobe key=value format, into the sysfs parameters of a loaded module.
+	// It returns the values read back from sysfs.
+	WriteParameters(module string, params []string) (map[string]string, error)
+}
+
+type moduleParametersWriter struct {
+	sysModuleDir string
+}
+
+func NewModuleParametersWriter() ModuleParametersWriter {
+	return &moduleParametersWriter{sysModuleDir: sysModuleDir}
+}
+
+func (m *moduleParametersWriter) WriteParameters(module string, params []string) (map[string]string, error) {
+	module = normalizeModuleName(module)
+
+	moduleDir := filepath.Join(m.sysModuleDir, module)
+
+	if _, err := os.Stat(moduleDir); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", ErrModuleNotLoaded, module)
+		}
+
+		return nil, fmt.Errorf("could not stat %s: %v", moduleDir, err)
+	}
+
+	values := make(map[string]string, len(params))
+	order := make([]string, 0, len(params))
+
+	// Check all parameters first, so that we do not leave the module partially updated.
+	for _, p := range params {
+		name, value := parseModuleParameter(p)
+
+		path := filepath.Join(moduleDir, "parameters", name)
+
+		fi, err := os.Stat(path)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil, fmt.Errorf("%w: %s", ErrUnknownParameter, name)
+			}
+
+			return nil, fmt.Errorf("could not stat %s: %v", path, err)
+		}
+
+		if fi.Mode().Perm()&0222 == 0 {
+			return nil, fmt.Errorf("%w: %s", ErrParameterReadOnly, name)
+		}
+
+		if _, ok := values[name]; !ok {
+			order = append(order, name)
+		}
+
+		values[name] = value
+	}
+
+	applied := make(map[string]string, len(values))
+
+	for _, name := range order {
+		path := filepath.Join(moduleDir, "parameters", name)
+
+		if err := os.WriteFile(path, []byte(values[name]), 0644); err != nil {
+			return nil, fmt.Errorf("%w: could not write %q into %s: %w", ErrInvalidParameters, values[name], path, err)
+		}
+
+		v, err := readSysfsValue(path)
+		if err != nil {
+			return nil, err
+		}
+
+		applied[name] = v
+	}
+
+	return applied, nil
+}
+
+// parseModuleParameter returns the sysfs name and value of a parameter in the modprobe key=value format.
+// Like the kernel, it treats dashes and underscores in the name as equivalent.
+func parseModuleParameter(p string) (string, string) {
+	name, value, ok := strings.Cut(p, "=")
+	if !ok {
+		// boolean parameters may be set without a value
+		value = "1"
+	}
+
+	value = strings.TrimSuffix(strings.TrimPrefix(value, `"`), `"`)
+
+	return strings.ReplaceAll(name, "-", "_"), value
+}
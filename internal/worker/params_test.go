@@ -0,0 +1,78 @@
+package worker
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("moduleParametersWriter_WriteParameters", func() {
+	var (
+		mpw       *moduleParametersWriter
+		paramsDir string
+	)
+
+	BeforeEach(func() {
+		mpw = &moduleParametersWriter{sysModuleDir: GinkgoT().TempDir()}
+
+		paramsDir = filepath.Join(mpw.sysModuleDir, "kmm_test", "parameters")
+		Expect(os.MkdirAll(paramsDir, 0755)).To(Succeed())
+
+		params := map[string]os.FileMode{
+			"debug_level": 0644,
+			"enabled":     0644,
+			"queues":      0444,
+		}
+
+		for name, mode := range params {
+			Expect(os.WriteFile(filepath.Join(paramsDir, name), []byte("0\n"), mode)).To(Succeed())
+		}
+	})
+
+	readParam := func(name string) string {
+		GinkgoHelper()
+
+		b, err := os.ReadFile(filepath.Join(paramsDir, name))
+		Expect(err).NotTo(HaveOccurred())
+
+		return string(b)
+	}
+
+	It("should return ErrModuleNotLoaded if the module is not in sysfs", func() {
+		_, err := mpw.WriteParameters("kmm_other", []string{"debug_level=1"})
+		Expect(err).To(MatchError(ErrModuleNotLoaded))
+	})
+
+	It("should write the parameters and return their new values", func() {
+		Expect(
+			mpw.WriteParameters("kmm-test", []string{"debug-level=3", "enabled"}),
+		).To(
+			Equal(map[string]string{"debug_level": "3", "enabled": "1"}),
+		)
+
+		Expect(readParam("debug_level")).To(Equal("3"))
+		Expect(readParam("enabled")).To(Equal("1"))
+	})
+
+	It("should strip quotes around values", func() {
+		Expect(
+			mpw.WriteParameters("kmm_test", []string{`debug_level="2"`}),
+		).To(
+			Equal(map[string]string{"debug_level": "2"}),
+		)
+	})
+
+	It("should not write anything if one of the parameters is read-only", func() {
+		_, err := mpw.WriteParameters("kmm_test", []string{"debug_level=3", "queues=4"})
+		Expect(err).To(MatchError(ErrParameterReadOnly))
+
+		Expect(readParam("debug_level")).To(Equal("0\n"))
+	})
+
+	It("should return ErrUnknownParameter if the parameter does not exist", func() {
+		_, err := mpw.WriteParameters("kmm_test", []string{"unknown=1"})
+		Expect(err).To(MatchError(ErrUnknownParameter))
+	})
+})
@@ -2,6 +2,7 @@ package worker
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io/fs"
 	"os"
@@ -59,3 +60,26 @@ func ReadKubernetesSecrets(ctx context.Context, rootDir string, logger logr.Logg
 
 	return kubernetes.NewFromPullSecrets(ctx, secrets)
 }
+
+// ReadGlobalPullSecret returns a keychain built from the kubelet's global pull secret at path.
+// It returns an empty keychain if path does not exist.
+func ReadGlobalPullSecret(ctx context.Context, path string, logger logr.Logger) (authn.Keychain, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			logger.Info("Global pull secret not found; ignoring", "path", path)
+			return authn.NewMultiKeychain(), nil
+		}
+
+		return nil, fmt.Errorf("could not read %s: %v", path, err)
+	}
+
+	logger.Info("Reading global pull secret", "path", path)
+
+	s := v1.Secret{
+		Type: v1.SecretTypeDockerConfigJson,
+		Data: map[string][]byte{v1.DockerConfigJsonKey: b},
+	}
+
+	return kubernetes.NewFromPullSecrets(ctx, []v1.Secret{s})
+}
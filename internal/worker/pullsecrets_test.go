@@ -3,6 +3,7 @@ package worker
 import (
 	"context"
 
+	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -43,3 +44,32 @@ var _ = Describe("ReadKubernetesSecrets", func() {
 
 	})
 })
+
+var _ = Describe("ReadGlobalPullSecret", func() {
+	It("should return an empty keychain if the file does not exist", func() {
+		kc, err := ReadGlobalPullSecret(context.TODO(), "/non/existent/path", GinkgoLogr)
+		Expect(err).NotTo(HaveOccurred())
+
+		tag, err := name.NewTag("dockerconfigjson.registry/repo/image")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(kc.Resolve(tag)).To(Equal(authn.Anonymous))
+	})
+
+	It("should read the file as a dockerconfigjson", func() {
+		kc, err := ReadGlobalPullSecret(context.TODO(), "testdata/pull-secrets/sub0/sub0sub/.dockerconfigjson", GinkgoLogr)
+		Expect(err).NotTo(HaveOccurred())
+
+		tag, err := name.NewTag("dockerconfigjson.registry/repo/image")
+		Expect(err).NotTo(HaveOccurred())
+
+		authenticator, err := kc.Resolve(tag)
+		Expect(err).NotTo(HaveOccurred())
+
+		authConfig, err := authenticator.Authorization()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(authConfig.Username).To(Equal("username"))
+		Expect(authConfig.Password).To(Equal("dockerconfigjson"))
+	})
+})
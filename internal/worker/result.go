@@ -0,0 +1,263 @@
+package worker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"unicode/utf8"
+
+	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/modinfo"
+)
+
+const (
+	ActionCheck         = "check"
+	ActionLoad          = "load"
+	ActionSetParameters = "setParameters"
+	ActionUnload        = "unload"
+
+	// TerminationLogPath is the file whose content Kubernetes exposes as the termination message of the container.
+	TerminationLogPath = "/dev/termination-log"
+
+	// maxTerminationMessageSize is the maximum size of a termination message kept by the kubelet.
+	maxTerminationMessageSize = 4096
+)
+
+// States of the modules of a modules graph reported in the worker result.
+const (
+	ModuleStateFailed   = "Failed"
+	ModuleStateLoaded   = "Loaded"
+	ModuleStateSkipped  = "Skipped"
+	ModuleStateUnloaded = "Unloaded"
+)
+
+// Error categories reported in the worker result.
+const (
+	CategoryAlreadyLoaded       = "AlreadyLoaded"
+	CategoryFirmwareCopyFailed  = "FirmwareCopyFailed"
+	CategoryInvalidConfig       = "InvalidConfig"
+	CategoryInvalidModuleFormat = "InvalidModuleFormat"
+	CategoryInvalidSignature    = "InvalidSignature"
+	CategoryInvalidParameters   = "InvalidParameters"
+	CategoryModuleInUse         = "ModuleInUse"
+	CategoryModuleNotFound      = "ModuleNotFound"
+	CategoryModuleNotLoaded     = "ModuleNotLoaded"
+	CategoryModuleNotSigned     = "ModuleNotSigned"
+	CategoryParameterReadOnly   = "ParameterReadOnly"
+	CategorySignatureRejected   = "SignatureRejected"
+	CategoryUnknown             = "Unknown"
+	CategoryUnknownParameter    = "UnknownParameter"
+	CategoryUnknownSymbol       = "UnknownSymbol"
+	CategoryUnsupportedArgument = "UnsupportedArgument"
+	CategoryVerificationFailed  = "VerificationFailed"
+	CategoryVermagicMismatch    = "VermagicMismatch"
+)
+
+var (
+	ErrFirmwareCopyFailed = errors.New("could not copy the firmware")
+	ErrInvalidConfig      = errors.New("invalid worker configuration")
+	ErrVerificationFailed = errors.New("module verification failed")
+)
+
+var errorCategories = []struct {
+	err      error
+	category string
+}{
+	{err: ErrAlreadyLoaded, category: CategoryAlreadyLoaded},
+	{err: ErrFirmwareCopyFailed, category: CategoryFirmwareCopyFailed},
+	{err: ErrInvalidConfig, category: CategoryInvalidConfig},
+	{err: ErrInvalidModuleFormat, category: CategoryInvalidModuleFormat},
+	{err: ErrInvalidParameters, category: CategoryInvalidParameters},
+	{err: ErrInvalidSignature, category: CategoryInvalidSignature},
+	{err: ErrKeyRejected, category: CategorySignatureRejected},
+	{err: ErrModuleInUse, category: CategoryModuleInUse},
+	{err: ErrModuleNotFound, category: CategoryModuleNotFound},
+	{err: ErrModuleNotLoaded, category: CategoryModuleNotLoaded},
+	{err: ErrModuleNotSigned, category: CategoryModuleNotSigned},
+	{err: ErrParameterReadOnly, category: CategoryParameterReadOnly},
+	{err: ErrUnknownParameter, category: CategoryUnknownParameter},
+	{err: ErrUnknownSymbol, category: CategoryUnknownSymbol},
+	{err: ErrUnsupportedArgument, category: CategoryUnsupportedArgument},
+	{err: ErrVerificationFailed, category: CategoryVerificationFailed},
+	{err: modinfo.ErrVermagicMismatch, category: CategoryVermagicMismatch},
+}
+
+// ErrorCategory returns a short, machine-readable description of err.
+// It returns an empty string if err is nil.
+func ErrorCategory(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	for _, c := range errorCategories {
+		if errors.Is(err, c.err) {
+			return c.category
+		}
+	}
+
+	return CategoryUnknown
+}
+
+// WriteResult adds err to res and writes it as JSON to path.
+// The message is trimmed to the size kept by the kubelet, dropping stderr and kernel log lines first.
+func WriteResult(path string, res *kmmv1beta1.WorkerResult, err error) error {
+	r := *res
+
+	if err != nil {
+		r.Error = err.Error()
+		r.ErrorCategory = ErrorCategory(err)
+
+		if r.ExitCode == 0 {
+			r.ExitCode = 1
+		}
+	}
+
+	b, err := marshalResult(&r)
+	if err != nil {
+		return err
+	}
+
+	if err = os.WriteFile(path, b, 0644); err != nil {
+		return fmt.Errorf("could not write the result to %s: %v", path, err)
+	}
+
+	return nil
+}
+
+func marshalResult(r *kmmv1beta1.WorkerResult) ([]byte, error) {
+	for {
+		b, err := json.Marshal(r)
+		if err != nil {
+			return nil, fmt.Errorf("could not marshal the result: %v", err)
+		}
+
+		if len(b) <= maxTerminationMessageSize {
+			return b, nil
+		}
+
+		if !trimResult(r, len(b)-maxTerminationMessageSize) {
+			return nil, fmt.Errorf("the result is %d bytes long, more than the %d bytes kept by the kubelet", len(b), maxTerminationMessageSize)
+		}
+	}
+}
+
+// trimResult removes about excess bytes of JSON from the least relevant field of r that is not empty, and returns
+// false if there is nothing left to remove.
+// The action, exit code, error category and boot ID are always kept; the modules that the operator needs to restore
+// or unblacklist come last.
+func trimResult(r *kmmv1beta1.WorkerResult, excess int) bool {
+	switch {
+	case len(r.Stderr) > 0:
+		// the first lines are the least relevant
+		r.Stderr = trimFront(r.Stderr, excess)
+	case len(r.KernelLog) > 0:
+		r.KernelLog = trimFront(r.KernelLog, excess)
+	case len(r.Parameters) > 0:
+		r.Parameters = trimMap(r.Parameters, excess)
+	case len(r.BlockedBy) > 0:
+		r.BlockedBy = trimBack(r.BlockedBy, excess)
+	case len(r.ModuleResults) > 0:
+		r.ModuleResults = trimBack(r.ModuleResults, excess)
+	case len(r.Modules) > 0:
+		r.Modules = trimBack(r.Modules, excess)
+	case len(r.ModprobeArgs) > 0:
+		r.ModprobeArgs = trimBack(r.ModprobeArgs, excess)
+	case len(r.RestoredInTreeModules) > 0:
+		r.RestoredInTreeModules = trimBack(r.RestoredInTreeModules, excess)
+	case r.InTreeModulesRestoreError != "":
+		r.InTreeModulesRestoreError = truncateString(r.InTreeModulesRestoreError, excess)
+	case r.Error != "":
+		r.Error = truncateString(r.Error, excess)
+	case len(r.BlacklistedModules) > 0:
+		r.BlacklistedModules = trimBack(r.BlacklistedModules, excess)
+	case len(r.RemovedInTreeModules) > 0:
+		r.RemovedInTreeModules = trimBack(r.RemovedInTreeModules, excess)
+	default:
+		return false
+	}
+
+	return true
+}
+
+// jsonSize returns the size of v in JSON, followed by a comma.
+func jsonSize(v any) int {
+	b, _ := json.Marshal(v)
+	return len(b) + 1
+}
+
+// trimFront removes the first elements of s until their JSON is at least excess bytes long.
+// It returns nil if no element is left, so that the field is omitted.
+func trimFront[T any](s []T, excess int) []T {
+	i := 0
+
+	for removed := 0; i < len(s) && removed < excess; i++ {
+		removed += jsonSize(s[i])
+	}
+
+	if i == len(s) {
+		return nil
+	}
+
+	return s[i:]
+}
+
+// trimBack removes the last elements of s until their JSON is at least excess bytes long.
+// It returns nil if no element is left, so that the field is omitted.
+func trimBack[T any](s []T, excess int) []T {
+	i := len(s)
+
+	for removed := 0; i > 0 && removed < excess; i-- {
+		removed += jsonSize(s[i-1])
+	}
+
+	if i == 0 {
+		return nil
+	}
+
+	return s[:i]
+}
+
+// trimMap returns a copy of m without the entries with the greatest keys, whose JSON is at least excess bytes long.
+// It returns nil if no entry is left, so that the field is omitted.
+func trimMap(m map[string]string, excess int) map[string]string {
+	keys := make([]string, 0, len(m))
+
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	for removed := 0; len(keys) > 0 && removed < excess; keys = keys[:len(keys)-1] {
+		k := keys[len(keys)-1]
+		removed += jsonSize(k) + jsonSize(m[k])
+	}
+
+	if len(keys) == 0 {
+		return nil
+	}
+
+	trimmed := make(map[string]string, len(keys))
+
+	for _, k := range keys {
+		trimmed[k] = m[k]
+	}
+
+	return trimmed
+}
+
+// truncateString removes at least excess bytes from the end of s, without splitting a UTF-8 encoded rune.
+func truncateString(s string, excess int) string {
+	n := len(s) - excess
+	if n <= 0 {
+		return ""
+	}
+
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+
+	return s[:n]
+}
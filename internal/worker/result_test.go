@@ -0,0 +1,162 @@
+package worker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
+)
+
+var _ = Describe("ErrorCategory", func() {
+	DescribeTable(
+		"should return the category of the error",
+		func(err error, expected string) {
+			Expect(ErrorCategory(err)).To(Equal(expected))
+		},
+		Entry("no error", nil, ""),
+		Entry("unknown error", errors.New("random error"), CategoryUnknown),
+		Entry("wrapped error", fmt.Errorf("could not load: %w", ErrKeyRejected), CategorySignatureRejected),
+		Entry("firmware", fmt.Errorf("%w: some error", ErrFirmwareCopyFailed), CategoryFirmwareCopyFailed),
+		Entry("verification", fmt.Errorf("%w: some error", ErrVerificationFailed), CategoryVerificationFailed),
+	)
+})
+
+var _ = Describe("WriteResult", func() {
+	var path string
+
+	BeforeEach(func() {
+		path = filepath.Join(GinkgoT().TempDir(), "termination-log")
+	})
+
+	readResult := func() *kmmv1beta1.WorkerResult {
+		GinkgoHelper()
+
+		b, err := os.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(len(b)).To(BeNumerically("<=", maxTerminationMessageSize))
+
+		res := kmmv1beta1.WorkerResult{}
+		Expect(json.Unmarshal(b, &res)).To(Succeed())
+
+		return &res
+	}
+
+	It("should write a successful result", func() {
+		res := kmmv1beta1.WorkerResult{Action: ActionLoad, Modules: []string{"kmm_test"}}
+
+		Expect(WriteResult(path, &res, nil)).To(Succeed())
+		Expect(readResult()).To(Equal(&res))
+	})
+
+	It("should add the error and its category", func() {
+		res := kmmv1beta1.WorkerResult{Action: ActionUnload, ExitCode: 1}
+
+		Expect(
+			WriteResult(path, &res, fmt.Errorf("could not unload: %w", ErrModuleInUse)),
+		).To(
+			Succeed(),
+		)
+
+		Expect(
+			readResult(),
+		).To(
+			Equal(&kmmv1beta1.WorkerResult{
+				Action:        ActionUnload,
+				ExitCode:      1,
+				ErrorCategory: CategoryModuleInUse,
+				Error:         "could not unload: module is in use",
+			}),
+		)
+	})
+
+	It("should set a non-zero exit code if the error did not come from modprobe", func() {
+		res := kmmv1beta1.WorkerResult{Action: ActionLoad}
+
+		Expect(WriteResult(path, &res, ErrInvalidConfig)).To(Succeed())
+		Expect(readResult().ExitCode).To(BeEquivalentTo(1))
+	})
+
+	It("should drop the first stderr lines if the result is too large", func() {
+		res := kmmv1beta1.WorkerResult{Action: ActionLoad}
+
+		for i := 0; i < 100; i++ {
+			res.Stderr = append(res.Stderr, fmt.Sprintf("%03d %s", i, strings.Repeat("a", 100)))
+		}
+
+		Expect(WriteResult(path, &res, errors.New("random error"))).To(Succeed())
+
+		written := readResult()
+		Expect(written.Error).To(Equal("random error"))
+		Expect(written.Stderr).NotTo(BeEmpty())
+		Expect(written.Stderr[len(written.Stderr)-1]).To(HavePrefix("099 "))
+	})
+
+	It("should shorten the error if it is too large", func() {
+		res := kmmv1beta1.WorkerResult{Action: ActionLoad}
+
+		Expect(WriteResult(path, &res, errors.New(strings.Repeat("a", 2*maxTerminationMessageSize)))).To(Succeed())
+		Expect(readResult().Error).To(HavePrefix("aaa"))
+	})
+
+	It("should not split a rune when shortening the error", func() {
+		res := kmmv1beta1.WorkerResult{Action: ActionLoad}
+
+		Expect(WriteResult(path, &res, errors.New(strings.Repeat("é", maxTerminationMessageSize)))).To(Succeed())
+
+		written := readResult()
+		Expect(utf8.ValidString(written.Error)).To(BeTrue())
+		Expect(written.Error).To(HavePrefix("ééé"))
+	})
+
+	It("should trim all lists and keep the fields needed by the operator", func() {
+		res := kmmv1beta1.WorkerResult{
+			Action:     ActionLoad,
+			BootID:     "boot-id",
+			Parameters: make(map[string]string),
+		}
+
+		long := strings.Repeat("a", 100)
+
+		for i := 0; i < 100; i++ {
+			res.BlockedBy = append(res.BlockedBy, fmt.Sprintf("module:%03d%s", i, long))
+			res.KernelLog = append(res.KernelLog, fmt.Sprintf("%03d %s", i, long))
+			res.ModprobeArgs = append(res.ModprobeArgs, fmt.Sprintf("%03d%s", i, long))
+			res.Modules = append(res.Modules, fmt.Sprintf("%03d%s", i, long))
+			res.ModuleResults = append(res.ModuleResults, kmmv1beta1.ModuleResult{Name: fmt.Sprintf("%03d%s", i, long)})
+			res.Parameters[fmt.Sprintf("param%03d", i)] = long
+			res.Stderr = append(res.Stderr, fmt.Sprintf("%03d %s", i, long))
+		}
+
+		res.RestoredInTreeModules = []string{"intree3"}
+		res.RemovedInTreeModules = []string{"intree1", "intree2"}
+		res.BlacklistedModules = []string{"intree1"}
+
+		Expect(WriteResult(path, &res, errors.New("random error"))).To(Succeed())
+
+		written := readResult()
+		Expect(written.Action).To(Equal(ActionLoad))
+		Expect(written.BootID).To(Equal("boot-id"))
+		Expect(written.Error).To(Equal("random error"))
+		Expect(written.RemovedInTreeModules).To(Equal(res.RemovedInTreeModules))
+		Expect(written.BlacklistedModules).To(Equal(res.BlacklistedModules))
+		Expect(written.Stderr).To(BeEmpty())
+		Expect(written.KernelLog).To(BeEmpty())
+		Expect(written.Parameters).To(BeEmpty())
+		Expect(written.BlockedBy).To(BeEmpty())
+		Expect(written.ModuleResults).To(BeEmpty())
+		Expect(written.Modules).To(BeEmpty())
+		Expect(written.ModprobeArgs).NotTo(BeEmpty())
+		Expect(written.ModprobeArgs[0]).To(HavePrefix("000"))
+		Expect(written.RestoredInTreeModules).To(Equal(res.RestoredInTreeModules))
+
+		// the result of the worker is not modified
+		Expect(res.Parameters).To(HaveLen(100))
+	})
+})
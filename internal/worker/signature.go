@@ -0,0 +1,544 @@
+package worker
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+
+	"github.com/go-logr/logr"
+	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/modinfo"
+)
+
+//go:generate mockgen -source=signature.go -package=worker -destination=mock_signature.go
+
+const (
+	// moduleSignatureMagic terminates the signature appended to kernel modules by sign-file.
+	moduleSignatureMagic = "~Module signature appended~\n"
+
+	// moduleSignatureInfoSize is the size of struct module_signature, which precedes the magic string.
+	moduleSignatureInfoSize = 12
+
+	// pkeyIDPKCS7 is the id_type of PKCS#7 module signatures.
+	pkeyIDPKCS7 = 2
+
+	efiVarsDir = "/sys/firmware/efi/efivars"
+
+	efiSecureBootVar = "SecureBoot-8be4df61-93ca-11d2-aa0d-00e098032b8c"
+	efiDBVar         = "db-d719b2cb-3d3a-4596-a3bc-dad00e67656f"
+	efiMokListRTVar  = "MokListRT-605dab50-e046-4300-abb6-3dd810dd8b23"
+)
+
+var (
+	ErrInvalidSignature = errors.New("invalid module signature")
+	ErrModuleNotSigned  = errors.New("module is not signed")
+)
+
+var (
+	oidSignedData    = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2}
+	oidMessageDigest = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 4}
+
+	digestAlgorithms = map[string]crypto.Hash{
+		"1.3.14.3.2.26":          crypto.SHA1,
+		"2.16.840.1.101.3.4.2.1": crypto.SHA256,
+		"2.16.840.1.101.3.4.2.2": crypto.SHA384,
+		"2.16.840.1.101.3.4.2.3": crypto.SHA512,
+	}
+
+	// efiCertX509GUID is EFI_CERT_X509_GUID, in the mixed-endian layout of EFI_GUID.
+	efiCertX509GUID = []byte{
+		0xa1, 0x59, 0xc0, 0xa5, 0xe4, 0x94, 0xa7, 0x4a, 0x87, 0xb5, 0xab, 0x15, 0x5c, 0x2b, 0xf0, 0x72,
+	}
+)
+
+type SignatureVerifier interface {
+	// VerifyModules checks that the module files carry a valid signature made with a trusted key.
+	VerifyModules(paths []string, spec *kmmv1beta1.SignatureVerificationSpec) error
+}
+
+type signatureVerifier struct {
+	certPath   string
+	efiVarsDir string
+	logger     logr.Logger
+}
+
+func NewSignatureVerifier(logger logr.Logger) SignatureVerifier {
+	return &signatureVerifier{
+		certPath:   SignatureCertPath,
+		efiVarsDir: efiVarsDir,
+		logger:     logger,
+	}
+}
+
+func (s *signatureVerifier) VerifyModules(paths []string, spec *kmmv1beta1.SignatureVerificationSpec) error {
+	var (
+		certs []*x509.Certificate
+		err   error
+	)
+
+	switch {
+	case spec.CertSecret != nil:
+		if certs, err = readPEMCertificates(s.certPath); err != nil {
+			return err
+		}
+	case spec.UEFIKeys:
+		enabled, err := s.secureBootEnabled()
+		if err != nil {
+			return err
+		}
+
+		if !enabled {
+			s.logger.Info("Secure Boot is disabled; not verifying module signatures")
+			return nil
+		}
+
+		if certs, err = s.readEFICertificates(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("%w: neither a certificate nor UEFI keys are set for signature verification", ErrInvalidConfig)
+	}
+
+	for _, p := range paths {
+		s.logger.Info("Verifying the module signature", "path", p)
+
+		if err = verifyModuleSignature(p, certs); err != nil {
+			return fmt.Errorf("%s: %w", filepath.Base(p), err)
+		}
+	}
+
+	return nil
+}
+
+func (s *signatureVerifier) secureBootEnabled() (bool, error) {
+	path := filepath.Join(s.efiVarsDir, efiSecureBootVar)
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			// legacy BIOS boot
+			return false, nil
+		}
+
+		return false, fmt.Errorf("could not read %s: %v", path, err)
+	}
+
+	// 4 bytes of attributes, then the value
+	return len(b) == 5 && b[4] == 1, nil
+}
+
+// readEFICertificates returns the X.509 certificates of the UEFI db and of the MOK list, from which the kernel
+// populates its .platform and .machine keyrings.
+// The keys of the .builtin_trusted_keys keyring are compiled into the kernel and cannot be read from user space.
+func (s *signatureVerifier) readEFICertificates() ([]*x509.Certificate, error) {
+	certs := make([]*x509.Certificate, 0)
+
+	for _, name := range []string{efiDBVar, efiMokListRTVar} {
+		path := filepath.Join(s.efiVarsDir, name)
+
+		b, err := os.ReadFile(path)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+
+			return nil, fmt.Errorf("could not read %s: %v", path, err)
+		}
+
+		if len(b) < 4 {
+			return nil, fmt.Errorf("%s: variable too short", path)
+		}
+
+		varCerts, err := parseEFISignatureLists(b[4:])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+
+		certs = append(certs, varCerts...)
+	}
+
+	if len(certs) == 0 {
+		return nil, errors.New("no certificate found in the UEFI db and MOK list")
+	}
+
+	return certs, nil
+}
+
+// parseEFISignatureLists returns the X.509 certificates found in a sequence of EFI_SIGNATURE_LIST.
+func parseEFISignatureLists(data []byte) ([]*x509.Certificate, error) {
+	const headerSize = 28
+
+	certs := make([]*x509.Certificate, 0)
+
+	for len(data) > 0 {
+		if len(data) < headerSize {
+			return nil, errors.New("truncated signature list")
+		}
+
+		listSize := binary.LittleEndian.Uint32(data[16:20])
+		sigHeaderSize := binary.LittleEndian.Uint32(data[20:24])
+		sigSize := binary.LittleEndian.Uint32(data[24:28])
+
+		if uint64(listSize) > uint64(len(data)) || uint64(listSize) < uint64(headerSize)+uint64(sigHeaderSize) || sigSize <= 16 {
+			return nil, fmt.Errorf("invalid signature list size %d", listSize)
+		}
+
+		list := data[headerSize+sigHeaderSize : listSize]
+
+		if bytes.Equal(data[:16], efiCertX509GUID) {
+			for ; len(list) >= int(sigSize); list = list[sigSize:] {
+				// each entry starts with the GUID of its owner
+				cert, err := x509.ParseCertificate(list[16:sigSize])
+				if err != nil {
+					return nil, fmt.Errorf("could not parse a certificate: %v", err)
+				}
+
+				certs = append(certs, cert)
+			}
+		}
+
+		data = data[listSize:]
+	}
+
+	return certs, nil
+}
+
+func readPEMCertificates(path string) ([]*x509.Certificate, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the certificate %s: %v", path, err)
+	}
+
+	certs := make([]*x509.Certificate, 0)
+
+	for {
+		var block *pem.Block
+
+		if block, b = pem.Decode(b); block == nil {
+			break
+		}
+
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse a certificate in %s: %v", path, err)
+		}
+
+		certs = append(certs, cert)
+	}
+
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("%s: no PEM certificate found", path)
+	}
+
+	return certs, nil
+}
+
+type pkcs7ContentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"explicit,tag:0"`
+}
+
+type pkcs7IssuerAndSerial struct {
+	Issuer asn1.RawValue
+	Serial *big.Int
+}
+
+type pkcs7Attribute struct {
+	Type   asn1.ObjectIdentifier
+	Values asn1.RawValue `asn1:"set"`
+}
+
+// moduleSigner is the signer information of a module signature.
+type moduleSigner struct {
+	issuerAndSerial *pkcs7IssuerAndSerial
+	subjectKeyID    []byte
+	hash            crypto.Hash
+	// signedAttrs holds the DER-encoded authenticated attributes, if any.
+	signedAttrs []byte
+	signature   []byte
+}
+
+func (m *moduleSigner) matches(cert *x509.Certificate) bool {
+	if m.subjectKeyID != nil {
+		return bytes.Equal(m.subjectKeyID, cert.SubjectKeyId)
+	}
+
+	return bytes.Equal(m.issuerAndSerial.Issuer.FullBytes, cert.RawIssuer) &&
+		m.issuerAndSerial.Serial.Cmp(cert.SerialNumber) == 0
+}
+
+// verifyModuleSignature checks the PKCS#7 signature appended to the module file at path against certs.
+func verifyModuleSignature(path string, certs []*x509.Certificate) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("could not read %s: %v", path, err)
+	}
+
+	// compressed modules are signed before being compressed
+	if b, err = modinfo.Decompress(b); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	content, sig, err := splitModuleSignature(b)
+	if err != nil {
+		return err
+	}
+
+	signer, err := parsePKCS7Signer(sig)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+
+	for _, cert := range certs {
+		if !signer.matches(cert) {
+			continue
+		}
+
+		if err = signer.verify(content, cert); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("%w: not signed by a trusted key", ErrInvalidSignature)
+}
+
+// splitModuleSignature returns the module content and its PKCS#7 signature.
+func splitModuleSignature(b []byte) ([]byte, []byte, error) {
+	if !bytes.HasSuffix(b, []byte(moduleSignatureMagic)) {
+		return nil, nil, ErrModuleNotSigned
+	}
+
+	b = b[:len(b)-len(moduleSignatureMagic)]
+
+	if len(b) < moduleSignatureInfoSize {
+		return nil, nil, fmt.Errorf("%w: truncated signature information", ErrInvalidSignature)
+	}
+
+	// struct module_signature { u8 algo, hash, id_type, signer_len, key_id_len; u8 pad[3]; __be32 sig_len; }
+	info := b[len(b)-moduleSignatureInfoSize:]
+	b = b[:len(b)-moduleSignatureInfoSize]
+
+	if info[2] != pkeyIDPKCS7 {
+		return nil, nil, fmt.Errorf("%w: unsupported signature type %d", ErrInvalidSignature, info[2])
+	}
+
+	sigLen := binary.BigEndian.Uint32(info[8:])
+
+	if uint64(sigLen) > uint64(len(b)) {
+		return nil, nil, fmt.Errorf("%w: signature length %d exceeds the file size", ErrInvalidSignature, sigLen)
+	}
+
+	return b[:len(b)-int(sigLen)], b[len(b)-int(sigLen):], nil
+}
+
+// parsePKCS7Signer returns the first signer of a PKCS#7 SignedData structure with detached content.
+func parsePKCS7Signer(der []byte) (*moduleSigner, error) {
+	ci := pkcs7ContentInfo{}
+
+	if _, err := asn1.Unmarshal(der, &ci); err != nil {
+		return nil, fmt.Errorf("could not parse the content info: %v", err)
+	}
+
+	if !ci.ContentType.Equal(oidSignedData) {
+		return nil, fmt.Errorf("unexpected content type %s", ci.ContentType)
+	}
+
+	// SignedData: version, digestAlgorithms, contentInfo, [0] certificates, [1] crls, signerInfos
+	sdElems, err := asn1SequenceElements(ci.Content.Bytes, 4)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse the signed data: %v", err)
+	}
+
+	signerInfos, err := asn1Elements(sdElems[len(sdElems)-1].Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse the signer infos: %v", err)
+	}
+
+	if len(signerInfos) == 0 {
+		return nil, errors.New("no signer info")
+	}
+
+	// SignerInfo: version, sid, digestAlgorithm, [0] authenticatedAttributes, digestEncryptionAlgorithm,
+	// encryptedDigest, [1] unauthenticatedAttributes
+	siElems, err := asn1SequenceElements(signerInfos[0].FullBytes, 5)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse the signer info: %v", err)
+	}
+
+	signer := moduleSigner{}
+
+	switch sid := siElems[1]; {
+	case sid.Class == asn1.ClassContextSpecific && sid.Tag == 0:
+		signer.subjectKeyID = sid.Bytes
+	case sid.Class == asn1.ClassUniversal && sid.Tag == asn1.TagSequence:
+		signer.issuerAndSerial = &pkcs7IssuerAndSerial{}
+
+		if _, err = asn1.Unmarshal(sid.FullBytes, signer.issuerAndSerial); err != nil {
+			return nil, fmt.Errorf("could not parse the signer identifier: %v", err)
+		}
+	default:
+		return nil, fmt.Errorf("unexpected signer identifier tag %d", sid.Tag)
+	}
+
+	digestAlg := struct {
+		Algorithm  asn1.ObjectIdentifier
+		Parameters asn1.RawValue `asn1:"optional"`
+	}{}
+
+	if _, err = asn1.Unmarshal(siElems[2].FullBytes, &digestAlg); err != nil {
+		return nil, fmt.Errorf("could not parse the digest algorithm: %v", err)
+	}
+
+	var ok bool
+
+	if signer.hash, ok = digestAlgorithms[digestAlg.Algorithm.String()]; !ok {
+		return nil, fmt.Errorf("unsupported digest algorithm %s", digestAlg.Algorithm)
+	}
+
+	rest := siElems[3:]
+
+	if rest[0].Class == asn1.ClassContextSpecific && rest[0].Tag == 0 {
+		// The signature covers the attributes encoded as a SET, rather than with their implicit tag.
+		signer.signedAttrs = append([]byte{0x31}, rest[0].FullBytes[1:]...)
+		rest = rest[1:]
+	}
+
+	if len(rest) < 2 || rest[1].Tag != asn1.TagOctetString {
+		return nil, errors.New("missing encrypted digest")
+	}
+
+	signer.signature = rest[1].Bytes
+
+	return &signer, nil
+}
+
+func (m *moduleSigner) verify(content []byte, cert *x509.Certificate) error {
+	signed := content
+
+	if m.signedAttrs != nil {
+		attrs := make([]pkcs7Attribute, 0)
+
+		if _, err := asn1.UnmarshalWithParams(m.signedAttrs, &attrs, "set"); err != nil {
+			return fmt.Errorf("could not parse the authenticated attributes: %v", err)
+		}
+
+		h := m.hash.New()
+		h.Write(content)
+
+		found := false
+
+		for _, a := range attrs {
+			if !a.Type.Equal(oidMessageDigest) {
+				continue
+			}
+
+			var digest []byte
+
+			if _, err := asn1.Unmarshal(a.Values.Bytes, &digest); err != nil {
+				return fmt.Errorf("could not parse the message digest: %v", err)
+			}
+
+			if !bytes.Equal(digest, h.Sum(nil)) {
+				return errors.New("message digest mismatch")
+			}
+
+			found = true
+		}
+
+		if !found {
+			return errors.New("missing message digest attribute")
+		}
+
+		signed = m.signedAttrs
+	}
+
+	alg, err := x509SignatureAlgorithm(cert.PublicKeyAlgorithm, m.hash)
+	if err != nil {
+		return err
+	}
+
+	return cert.CheckSignature(alg, signed, m.signature)
+}
+
+func x509SignatureAlgorithm(pub x509.PublicKeyAlgorithm, hash crypto.Hash) (x509.SignatureAlgorithm, error) {
+	algorithms := map[x509.PublicKeyAlgorithm]map[crypto.Hash]x509.SignatureAlgorithm{
+		x509.RSA: {
+			crypto.SHA1:   x509.SHA1WithRSA,
+			crypto.SHA256: x509.SHA256WithRSA,
+			crypto.SHA384: x509.SHA384WithRSA,
+			crypto.SHA512: x509.SHA512WithRSA,
+		},
+		x509.ECDSA: {
+			crypto.SHA1:   x509.ECDSAWithSHA1,
+			crypto.SHA256: x509.ECDSAWithSHA256,
+			crypto.SHA384: x509.ECDSAWithSHA384,
+			crypto.SHA512: x509.ECDSAWithSHA512,
+		},
+	}
+
+	alg, ok := algorithms[pub][hash]
+	if !ok {
+		return x509.UnknownSignatureAlgorithm, fmt.Errorf("unsupported %s key with %s digest", pub, hash)
+	}
+
+	return alg, nil
+}
+
+// asn1SequenceElements returns the elements of the DER sequence b, which must have at least minElems elements.
+func asn1SequenceElements(b []byte, minElems int) ([]asn1.RawValue, error) {
+	seq := asn1.RawValue{}
+
+	rest, err := asn1.Unmarshal(b, &seq)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rest) > 0 || seq.Tag != asn1.TagSequence {
+		return nil, errors.New("not a single sequence")
+	}
+
+	elems, err := asn1Elements(seq.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(elems) < minElems {
+		return nil, fmt.Errorf("%d elements, expected at least %d", len(elems), minElems)
+	}
+
+	return elems, nil
+}
+
+// asn1Elements returns the successive DER elements found in b.
+func asn1Elements(b []byte) ([]asn1.RawValue, error) {
+	elems := make([]asn1.RawValue, 0)
+
+	for len(b) > 0 {
+		var (
+			v   asn1.RawValue
+			err error
+		)
+
+		if b, err = asn1.Unmarshal(b, &v); err != nil {
+			return nil, err
+		}
+
+		elems = append(elems, v)
+	}
+
+	return elems, nil
+}
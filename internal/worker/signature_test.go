@@ -0,0 +1,184 @@
+package worker
+
+import (
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
+	"github.com/ulikunitz/xz"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	testSignedModule      = "testdata/signature/kmm-test-signed.ko"
+	testSignedKeyIDModule = "testdata/signature/kmm-test-signed-keyid.ko"
+	testSigningCert       = "testdata/signature/cert.pem"
+	testOtherCert         = "testdata/signature/other-cert.pem"
+)
+
+var _ = Describe("verifyModuleSignature", func() {
+	readCerts := func(path string) []*x509.Certificate {
+		GinkgoHelper()
+
+		certs, err := readPEMCertificates(path)
+		Expect(err).NotTo(HaveOccurred())
+
+		return certs
+	}
+
+	DescribeTable(
+		"should accept modules signed with a trusted certificate",
+		func(path string) {
+			Expect(verifyModuleSignature(path, readCerts(testSigningCert))).To(Succeed())
+		},
+		Entry("issuer and serial number", testSignedModule),
+		Entry("subject key identifier", testSignedKeyIDModule),
+	)
+
+	It("should accept compressed modules", func() {
+		b, err := os.ReadFile(testSignedModule)
+		Expect(err).NotTo(HaveOccurred())
+
+		path := filepath.Join(GinkgoT().TempDir(), "kmm-test.ko.xz")
+
+		f, err := os.Create(path)
+		Expect(err).NotTo(HaveOccurred())
+		defer f.Close()
+
+		w, err := xz.NewWriter(f)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = w.Write(b)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(w.Close()).To(Succeed())
+
+		Expect(verifyModuleSignature(path, readCerts(testSigningCert))).To(Succeed())
+	})
+
+	It("should reject modules signed with another certificate", func() {
+		Expect(
+			verifyModuleSignature(testSignedModule, readCerts(testOtherCert)),
+		).To(
+			MatchError(ErrInvalidSignature),
+		)
+	})
+
+	It("should reject unsigned modules", func() {
+		Expect(
+			verifyModuleSignature(filepath.Join(testModulesDir, "extra", "kmm-test.ko"), readCerts(testSigningCert)),
+		).To(
+			MatchError(ErrModuleNotSigned),
+		)
+	})
+
+	It("should reject modules modified after signing", func() {
+		b, err := os.ReadFile(testSignedModule)
+		Expect(err).NotTo(HaveOccurred())
+
+		b[100] ^= 0xff
+
+		path := filepath.Join(GinkgoT().TempDir(), "kmm-test.ko")
+		Expect(os.WriteFile(path, b, 0644)).To(Succeed())
+
+		Expect(
+			verifyModuleSignature(path, readCerts(testSigningCert)),
+		).To(
+			MatchError(ErrInvalidSignature),
+		)
+	})
+})
+
+var _ = Describe("signatureVerifier_VerifyModules", func() {
+	var sv *signatureVerifier
+
+	writeEFIVar := func(name string, value []byte) {
+		GinkgoHelper()
+
+		// attributes
+		b := []byte{0x07, 0, 0, 0}
+
+		Expect(os.WriteFile(filepath.Join(sv.efiVarsDir, name), append(b, value...), 0644)).To(Succeed())
+	}
+
+	// efiSignatureList returns an EFI_SIGNATURE_LIST holding the certificate in the PEM file at path.
+	efiSignatureList := func(path string) []byte {
+		GinkgoHelper()
+
+		b, err := os.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+
+		block, _ := pem.Decode(b)
+		Expect(block).NotTo(BeNil())
+
+		sigSize := 16 + len(block.Bytes)
+
+		list := make([]byte, 28, 28+sigSize)
+		copy(list, efiCertX509GUID)
+		binary.LittleEndian.PutUint32(list[16:], uint32(28+sigSize))
+		binary.LittleEndian.PutUint32(list[24:], uint32(sigSize))
+
+		// owner GUID
+		list = append(list, make([]byte, 16)...)
+
+		return append(list, block.Bytes...)
+	}
+
+	BeforeEach(func() {
+		sv = &signatureVerifier{
+			certPath:   testSigningCert,
+			efiVarsDir: GinkgoT().TempDir(),
+			logger:     GinkgoLogr,
+		}
+	})
+
+	It("should use the configured certificate", func() {
+		spec := kmmv1beta1.SignatureVerificationSpec{CertSecret: &v1.LocalObjectReference{Name: "cert"}}
+
+		Expect(sv.VerifyModules([]string{testSignedModule}, &spec)).To(Succeed())
+
+		sv.certPath = testOtherCert
+
+		Expect(
+			sv.VerifyModules([]string{testSignedModule}, &spec),
+		).To(
+			MatchError(ErrInvalidSignature),
+		)
+	})
+
+	It("should not verify anything if Secure Boot is disabled", func() {
+		writeEFIVar(efiSecureBootVar, []byte{0})
+
+		Expect(
+			sv.VerifyModules([]string{filepath.Join(testModulesDir, "extra", "kmm-test.ko")}, &kmmv1beta1.SignatureVerificationSpec{UEFIKeys: true}),
+		).To(
+			Succeed(),
+		)
+	})
+
+	It("should use the certificates of the UEFI db and MOK list if Secure Boot is enabled", func() {
+		writeEFIVar(efiSecureBootVar, []byte{1})
+		writeEFIVar(efiDBVar, efiSignatureList(testOtherCert))
+
+		paths := []string{testSignedModule}
+		spec := kmmv1beta1.SignatureVerificationSpec{UEFIKeys: true}
+
+		Expect(sv.VerifyModules(paths, &spec)).To(MatchError(ErrInvalidSignature))
+
+		writeEFIVar(efiMokListRTVar, efiSignatureList(testSigningCert))
+
+		Expect(sv.VerifyModules(paths, &spec)).To(Succeed())
+	})
+
+	It("should return ErrInvalidConfig if no key source is set", func() {
+		Expect(
+			sv.VerifyModules([]string{testSignedModule}, &kmmv1beta1.SignatureVerificationSpec{}),
+		).To(
+			MatchError(ErrInvalidConfig),
+		)
+	})
+})
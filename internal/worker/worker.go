@@ -2,50 +2,236 @@ package worker
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
-	cp "github.com/otiai10/copy"
 	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/nmc"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/utils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
 )
 
 //go:generate mockgen -source=worker.go -package=worker -destination=mock_worker.go
 
 type Worker interface {
+	// CheckKmod makes sure that the configured modules are still live in the kernel.
+	CheckKmod(cfg *kmmv1beta1.ModuleConfig) error
+	GetKmodStatus(cfg *kmmv1beta1.ModuleConfig) (*KmodStatus, error)
 	LoadKmod(ctx context.Context, cfg *kmmv1beta1.ModuleConfig, firmwareMountPath string) error
+	// Result returns the outcome of the last LoadKmod or UnloadKmod call.
+	Result() *kmmv1beta1.WorkerResult
 	SetFirmwareClassPath(value string) error
+	// SetParameters updates the parameters of a loaded module through sysfs, without reloading it.
+	SetParameters(cfg *kmmv1beta1.ModuleConfig) error
 	UnloadKmod(ctx context.Context, cfg *kmmv1beta1.ModuleConfig, firmwareMountPath string) error
+	// WriteBlacklist writes the modprobe configuration file at path that prevents the in-tree modules replaced by cfg
+	// from being loaded again, for example by udev after a reboot.
+	// The modules are recorded in the result of the last LoadKmod call.
+	WriteBlacklist(cfg *kmmv1beta1.ModuleConfig, path string) error
+	// RemoveBlacklist removes the file written by WriteBlacklist, if it exists.
+	RemoveBlacklist(path string) error
+	// RestoreInTreeModules loads modules from the host's modules tree, typically the in-tree modules that were removed
+	// by LoadKmod.
+	// The outcome is recorded in the result of the last UnloadKmod call.
+	RestoreInTreeModules(ctx context.Context, modules []string) error
 }
 
 type worker struct {
 	logger logr.Logger
 	mr     ModprobeRunner
 	fh     utils.FSHelper
+	msr    ModuleStateReader
+	kw     KmsgWatcher
+	pw     ModuleParametersWriter
+	fi     FirmwareInstaller
+	sv     SignatureVerifier
+	result kmmv1beta1.WorkerResult
 }
 
-func NewWorker(mr ModprobeRunner, fh utils.FSHelper, logger logr.Logger) Worker {
+func NewWorker(
+	mr ModprobeRunner,
+	fh utils.FSHelper,
+	msr ModuleStateReader,
+	kw KmsgWatcher,
+	pw ModuleParametersWriter,
+	fi FirmwareInstaller,
+	sv SignatureVerifier,
+	logger logr.Logger,
+) Worker {
 	return &worker{
 		logger: logger,
 		mr:     mr,
 		fh:     fh,
+		msr:    msr,
+		kw:     kw,
+		pw:     pw,
+		fi:     fi,
+		sv:     sv,
 	}
 }
 
-const sharedFilesDir = "/tmp"
+// imageModulesDir returns the directory where the modules tree for the configured kernel was copied from the image.
+func imageModulesDir(cfg *kmmv1beta1.ModuleConfig) string {
+	return filepath.Join(SharedFilesDir, cfg.Modprobe.DirName, "lib", "modules", cfg.KernelVersion)
+}
+
+// GetKmodStatus returns the state of the configured module in the running kernel, as well as the srcversion of the
+// module file copied from the image, if it can be found.
+func (w *worker) GetKmodStatus(cfg *kmmv1beta1.ModuleConfig) (*KmodStatus, error) {
+	moduleName := cfg.Modprobe.ModuleName
+	if moduleName == "" {
+		return nil, errors.New("moduleName is not set in the configuration")
+	}
+
+	state, err := w.msr.GetModuleState(moduleName)
+	if err != nil {
+		return nil, fmt.Errorf("could not get the state of module %s: %v", moduleName, err)
+	}
+
+	status := KmodStatus{ModuleState: *state}
+
+	status.ExpectedSrcVersion, err = w.msr.GetImageSrcVersion(imageModulesDir(cfg), moduleName)
+	if err != nil {
+		w.logger.Info(utils.WarnString("could not determine the srcversion of the module shipped in the image"), "error", err)
+	}
+
+	return &status, nil
+}
+
+// CheckKmod returns an error wrapping ErrModuleNotLoaded if one of the configured modules is not live in the kernel,
+// for example because it was removed with rmmod after KMM loaded it.
+func (w *worker) CheckKmod(cfg *kmmv1beta1.ModuleConfig) error {
+	names := configuredModules(cfg)
+
+	for _, name := range names {
+		state, err := w.msr.GetModuleState(name)
+		if err != nil {
+			return fmt.Errorf("could not get the state of module %s: %v", name, err)
+		}
+
+		if !state.Loaded {
+			return fmt.Errorf("%w: module %s is not present in %s", ErrModuleNotLoaded, state.Name, procModulesPath)
+		}
+
+		if state.InitState != "" && state.InitState != "live" {
+			return fmt.Errorf("%w: module %s is in state %q instead of live", ErrModuleNotLoaded, state.Name, state.InitState)
+		}
+	}
+
+	w.logger.Info("All modules are loaded", "modules", names)
+
+	return nil
+}
+
+func (w *worker) Result() *kmmv1beta1.WorkerResult {
+	res := w.result
+	return &res
+}
+
+// runModprobe runs modprobe and records the outcome of the invocation in the worker's result.
+func (w *worker) runModprobe(ctx context.Context, args ...string) error {
+	res, err := w.mr.Run(ctx, args...)
+
+	w.recordRun(args, res)
+
+	return err
+}
+
+// recordRun records the arguments and the outcome of the last kmod invocation in the worker's result.
+func (w *worker) recordRun(args []string, res *ModprobeResult) {
+	w.result.ModprobeArgs = args
+
+	if res != nil {
+		w.result.ExitCode = int32(res.ExitCode)
+		w.result.Stderr = res.Stderr
+		w.result.Modules = append(w.result.Modules, res.Modules...)
+	}
+}
+
+// startKernelLogCapture starts capturing the kernel log.
+// It returns nil if the kernel log cannot be read, as it is not needed to load or unload modules.
+func (w *worker) startKernelLogCapture() KmsgCapture {
+	kc, err := w.kw.Start()
+	if err != nil {
+		w.logger.Info(utils.WarnString("Could not capture the kernel log"), "error", err)
+		return nil
+	}
+
+	return kc
+}
+
+// collectKernelLog logs the kernel log records related to cfg's modules.
+// If the operation failed, the records are also added to the result.
+func (w *worker) collectKernelLog(kc KmsgCapture, cfg *kmmv1beta1.ModuleConfig, opErr error) {
+	if kc == nil {
+		return
+	}
+
+	records, err := kc.Stop()
+	if err != nil {
+		w.logger.Info(utils.WarnString("Could not read the kernel log"), "error", err)
+		return
+	}
+
+	modules := append([]string{cfg.Modprobe.ModuleName}, configuredModules(cfg)...)
+	modules = append(modules, w.result.Modules...)
+
+	for _, r := range filterKmsgRecords(records, modules) {
+		w.logger.Info("Kernel log", "record", r.String())
+
+		if opErr != nil {
+			w.result.KernelLog = append(w.result.KernelLog, r.String())
+		}
+	}
+}
 
 func (w *worker) LoadKmod(ctx context.Context, cfg *kmmv1beta1.ModuleConfig, firmwareMountPath string) error {
+	w.result = kmmv1beta1.WorkerResult{Action: ActionLoad}
+
+	kc := w.startKernelLogCapture()
 
-	inTreeModulesToRemove := cfg.InTreeModulesToRemove
-	// [TODO] - remove handling cfg.InTreeModuleToRemove once we cease to support it
-	if inTreeModulesToRemove == nil && cfg.InTreeModuleToRemove != "" {
-		inTreeModulesToRemove = []string{cfg.InTreeModuleToRemove}
+	err := w.loadKmod(ctx, cfg, firmwareMountPath)
+
+	w.collectKernelLog(kc, cfg, err)
+
+	if err == nil {
+		// The operator compares the boot ID of the node to this one to find out if the module was lost in a reboot.
+		if w.result.BootID, err = w.msr.GetBootID(); err != nil {
+			w.logger.Info(utils.WarnString("Could not read the boot ID"), "error", err)
+			err = nil
+		}
+	}
+
+	return err
+}
+
+func (w *worker) loadKmod(ctx context.Context, cfg *kmmv1beta1.ModuleConfig, firmwareMountPath string) error {
+	if cfg.Modprobe.RawArgs == nil {
+		w.ensureModulesDep(cfg)
 	}
 
-	if inTreeModulesToRemove != nil {
+	if cfg.Modprobe.SignatureVerification != nil && cfg.Modprobe.RawArgs == nil {
+		if err := w.verifySignatures(cfg); err != nil {
+			return fmt.Errorf("could not verify the signature of module %s: %w", cfg.Modprobe.ModuleName, err)
+		}
+	}
+
+	if cfg.Modprobe.RawArgs == nil && !forcesVermagic(cfg.Modprobe.Args) {
+		if err := w.checkVermagic(cfg); err != nil {
+			return err
+		}
+	}
+
+	if inTreeModulesToRemove := nmc.InTreeModulesToRemove(cfg); inTreeModulesToRemove != nil {
 		w.logger.Info("Unloading in-tree modules", "names", inTreeModulesToRemove)
 		modulesToUnload := make([]string, 0, len(inTreeModulesToRemove))
 		for _, module := range inTreeModulesToRemove {
@@ -63,38 +249,50 @@ func (w *worker) LoadKmod(ctx context.Context, cfg *kmmv1beta1.ModuleConfig, fir
 		}
 
 		if len(modulesToUnload) > 0 {
+			removedBefore := len(w.result.Modules)
+
 			runArgs := append([]string{"-rv"}, modulesToUnload...)
-			if err := w.mr.Run(ctx, runArgs...); err != nil {
-				return fmt.Errorf("could not remove in-tree modules %s: %v", strings.Join(modulesToUnload, ""), err)
+			if err := w.runModprobe(ctx, runArgs...); err != nil {
+				return fmt.Errorf("could not remove in-tree modules %s: %w", strings.Join(modulesToUnload, ""), err)
 			}
+
+			w.result.RemovedInTreeModules = removedModules(modulesToUnload, w.result.Modules[removedBefore:])
 		}
 	}
 
 	// prepare firmware
 	if cfg.Modprobe.FirmwarePath != "" {
-		imageFirmwarePath := filepath.Join(sharedFilesDir, cfg.Modprobe.FirmwarePath)
+		imageFirmwarePath := filepath.Join(SharedFilesDir, cfg.Modprobe.FirmwarePath)
 		w.logger.Info("preparing firmware for loading", "image directory", imageFirmwarePath, "host mount directory", firmwareMountPath)
-		options := cp.Options{
-			OnError: func(src, dest string, err error) error {
-				if err != nil {
-					return fmt.Errorf("internal copy error: failed to copy from %s to %s: %v", src, dest, err)
-				}
-				return nil
-			},
-		}
-		if err := cp.Copy(imageFirmwarePath, firmwareMountPath, options); err != nil {
-			return fmt.Errorf("failed to copy firmware from path %s to path %s: %v", imageFirmwarePath, firmwareMountPath, err)
+		if err := w.fi.Install(cfg.Modprobe.ModuleName, imageFirmwarePath, firmwareMountPath); err != nil {
+			return fmt.Errorf("%w: failed to install firmware from path %s to path %s: %v", ErrFirmwareCopyFailed, imageFirmwarePath, firmwareMountPath, err)
 		}
 	}
 
 	moduleName := cfg.Modprobe.ModuleName
 
+	if cfg.Modprobe.ModulesGraph != nil {
+		if err := w.loadModulesGraph(ctx, cfg); err != nil {
+			return err
+		}
+
+		return w.verifyLoaded(cfg)
+	}
+
+	if cfg.Modprobe.ModuleFiles != nil {
+		if err := w.loadModuleFiles(ctx, cfg); err != nil {
+			return err
+		}
+
+		return w.verifyLoaded(cfg)
+	}
+
 	var args []string
 
 	if cfg.Modprobe.RawArgs != nil {
 		args = cfg.Modprobe.RawArgs.Load
 	} else {
-		args = []string{"-vd", filepath.Join(sharedFilesDir, cfg.Modprobe.DirName)}
+		args = []string{"-vd", filepath.Join(SharedFilesDir, cfg.Modprobe.DirName)}
 
 		if cfg.Modprobe.Args != nil {
 			args = append(args, cfg.Modprobe.Args.Load...)
@@ -104,7 +302,158 @@ func (w *worker) LoadKmod(ctx context.Context, cfg *kmmv1beta1.ModuleConfig, fir
 		args = append(args, cfg.Modprobe.Parameters...)
 	}
 
-	return w.mr.Run(ctx, args...)
+	if err := w.runModprobe(ctx, args...); err != nil {
+		return err
+	}
+
+	if cfg.Modprobe.RawArgs != nil {
+		w.logger.Info("rawArgs are used; not verifying that the module was loaded")
+		return nil
+	}
+
+	return w.verifyLoaded(cfg)
+}
+
+// configuredModules returns the names of the modules that the worker is asked to load.
+func configuredModules(cfg *kmmv1beta1.ModuleConfig) []string {
+	if cfg.Modprobe.ModulesLoadingOrder != nil {
+		return cfg.Modprobe.ModulesLoadingOrder
+	}
+
+	if cfg.Modprobe.ModulesGraph != nil {
+		names := make([]string, 0, len(cfg.Modprobe.ModulesGraph))
+
+		for _, n := range cfg.Modprobe.ModulesGraph {
+			names = append(names, n.Name)
+		}
+
+		return names
+	}
+
+	if cfg.Modprobe.ModuleFiles != nil {
+		names := make([]string, 0, len(cfg.Modprobe.ModuleFiles))
+
+		for _, f := range cfg.Modprobe.ModuleFiles {
+			names = append(names, moduleNameFromPath(f))
+		}
+
+		return names
+	}
+
+	return []string{cfg.Modprobe.ModuleName}
+}
+
+// forcesVermagic returns true if the modprobe arguments make it ignore the version magic of modules.
+func forcesVermagic(args *kmmv1beta1.ModprobeArgs) bool {
+	if args == nil {
+		return false
+	}
+
+	return slices.ContainsFunc(args.Load, func(arg string) bool {
+		return arg == "-f" || arg == "--force" || arg == "--force-vermagic"
+	})
+}
+
+// checkVermagic makes sure that the modules shipped in the image were built for the target kernel, which would
+// otherwise reject them with a terse "Exec format error".
+func (w *worker) checkVermagic(cfg *kmmv1beta1.ModuleConfig) error {
+	dir := imageModulesDir(cfg)
+
+	for _, name := range configuredModules(cfg) {
+		info, err := w.msr.GetImageModuleInfo(dir, name)
+		if err != nil {
+			w.logger.Info(utils.WarnString("could not read the module information; not checking its vermagic"), "module", name, "error", err)
+			continue
+		}
+
+		if err = info.CheckKernel(cfg.KernelVersion); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// verifySignatures verifies the signature of the module files that the worker loads from the image.
+func (w *worker) verifySignatures(cfg *kmmv1beta1.ModuleConfig) error {
+	dir := imageModulesDir(cfg)
+
+	if cfg.Modprobe.ModuleFiles != nil {
+		paths := make([]string, 0, len(cfg.Modprobe.ModuleFiles))
+
+		for _, f := range cfg.Modprobe.ModuleFiles {
+			paths = append(paths, filepath.Join(dir, f))
+		}
+
+		return w.sv.VerifyModules(paths, cfg.Modprobe.SignatureVerification)
+	}
+
+	deps, err := readModulesDep(dir)
+	if err != nil {
+		return fmt.Errorf("could not read the module dependencies: %v", err)
+	}
+
+	names := configuredModules(cfg)
+
+	paths := make([]string, 0, len(names))
+	seen := sets.New[string]()
+
+	for _, name := range names {
+		md, ok := deps[normalizeModuleName(name)]
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrModuleNotFound, name)
+		}
+
+		for _, p := range append([]string{md.Path}, md.Deps...) {
+			path := filepath.Join(dir, p)
+
+			if seen.Has(path) {
+				continue
+			}
+
+			seen.Insert(path)
+
+			// dependencies that are not shipped in the image are not loaded from it
+			if _, err = os.Stat(path); err != nil && p != md.Path {
+				continue
+			}
+
+			paths = append(paths, path)
+		}
+	}
+
+	return w.sv.VerifyModules(paths, cfg.Modprobe.SignatureVerification)
+}
+
+// verifyLoaded makes sure that the module is live in the kernel and that it was loaded from the file shipped in the
+// image, rather than being an older version that was already present.
+func (w *worker) verifyLoaded(cfg *kmmv1beta1.ModuleConfig) error {
+	status, err := w.GetKmodStatus(cfg)
+	if err != nil {
+		return fmt.Errorf("%w: could not get the module state: %v", ErrVerificationFailed, err)
+	}
+
+	if !status.Loaded {
+		return fmt.Errorf("%w: module %s is not present in %s after loading", ErrVerificationFailed, status.Name, procModulesPath)
+	}
+
+	if status.InitState != "" && status.InitState != "live" {
+		return fmt.Errorf("%w: module %s is in state %q instead of live", ErrVerificationFailed, status.Name, status.InitState)
+	}
+
+	if status.ExpectedSrcVersion != "" && status.SrcVersion != status.ExpectedSrcVersion {
+		return fmt.Errorf(
+			"%w: module %s has srcversion %q in the kernel, while the file in the image has %q; another version of the module was probably already loaded",
+			ErrVerificationFailed,
+			status.Name,
+			status.SrcVersion,
+			status.ExpectedSrcVersion,
+		)
+	}
+
+	w.logger.Info("Module loaded", "name", status.Name, "srcversion", status.SrcVersion, "refcnt", status.RefCount, "taint", status.Taint)
+
+	return nil
 }
 
 var firmwareClassPathLocation = FirmwareClassPathLocation
@@ -131,16 +480,57 @@ func (w *worker) SetFirmwareClassPath(value string) error {
 	return nil
 }
 
+func (w *worker) SetParameters(cfg *kmmv1beta1.ModuleConfig) error {
+	w.result = kmmv1beta1.WorkerResult{Action: ActionSetParameters}
+
+	moduleName := cfg.Modprobe.ModuleName
+	if moduleName == "" {
+		return fmt.Errorf("%w: moduleName is not set", ErrInvalidConfig)
+	}
+
+	if cfg.Modprobe.RawArgs != nil {
+		return fmt.Errorf("%w: parameters cannot be updated when rawArgs are used", ErrInvalidConfig)
+	}
+
+	w.logger.Info("Updating module parameters", "name", moduleName, "parameters", cfg.Modprobe.Parameters)
+
+	applied, err := w.pw.WriteParameters(moduleName, cfg.Modprobe.Parameters)
+	if err != nil {
+		return fmt.Errorf("could not update the parameters of module %s: %w", moduleName, err)
+	}
+
+	w.result.Parameters = applied
+
+	w.logger.Info("Module parameters updated", "name", moduleName, "values", applied)
+
+	return nil
+}
+
 func (w *worker) UnloadKmod(ctx context.Context, cfg *kmmv1beta1.ModuleConfig, firmwareMountPath string) error {
+	w.result = kmmv1beta1.WorkerResult{Action: ActionUnload}
+
+	kc := w.startKernelLogCapture()
 
+	err := w.unloadKmod(ctx, cfg, firmwareMountPath)
+
+	w.collectKernelLog(kc, cfg, err)
+
+	return err
+}
+
+func (w *worker) unloadKmod(ctx context.Context, cfg *kmmv1beta1.ModuleConfig, firmwareMountPath string) error {
 	moduleName := cfg.Modprobe.ModuleName
 
+	if cfg.Modprobe.RawArgs == nil {
+		w.ensureModulesDep(cfg)
+	}
+
 	var args []string
 
 	if cfg.Modprobe.RawArgs != nil {
 		args = cfg.Modprobe.RawArgs.Unload
 	} else {
-		args = []string{"-rvd", filepath.Join(sharedFilesDir, cfg.Modprobe.DirName)}
+		args = []string{"-rvd", filepath.Join(SharedFilesDir, cfg.Modprobe.DirName)}
 
 		if cfg.Modprobe.Args != nil {
 			args = append(args, cfg.Modprobe.Args.Unload...)
@@ -149,16 +539,32 @@ func (w *worker) UnloadKmod(ctx context.Context, cfg *kmmv1beta1.ModuleConfig, f
 		args = append(args, moduleName)
 	}
 
-	w.logger.Info("Unloading module", "name", moduleName)
+	if moduleName != "" {
+		if err := w.waitUntilUnused(ctx, moduleName, cfg.Modprobe.UnloadWaitTimeout); err != nil {
+			return fmt.Errorf("could not unload module %s: %w", moduleName, err)
+		}
+	}
 
-	if err := w.mr.Run(ctx, args...); err != nil {
-		return fmt.Errorf("could not unload module %s: %v", moduleName, err)
+	if cfg.Modprobe.ModulesGraph != nil {
+		if err := w.unloadModulesGraph(ctx, cfg); err != nil {
+			return err
+		}
+	} else if cfg.Modprobe.ModuleFiles != nil {
+		if err := w.unloadModuleFiles(ctx, cfg); err != nil {
+			return err
+		}
+	} else {
+		w.logger.Info("Unloading module", "name", moduleName)
+
+		if err := w.runModprobe(ctx, args...); err != nil {
+			return fmt.Errorf("could not unload module %s: %w", moduleName, err)
+		}
 	}
 
 	//remove firmware files only (no directories)
 	if cfg.Modprobe.FirmwarePath != "" {
-		imageFirmwarePath := filepath.Join(sharedFilesDir, cfg.Modprobe.FirmwarePath)
-		err := w.fh.RemoveSrcFilesFromDst(imageFirmwarePath, firmwareMountPath)
+		imageFirmwarePath := filepath.Join(SharedFilesDir, cfg.Modprobe.FirmwarePath)
+		err := w.fi.Remove(cfg.Modprobe.ModuleName, imageFirmwarePath, firmwareMountPath)
 		if err != nil {
 			w.logger.Info(utils.WarnString("failed to remove all firmware blobs"), "error", err)
 		}
@@ -166,3 +572,87 @@ func (w *worker) UnloadKmod(ctx context.Context, cfg *kmmv1beta1.ModuleConfig, f
 
 	return nil
 }
+
+// unloadWaitBackoff is the delay between two checks of a module that is still in use.
+var unloadWaitBackoff = wait.Backoff{
+	Duration: time.Second,
+	Factor:   2,
+	Cap:      30 * time.Second,
+	Steps:    math.MaxInt32,
+}
+
+// waitUntilUnused waits for the module to be released by the modules and processes using it.
+// It returns an error wrapping ErrModuleInUse if the module is still in use after timeout, and records its users in
+// the result.
+// If the module cannot be inspected, waitUntilUnused logs a warning and returns nil, leaving modprobe to fail if the
+// module is still in use.
+func (w *worker) waitUntilUnused(ctx context.Context, moduleName string, timeout *metav1.Duration) error {
+	deadline := time.Now()
+
+	if timeout != nil {
+		deadline = deadline.Add(timeout.Duration)
+	}
+
+	backoff := unloadWaitBackoff
+
+	for {
+		state, err := w.msr.GetModuleState(moduleName)
+		if err != nil {
+			w.logger.Info(utils.WarnString("could not check if the module is in use"), "name", moduleName, "error", err)
+			return nil
+		}
+
+		if !state.Loaded || (state.RefCount == 0 && len(state.Holders) == 0 && len(state.UsedBy) == 0) {
+			return nil
+		}
+
+		users := w.moduleUsers(state)
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			w.result.BlockedBy = users
+
+			if len(users) == 0 {
+				return fmt.Errorf("%w: reference count is %d", ErrModuleInUse, state.RefCount)
+			}
+
+			return fmt.Errorf(
+				"%w: reference count is %d; blocked by %s",
+				ErrModuleInUse,
+				state.RefCount,
+				strings.Join(users, ", "),
+			)
+		}
+
+		delay := min(backoff.Step(), remaining)
+
+		w.logger.Info("Module is in use; waiting", "name", moduleName, "refcnt", state.RefCount, "users", users, "delay", delay)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// moduleUsers returns the modules, as "module:<name>", and the processes, as "process:<pid> (<command>)", using a
+// loaded module.
+func (w *worker) moduleUsers(state *ModuleState) []string {
+	var users []string
+
+	for _, m := range sets.List(sets.New(state.Holders...).Insert(state.UsedBy...)) {
+		users = append(users, "module:"+m)
+	}
+
+	processes, err := w.msr.GetModuleProcesses(state.Name)
+	if err != nil {
+		w.logger.Info(utils.WarnString("could not look for processes using the module"), "name", state.Name, "error", err)
+	}
+
+	for _, p := range processes {
+		users = append(users, "process:"+p)
+	}
+
+	return users
+}
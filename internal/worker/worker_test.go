@@ -6,18 +6,25 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
+	"github.com/rh-ecosystem-edge/kernel-module-management/internal/modinfo"
 	"github.com/rh-ecosystem-edge/kernel-module-management/internal/utils"
 	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
 )
 
 var _ = Describe("worker_LoadKmod", func() {
 	var (
 		fh       *utils.MockFSHelper
 		mr       *MockModprobeRunner
+		msr      *MockModuleStateReader
+		fi       *MockFirmwareInstaller
+		sv       *MockSignatureVerifier
 		w        Worker
 		imageDir string
 		hostDir  string
@@ -27,7 +34,12 @@ var _ = Describe("worker_LoadKmod", func() {
 		ctrl := gomock.NewController(GinkgoT())
 		fh = utils.NewMockFSHelper(ctrl)
 		mr = NewMockModprobeRunner(ctrl)
-		w = NewWorker(mr, fh, GinkgoLogr)
+		msr = NewMockModuleStateReader(ctrl)
+		fi = NewMockFirmwareInstaller(ctrl)
+		sv = NewMockSignatureVerifier(ctrl)
+		w = NewWorker(mr, fh, msr, newFakeKmsgWatcher(""), nil, fi, sv, GinkgoLogr)
+
+		msr.EXPECT().GetBootID().AnyTimes()
 
 		var err error
 		imageDir, err = os.MkdirTemp("", "imageDir")
@@ -46,27 +58,119 @@ var _ = Describe("worker_LoadKmod", func() {
 	ctx := context.TODO()
 
 	const (
-		dirName    = "/dir"
-		imageName  = "some-image-name"
-		moduleName = "test"
+		dirName       = "/dir"
+		imageName     = "some-image-name"
+		kernelVersion = "5.14.0"
+		moduleName    = "test"
+		srcVersion    = "8F3A4E1D7C2B9A0F6E5D4C3"
 	)
 
+	modulesDir := filepath.Join(SharedFilesDir, dirName, "lib", "modules", kernelVersion)
+
+	expectVermagicCheck := func(name string) {
+		msr.EXPECT().GetImageModuleInfo(modulesDir, name).Return(&modinfo.ModuleInfo{Name: name, Vermagic: kernelVersion + " SMP mod_unload "}, nil)
+	}
+
+	expectVerification := func() {
+		msr.EXPECT().GetModuleState(moduleName).Return(&ModuleState{Name: moduleName, Loaded: true, InitState: "live", SrcVersion: srcVersion}, nil)
+		msr.EXPECT().GetImageSrcVersion(modulesDir, moduleName).Return(srcVersion, nil)
+	}
+
 	It("should return an error if modprobe failed", func() {
 		cfg := v1beta1.ModuleConfig{
 			ContainerImage: imageName,
+			KernelVersion:  kernelVersion,
+			Modprobe: v1beta1.ModprobeSpec{
+				ModuleName: moduleName,
+				DirName:    dirName,
+			},
+		}
+
+		expectVermagicCheck(moduleName)
+		mr.EXPECT().
+			Run(ctx, "-vd", filepath.Join(SharedFilesDir, dirName), moduleName).
+			Return(&ModprobeResult{ExitCode: 1, Stderr: []string{"some error"}}, errors.New("random error"))
+
+		Expect(
+			w.LoadKmod(ctx, &cfg, ""),
+		).To(
+			HaveOccurred(),
+		)
+
+		Expect(
+			w.Result(),
+		).To(
+			Equal(&v1beta1.WorkerResult{
+				Action:       ActionLoad,
+				ModprobeArgs: []string{"-vd", filepath.Join(SharedFilesDir, dirName), moduleName},
+				ExitCode:     1,
+				Stderr:       []string{"some error"},
+			}),
+		)
+	})
+
+	It("should record the boot ID in the result once the module is loaded", func() {
+		ctrl := gomock.NewController(GinkgoT())
+		msr = NewMockModuleStateReader(ctrl)
+		w = NewWorker(mr, fh, msr, newFakeKmsgWatcher(""), nil, fi, sv, GinkgoLogr)
+
+		cfg := v1beta1.ModuleConfig{
+			ContainerImage: imageName,
+			KernelVersion:  kernelVersion,
 			Modprobe: v1beta1.ModprobeSpec{
 				ModuleName: moduleName,
 				DirName:    dirName,
 			},
 		}
 
-		mr.EXPECT().Run(ctx, "-vd", filepath.Join(sharedFilesDir, dirName), moduleName).Return(errors.New("random error"))
+		gomock.InOrder(
+			msr.EXPECT().GetImageModuleInfo(modulesDir, moduleName).Return(&modinfo.ModuleInfo{Vermagic: kernelVersion}, nil),
+			mr.EXPECT().Run(ctx, "-vd", filepath.Join(SharedFilesDir, dirName), moduleName).Return(&ModprobeResult{}, nil),
+			msr.EXPECT().GetModuleState(moduleName).Return(&ModuleState{Name: moduleName, Loaded: true, InitState: "live"}, nil),
+			msr.EXPECT().GetImageSrcVersion(modulesDir, moduleName),
+			msr.EXPECT().GetBootID().Return("boot-id", nil),
+		)
+
+		Expect(
+			w.LoadKmod(ctx, &cfg, ""),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(w.Result().BootID).To(Equal("boot-id"))
+	})
+
+	It("should add the related kernel log records to the result if modprobe failed", func() {
+		w = NewWorker(mr, fh, msr, newFakeKmsgWatcher(testKmsg), nil, fi, sv, GinkgoLogr)
+
+		cfg := v1beta1.ModuleConfig{
+			ContainerImage: imageName,
+			KernelVersion:  kernelVersion,
+			Modprobe: v1beta1.ModprobeSpec{
+				ModuleName: "kmm_test",
+				DirName:    dirName,
+			},
+		}
+
+		expectVermagicCheck("kmm_test")
+		mr.EXPECT().
+			Run(ctx, "-vd", filepath.Join(SharedFilesDir, dirName), "kmm_test").
+			Return(&ModprobeResult{ExitCode: 1}, errors.New("random error"))
 
 		Expect(
 			w.LoadKmod(ctx, &cfg, ""),
 		).To(
 			HaveOccurred(),
 		)
+
+		Expect(
+			w.Result().KernelLog,
+		).To(
+			Equal([]string{
+				"[    5.000000] kmm_test: loading out-of-tree module taints kernel.",
+				"[    5.000100] kmm_test: module verification failed: signature and/or required key missing - tainting kernel",
+			}),
+		)
 	})
 
 	It("should remove present-on-host in-tree module if configured", func() {
@@ -74,6 +178,7 @@ var _ = Describe("worker_LoadKmod", func() {
 
 		cfg := v1beta1.ModuleConfig{
 			ContainerImage:        imageName,
+			KernelVersion:         kernelVersion,
 			InTreeModulesToRemove: inTreeModulesToRemove,
 			Modprobe: v1beta1.ModprobeSpec{
 				ModuleName: moduleName,
@@ -81,25 +186,41 @@ var _ = Describe("worker_LoadKmod", func() {
 			},
 		}
 
+		expectVermagicCheck(moduleName)
 		gomock.InOrder(
 			fh.EXPECT().FileExists("/lib/modules", "^intree1.ko").Return(true, nil),
 			fh.EXPECT().FileExists("/lib/modules", "^intree2.ko").Return(false, nil),
 			fh.EXPECT().FileExists("/lib/modules", "^intree3.ko").Return(true, nil),
 			fh.EXPECT().FileExists("/lib/modules", "^intree4.ko").Return(false, fmt.Errorf("some error")),
-			mr.EXPECT().Run(ctx, "-rv", "intree1", "intree3"),
-			mr.EXPECT().Run(ctx, "-vd", filepath.Join(sharedFilesDir, dirName), moduleName),
+			// intree3 is not loaded
+			mr.EXPECT().Run(ctx, "-rv", "intree1", "intree3").Return(&ModprobeResult{Modules: []string{"intree1", "intree1_dep"}}, nil),
+			mr.EXPECT().Run(ctx, "-vd", filepath.Join(SharedFilesDir, dirName), moduleName).Return(&ModprobeResult{Modules: []string{moduleName}}, nil),
 		)
+		expectVerification()
 
 		Expect(
 			w.LoadKmod(ctx, &cfg, ""),
 		).NotTo(
 			HaveOccurred(),
 		)
+
+		Expect(
+			w.Result(),
+		).To(
+			Equal(&v1beta1.WorkerResult{
+				Action:       ActionLoad,
+				ModprobeArgs: []string{"-vd", filepath.Join(SharedFilesDir, dirName), moduleName},
+				Modules:      []string{"intree1", "intree1_dep", moduleName},
+				// only the modules that were actually removed can be restored
+				RemovedInTreeModules: []string{"intree1"},
+			}),
+		)
 	})
 
 	It("should use deprecated InTreeModuleToRemove if configured", func() {
 		cfg := v1beta1.ModuleConfig{
 			ContainerImage:        imageName,
+			KernelVersion:         kernelVersion,
 			InTreeModuleToRemove:  "intreeToRemove",
 			InTreeModulesToRemove: nil,
 			Modprobe: v1beta1.ModprobeSpec{
@@ -108,11 +229,13 @@ var _ = Describe("worker_LoadKmod", func() {
 			},
 		}
 
+		expectVermagicCheck(moduleName)
 		gomock.InOrder(
 			fh.EXPECT().FileExists("/lib/modules", "^intreeToRemove.ko").Return(true, nil),
 			mr.EXPECT().Run(ctx, "-rv", "intreeToRemove"),
-			mr.EXPECT().Run(ctx, "-vd", filepath.Join(sharedFilesDir, dirName), moduleName),
+			mr.EXPECT().Run(ctx, "-vd", filepath.Join(SharedFilesDir, dirName), moduleName),
 		)
+		expectVerification()
 
 		Expect(
 			w.LoadKmod(ctx, &cfg, ""),
@@ -121,9 +244,245 @@ var _ = Describe("worker_LoadKmod", func() {
 		)
 	})
 
-	It("should copy all the firmware files/directories if configured", func() {
+	Context("modules graph", func() {
+		var cfg v1beta1.ModuleConfig
+
+		BeforeEach(func() {
+			cfg = v1beta1.ModuleConfig{
+				ContainerImage: imageName,
+				KernelVersion:  kernelVersion,
+				Modprobe: v1beta1.ModprobeSpec{
+					ModuleName: moduleName,
+					DirName:    dirName,
+					Parameters: []string{"a=1"},
+					ModulesGraph: []v1beta1.ModuleGraphNode{
+						{Name: moduleName, Pre: []string{"transport_a", "transport_b"}},
+						{Name: "transport_a", Pre: []string{"core"}},
+						{Name: "transport_b", Parameters: []string{"b=2"}},
+						{Name: "core"},
+					},
+				},
+			}
+
+			for _, n := range cfg.Modprobe.ModulesGraph {
+				expectVermagicCheck(n.Name)
+			}
+		})
+
+		It("should load the modules in topological order with their own parameters", func() {
+			gomock.InOrder(
+				mr.EXPECT().Run(ctx, "-vd", filepath.Join(SharedFilesDir, dirName), "transport_b", "b=2"),
+				mr.EXPECT().Run(ctx, "-vd", filepath.Join(SharedFilesDir, dirName), "core"),
+				mr.EXPECT().Run(ctx, "-vd", filepath.Join(SharedFilesDir, dirName), "transport_a"),
+				mr.EXPECT().Run(ctx, "-vd", filepath.Join(SharedFilesDir, dirName), moduleName, "a=1"),
+			)
+			expectVerification()
+
+			Expect(
+				w.LoadKmod(ctx, &cfg, ""),
+			).NotTo(
+				HaveOccurred(),
+			)
+
+			Expect(w.Result().ModuleResults).To(Equal([]v1beta1.ModuleResult{
+				{Name: "transport_b", State: ModuleStateLoaded},
+				{Name: "core", State: ModuleStateLoaded},
+				{Name: "transport_a", State: ModuleStateLoaded},
+				{Name: moduleName, State: ModuleStateLoaded},
+			}))
+		})
+
+		It("should load the independent modules and report the failures per module", func() {
+			gomock.InOrder(
+				mr.EXPECT().Run(ctx, "-vd", filepath.Join(SharedFilesDir, dirName), "transport_b", "b=2"),
+				mr.EXPECT().
+					Run(ctx, "-vd", filepath.Join(SharedFilesDir, dirName), "core").
+					Return(&ModprobeResult{ExitCode: 1}, ErrUnknownSymbol),
+			)
+
+			err := w.LoadKmod(ctx, &cfg, "")
+			Expect(err).To(MatchError(ErrUnknownSymbol))
+			Expect(ErrorCategory(err)).To(Equal(CategoryUnknownSymbol))
+
+			Expect(w.Result().ModuleResults).To(Equal([]v1beta1.ModuleResult{
+				{Name: "transport_b", State: ModuleStateLoaded},
+				{Name: "core", State: ModuleStateFailed, Error: ErrUnknownSymbol.Error()},
+				{Name: "transport_a", State: ModuleStateSkipped, Error: "depends on core"},
+				{Name: moduleName, State: ModuleStateSkipped, Error: "depends on transport_a"},
+			}))
+		})
+	})
+
+	Context("module files", func() {
+		var cfg v1beta1.ModuleConfig
+
+		BeforeEach(func() {
+			cfg = v1beta1.ModuleConfig{
+				ContainerImage: imageName,
+				KernelVersion:  kernelVersion,
+				Modprobe: v1beta1.ModprobeSpec{
+					ModuleName:  moduleName,
+					DirName:     dirName,
+					Parameters:  []string{"a=1"},
+					ModuleFiles: []string{"kernel/drivers/kmm-core.ko", "extra/kmm-transport.ko", "extra/test.ko"},
+				},
+			}
+
+			for _, name := range []string{"kmm_core", "kmm_transport", moduleName} {
+				expectVermagicCheck(name)
+			}
+		})
+
+		It("should insert the files in order, with the parameters for the last one only", func() {
+			gomock.InOrder(
+				mr.EXPECT().Insmod(ctx, filepath.Join(modulesDir, "kernel/drivers/kmm-core.ko")),
+				mr.EXPECT().
+					Insmod(ctx, filepath.Join(modulesDir, "extra/kmm-transport.ko")).
+					Return(&ModprobeResult{ExitCode: 1}, ErrAlreadyLoaded),
+				mr.EXPECT().
+					Insmod(ctx, filepath.Join(modulesDir, "extra/test.ko"), "a=1").
+					Return(&ModprobeResult{Modules: []string{moduleName}}, nil),
+			)
+			expectVerification()
+
+			Expect(
+				w.LoadKmod(ctx, &cfg, ""),
+			).NotTo(
+				HaveOccurred(),
+			)
+
+			res := w.Result()
+			Expect(res.ModprobeArgs).To(Equal([]string{"insmod", filepath.Join(modulesDir, "extra/test.ko"), "a=1"}))
+			Expect(res.Modules).To(Equal([]string{moduleName}))
+			Expect(res.ModuleResults).To(Equal([]v1beta1.ModuleResult{
+				{Name: "kmm_core", State: ModuleStateLoaded},
+				{Name: "kmm_transport", State: ModuleStateLoaded},
+				{Name: moduleName, State: ModuleStateLoaded},
+			}))
+		})
+
+		It("should stop at the first file that cannot be inserted", func() {
+			gomock.InOrder(
+				mr.EXPECT().
+					Insmod(ctx, filepath.Join(modulesDir, "kernel/drivers/kmm-core.ko")).
+					Return(&ModprobeResult{ExitCode: 1}, ErrInvalidModuleFormat),
+			)
+
+			err := w.LoadKmod(ctx, &cfg, "")
+			Expect(err).To(MatchError(ErrInvalidModuleFormat))
+			Expect(ErrorCategory(err)).To(Equal(CategoryInvalidModuleFormat))
+
+			Expect(w.Result().ModuleResults).To(Equal([]v1beta1.ModuleResult{
+				{Name: "kmm_core", State: ModuleStateFailed, Error: ErrInvalidModuleFormat.Error()},
+			}))
+		})
+	})
+
+	It("should generate modules.dep if the image does not ship it", func() {
+		shared, err := os.MkdirTemp(SharedFilesDir, "image")
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(os.RemoveAll, shared)
+
+		dir := filepath.Join(shared, "lib", "modules", kernelVersion)
+
+		copyModDepTestData(dir)
+
+		cfg := v1beta1.ModuleConfig{
+			ContainerImage: imageName,
+			KernelVersion:  kernelVersion,
+			Modprobe: v1beta1.ModprobeSpec{
+				ModuleName: "kmm_stack",
+				DirName:    filepath.Base(shared),
+			},
+		}
+
+		gomock.InOrder(
+			msr.EXPECT().GetImageModuleInfo(dir, "kmm_stack").Return(&modinfo.ModuleInfo{Vermagic: kernelVersion}, nil),
+			mr.EXPECT().Run(ctx, "-vd", shared, "kmm_stack"),
+			msr.EXPECT().GetModuleState("kmm_stack").Return(&ModuleState{Name: "kmm_stack", Loaded: true, InitState: "live"}, nil),
+			msr.EXPECT().GetImageSrcVersion(dir, "kmm_stack"),
+		)
+
+		Expect(
+			w.LoadKmod(ctx, &cfg, ""),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(filepath.Join(dir, modulesDepFileName)).To(BeAnExistingFile())
+	})
+
+	Context("signature verification", func() {
+		var (
+			cfg  v1beta1.ModuleConfig
+			dir  string
+			spec *v1beta1.SignatureVerificationSpec
+		)
+
+		BeforeEach(func() {
+			shared, err := os.MkdirTemp(SharedFilesDir, "image")
+			Expect(err).NotTo(HaveOccurred())
+			DeferCleanup(os.RemoveAll, shared)
+
+			spec = &v1beta1.SignatureVerificationSpec{UEFIKeys: true}
+
+			cfg = v1beta1.ModuleConfig{
+				ContainerImage: imageName,
+				KernelVersion:  kernelVersion,
+				Modprobe: v1beta1.ModprobeSpec{
+					ModuleName:            moduleName,
+					DirName:               filepath.Base(shared),
+					SignatureVerification: spec,
+				},
+			}
+
+			dir = filepath.Join(shared, "lib", "modules", kernelVersion)
+
+			const modulesDep = `extra/test.ko: extra/dep.ko kernel/not-shipped.ko
+extra/dep.ko:
+`
+
+			Expect(os.MkdirAll(filepath.Join(dir, "extra"), 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "modules.dep"), []byte(modulesDep), 0644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "extra", "test.ko"), nil, 0644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "extra", "dep.ko"), nil, 0644)).To(Succeed())
+		})
+
+		It("should verify the module and its dependencies shipped in the image before loading", func() {
+			paths := []string{filepath.Join(dir, "extra", "test.ko"), filepath.Join(dir, "extra", "dep.ko")}
+
+			gomock.InOrder(
+				sv.EXPECT().VerifyModules(paths, spec),
+				msr.EXPECT().GetImageModuleInfo(dir, moduleName).Return(&modinfo.ModuleInfo{Vermagic: kernelVersion}, nil),
+				mr.EXPECT().Run(ctx, "-vd", filepath.Join(SharedFilesDir, cfg.Modprobe.DirName), moduleName),
+				msr.EXPECT().GetModuleState(moduleName).Return(&ModuleState{Name: moduleName, Loaded: true, InitState: "live"}, nil),
+				msr.EXPECT().GetImageSrcVersion(dir, moduleName),
+			)
+
+			Expect(
+				w.LoadKmod(ctx, &cfg, ""),
+			).NotTo(
+				HaveOccurred(),
+			)
+		})
+
+		It("should not load the module if its signature is invalid", func() {
+			sv.EXPECT().VerifyModules(gomock.Any(), spec).Return(ErrInvalidSignature)
+
+			Expect(
+				w.LoadKmod(ctx, &cfg, ""),
+			).To(
+				MatchError(ErrInvalidSignature),
+			)
+
+			Expect(w.Result().ModprobeArgs).To(BeNil())
+		})
+	})
+
+	It("should install the firmware files if configured", func() {
 		cfg := v1beta1.ModuleConfig{
 			ContainerImage: imageName,
+			KernelVersion:  kernelVersion,
 			Modprobe: v1beta1.ModprobeSpec{
 				ModuleName:   moduleName,
 				DirName:      dirName,
@@ -131,26 +490,39 @@ var _ = Describe("worker_LoadKmod", func() {
 			},
 		}
 
-		err := os.MkdirAll(filepath.Join(sharedFilesDir, "firmwareDir", "binDir"), 0750)
-		Expect(err).Should(BeNil())
-		err = os.WriteFile(filepath.Join(sharedFilesDir, "firmwareDir", "firwmwareFile1"), []byte("some data 1"), 0660)
-		Expect(err).Should(BeNil())
-		err = os.WriteFile(filepath.Join(sharedFilesDir, "firmwareDir", "binDir", "firwmwareFile2"), []byte("some data 2"), 0660)
-		Expect(err).Should(BeNil())
-
-		mr.EXPECT().Run(ctx, "-vd", filepath.Join(sharedFilesDir, dirName), moduleName)
+		expectVermagicCheck(moduleName)
+		gomock.InOrder(
+			fi.EXPECT().Install(moduleName, filepath.Join(SharedFilesDir, "firmwareDir"), hostDir),
+			mr.EXPECT().Run(ctx, "-vd", filepath.Join(SharedFilesDir, dirName), moduleName),
+		)
+		expectVerification()
 
 		Expect(
 			w.LoadKmod(ctx, &cfg, hostDir),
 		).NotTo(
 			HaveOccurred(),
 		)
-		_, err = os.Stat(hostDir + "/binDir")
-		Expect(err).Should(BeNil())
-		_, err = os.Stat(hostDir + "/binDir/firwmwareFile2")
-		Expect(err).Should(BeNil())
-		_, err = os.Stat(hostDir + "/firwmwareFile1")
-		Expect(err).Should(BeNil())
+	})
+
+	It("should return an error if the firmware could not be installed", func() {
+		cfg := v1beta1.ModuleConfig{
+			ContainerImage: imageName,
+			KernelVersion:  kernelVersion,
+			Modprobe: v1beta1.ModprobeSpec{
+				ModuleName:   moduleName,
+				DirName:      dirName,
+				FirmwarePath: "/firmwareDir",
+			},
+		}
+
+		expectVermagicCheck(moduleName)
+		fi.EXPECT().Install(moduleName, filepath.Join(SharedFilesDir, "firmwareDir"), hostDir).Return(errors.New("random error"))
+
+		Expect(
+			w.LoadKmod(ctx, &cfg, hostDir),
+		).To(
+			MatchError(ErrFirmwareCopyFailed),
+		)
 	})
 
 	It("should use rawArgs if they are defined", func() {
@@ -158,6 +530,7 @@ var _ = Describe("worker_LoadKmod", func() {
 
 		cfg := v1beta1.ModuleConfig{
 			ContainerImage: imageName,
+			KernelVersion:  kernelVersion,
 			Modprobe: v1beta1.ModprobeSpec{
 				RawArgs: &v1beta1.ModprobeArgs{Load: rawArgs},
 			},
@@ -175,6 +548,7 @@ var _ = Describe("worker_LoadKmod", func() {
 	It("should use all modprobe settings", func() {
 		cfg := v1beta1.ModuleConfig{
 			ContainerImage: imageName,
+			KernelVersion:  kernelVersion,
 			Modprobe: v1beta1.ModprobeSpec{
 				ModuleName: moduleName,
 				Parameters: []string{"key0=value0", "key1=value1"},
@@ -183,7 +557,9 @@ var _ = Describe("worker_LoadKmod", func() {
 			},
 		}
 
-		mr.EXPECT().Run(ctx, "-vd", filepath.Join(sharedFilesDir, dirName), "a", "b", "c", moduleName, "key0=value0", "key1=value1")
+		expectVermagicCheck(moduleName)
+		mr.EXPECT().Run(ctx, "-vd", filepath.Join(SharedFilesDir, dirName), "a", "b", "c", moduleName, "key0=value0", "key1=value1")
+		expectVerification()
 
 		Expect(
 			w.LoadKmod(ctx, &cfg, ""),
@@ -191,10 +567,268 @@ var _ = Describe("worker_LoadKmod", func() {
 			HaveOccurred(),
 		)
 	})
+
+	It("should return an error if the module is not loaded after modprobe", func() {
+		cfg := v1beta1.ModuleConfig{
+			ContainerImage: imageName,
+			KernelVersion:  kernelVersion,
+			Modprobe: v1beta1.ModprobeSpec{
+				ModuleName: moduleName,
+				DirName:    dirName,
+			},
+		}
+
+		expectVermagicCheck(moduleName)
+		gomock.InOrder(
+			mr.EXPECT().Run(ctx, "-vd", filepath.Join(SharedFilesDir, dirName), moduleName),
+			msr.EXPECT().GetModuleState(moduleName).Return(&ModuleState{Name: moduleName}, nil),
+			msr.EXPECT().GetImageSrcVersion(modulesDir, moduleName).Return(srcVersion, nil),
+		)
+
+		Expect(
+			w.LoadKmod(ctx, &cfg, ""),
+		).To(
+			HaveOccurred(),
+		)
+	})
+
+	It("should return an error if the module is not live", func() {
+		cfg := v1beta1.ModuleConfig{
+			ContainerImage: imageName,
+			KernelVersion:  kernelVersion,
+			Modprobe: v1beta1.ModprobeSpec{
+				ModuleName: moduleName,
+				DirName:    dirName,
+			},
+		}
+
+		expectVermagicCheck(moduleName)
+		gomock.InOrder(
+			mr.EXPECT().Run(ctx, "-vd", filepath.Join(SharedFilesDir, dirName), moduleName),
+			msr.EXPECT().GetModuleState(moduleName).Return(&ModuleState{Name: moduleName, Loaded: true, InitState: "going"}, nil),
+			msr.EXPECT().GetImageSrcVersion(modulesDir, moduleName).Return(srcVersion, nil),
+		)
+
+		Expect(
+			w.LoadKmod(ctx, &cfg, ""),
+		).To(
+			HaveOccurred(),
+		)
+	})
+
+	It("should return an error if the srcversion differs from the one in the image", func() {
+		cfg := v1beta1.ModuleConfig{
+			ContainerImage: imageName,
+			KernelVersion:  kernelVersion,
+			Modprobe: v1beta1.ModprobeSpec{
+				ModuleName: moduleName,
+				DirName:    dirName,
+			},
+		}
+
+		expectVermagicCheck(moduleName)
+		gomock.InOrder(
+			mr.EXPECT().Run(ctx, "-vd", filepath.Join(SharedFilesDir, dirName), moduleName),
+			msr.EXPECT().GetModuleState(moduleName).Return(&ModuleState{Name: moduleName, Loaded: true, InitState: "live", SrcVersion: "old"}, nil),
+			msr.EXPECT().GetImageSrcVersion(modulesDir, moduleName).Return(srcVersion, nil),
+		)
+
+		Expect(
+			w.LoadKmod(ctx, &cfg, ""),
+		).To(
+			And(MatchError(ErrVerificationFailed), MatchError(ContainSubstring("srcversion"))),
+		)
+	})
+
+	It("should not compare srcversions if the one in the image cannot be read", func() {
+		cfg := v1beta1.ModuleConfig{
+			ContainerImage: imageName,
+			KernelVersion:  kernelVersion,
+			Modprobe: v1beta1.ModprobeSpec{
+				ModuleName: moduleName,
+				DirName:    dirName,
+			},
+		}
+
+		expectVermagicCheck(moduleName)
+		gomock.InOrder(
+			mr.EXPECT().Run(ctx, "-vd", filepath.Join(SharedFilesDir, dirName), moduleName),
+			msr.EXPECT().GetModuleState(moduleName).Return(&ModuleState{Name: moduleName, Loaded: true, InitState: "live", SrcVersion: "old"}, nil),
+			msr.EXPECT().GetImageSrcVersion(modulesDir, moduleName).Return("", errors.New("some error")),
+		)
+
+		Expect(
+			w.LoadKmod(ctx, &cfg, ""),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
+
+	Context("vermagic", func() {
+		var cfg v1beta1.ModuleConfig
+
+		BeforeEach(func() {
+			cfg = v1beta1.ModuleConfig{
+				ContainerImage: imageName,
+				KernelVersion:  kernelVersion,
+				Modprobe: v1beta1.ModprobeSpec{
+					ModuleName: moduleName,
+					DirName:    dirName,
+				},
+			}
+		})
+
+		It("should not load a module built for another kernel", func() {
+			msr.
+				EXPECT().
+				GetImageModuleInfo(modulesDir, moduleName).
+				Return(&modinfo.ModuleInfo{Name: moduleName, Vermagic: "5.14.1 SMP mod_unload "}, nil)
+
+			Expect(
+				w.LoadKmod(ctx, &cfg, ""),
+			).To(
+				And(
+					MatchError(modinfo.ErrVermagicMismatch),
+					MatchError(ContainSubstring(`module test was built for kernel "5.14.1"`)),
+				),
+			)
+
+			Expect(w.Result().ModprobeArgs).To(BeNil())
+		})
+
+		It("should load the module if its information cannot be read", func() {
+			gomock.InOrder(
+				msr.EXPECT().GetImageModuleInfo(modulesDir, moduleName).Return(nil, errors.New("some error")),
+				mr.EXPECT().Run(ctx, "-vd", filepath.Join(SharedFilesDir, dirName), moduleName),
+			)
+			expectVerification()
+
+			Expect(
+				w.LoadKmod(ctx, &cfg, ""),
+			).NotTo(
+				HaveOccurred(),
+			)
+		})
+
+		It("should not check the vermagic if modprobe is forced to ignore it", func() {
+			cfg.Modprobe.Args = &v1beta1.ModprobeArgs{Load: []string{"--force-vermagic"}}
+
+			mr.EXPECT().Run(ctx, "-vd", filepath.Join(SharedFilesDir, dirName), "--force-vermagic", moduleName)
+			expectVerification()
+
+			Expect(
+				w.LoadKmod(ctx, &cfg, ""),
+			).NotTo(
+				HaveOccurred(),
+			)
+		})
+	})
+})
+
+var _ = Describe("worker_CheckKmod", func() {
+	var (
+		msr *MockModuleStateReader
+		w   Worker
+	)
+
+	BeforeEach(func() {
+		ctrl := gomock.NewController(GinkgoT())
+		msr = NewMockModuleStateReader(ctrl)
+		w = NewWorker(nil, nil, msr, nil, nil, nil, nil, GinkgoLogr)
+	})
+
+	cfg := v1beta1.ModuleConfig{
+		Modprobe: v1beta1.ModprobeSpec{
+			ModuleName:          "test",
+			ModulesLoadingOrder: []string{"test", "dep"},
+		},
+	}
+
+	It("should return an error if the module state cannot be read", func() {
+		msr.EXPECT().GetModuleState("test").Return(nil, errors.New("some error"))
+
+		Expect(w.CheckKmod(&cfg)).To(HaveOccurred())
+	})
+
+	It("should succeed if all modules are live", func() {
+		gomock.InOrder(
+			msr.EXPECT().GetModuleState("test").Return(&ModuleState{Name: "test", Loaded: true, InitState: "live"}, nil),
+			msr.EXPECT().GetModuleState("dep").Return(&ModuleState{Name: "dep", Loaded: true, InitState: "live"}, nil),
+		)
+
+		Expect(w.CheckKmod(&cfg)).To(Succeed())
+	})
+
+	It("should return ErrModuleNotLoaded if a module is not loaded", func() {
+		gomock.InOrder(
+			msr.EXPECT().GetModuleState("test").Return(&ModuleState{Name: "test", Loaded: true, InitState: "live"}, nil),
+			msr.EXPECT().GetModuleState("dep").Return(&ModuleState{Name: "dep"}, nil),
+		)
+
+		Expect(
+			w.CheckKmod(&cfg),
+		).To(
+			MatchError("module is not loaded: module dep is not present in /proc/modules"),
+		)
+	})
+
+	It("should return ErrModuleNotLoaded if a module is being unloaded", func() {
+		msr.EXPECT().GetModuleState("test").Return(&ModuleState{Name: "test", Loaded: true, InitState: "going"}, nil)
+
+		Expect(w.CheckKmod(&cfg)).To(MatchError(ErrModuleNotLoaded))
+	})
+})
+
+var _ = Describe("worker_GetKmodStatus", func() {
+	var (
+		msr *MockModuleStateReader
+		w   Worker
+	)
+
+	BeforeEach(func() {
+		ctrl := gomock.NewController(GinkgoT())
+		msr = NewMockModuleStateReader(ctrl)
+		w = NewWorker(nil, nil, msr, nil, nil, nil, nil, GinkgoLogr)
+	})
+
+	cfg := v1beta1.ModuleConfig{
+		KernelVersion: "5.14.0",
+		Modprobe: v1beta1.ModprobeSpec{
+			ModuleName: "test",
+			DirName:    "/dir",
+		},
+	}
+
+	It("should return an error if moduleName is not set", func() {
+		_, err := w.GetKmodStatus(&v1beta1.ModuleConfig{})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should return an error if the module state cannot be read", func() {
+		msr.EXPECT().GetModuleState("test").Return(nil, errors.New("some error"))
+
+		_, err := w.GetKmodStatus(&cfg)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should return the module state and the expected srcversion", func() {
+		state := ModuleState{Name: "test", Loaded: true, SrcVersion: "abc"}
+
+		gomock.InOrder(
+			msr.EXPECT().GetModuleState("test").Return(&state, nil),
+			msr.EXPECT().GetImageSrcVersion("/tmp/dir/lib/modules/5.14.0", "test").Return("def", nil),
+		)
+
+		Expect(
+			w.GetKmodStatus(&cfg),
+		).To(
+			Equal(&KmodStatus{ModuleState: state, ExpectedSrcVersion: "def"}),
+		)
+	})
 })
 
 var _ = Describe("worker_SetFirmwareClassPath", func() {
-	w := NewWorker(nil, nil, GinkgoLogr)
+	w := NewWorker(nil, nil, nil, nil, nil, nil, nil, GinkgoLogr)
 
 	AfterEach(func() {
 		firmwareClassPathLocation = FirmwareClassPathLocation
@@ -240,10 +874,87 @@ var _ = Describe("worker_SetFirmwareClassPath", func() {
 	)
 })
 
+var _ = Describe("worker_SetParameters", func() {
+	const moduleName = "kmm-test"
+
+	var (
+		pw *MockModuleParametersWriter
+		w  Worker
+	)
+
+	BeforeEach(func() {
+		ctrl := gomock.NewController(GinkgoT())
+		pw = NewMockModuleParametersWriter(ctrl)
+		w = NewWorker(nil, nil, nil, nil, pw, nil, nil, GinkgoLogr)
+	})
+
+	It("should return an error if rawArgs are used", func() {
+		cfg := v1beta1.ModuleConfig{
+			Modprobe: v1beta1.ModprobeSpec{
+				ModuleName: moduleName,
+				RawArgs:    &v1beta1.ModprobeArgs{Load: []string{"a"}},
+			},
+		}
+
+		Expect(
+			w.SetParameters(&cfg),
+		).To(
+			MatchError(ErrInvalidConfig),
+		)
+	})
+
+	It("should return the error of the parameters writer", func() {
+		cfg := v1beta1.ModuleConfig{
+			Modprobe: v1beta1.ModprobeSpec{
+				ModuleName: moduleName,
+				Parameters: []string{"key0=value0"},
+			},
+		}
+
+		pw.EXPECT().WriteParameters(moduleName, cfg.Modprobe.Parameters).Return(nil, ErrParameterReadOnly)
+
+		Expect(
+			w.SetParameters(&cfg),
+		).To(
+			MatchError(ErrParameterReadOnly),
+		)
+
+		Expect(w.Result()).To(Equal(&v1beta1.WorkerResult{Action: ActionSetParameters}))
+	})
+
+	It("should record the applied values in the result", func() {
+		cfg := v1beta1.ModuleConfig{
+			Modprobe: v1beta1.ModprobeSpec{
+				ModuleName: moduleName,
+				Parameters: []string{"key0=value0"},
+			},
+		}
+
+		pw.EXPECT().WriteParameters(moduleName, cfg.Modprobe.Parameters).Return(map[string]string{"key0": "value0"}, nil)
+
+		Expect(
+			w.SetParameters(&cfg),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(
+			w.Result(),
+		).To(
+			Equal(&v1beta1.WorkerResult{
+				Action:     ActionSetParameters,
+				Parameters: map[string]string{"key0": "value0"},
+			}),
+		)
+	})
+})
+
 var _ = Describe("worker_UnloadKmod", func() {
 	var (
 		mr       *MockModprobeRunner
 		fh       *utils.MockFSHelper
+		msr      *MockModuleStateReader
+		fi       *MockFirmwareInstaller
 		w        Worker
 		imageDir string
 		hostDir  string
@@ -253,7 +964,9 @@ var _ = Describe("worker_UnloadKmod", func() {
 		ctrl := gomock.NewController(GinkgoT())
 		mr = NewMockModprobeRunner(ctrl)
 		fh = utils.NewMockFSHelper(ctrl)
-		w = NewWorker(mr, fh, GinkgoLogr)
+		msr = NewMockModuleStateReader(ctrl)
+		fi = NewMockFirmwareInstaller(ctrl)
+		w = NewWorker(mr, fh, msr, newFakeKmsgWatcher(""), nil, fi, nil, GinkgoLogr)
 		var err error
 		imageDir, err = os.MkdirTemp("", "imageDir")
 		Expect(err).Should(BeNil())
@@ -276,6 +989,8 @@ var _ = Describe("worker_UnloadKmod", func() {
 		moduleName = "test"
 	)
 
+	unusedState := &ModuleState{Name: moduleName, Loaded: true}
+
 	It("should return an error if modprobe failed", func() {
 		cfg := v1beta1.ModuleConfig{
 			ContainerImage: imageName,
@@ -285,7 +1000,8 @@ var _ = Describe("worker_UnloadKmod", func() {
 			},
 		}
 
-		mr.EXPECT().Run(ctx, "-rvd", filepath.Join(sharedFilesDir, dirName), moduleName).Return(errors.New("random error"))
+		msr.EXPECT().GetModuleState(moduleName).Return(unusedState, nil)
+		mr.EXPECT().Run(ctx, "-rvd", filepath.Join(SharedFilesDir, dirName), moduleName).Return(nil, errors.New("random error"))
 
 		Expect(
 			w.UnloadKmod(ctx, &cfg, ""),
@@ -313,6 +1029,96 @@ var _ = Describe("worker_UnloadKmod", func() {
 		)
 	})
 
+	It("should unload the modules graph in reverse order and report the failures per module", func() {
+		cfg := v1beta1.ModuleConfig{
+			ContainerImage: imageName,
+			Modprobe: v1beta1.ModprobeSpec{
+				ModuleName: moduleName,
+				DirName:    dirName,
+				ModulesGraph: []v1beta1.ModuleGraphNode{
+					{Name: moduleName, Pre: []string{"transport_a", "transport_b"}},
+					{Name: "transport_a", Pre: []string{"core"}},
+					{Name: "transport_b"},
+					{Name: "core"},
+				},
+			},
+		}
+
+		gomock.InOrder(
+			msr.EXPECT().GetModuleState(moduleName).Return(unusedState, nil),
+			mr.EXPECT().Run(ctx, "-rvd", filepath.Join(SharedFilesDir, dirName), moduleName),
+			mr.EXPECT().
+				Run(ctx, "-rvd", filepath.Join(SharedFilesDir, dirName), "transport_a").
+				Return(&ModprobeResult{ExitCode: 1}, ErrModuleInUse),
+			mr.EXPECT().Run(ctx, "-rvd", filepath.Join(SharedFilesDir, dirName), "transport_b"),
+		)
+
+		Expect(
+			w.UnloadKmod(ctx, &cfg, ""),
+		).To(
+			MatchError(ErrModuleInUse),
+		)
+
+		Expect(w.Result().ModuleResults).To(Equal([]v1beta1.ModuleResult{
+			{Name: moduleName, State: ModuleStateUnloaded},
+			{Name: "transport_a", State: ModuleStateFailed, Error: ErrModuleInUse.Error()},
+			{Name: "core", State: ModuleStateSkipped, Error: "needed by transport_a"},
+			{Name: "transport_b", State: ModuleStateUnloaded},
+		}))
+	})
+
+	It("should remove the module files in reverse order and ignore dependencies still in use", func() {
+		cfg := v1beta1.ModuleConfig{
+			ContainerImage: imageName,
+			Modprobe: v1beta1.ModprobeSpec{
+				ModuleName:  moduleName,
+				DirName:     dirName,
+				ModuleFiles: []string{"kernel/drivers/kmm-core.ko", "extra/kmm-transport.ko", "extra/test.ko"},
+			},
+		}
+
+		gomock.InOrder(
+			msr.EXPECT().GetModuleState(moduleName).Return(unusedState, nil),
+			mr.EXPECT().Rmmod(ctx, moduleName),
+			mr.EXPECT().Rmmod(ctx, "kmm_transport").Return(&ModprobeResult{ExitCode: 1}, ErrModuleInUse),
+			mr.EXPECT().Rmmod(ctx, "kmm_core"),
+		)
+
+		Expect(
+			w.UnloadKmod(ctx, &cfg, ""),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(w.Result().ModuleResults).To(Equal([]v1beta1.ModuleResult{
+			{Name: moduleName, State: ModuleStateUnloaded},
+			{Name: "kmm_transport", State: ModuleStateSkipped, Error: ErrModuleInUse.Error()},
+			{Name: "kmm_core", State: ModuleStateUnloaded},
+		}))
+	})
+
+	It("should return an error if the module file cannot be removed", func() {
+		cfg := v1beta1.ModuleConfig{
+			ContainerImage: imageName,
+			Modprobe: v1beta1.ModprobeSpec{
+				ModuleName:  moduleName,
+				DirName:     dirName,
+				ModuleFiles: []string{"kernel/drivers/kmm-core.ko", "extra/test.ko"},
+			},
+		}
+
+		gomock.InOrder(
+			msr.EXPECT().GetModuleState(moduleName).Return(unusedState, nil),
+			mr.EXPECT().Rmmod(ctx, moduleName).Return(&ModprobeResult{ExitCode: 1}, ErrModuleInUse),
+		)
+
+		Expect(
+			w.UnloadKmod(ctx, &cfg, ""),
+		).To(
+			MatchError(ErrModuleInUse),
+		)
+	})
+
 	It("should use all modprobe settings", func() {
 		cfg := v1beta1.ModuleConfig{
 			ContainerImage: imageName,
@@ -323,7 +1129,8 @@ var _ = Describe("worker_UnloadKmod", func() {
 			},
 		}
 
-		mr.EXPECT().Run(ctx, "-rvd", filepath.Join(sharedFilesDir, dirName), "a", "b", "c", moduleName)
+		msr.EXPECT().GetModuleState(moduleName).Return(unusedState, nil)
+		mr.EXPECT().Run(ctx, "-rvd", filepath.Join(SharedFilesDir, dirName), "a", "b", "c", moduleName)
 
 		Expect(
 			w.UnloadKmod(ctx, &cfg, ""),
@@ -332,7 +1139,7 @@ var _ = Describe("worker_UnloadKmod", func() {
 		)
 	})
 
-	It("should remove all firmware file only", func() {
+	It("should remove the firmware files", func() {
 		cfg := v1beta1.ModuleConfig{
 			ContainerImage: imageName,
 			Modprobe: v1beta1.ModprobeSpec{
@@ -342,8 +1149,9 @@ var _ = Describe("worker_UnloadKmod", func() {
 			},
 		}
 
-		mr.EXPECT().Run(ctx, "-rvd", filepath.Join(sharedFilesDir, dirName), moduleName)
-		fh.EXPECT().RemoveSrcFilesFromDst(filepath.Join(sharedFilesDir, cfg.Modprobe.FirmwarePath), hostDir).Return(nil)
+		msr.EXPECT().GetModuleState(moduleName).Return(unusedState, nil)
+		mr.EXPECT().Run(ctx, "-rvd", filepath.Join(SharedFilesDir, dirName), moduleName)
+		fi.EXPECT().Remove(moduleName, filepath.Join(SharedFilesDir, cfg.Modprobe.FirmwarePath), hostDir)
 
 		Expect(
 			w.UnloadKmod(ctx, &cfg, hostDir),
@@ -351,6 +1159,84 @@ var _ = Describe("worker_UnloadKmod", func() {
 			HaveOccurred(),
 		)
 	})
+
+	It("should fail without running modprobe if the module is in use", func() {
+		cfg := v1beta1.ModuleConfig{
+			ContainerImage: imageName,
+			Modprobe: v1beta1.ModprobeSpec{
+				ModuleName: moduleName,
+				DirName:    dirName,
+			},
+		}
+
+		state := ModuleState{
+			Name:     moduleName,
+			Loaded:   true,
+			RefCount: 2,
+			UsedBy:   []string{"user1"},
+			Holders:  []string{"user1"},
+		}
+
+		gomock.InOrder(
+			msr.EXPECT().GetModuleState(moduleName).Return(&state, nil),
+			msr.EXPECT().GetModuleProcesses(moduleName).Return([]string{"123 (app)"}, nil),
+		)
+
+		err := w.UnloadKmod(ctx, &cfg, "")
+		Expect(err).To(MatchError(ErrModuleInUse))
+		Expect(err.Error()).To(ContainSubstring("blocked by module:user1, process:123 (app)"))
+		Expect(w.Result().BlockedBy).To(Equal([]string{"module:user1", "process:123 (app)"}))
+	})
+
+	It("should wait for the module to be unused", func() {
+		DeferCleanup(func(b wait.Backoff) { unloadWaitBackoff = b }, unloadWaitBackoff)
+		unloadWaitBackoff = wait.Backoff{Duration: time.Millisecond, Steps: 1}
+
+		cfg := v1beta1.ModuleConfig{
+			ContainerImage: imageName,
+			Modprobe: v1beta1.ModprobeSpec{
+				ModuleName:        moduleName,
+				DirName:           dirName,
+				UnloadWaitTimeout: &metav1.Duration{Duration: time.Minute},
+			},
+		}
+
+		gomock.InOrder(
+			msr.EXPECT().GetModuleState(moduleName).Return(&ModuleState{Name: moduleName, Loaded: true, RefCount: 1}, nil),
+			msr.EXPECT().GetModuleProcesses(moduleName),
+			msr.EXPECT().GetModuleState(moduleName).Return(unusedState, nil),
+			mr.EXPECT().Run(ctx, "-rvd", filepath.Join(SharedFilesDir, dirName), moduleName),
+		)
+
+		Expect(
+			w.UnloadKmod(ctx, &cfg, ""),
+		).NotTo(
+			HaveOccurred(),
+		)
+
+		Expect(w.Result().BlockedBy).To(BeEmpty())
+	})
+
+	It("should run modprobe if the module state cannot be read", func() {
+		cfg := v1beta1.ModuleConfig{
+			ContainerImage: imageName,
+			Modprobe: v1beta1.ModprobeSpec{
+				ModuleName: moduleName,
+				DirName:    dirName,
+			},
+		}
+
+		gomock.InOrder(
+			msr.EXPECT().GetModuleState(moduleName).Return(nil, errors.New("random error")),
+			mr.EXPECT().Run(ctx, "-rvd", filepath.Join(SharedFilesDir, dirName), moduleName),
+		)
+
+		Expect(
+			w.UnloadKmod(ctx, &cfg, ""),
+		).NotTo(
+			HaveOccurred(),
+		)
+	})
 })
 
 func ToInterfaceSlice[T any](s []T) []interface{} {